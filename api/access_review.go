@@ -0,0 +1,149 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// title: explain permission check
+// path: /permissions/explain
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Not found
+func explainPermission(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !permission.Check(t, permission.PermRoleReadExplain) {
+		return permission.ErrUnauthorized
+	}
+	ctx := r.Context()
+	scheme, contexts, err := accessCheckParams(ctx, r)
+	if err != nil {
+		return err
+	}
+	email := InputValue(r, "user")
+	tokenID := InputValue(r, "token")
+	var principal *auth.Principal
+	switch {
+	case email != "" && tokenID == "":
+		user, userErr := auth.GetUserByEmail(email)
+		if userErr != nil {
+			if userErr == authTypes.ErrUserNotFound {
+				return &errors.HTTP{Code: http.StatusNotFound, Message: userErr.Error()}
+			}
+			return userErr
+		}
+		principal, err = auth.UserPrincipal(user)
+	case tokenID != "" && email == "":
+		teamToken, tokenErr := servicemanager.TeamToken.FindByTokenID(ctx, tokenID)
+		if tokenErr != nil {
+			if tokenErr == authTypes.ErrTeamTokenNotFound {
+				return &errors.HTTP{Code: http.StatusNotFound, Message: tokenErr.Error()}
+			}
+			return tokenErr
+		}
+		principal, err = auth.TeamTokenPrincipal(teamToken)
+	default:
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "either user or token must be provided",
+		}
+	}
+	if err != nil {
+		return err
+	}
+	explanation := principal.Explain(scheme, contexts...)
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(explanation)
+}
+
+// title: permission access review
+// path: /permissions/review
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Not found
+func accessReview(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !permission.Check(t, permission.PermRoleReadReview) {
+		return permission.ErrUnauthorized
+	}
+	ctx := r.Context()
+	scheme, contexts, err := accessCheckParams(ctx, r)
+	if err != nil {
+		return err
+	}
+	entries, err := auth.AccessReview(ctx, scheme, contexts...)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(entries)
+}
+
+// accessCheckParams parses the permission and the object being checked,
+// expanding apps and pools into the same contexts used by their handlers.
+func accessCheckParams(ctx context.Context, r *http.Request) (*permission.PermissionScheme, []permTypes.PermissionContext, error) {
+	permName := InputValue(r, "permission")
+	if permName == "" {
+		return nil, nil, &errors.HTTP{Code: http.StatusBadRequest, Message: "permission is required"}
+	}
+	scheme, err := permission.SafeGet(permName)
+	if err != nil {
+		return nil, nil, &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid permission %q: %v", permName, err),
+		}
+	}
+	ctxTypeName := InputValue(r, "context")
+	ctxValue := InputValue(r, "value")
+	if ctxTypeName == "" {
+		return scheme, nil, nil
+	}
+	ctxType, err := permission.ParseContext(ctxTypeName)
+	if err != nil {
+		return nil, nil, &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	switch ctxType {
+	case permTypes.CtxGlobal:
+		return scheme, nil, nil
+	case permTypes.CtxApp:
+		a, err := getApp(ctx, ctxValue)
+		if err != nil {
+			return nil, nil, err
+		}
+		return scheme, contextsForApp(a), nil
+	case permTypes.CtxPool:
+		_, err = pool.GetPoolByName(ctx, ctxValue)
+		if err != nil {
+			if err == pool.ErrPoolNotFound {
+				return nil, nil, &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+			}
+			return nil, nil, err
+		}
+	}
+	return scheme, []permTypes.PermissionContext{permission.Context(ctxType, ctxValue)}, nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestExplainPermissionForUser(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name, Tags: []string{"prod"}}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	role, err := permission.NewRole("deployer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = role.AddCondition(permission.RoleCondition{Type: permission.ConditionTag, Value: "prod"})
+	c.Assert(err, check.IsNil)
+	user := &auth.User{Email: "explained@tsuru.io", Password: "123456"}
+	_, err = nativeScheme.Create(context.TODO(), user)
+	c.Assert(err, check.IsNil)
+	err = user.AddRole("deployer", s.team.Name)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleReadExplain,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.10/permissions/explain?permission=app.deploy&context=app&value=myapp&user=explained@tsuru.io", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	c.Assert(rec.Header().Get("Content-Type"), check.Equals, "application/json")
+	var explanation auth.CheckExplanation
+	err = json.Unmarshal(rec.Body.Bytes(), &explanation)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Principal, check.Equals, "explained@tsuru.io")
+	c.Assert(explanation.Contexts, check.DeepEquals, contextsForApp(&a))
+	c.Assert(explanation.Permissions, check.DeepEquals, []auth.ExplainedPermission{{
+		Permission:     "app.deploy(team " + s.team.Name + ") if [tag=prod]",
+		Source:         auth.PermissionSource{Role: "deployer", ContextType: "team", ContextValue: s.team.Name},
+		ContextMatch:   true,
+		ConditionMatch: true,
+		Applied:        true,
+	}})
+}
+
+func (s *S) TestExplainPermissionForTeamToken(c *check.C) {
+	teamToken, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team: s.team.Name,
+	}, s.token)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleReadExplain,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.10/permissions/explain?permission=pool.create&token="+teamToken.TokenID, nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	var explanation auth.CheckExplanation
+	err = json.Unmarshal(rec.Body.Bytes(), &explanation)
+	c.Assert(err, check.IsNil)
+	c.Assert(explanation.Kind, check.Equals, auth.PrincipalKindTeamToken)
+	c.Assert(explanation.Principal, check.Equals, teamToken.TokenID)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Permissions, check.HasLen, 0)
+}
+
+func (s *S) TestExplainPermissionRequiresPrincipal(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleReadExplain,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.10/permissions/explain?permission=app.deploy", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(rec.Body.String(), check.Equals, "either user or token must be provided\n")
+}
+
+func (s *S) TestExplainPermissionUserNotFound(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleReadExplain,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.10/permissions/explain?permission=app.deploy&user=nobody@tsuru.io", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestExplainPermissionUnauthorized(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleUpdate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.10/permissions/explain?permission=app.deploy&user="+s.user.Email, nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestAccessReview(c *check.C) {
+	role, err := permission.NewRole("pool-admin", "pool", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions("pool.update")
+	c.Assert(err, check.IsNil)
+	user := &auth.User{Email: "reviewed@tsuru.io", Password: "123456"}
+	_, err = nativeScheme.Create(context.TODO(), user)
+	c.Assert(err, check.IsNil)
+	err = user.AddRole("pool-admin", s.Pool)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleReadReview,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.10/permissions/review?permission=pool.update.team&context=pool&value="+s.Pool, nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	var entries []auth.AccessReviewEntry
+	err = json.Unmarshal(rec.Body.Bytes(), &entries)
+	c.Assert(err, check.IsNil)
+	var found *auth.AccessReviewEntry
+	for i := range entries {
+		if entries[i].Name == "reviewed@tsuru.io" {
+			found = &entries[i]
+		}
+	}
+	c.Assert(found, check.NotNil)
+	c.Assert(found.Sources, check.DeepEquals, []auth.PermissionSource{
+		{Role: "pool-admin", ContextType: "pool", ContextValue: s.Pool},
+	})
+}
+
+func (s *S) TestAccessReviewPoolNotFound(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleReadReview,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.10/permissions/review?permission=pool.update&context=pool&value=unknown", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestAccessReviewInvalidPermission(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleReadReview,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req, err := http.NewRequest(http.MethodGet, "/1.10/permissions/review?permission=invalid.perm", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+}
@@ -288,6 +288,10 @@ func appInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return err
 	}
+	err = a.FillProcessContainers()
+	if err != nil {
+		return err
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(&a)
@@ -304,6 +308,12 @@ type inputApp struct {
 	RouterOpts   map[string]string
 	Tags         []string
 	PlanOverride appTypes.PlanOverride
+	ProcessPlans map[string]inputProcessPlan
+}
+
+type inputProcessPlan struct {
+	Plan     string
+	Override appTypes.PlanOverride
 }
 
 func autoTeamOwner(ctx stdContext.Context, t auth.Token, perm *permission.PermissionScheme) (string, error) {
@@ -476,6 +486,12 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 		UpdatePlatform: imageReset,
 		RouterOpts:     ia.RouterOpts,
 	}
+	for process, processPlan := range ia.ProcessPlans {
+		if updateData.ProcessPlans == nil {
+			updateData.ProcessPlans = make(map[string]appTypes.Plan)
+		}
+		updateData.ProcessPlans[process] = appTypes.Plan{Name: processPlan.Plan, Override: processPlan.Override}
+	}
 	tags, _ := InputValues(r, "tag")
 	noRestart, _ := strconv.ParseBool(InputValue(r, "noRestart"))
 	updateData.Tags = append(updateData.Tags, tags...) // for compatibility
@@ -500,6 +516,15 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	if updateData.Plan.Override != (appTypes.PlanOverride{}) {
 		wantedPerms = append(wantedPerms, permission.PermAppUpdatePlanoverride)
 	}
+	for _, processPlan := range updateData.ProcessPlans {
+		hasOverride := processPlan.Override != (appTypes.PlanOverride{})
+		if processPlan.Name != "" || !hasOverride {
+			wantedPerms = append(wantedPerms, permission.PermAppUpdatePlan)
+		}
+		if hasOverride {
+			wantedPerms = append(wantedPerms, permission.PermAppUpdatePlanoverride)
+		}
+	}
 	if updateData.Pool != "" {
 		if noRestart {
 			return &errors.HTTP{Code: http.StatusBadRequest, Message: "You must restart the app when changing the pool."}
@@ -971,6 +996,7 @@ func writeEnvVars(w http.ResponseWriter, a *app.App, variables ...string) error
 // produce: application/x-json-stream
 // responses:
 //   200: Envs updated
+//   202: Waiting for approval
 //   400: Invalid data
 //   401: Unauthorized
 //   404: App not found
@@ -1001,6 +1027,9 @@ func setEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 			toExclude = append(toExclude, fmt.Sprintf("Envs.%d.Value", i))
 		}
 	}
+	if queued, err := queueForApproval(w, r, t, "env-set", toExclude, &a); queued || err != nil {
+		return err
+	}
 	evt, err := event.New(&event.Opts{
 		Target:     appTarget(appName),
 		Kind:       permission.PermAppUpdateEnvSet,
@@ -1045,6 +1074,7 @@ func setEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 // produce: application/x-json-stream
 // responses:
 //   200: Envs removed
+//   202: Waiting for approval
 //   400: Invalid data
 //   401: Unauthorized
 //   404: App not found
@@ -1070,6 +1100,9 @@ func unsetEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
+	if queued, err := queueForApproval(w, r, t, "env-unset", nil, &a); queued || err != nil {
+		return err
+	}
 	evt, err := event.New(&event.Opts{
 		Target:     appTarget(appName),
 		Kind:       permission.PermAppUpdateEnvUnset,
@@ -1628,8 +1661,10 @@ func addLog(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 // path: /swap
 // method: POST
 // consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
 // responses:
 //   200: Ok
+//   202: Waiting for approval
 //   400: Invalid data
 //   401: Unauthorized
 //   404: App not found
@@ -1641,9 +1676,21 @@ func swap(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	app2Name := InputValue(r, "app2")
 	forceSwap := InputValue(r, "force")
 	cnameOnly, _ := strconv.ParseBool(InputValue(r, "cnameOnly"))
+	verify, _ := strconv.ParseBool(InputValue(r, "verify"))
 	if forceSwap == "" {
 		forceSwap = "false"
 	}
+	var monitorTime, monitorInterval int
+	if verify {
+		monitorTime, err = optionalIntInput(r, "monitorTime")
+		if err != nil {
+			return err
+		}
+		monitorInterval, err = optionalIntInput(r, "monitorInterval")
+		if err != nil {
+			return err
+		}
+	}
 	app1, err := getApp(ctx, app1Name)
 	if err != nil {
 		return err
@@ -1661,6 +1708,9 @@ func swap(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	if !allowed1 || !allowed2 {
 		return permission.ErrUnauthorized
 	}
+	if queued, err := queueForApproval(w, r, t, "swap", nil, app1, app2); queued || err != nil {
+		return err
+	}
 	evt, err := event.New(&event.Opts{
 		Target: appTarget(app1Name),
 		ExtraTargets: []event.ExtraTarget{
@@ -1701,7 +1751,36 @@ func swap(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 			}
 		}
 	}
-	return app.Swap(ctx, app1, app2, cnameOnly)
+	if !verify {
+		return app.Swap(ctx, app1, app2, cnameOnly)
+	}
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	return app.GuardedSwap(ctx, app1, app2, app.GuardedSwapArgs{
+		CNameOnly:       cnameOnly,
+		SmokeTest:       InputValue(r, "smokeTest"),
+		MonitorDuration: time.Duration(monitorTime) * time.Second,
+		MonitorInterval: time.Duration(monitorInterval) * time.Second,
+		Writer:          evt,
+	})
+}
+
+func optionalIntInput(r *http.Request, name string) (int, error) {
+	value := InputValue(r, name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid value for %s: %q", name, value),
+		}
+	}
+	return n, nil
 }
 
 // title: app start
@@ -2077,8 +2156,5 @@ func listCertificates(w http.ResponseWriter, r *http.Request, t auth.Token) erro
 }
 
 func contextsForApp(a *app.App) []permTypes.PermissionContext {
-	return append(permission.Contexts(permTypes.CtxTeam, a.Teams),
-		permission.Context(permTypes.CtxApp, a.Name),
-		permission.Context(permTypes.CtxPool, a.Pool),
-	)
+	return a.PermissionContexts()
 }
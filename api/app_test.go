@@ -1890,6 +1890,57 @@ func (s *S) TestUpdateAppPlanOnly(c *check.C) {
 	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 1)
 }
 
+func (s *S) TestUpdateAppProcessPlan(c *check.C) {
+	config.Set("docker:router", "fake")
+	defer config.Unset("docker:router")
+	smallPlan := appTypes.Plan{Name: "small", Memory: 134217728, CPUMilli: 100}
+	s.mockService.Plan.OnFindByName = func(name string) (*appTypes.Plan, error) {
+		c.Assert(name, check.Equals, smallPlan.Name)
+		return &smallPlan, nil
+	}
+	defaultPlan, err := s.mockService.Plan.DefaultPlan(context.TODO())
+	c.Assert(err, check.IsNil)
+	s.mockService.Plan.OnList = func() ([]appTypes.Plan, error) {
+		return []appTypes.Plan{*defaultPlan, smallPlan}, nil
+	}
+	a := app.App{Name: "someapp", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	body := strings.NewReader("processplans.worker.plan=small&processplans.worker.override.cpumilli=200")
+	request, err := http.NewRequest("PUT", "/apps/someapp", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %v", recorder.Body.String()))
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.GetProcessPlan("worker").Name, check.Equals, "small")
+	c.Assert(dbApp.GetProcessMemory("worker"), check.Equals, smallPlan.Memory)
+	c.Assert(dbApp.GetProcessMilliCPU("worker"), check.Equals, 200)
+	c.Assert(dbApp.GetProcessPlan("web"), check.DeepEquals, dbApp.Plan)
+}
+
+func (s *S) TestUpdateAppProcessPlanOverrideWithoutPermission(c *check.C) {
+	a := app.App{Name: "someapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdatePlan,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	body := strings.NewReader("processplans.worker.override.memory=1024")
+	request, err := http.NewRequest("PUT", "/apps/someapp", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *S) TestUpdateAppPlanOverrideOnly(c *check.C) {
 	config.Set("docker:router", "fake")
 	defer config.Unset("docker:router")
@@ -6030,6 +6081,100 @@ func (s *S) TestSwapIncompatibleAppsForceSwap(c *check.C) {
 	c.Assert(recorder.Body.String(), check.Equals, "")
 }
 
+func (s *S) TestSwapVerify(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	c.Assert(err, check.IsNil)
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := app.App{Name: "app2", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &app2, s.user)
+	c.Assert(err, check.IsNil)
+	routertest.FakeRouter.SetBackendAddr(context.TODO(), app2.Name, srvURL.Host)
+	b := strings.NewReader("app1=app1&app2=app2&cnameOnly=false&verify=true")
+	request, err := http.NewRequest("POST", "/swap", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*Verifying healthcheck of app \\"app2\\".*Swapping apps.*`)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(app1.Name),
+		ExtraTargets: []event.ExtraTarget{
+			{Target: event.Target{Type: "app", Value: app2.Name}, Lock: true},
+		},
+		Owner: s.token.GetUserName(),
+		Kind:  "app.update.swap",
+		StartCustomData: []map[string]interface{}{
+			{"name": "app1", "value": app1.Name},
+			{"name": "app2", "value": app2.Name},
+			{"name": "cnameOnly", "value": "false"},
+			{"name": "verify", "value": "true"},
+		},
+		LogMatches: []string{`Verifying healthcheck of app "app2"`},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSwapVerifyHealthcheckFailure(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	c.Assert(err, check.IsNil)
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name, CName: []string{"app1.cname"}}
+	err = app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := app.App{Name: "app2", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &app2, s.user)
+	c.Assert(err, check.IsNil)
+	routertest.FakeRouter.SetBackendAddr(context.TODO(), app2.Name, srvURL.Host)
+	b := strings.NewReader("app1=app1&app2=app2&verify=true&monitorTime=10")
+	request, err := http.NewRequest("POST", "/swap", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*swap healthcheck verification failed for app \\"app2\\".*`)
+	var dbApp app.App
+	err = s.conn.Apps().Find(bson.M{"name": app1.Name}).One(&dbApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.CName, check.DeepEquals, []string{"app1.cname"})
+	c.Assert(eventtest.EventDesc{
+		Target:       appTarget(app1.Name),
+		Owner:        s.token.GetUserName(),
+		Kind:         "app.update.swap",
+		ErrorMatches: `swap healthcheck verification failed for app "app2".*`,
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSwapVerifyInvalidMonitorTime(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := app.App{Name: "app2", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &app2, s.user)
+	c.Assert(err, check.IsNil)
+	b := strings.NewReader("app1=app1&app2=app2&verify=true&monitorTime=abc")
+	request, err := http.NewRequest("POST", "/swap", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid value for monitorTime: \"abc\"\n")
+}
+
 func (s *S) TestStartHandler(c *check.C) {
 	config.Set("docker:router", "fake")
 	defer config.Unset("docker:router")
@@ -0,0 +1,397 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const approvalRequestKind = "approval-request"
+
+// approvalOperations maps the operations that may be queued for approval to
+// the handlers used to replay them once approved.
+var approvalOperations map[string]AuthorizationRequiredHandler
+
+func init() {
+	approvalOperations = map[string]AuthorizationRequiredHandler{
+		"deploy":     deploy,
+		"rollback":   deployRollback,
+		"rebuild":    deployRebuild,
+		"env-set":    setEnv,
+		"env-unset":  unsetEnv,
+		"swap":       swap,
+		"protection": appProtectionUpdate,
+	}
+}
+
+// queueForApproval stores the request as a pending approval request when any
+// of the given apps is protected, answering with 202 Accepted. The returned
+// bool tells the caller whether the request was queued, in which case the
+// operation must not be executed. Inputs listed in exclude are masked in the
+// stored input summary, just like in events, and the stored body is encrypted
+// when there are any.
+func queueForApproval(w http.ResponseWriter, r *http.Request, t auth.Token, operation string, exclude []string, apps ...*app.App) (bool, error) {
+	if context.IsApprovalReplay(r) {
+		return false, nil
+	}
+	var names []string
+	required := 0
+	for _, a := range apps {
+		names = append(names, a.Name)
+		if a.RequiredApprovals > required {
+			required = a.RequiredApprovals
+		}
+	}
+	if required == 0 {
+		return false, nil
+	}
+	if t.IsAppToken() {
+		return false, &errors.HTTP{Code: http.StatusForbidden, Message: "operations on protected apps must be requested by a user"}
+	}
+	if _, err := t.User(); err != nil {
+		return false, &errors.HTTP{Code: http.StatusForbidden, Message: "operations on protected apps must be requested by a user"}
+	}
+	contentType := r.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "multipart/") {
+		return false, &errors.HTTP{Code: http.StatusBadRequest, Message: "file uploads to protected apps cannot be approved, deploy an image or an archive-url instead"}
+	}
+	input := event.FormToCustomData(InputFields(r, exclude...))
+	var body []byte
+	if contentType == "application/json" {
+		data, err := context.GetBody(r)
+		if err != nil {
+			return false, err
+		}
+		body = data
+	} else if err := parseForm(r); err == nil && len(r.PostForm) > 0 {
+		contentType = "application/x-www-form-urlencoded"
+		body = []byte(r.PostForm.Encode())
+	}
+	req := app.ApprovalRequest{
+		Apps:              names,
+		Operation:         operation,
+		Requester:         t.GetUserName(),
+		Input:             input,
+		Method:            r.Method,
+		URL:               r.URL.String(),
+		ContentType:       contentType,
+		RequiredApprovals: required,
+	}
+	err := req.SetBody(body, len(exclude) > 0)
+	if err != nil {
+		return false, err
+	}
+	err = app.CreateApprovalRequest(&req)
+	if err != nil {
+		return false, err
+	}
+	var extraTargets []event.ExtraTarget
+	for _, name := range names[1:] {
+		extraTargets = append(extraTargets, event.ExtraTarget{Target: appTarget(name)})
+	}
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       appTarget(names[0]),
+		ExtraTargets: extraTargets,
+		InternalKind: approvalRequestKind,
+		RawOwner:     event.Owner{Type: event.OwnerTypeUser, Name: req.Requester},
+		CustomData: map[string]interface{}{
+			"approvalRequest":   req.ID.Hex(),
+			"operation":         operation,
+			"requiredApprovals": required,
+			"input":             input,
+		},
+		DisableLock: true,
+		Allowed:     event.Allowed(permission.PermAppReadEvents, contextsForApp(apps[0])...),
+	})
+	if err != nil {
+		log.Errorf("unable to record approval request %s: %v", req.ID.Hex(), err)
+	} else {
+		evt.Done(nil)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	return true, json.NewEncoder(w).Encode(req)
+}
+
+// replayApprovalRequest executes an approved request as its requester,
+// streaming the operation output to w.
+func replayApprovalRequest(w http.ResponseWriter, req *app.ApprovalRequest) error {
+	execErr := func() error {
+		handler, ok := approvalOperations[req.Operation]
+		if !ok {
+			return fmt.Errorf("unknown approval operation %q", req.Operation)
+		}
+		body, err := req.RequestBody()
+		if err != nil {
+			return err
+		}
+		replay, err := http.NewRequest(req.Method, req.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if req.ContentType != "" {
+			replay.Header.Set("Content-Type", req.ContentType)
+		}
+		context.SetApprovalReplay(replay)
+		return handler(w, replay, &auth.APIToken{UserEmail: req.Requester})
+	}()
+	if err := req.SetResult(execErr); err != nil {
+		log.Errorf("unable to store result of approval request %s: %v", req.ID.Hex(), err)
+	}
+	return execErr
+}
+
+func approverPermission() (*permission.PermissionScheme, error) {
+	name, _ := config.GetString("approvals:permission")
+	if name == "" {
+		return permission.PermAppApprovalReview, nil
+	}
+	scheme, err := permission.SafeGet(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approvals:permission %q: %v", name, err)
+	}
+	return scheme, nil
+}
+
+// approvalRequestFromURL loads the approval request in the URL, making sure it
+// involves the app in the URL and that the token has the given permission on
+// it.
+func approvalRequestFromURL(r *http.Request, t auth.Token, scheme *permission.PermissionScheme) (app.App, *app.ApprovalRequest, error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return a, nil, err
+	}
+	if !permission.Check(t, scheme, contextsForApp(&a)...) {
+		return a, nil, permission.ErrUnauthorized
+	}
+	id := r.URL.Query().Get(":id")
+	if !bson.IsObjectIdHex(id) {
+		return a, nil, &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("id parameter is not ObjectId: %s", id)}
+	}
+	req, err := app.GetApprovalRequest(bson.ObjectIdHex(id))
+	if err == nil && !req.Involves(a.Name) {
+		err = app.ErrApprovalRequestNotFound
+	}
+	if err == app.ErrApprovalRequestNotFound {
+		return a, nil, &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return a, req, err
+}
+
+// approvalRequestForReview loads the approval request in the URL, making sure
+// the token holds the approver permission on the team owning each app
+// involved in the request.
+func approvalRequestForReview(r *http.Request, t auth.Token) (app.App, *app.ApprovalRequest, error) {
+	scheme, err := approverPermission()
+	if err != nil {
+		return app.App{}, nil, err
+	}
+	a, req, err := approvalRequestFromURL(r, t, permission.PermAppApprovalRead)
+	if err != nil {
+		return a, nil, err
+	}
+	for _, name := range req.Apps {
+		involved, err := getApp(r.Context(), name)
+		if err != nil {
+			return a, nil, err
+		}
+		if !permission.Check(t, scheme, permission.Context(permTypes.CtxTeam, involved.TeamOwner)) {
+			return a, nil, permission.ErrUnauthorized
+		}
+	}
+	return a, req, nil
+}
+
+func reviewError(err error) error {
+	switch err {
+	case app.ErrApprovalSelfReview:
+		return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error()}
+	case app.ErrApprovalNotPending, app.ErrApprovalAlreadyReviewed, app.ErrApprovalConflict, app.ErrApprovalExpired:
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	return err
+}
+
+func reviewEvent(r *http.Request, t auth.Token, a *app.App, decision string) (*event.Event, error) {
+	customData := event.FormToCustomData(InputFields(r))
+	customData = append(customData, map[string]interface{}{"name": "decision", "value": decision})
+	return event.New(&event.Opts{
+		Target:      appTarget(a.Name),
+		Kind:        permission.PermAppApprovalReview,
+		Owner:       t,
+		CustomData:  customData,
+		DisableLock: true,
+		Allowed:     event.Allowed(permission.PermAppReadEvents, contextsForApp(a)...),
+	})
+}
+
+// title: update app protection
+// path: /apps/{app}/protection
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   200: Protection updated
+//   202: Waiting for approval
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func appProtectionUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, permission.PermAppUpdateProtection, contextsForApp(&a)...) {
+		return permission.ErrUnauthorized
+	}
+	required, err := strconv.Atoi(InputValue(r, "requiredApprovals"))
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "requiredApprovals must be an integer"}
+	}
+	if required < a.RequiredApprovals {
+		if queued, err := queueForApproval(w, r, t, "protection", nil, &a); queued || err != nil {
+			return err
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateProtection,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return a.SetRequiredApprovals(required)
+}
+
+// title: app approval request list
+// path: /apps/{app}/approvals
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   401: Unauthorized
+//   404: App not found
+func approvalRequestList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, permission.PermAppApprovalRead, contextsForApp(&a)...) {
+		return permission.ErrUnauthorized
+	}
+	reqs, err := app.ListApprovalRequests(a.Name, InputValue(r, "status"))
+	if err != nil {
+		return err
+	}
+	if len(reqs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(reqs)
+}
+
+// title: app approval request info
+// path: /apps/{app}/approvals/{id}
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   400: Invalid id
+//   401: Unauthorized
+//   404: Not found
+func approvalRequestInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	_, req, err := approvalRequestFromURL(r, t, permission.PermAppApprovalRead)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(req)
+}
+
+// title: approve app approval request
+// path: /apps/{app}/approvals/{id}/approve
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Approved, operation output is streamed once the request is executed
+//   400: Invalid id
+//   401: Unauthorized
+//   403: Requester cannot review
+//   404: Not found
+//   409: Request not pending or already reviewed
+func approvalRequestApprove(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	a, req, err := approvalRequestForReview(r, t)
+	if err != nil {
+		return err
+	}
+	evt, err := reviewEvent(r, t, &a, "approve")
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	ready, err := req.Approve(app.ApprovalVote{User: t.GetUserName(), Comment: InputValue(r, "comment")})
+	if err != nil {
+		return reviewError(err)
+	}
+	if !ready {
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(req)
+	}
+	return replayApprovalRequest(w, req)
+}
+
+// title: reject app approval request
+// path: /apps/{app}/approvals/{id}/reject
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Rejected
+//   400: Invalid id
+//   401: Unauthorized
+//   403: Requester cannot review
+//   404: Not found
+//   409: Request not pending or already reviewed
+func approvalRequestReject(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	a, req, err := approvalRequestForReview(r, t)
+	if err != nil {
+		return err
+	}
+	evt, err := reviewEvent(r, t, &a, "reject")
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = req.Reject(app.ApprovalVote{User: t.GetUserName(), Comment: InputValue(r, "comment")})
+	if err != nil {
+		return reviewError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(req)
+}
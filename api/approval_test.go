@@ -0,0 +1,338 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createProtectedApp(c *check.C, required int) *app.App {
+	a := app.App{Name: "protected-app", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetRequiredApprovals(required)
+	c.Assert(err, check.IsNil)
+	return &a
+}
+
+func (s *S) approverToken(c *check.C, name string) auth.Token {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, name, permission.Permission{
+		Scheme:  permission.PermAppApproval,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	return token
+}
+
+func (s *S) queueSetEnv(c *check.C, a *app.App, body string) app.ApprovalRequest {
+	request, err := http.NewRequest("POST", fmt.Sprintf("/apps/%s/env", a.Name), strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusAccepted, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var req app.ApprovalRequest
+	err = json.NewDecoder(recorder.Body).Decode(&req)
+	c.Assert(err, check.IsNil)
+	return req
+}
+
+func (s *S) reviewApprovalRequest(a *app.App, id, action string, token auth.Token) *httptest.ResponseRecorder {
+	url := fmt.Sprintf("/apps/%s/approvals/%s/%s", a.Name, id, action)
+	request, _ := http.NewRequest("POST", url, strings.NewReader("comment=reviewed"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (s *S) TestSetEnvProtectedAppQueued(c *check.C) {
+	config.Set("approvals:encryption-key", "approval-secret")
+	defer config.Unset("approvals:encryption-key")
+	a := s.createProtectedApp(c, 1)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_PASSWORD&Envs.0.Value=secret&Private=true")
+	c.Assert(req.Status, check.Equals, app.ApprovalPending)
+	c.Assert(req.Operation, check.Equals, "env-set")
+	c.Assert(req.Apps, check.DeepEquals, []string{a.Name})
+	c.Assert(req.Requester, check.Equals, s.user.Email)
+	c.Assert(req.RequiredApprovals, check.Equals, 1)
+	c.Assert(req.Input, check.Not(check.HasLen), 0)
+	for _, field := range req.Input {
+		if field["name"] == "Envs.0.Value" {
+			c.Assert(field["value"], check.Equals, "*****")
+		}
+	}
+	dbReq, err := app.GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.BodyEncrypted, check.Equals, true)
+	c.Assert(string(dbReq.Body), check.Not(check.Matches), `(?s).*secret.*`)
+	c.Assert(dbReq.ExpiresAt.After(dbReq.CreatedAt), check.Equals, true)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	_, ok := dbApp.Env["DATABASE_PASSWORD"]
+	c.Assert(ok, check.Equals, false)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.user.Email,
+		Kind:   approvalRequestKind,
+		StartCustomData: map[string]interface{}{
+			"approvalRequest": req.ID.Hex(),
+			"operation":       "env-set",
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestApprovalRequestApproveReplaysOperation(c *check.C) {
+	a := s.createProtectedApp(c, 1)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_HOST&Envs.0.Value=localhost")
+	token := s.approverToken(c, "approver")
+	recorder := s.reviewApprovalRequest(a, req.ID.Hex(), "approve", token)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*Setting 1 new environment variables.*`)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["DATABASE_HOST"].Value, check.Equals, "localhost")
+	dbReq, err := app.GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, app.ApprovalExecuted)
+	c.Assert(dbReq.Approvals, check.HasLen, 1)
+	c.Assert(dbReq.Approvals[0].User, check.Equals, token.GetUserName())
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  token.GetUserName(),
+		Kind:   "app.approval.review",
+		StartCustomData: []map[string]interface{}{
+			{"name": "decision", "value": "approve"},
+			{"name": "comment", "value": "reviewed"},
+		},
+	}, eventtest.HasEvent)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.user.Email,
+		Kind:   "app.update.env.set",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSetEnvProtectedAppPrivateWithoutEncryptionKey(c *check.C) {
+	a := s.createProtectedApp(c, 1)
+	body := strings.NewReader("Envs.0.Name=DATABASE_PASSWORD&Envs.0.Value=secret&Private=true")
+	request, err := http.NewRequest("POST", fmt.Sprintf("/apps/%s/env", a.Name), body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusInternalServerError)
+	c.Assert(recorder.Body.String(), check.Equals, app.ErrApprovalEncryptionKey.Error()+"\n")
+	reqs, err := app.ListApprovalRequests(a.Name, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.HasLen, 0)
+}
+
+func (s *S) TestApprovalRequestApproveReplaysPrivateEnv(c *check.C) {
+	config.Set("approvals:encryption-key", "approval-secret")
+	defer config.Unset("approvals:encryption-key")
+	a := s.createProtectedApp(c, 1)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_PASSWORD&Envs.0.Value=secret&Private=true")
+	recorder := s.reviewApprovalRequest(a, req.ID.Hex(), "approve", s.approverToken(c, "approver"))
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["DATABASE_PASSWORD"].Value, check.Equals, "secret")
+	c.Assert(dbApp.Env["DATABASE_PASSWORD"].Public, check.Equals, false)
+	dbReq, err := app.GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, app.ApprovalExecuted)
+	c.Assert(dbReq.Body, check.IsNil)
+}
+
+func (s *S) TestApprovalRequestApproveExpired(c *check.C) {
+	config.Set("approvals:expiration", "1ms")
+	defer config.Unset("approvals:expiration")
+	a := s.createProtectedApp(c, 1)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_HOST&Envs.0.Value=localhost")
+	time.Sleep(10 * time.Millisecond)
+	recorder := s.reviewApprovalRequest(a, req.ID.Hex(), "approve", s.approverToken(c, "approver"))
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	dbReq, err := app.GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, app.ApprovalExpired)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	_, ok := dbApp.Env["DATABASE_HOST"]
+	c.Assert(ok, check.Equals, false)
+}
+
+func (s *S) TestApprovalRequestApproveWaitsForRequiredApprovals(c *check.C) {
+	a := s.createProtectedApp(c, 2)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_HOST&Envs.0.Value=localhost")
+	recorder := s.reviewApprovalRequest(a, req.ID.Hex(), "approve", s.approverToken(c, "approver1"))
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result app.ApprovalRequest
+	err := json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Status, check.Equals, app.ApprovalPending)
+	c.Assert(result.Approvals, check.HasLen, 1)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	_, ok := dbApp.Env["DATABASE_HOST"]
+	c.Assert(ok, check.Equals, false)
+	recorder = s.reviewApprovalRequest(a, req.ID.Hex(), "approve", s.approverToken(c, "approver2"))
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbApp, err = app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["DATABASE_HOST"].Value, check.Equals, "localhost")
+}
+
+func (s *S) TestApprovalRequestReject(c *check.C) {
+	a := s.createProtectedApp(c, 1)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_HOST&Envs.0.Value=localhost")
+	token := s.approverToken(c, "approver")
+	recorder := s.reviewApprovalRequest(a, req.ID.Hex(), "reject", token)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbReq, err := app.GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, app.ApprovalRejected)
+	c.Assert(dbReq.Rejection.Comment, check.Equals, "reviewed")
+	recorder = s.reviewApprovalRequest(a, req.ID.Hex(), "approve", s.approverToken(c, "approver2"))
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	_, ok := dbApp.Env["DATABASE_HOST"]
+	c.Assert(ok, check.Equals, false)
+}
+
+func (s *S) TestApprovalRequestSelfReview(c *check.C) {
+	a := s.createProtectedApp(c, 1)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_HOST&Envs.0.Value=localhost")
+	recorder := s.reviewApprovalRequest(a, req.ID.Hex(), "approve", s.token)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Body.String(), check.Equals, app.ErrApprovalSelfReview.Error()+"\n")
+}
+
+func (s *S) TestApprovalRequestReviewWithoutPermission(c *check.C) {
+	a := s.createProtectedApp(c, 1)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_HOST&Envs.0.Value=localhost")
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "reader", permission.Permission{
+		Scheme:  permission.PermAppApprovalRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	recorder := s.reviewApprovalRequest(a, req.ID.Hex(), "approve", token)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestApprovalRequestList(c *check.C) {
+	a := s.createProtectedApp(c, 1)
+	req := s.queueSetEnv(c, a, "Envs.0.Name=DATABASE_HOST&Envs.0.Value=localhost")
+	request, err := http.NewRequest("GET", fmt.Sprintf("/apps/%s/approvals?status=pending", a.Name), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var reqs []app.ApprovalRequest
+	err = json.NewDecoder(recorder.Body).Decode(&reqs)
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.HasLen, 1)
+	c.Assert(reqs[0].ID, check.Equals, req.ID)
+	request, err = http.NewRequest("GET", fmt.Sprintf("/apps/%s/approvals?status=executed", a.Name), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestAppProtectionUpdate(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("PUT", "/apps/myapp/protection", strings.NewReader("requiredApprovals=2"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.RequiredApprovals, check.Equals, 2)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.update.protection",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":app", "value": a.Name},
+			{"name": "requiredApprovals", "value": "2"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestAppProtectionLoweringRequiresApproval(c *check.C) {
+	a := s.createProtectedApp(c, 1)
+	request, err := http.NewRequest("PUT", fmt.Sprintf("/apps/%s/protection", a.Name), strings.NewReader("requiredApprovals=0"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusAccepted)
+	var req app.ApprovalRequest
+	err = json.NewDecoder(recorder.Body).Decode(&req)
+	c.Assert(err, check.IsNil)
+	c.Assert(req.Operation, check.Equals, "protection")
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.RequiredApprovals, check.Equals, 1)
+	recorder = s.reviewApprovalRequest(a, req.ID.Hex(), "approve", s.approverToken(c, "approver"))
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbApp, err = app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.RequiredApprovals, check.Equals, 0)
+}
+
+func (s *S) TestSwapProtectedAppQueued(c *check.C) {
+	a := s.createProtectedApp(c, 1)
+	other := app.App{Name: "other-app", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &other, s.user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(fmt.Sprintf("app1=%s&app2=%s&cnameOnly=false", other.Name, a.Name))
+	request, err := http.NewRequest("POST", "/swap", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusAccepted)
+	var req app.ApprovalRequest
+	err = json.NewDecoder(recorder.Body).Decode(&req)
+	c.Assert(err, check.IsNil)
+	c.Assert(req.Operation, check.Equals, "swap")
+	c.Assert(req.Apps, check.DeepEquals, []string{other.Name, a.Name})
+	reqs, err := app.ListApprovalRequests(a.Name, app.ApprovalPending)
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.HasLen, 1)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Kind:   "app.update.swap",
+	}, check.Not(eventtest.HasEvent))
+}
@@ -133,6 +133,10 @@ func login(w http.ResponseWriter, r *http.Request) (err error) {
 	if err != nil {
 		return handleAuthError(err)
 	}
+	if u, userErr := token.User(); userErr == nil && u.Disabled {
+		app.AuthScheme.Logout(ctx, token.GetValue())
+		return &errors.HTTP{Code: http.StatusForbidden, Message: authTypes.ErrUserDisabled.Error()}
+	}
 	return json.NewEncoder(w).Encode(map[string]string{"token": token.GetValue()})
 }
 
@@ -699,6 +703,10 @@ func removeUser(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	if err != nil {
 		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
 	}
+	return deleteUser(ctx, u)
+}
+
+func deleteUser(ctx context.Context, u *auth.User) error {
 	appNames, err := deployableApps(u, make(map[string]*permission.Role))
 	if err != nil {
 		return err
@@ -882,7 +890,7 @@ func expandRoleData(perms []permission.Permission, userRole authTypes.RoleInstan
 	}
 	rolePerms := make([]rolePermissionData, len(permissions))
 	for i, p := range permissions {
-		if perms != nil && allPermsMatch && !permission.CheckFromPermList(perms, p.Scheme, p.Context) {
+		if perms != nil && allPermsMatch && !p.Deny && !permission.CheckFromPermList(perms, p.Scheme, p.Context) {
 			allPermsMatch = false
 			break
 		}
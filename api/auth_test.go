@@ -332,6 +332,26 @@ func (s *AuthSuite) TestLoginPasswordDoesNotMatch(c *check.C) {
 	c.Assert(recorder.Body.String(), check.Matches, "^Authentication failed, wrong password.\n$")
 }
 
+func (s *AuthSuite) TestLoginDisabledUser(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	u.Disabled = true
+	err = u.Update()
+	c.Assert(err, check.IsNil)
+	b := strings.NewReader("password=123456")
+	request, err := http.NewRequest(http.MethodPost, "/users/nobody@globo.com/tokens", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Body.String(), check.Equals, "user is disabled\n")
+	n, err := s.conn.Tokens().Find(bson.M{"useremail": "nobody@globo.com"}).Count()
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 0)
+}
+
 func (s *AuthSuite) TestLoginEmailIsNotValid(c *check.C) {
 	b := strings.NewReader("password=123456")
 	request, err := http.NewRequest(http.MethodPost, "/users/nobody/tokens", b)
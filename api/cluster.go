@@ -6,11 +6,13 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
@@ -19,6 +21,7 @@ import (
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/cluster"
 	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/router/rebuild"
 	"github.com/tsuru/tsuru/servicemanager"
 	provTypes "github.com/tsuru/tsuru/types/provision"
 )
@@ -274,6 +277,88 @@ func deleteCluster(w http.ResponseWriter, r *http.Request, t auth.Token) (err er
 	return nil
 }
 
+// title: failover provisioner cluster
+// path: /provisioner/clusters/{name}/failover
+// method: POST
+// produce: application/x-json-stream
+// responses:
+//   200: Ok
+//   400: Cluster without placement
+//   401: Unauthorized
+//   404: Cluster not found
+func failoverCluster(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	allowed := permission.Check(t, permission.PermClusterUpdateFailover)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	clusterName := r.URL.Query().Get(":name")
+	provCluster, err := servicemanager.Cluster.FindByName(ctx, clusterName)
+	if err != nil {
+		if err == provTypes.ErrClusterNotFound {
+			return &tsuruErrors.HTTP{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+		}
+		return err
+	}
+	if provCluster.Placement == nil {
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "cluster must have a placement to fail over",
+		}
+	}
+	prov, err := provision.Get(provCluster.Provisioner)
+	if err != nil {
+		return err
+	}
+	multiProv, ok := prov.(provision.MultiClusterProvisioner)
+	if !ok {
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "provisioner does not support multiple clusters per pool",
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeCluster, Value: clusterName},
+		Kind:       permission.PermClusterUpdateFailover,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermClusterReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	if !provCluster.Placement.Drained {
+		provCluster.Placement.Drained = true
+		provCluster.Writer = evt
+		err = servicemanager.Cluster.Update(ctx, *provCluster)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	apps, err := app.List(ctx, &app.Filter{Pools: provCluster.Pools})
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		fmt.Fprintf(evt, "---- Failing over app %q ----\n", apps[i].Name)
+		err = multiProv.Failover(ctx, &apps[i], clusterName, evt)
+		if err != nil {
+			return err
+		}
+		rebuild.RoutesRebuildOrEnqueueWithProgress(apps[i].Name, evt)
+	}
+	return nil
+}
+
 type provisionerInfo struct {
 	Name        string                    `json:"name"`
 	ClusterHelp provTypes.ClusterHelpInfo `json:"cluster_help"`
@@ -312,6 +312,40 @@ func (s *S) TestDeleteCluster(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
 }
 
+func (s *S) TestFailoverClusterNotFound(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return nil, provision.ErrClusterNotFound
+	}
+	request, err := http.NewRequest(http.MethodPost, "/1.10/provisioner/clusters/c1/failover", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound, check.Commentf("body: %q", recorder.Body.String()))
+}
+
+func (s *S) TestFailoverClusterWithoutPlacement(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return &provision.Cluster{
+			Name:        "c1",
+			Addresses:   []string{"addr1"},
+			Provisioner: "fake",
+			Pools:       []string{"pool1"},
+		}, nil
+	}
+	s.mockService.Cluster.OnUpdate = func(clust provision.Cluster) error {
+		c.Fatal("cluster must not be updated")
+		return nil
+	}
+	request, err := http.NewRequest(http.MethodPost, "/1.10/provisioner/clusters/c1/failover", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(recorder.Body.String(), check.Equals, "cluster must have a placement to fail over\n")
+}
+
 func (s *S) TestListProvisioners(c *check.C) {
 	request, err := http.NewRequest(http.MethodGet, "/1.7/provisioner", nil)
 	c.Assert(err, check.IsNil)
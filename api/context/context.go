@@ -28,6 +28,7 @@ const (
 	preventUnlockKey
 	appContextKey
 	reqBodyKey
+	approvalReplayKey
 )
 
 func Clear(r *http.Request) {
@@ -145,6 +146,21 @@ func IsPreventUnlock(r *http.Request) bool {
 	return false
 }
 
+func SetApprovalReplay(r *http.Request) {
+	newReq := r.WithContext(context.WithValue(r.Context(), approvalReplayKey, true))
+	*r = *newReq
+}
+
+func IsApprovalReplay(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	if v, ok := r.Context().Value(approvalReplayKey).(bool); ok {
+		return v
+	}
+	return false
+}
+
 func SetRequestID(r *http.Request, requestIDHeader, requestID string) {
 	newReq := r.WithContext(context.WithValue(r.Context(), reqIDHeaderCtxKey(requestIDHeader), requestID))
 	*r = *newReq
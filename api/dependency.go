@@ -0,0 +1,149 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+// title: list app dependencies
+// path: /apps/{app}/dependencies
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: App not found
+func listAppDependencies(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppRead, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	info, err := a.DependenciesInfo()
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(info)
+}
+
+// title: add app dependency
+// path: /apps/{app}/dependencies
+// method: POST
+// consume: application/json
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func addAppDependency(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateDependencyAdd, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	var deps appTypes.AppDependencies
+	err = ParseInput(r, &deps)
+	if err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("unable to parse dependencies: %v", err),
+		}
+	}
+	if deps.Empty() {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "at least one app or cidr is required"}
+	}
+	// Depending on an app opens its units to the units of this app, so the
+	// user must be allowed to change the dependencies of the apps being
+	// added as well.
+	for _, name := range deps.Apps {
+		dep, err := app.GetByName(r.Context(), name)
+		if err != nil {
+			return dependencyError(err)
+		}
+		if !permission.Check(t, permission.PermAppUpdateDependencyAdd, contextsForApp(dep)...) {
+			return permission.ErrUnauthorized
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateDependencyAdd,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return dependencyError(a.AddDependency(deps))
+}
+
+// title: remove app dependency
+// path: /apps/{app}/dependencies
+// method: DELETE
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App or dependency not found
+func removeAppDependency(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateDependencyRemove, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	deps := appTypes.AppDependencies{
+		Apps:  r.URL.Query()["apps"],
+		CIDRs: r.URL.Query()["cidrs"],
+	}
+	if deps.Empty() {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "at least one app or cidr is required"}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateDependencyRemove,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return dependencyError(a.RemoveDependency(deps))
+}
+
+func dependencyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if err == appTypes.ErrDependencyNotFound || err == appTypes.ErrAppNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
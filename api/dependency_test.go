@@ -0,0 +1,127 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/permission"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestAddAppDependency(c *check.C) {
+	a := app.App{Name: "frontend", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	dep := app.App{Name: "backend", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &dep, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateDependencyAdd,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermAppUpdateDependencyAdd,
+		Context: permission.Context(permTypes.CtxApp, dep.Name),
+	})
+	b := strings.NewReader(`{"apps": ["backend"], "cidrs": ["10.0.0.0/8"]}`)
+	request, err := http.NewRequest("POST", "/apps/frontend/dependencies", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Dependencies, check.DeepEquals, appTypes.AppDependencies{
+		Apps:  []string{"backend"},
+		CIDRs: []string{"10.0.0.0/8"},
+	})
+	request, err = http.NewRequest("GET", "/apps/frontend/dependencies", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var info app.DependenciesInfo
+	err = json.Unmarshal(recorder.Body.Bytes(), &info)
+	c.Assert(err, check.IsNil)
+	c.Assert(info.AppDependencies, check.DeepEquals, dbApp.Dependencies)
+}
+
+func (s *S) TestAddAppDependencyWithoutAccessToDependency(c *check.C) {
+	a := app.App{Name: "frontend", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	dep := app.App{Name: "backend", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &dep, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateDependencyAdd,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader(`{"apps": ["backend"]}`)
+	request, err := http.NewRequest("POST", "/apps/frontend/dependencies", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestAddAppDependencyWithReadAccessToDependency(c *check.C) {
+	a := app.App{Name: "frontend", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	dep := app.App{Name: "backend", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &dep, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateDependencyAdd,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, dep.Name),
+	})
+	b := strings.NewReader(`{"apps": ["backend"]}`)
+	request, err := http.NewRequest("POST", "/apps/frontend/dependencies", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRemoveAppDependency(c *check.C) {
+	a := app.App{Name: "frontend", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.AddDependency(appTypes.AppDependencies{CIDRs: []string{"10.0.0.0/8", "172.16.0.0/12"}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("DELETE", "/apps/frontend/dependencies?cidrs=10.0.0.0/8", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Dependencies.CIDRs, check.DeepEquals, []string{"172.16.0.0/12"})
+	request, err = http.NewRequest("DELETE", "/apps/frontend/dependencies?cidrs=10.0.0.0/8", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
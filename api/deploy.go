@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tsuru/tsuru/app"
@@ -18,6 +19,7 @@ import (
 	tsuruIo "github.com/tsuru/tsuru/io"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/repository"
+	permTypes "github.com/tsuru/tsuru/types/permission"
 )
 
 const eventIDHeader = "X-Tsuru-Eventid"
@@ -28,6 +30,7 @@ const eventIDHeader = "X-Tsuru-Eventid"
 // consume: application/x-www-form-urlencoded
 // responses:
 //   200: OK
+//   202: Waiting for approval
 //   400: Invalid data
 //   403: Forbidden
 //   404: Not found
@@ -97,6 +100,13 @@ func deploy(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 			return &tsuruErrors.HTTP{Code: http.StatusForbidden, Message: "User does not have permission to do this action in this app"}
 		}
 	}
+	opts.FreezeOverride, err = deployFreezeOverride(r, t, instance)
+	if err != nil {
+		return err
+	}
+	if queued, err := queueForApproval(w, r, t, "deploy", nil, instance); queued || err != nil {
+		return err
+	}
 	var imageID string
 	evt, err := event.New(&event.Opts{
 		Target:        appTarget(appName),
@@ -126,6 +136,20 @@ func deploy(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	return err
 }
 
+// deployFreezeOverride returns the justification for deploying inside a
+// freeze window, only allowed to users with the deploy-freeze.override
+// permission in the app pool.
+func deployFreezeOverride(r *http.Request, t auth.Token, a *app.App) (string, error) {
+	justification := strings.TrimSpace(InputValue(r, "freeze-override"))
+	if justification == "" {
+		return "", nil
+	}
+	if !permission.Check(t, permission.PermDeployFreezeOverride, permission.Context(permTypes.CtxPool, a.Pool)) {
+		return "", &tsuruErrors.HTTP{Code: http.StatusForbidden, Message: "User does not have permission to override deploy freezes"}
+	}
+	return justification, nil
+}
+
 func permSchemeForDeploy(opts app.DeployOptions) *permission.PermissionScheme {
 	switch opts.GetKind() {
 	case app.DeployGit:
@@ -187,6 +211,7 @@ func diffDeploy(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 // produce: application/x-json-stream
 // responses:
 //   200: OK
+//   202: Waiting for approval
 //   400: Invalid data
 //   403: Forbidden
 //   404: Not found
@@ -232,6 +257,13 @@ func deployRollback(w http.ResponseWriter, r *http.Request, t auth.Token) error
 	if !canRollback {
 		return &tsuruErrors.HTTP{Code: http.StatusForbidden, Message: permission.ErrUnauthorized.Error()}
 	}
+	opts.FreezeOverride, err = deployFreezeOverride(r, t, instance)
+	if err != nil {
+		return err
+	}
+	if queued, err := queueForApproval(w, r, t, "rollback", nil, instance); queued || err != nil {
+		return err
+	}
 	var imageID string
 	evt, err := event.New(&event.Opts{
 		Target:        appTarget(appName),
@@ -326,6 +358,7 @@ func deployInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 // produce: application/x-json-stream
 // responses:
 //   200: OK
+//   202: Waiting for approval
 //   400: Invalid data
 //   403: Forbidden
 //   404: Not found
@@ -360,6 +393,13 @@ func deployRebuild(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if !canDeploy {
 		return &tsuruErrors.HTTP{Code: http.StatusForbidden, Message: permission.ErrUnauthorized.Error()}
 	}
+	opts.FreezeOverride, err = deployFreezeOverride(r, t, instance)
+	if err != nil {
+		return err
+	}
+	if queued, err := queueForApproval(w, r, t, "rebuild", nil, instance); queued || err != nil {
+		return err
+	}
 	var imageID string
 	evt, err := event.New(&event.Opts{
 		Target:        appTarget(appName),
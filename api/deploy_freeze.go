@@ -0,0 +1,167 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// title: deploy freeze window list
+// path: /deploy-freezes
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   401: Unauthorized
+func deployFreezeList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if len(permission.ContextsForPermission(t, permission.PermDeployFreezeRead)) == 0 {
+		return permission.ErrUnauthorized
+	}
+	allWindows, err := event.ListFreezeWindows()
+	if err != nil {
+		return err
+	}
+	var windows []event.FreezeWindow
+	for _, window := range allWindows {
+		if permission.Check(t, permission.PermDeployFreezeRead, freezeWindowContexts(&window)...) {
+			windows = append(windows, window)
+		}
+	}
+	if len(windows) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(windows)
+}
+
+// title: add deploy freeze window
+// path: /deploy-freezes
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   201: Created
+//   400: Invalid data
+//   401: Unauthorized
+//   409: Freeze window already exists
+func deployFreezeAdd(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	window := event.FreezeWindow{
+		Name:     InputValue(r, "name"),
+		Schedule: InputValue(r, "schedule"),
+		Timezone: InputValue(r, "timezone"),
+		Reason:   InputValue(r, "reason"),
+		Owner:    t.GetUserName(),
+	}
+	window.Pools, _ = InputValues(r, "pool")
+	window.Tags, _ = InputValues(r, "tag")
+	if !canManageFreezeWindow(t, permission.PermDeployFreezeCreate, &window) {
+		return permission.ErrUnauthorized
+	}
+	window.Duration, err = time.ParseDuration(InputValue(r, "duration"))
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid duration: %v", err)}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeDeployFreeze, Value: window.Name},
+		Kind:       permission.PermDeployFreezeCreate,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermDeployFreezeReadEvents, freezeWindowContexts(&window)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = event.AddFreezeWindow(&window)
+	if err == event.ErrFreezeWindowAlreadyExists {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// title: remove deploy freeze window
+// path: /deploy-freezes/{uuid}
+// method: DELETE
+// responses:
+//   200: OK
+//   400: Invalid uuid
+//   401: Unauthorized
+//   404: Freeze window not found
+func deployFreezeRemove(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if len(permission.ContextsForPermission(t, permission.PermDeployFreezeDelete)) == 0 {
+		return permission.ErrUnauthorized
+	}
+	uuid := r.URL.Query().Get(":uuid")
+	if !bson.IsObjectIdHex(uuid) {
+		msg := fmt.Sprintf("uuid parameter is not ObjectId: %s", uuid)
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: msg}
+	}
+	objID := bson.ObjectIdHex(uuid)
+	window, err := event.GetFreezeWindow(objID)
+	if err == event.ErrFreezeWindowNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	if !canManageFreezeWindow(t, permission.PermDeployFreezeDelete, window) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target: event.Target{Type: event.TargetTypeDeployFreeze, Value: objID.Hex()},
+		Kind:   permission.PermDeployFreezeDelete,
+		Owner:  t,
+		CustomData: []map[string]interface{}{
+			{"name": "ID", "value": objID.Hex()},
+		},
+		Allowed: event.Allowed(permission.PermDeployFreezeReadEvents, freezeWindowContexts(window)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = event.RemoveFreezeWindow(objID)
+	if err == event.ErrFreezeWindowNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// freezeWindowContexts returns the pool contexts of the pools covered by the
+// window. Windows without pools cover every pool, so they're only handled
+// with global permissions.
+func freezeWindowContexts(window *event.FreezeWindow) []permTypes.PermissionContext {
+	return permission.Contexts(permTypes.CtxPool, window.Pools)
+}
+
+// canManageFreezeWindow returns whether the token is allowed to use scheme
+// on every pool covered by the window.
+func canManageFreezeWindow(t auth.Token, scheme *permission.PermissionScheme, window *event.FreezeWindow) bool {
+	contexts := freezeWindowContexts(window)
+	if len(contexts) == 0 {
+		return permission.Check(t, scheme)
+	}
+	for _, ctx := range contexts {
+		if !permission.Check(t, scheme, ctx) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,283 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/builder"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	"github.com/tsuru/tsuru/provision"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *EventSuite) TestDeployFreezeList(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermDeployFreezeRead,
+		Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+	})
+	err := event.AddFreezeWindow(&event.FreezeWindow{Name: "friday", Schedule: "0 18 * * 5", Duration: 15 * time.Hour, Reason: "weekend"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/deploy-freezes", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var windows []event.FreezeWindow
+	err = json.NewDecoder(recorder.Body).Decode(&windows)
+	c.Assert(err, check.IsNil)
+	c.Assert(windows, check.HasLen, 1)
+	c.Assert(windows[0].Name, check.Equals, "friday")
+	c.Assert(windows[0].Duration, check.Equals, 15*time.Hour)
+}
+
+func (s *EventSuite) TestDeployFreezeListEmpty(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermDeployFreezeRead,
+		Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+	})
+	request, err := http.NewRequest("GET", "/deploy-freezes", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *EventSuite) TestDeployFreezeAdd(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermDeployFreezeCreate,
+		Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+	})
+	body := strings.NewReader("name=friday&schedule=0+18+*+*+5&duration=15h&timezone=America/Sao_Paulo&pool=prod&pool=staging&tag=critical&reason=weekend")
+	request, err := http.NewRequest("POST", "/deploy-freezes", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	windows, err := event.ListFreezeWindows()
+	c.Assert(err, check.IsNil)
+	c.Assert(windows, check.HasLen, 1)
+	c.Assert(windows[0].Schedule, check.Equals, "0 18 * * 5")
+	c.Assert(windows[0].Duration, check.Equals, 15*time.Hour)
+	c.Assert(windows[0].Timezone, check.Equals, "America/Sao_Paulo")
+	c.Assert(windows[0].Pools, check.DeepEquals, []string{"prod", "staging"})
+	c.Assert(windows[0].Tags, check.DeepEquals, []string{"critical"})
+	c.Assert(windows[0].Owner, check.Equals, token.GetUserName())
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeDeployFreeze, Value: "friday"},
+		Owner:  token.GetUserName(),
+		Kind:   "deploy-freeze.create",
+	}, eventtest.HasEvent)
+}
+
+func (s *EventSuite) TestDeployFreezeAddInvalid(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermDeployFreezeCreate,
+		Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+	})
+	tests := []struct {
+		body    string
+		code    int
+		message string
+	}{
+		{"name=friday&schedule=@daily&duration=1d&reason=r", http.StatusBadRequest, "invalid duration: .*"},
+		{"name=friday&schedule=0+25+*+*+*&duration=1h&reason=r", http.StatusBadRequest, "invalid schedule .*"},
+		{"name=friday&schedule=@daily&duration=1h", http.StatusBadRequest, "reason is required"},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("POST", "/deploy-freezes", strings.NewReader(tt.body))
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer "+token.GetValue())
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		server := RunServer(true)
+		server.ServeHTTP(recorder, request)
+		c.Check(recorder.Code, check.Equals, tt.code, check.Commentf(tt.body))
+		c.Check(recorder.Body.String(), check.Matches, tt.message+"\n", check.Commentf(tt.body))
+	}
+}
+
+func (s *EventSuite) TestDeployFreezeAddWithoutPermission(c *check.C) {
+	body := strings.NewReader("name=friday&schedule=@daily&duration=1h&reason=r")
+	request, err := http.NewRequest("POST", "/deploy-freezes", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *EventSuite) TestDeployFreezeRemove(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermDeployFreezeDelete,
+		Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+	})
+	window := &event.FreezeWindow{Name: "friday", Schedule: "0 18 * * 5", Duration: time.Hour, Reason: "weekend"}
+	err := event.AddFreezeWindow(window)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("DELETE", "/deploy-freezes/"+window.ID.Hex(), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	windows, err := event.ListFreezeWindows()
+	c.Assert(err, check.IsNil)
+	c.Assert(windows, check.HasLen, 0)
+	request, err = http.NewRequest("DELETE", "/deploy-freezes/"+bson.NewObjectId().Hex(), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *EventSuite) TestDeployFreezePoolScoped(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermDeployFreeze,
+		Context: permission.Context(permTypes.CtxPool, "prod"),
+	})
+	server := RunServer(true)
+	tests := []struct {
+		body string
+		code int
+	}{
+		{"name=prod&schedule=@daily&duration=1h&reason=r&pool=prod", http.StatusCreated},
+		{"name=all&schedule=@daily&duration=1h&reason=r", http.StatusForbidden},
+		{"name=both&schedule=@daily&duration=1h&reason=r&pool=prod&pool=dev", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("POST", "/deploy-freezes", strings.NewReader(tt.body))
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer "+token.GetValue())
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		c.Check(recorder.Code, check.Equals, tt.code, check.Commentf(tt.body))
+	}
+	dev := &event.FreezeWindow{Name: "dev", Schedule: "@daily", Duration: time.Hour, Reason: "r", Pools: []string{"dev"}}
+	err := event.AddFreezeWindow(dev)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/deploy-freezes", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var windows []event.FreezeWindow
+	err = json.NewDecoder(recorder.Body).Decode(&windows)
+	c.Assert(err, check.IsNil)
+	c.Assert(windows, check.HasLen, 1)
+	c.Assert(windows[0].Name, check.Equals, "prod")
+	request, err = http.NewRequest("DELETE", "/deploy-freezes/"+dev.ID.Hex(), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	request, err = http.NewRequest("DELETE", "/deploy-freezes/"+windows[0].ID.Hex(), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+}
+
+func (s *DeploySuite) TestDeployFrozen(c *check.C) {
+	err := event.AddFreezeWindow(&event.FreezeWindow{Name: "incident", Schedule: "* * * * *", Duration: time.Hour, Reason: "ongoing incident"})
+	c.Assert(err, check.IsNil)
+	a := app.App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	url := fmt.Sprintf("/apps/%s/deploy", a.Name)
+	request, err := http.NewRequest("POST", url, strings.NewReader("image=127.0.0.1:5000/tsuru/otherapp"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*deploys are frozen by freeze window "incident" \(ongoing incident\).*`)
+	c.Assert(eventtest.EventDesc{
+		Target:       appTarget(a.Name),
+		Kind:         "app.deploy",
+		ErrorMatches: `deploys are frozen.*`,
+	}, eventtest.HasEvent)
+}
+
+func (s *DeploySuite) TestDeployFrozenOverrideRequiresPermission(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	url := fmt.Sprintf("/apps/%s/deploy", a.Name)
+	request, err := http.NewRequest("POST", url, strings.NewReader("image=127.0.0.1:5000/tsuru/otherapp&freeze-override=hotfix"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Body.String(), check.Equals, "User does not have permission to override deploy freezes\n")
+}
+
+func (s *DeploySuite) TestDeployFrozenOverride(c *check.C) {
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		return newAppVersion(c, app), nil
+	}
+	err := event.AddFreezeWindow(&event.FreezeWindow{Name: "incident", Schedule: "* * * * *", Duration: time.Hour, Reason: "ongoing incident"})
+	c.Assert(err, check.IsNil)
+	a := app.App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppDeploy,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermDeployFreezeOverride,
+		Context: permission.Context(permTypes.CtxPool, a.Pool),
+	})
+	url := fmt.Sprintf("/apps/%s/deploy", a.Name)
+	request, err := http.NewRequest("POST", url, strings.NewReader("image=127.0.0.1:5000/tsuru/otherapp&freeze-override=hotfix+for+the+incident"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Matches, "(?s).*Overriding freeze window \"incident\".*Builder deploy called\nOK\n")
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  token.GetUserName(),
+		Kind:   "app.deploy",
+		StartCustomData: map[string]interface{}{
+			"freezeoverride": "hotfix for the incident",
+		},
+	}, eventtest.HasEvent)
+}
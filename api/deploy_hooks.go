@@ -0,0 +1,93 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+// title: list app deploy hooks
+// path: /apps/{app}/deploy-hooks
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: App not found
+func listAppDeployHooks(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppRead, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	info, err := a.DeployHooksInfo()
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(info)
+}
+
+// title: set app deploy hooks
+// path: /apps/{app}/deploy-hooks
+// method: PUT
+// consume: application/json
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func setAppDeployHooks(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateDeployHooks, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	var data struct {
+		Hooks []appTypes.DeployHook `json:"hooks"`
+	}
+	err = ParseInput(r, &data)
+	if err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("unable to parse deploy hooks: %v", err),
+		}
+	}
+	// Secrets are kept out of the event, only the hooks definitions are
+	// recorded.
+	hooks := make([]appTypes.DeployHook, len(data.Hooks))
+	for i, hook := range data.Hooks {
+		hook.Secret = ""
+		hooks[i] = hook
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateDeployHooks,
+		Owner:      t,
+		CustomData: map[string]interface{}{"hooks": hooks},
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return a.SetDeployHooks(data.Hooks)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/permission"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestSetAppDeployHooks(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateDeployHooks,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader(`{"hooks": [{"name": "smoke", "url": "http://checks.example.com/smoke", "stage": "post-deploy", "timeout": 60, "secret": "s3cr3t"}]}`)
+	request, err := http.NewRequest("PUT", "/apps/myapp/deploy-hooks", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.DeployHooks, check.DeepEquals, []appTypes.DeployHook{
+		{Name: "smoke", URL: "http://checks.example.com/smoke", Stage: appTypes.DeployHookPostDeploy, Timeout: 60, Secret: "s3cr3t"},
+	})
+	request, err = http.NewRequest("GET", "/apps/myapp/deploy-hooks", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Not(check.Matches), `(?s).*s3cr3t.*`)
+	var info app.DeployHooksInfo
+	err = json.Unmarshal(recorder.Body.Bytes(), &info)
+	c.Assert(err, check.IsNil)
+	c.Assert(info.App, check.DeepEquals, []appTypes.DeployHook{
+		{Name: "smoke", URL: "http://checks.example.com/smoke", Stage: appTypes.DeployHookPostDeploy, Timeout: 60},
+	})
+}
+
+func (s *S) TestSetAppDeployHooksInvalid(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	b := strings.NewReader(`{"hooks": [{"name": "smoke", "url": "http://checks.example.com/smoke", "stage": "during-deploy"}]}`)
+	request, err := http.NewRequest("PUT", "/apps/myapp/deploy-hooks", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid stage during-deploy for deploy hook smoke\n")
+}
+
+func (s *S) TestSetAppDeployHooksUnauthorized(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader(`{"hooks": []}`)
+	request, err := http.NewRequest("PUT", "/apps/myapp/deploy-hooks", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+)
+
+// title: list app jobs
+// path: /apps/{app}/jobs
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   401: Unauthorized
+//   404: App not found
+func listAppJobs(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppReadJob, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	jobs, err := a.Jobs()
+	if err != nil {
+		return jobError(err)
+	}
+	if len(jobs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(jobs)
+}
+
+// title: set app job
+// path: /apps/{app}/jobs
+// method: POST
+// consume: application/json
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func setAppJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateJobSet, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	var spec provision.JobSpec
+	err = ParseInput(r, &spec)
+	if err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("unable to parse job spec: %v", err),
+		}
+	}
+	err = spec.Validate()
+	if err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("unable to validate job spec: %v", err),
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateJobSet,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return jobError(a.SetJob(spec))
+}
+
+// title: remove app job
+// path: /apps/{app}/jobs/{job}
+// method: DELETE
+// responses:
+//   200: Ok
+//   401: Unauthorized
+//   404: App or job not found
+func removeAppJob(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	jobName := r.URL.Query().Get(":job")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateJobRemove, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateJobRemove,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return jobError(a.RemoveJob(jobName))
+}
+
+// title: list app job runs
+// path: /apps/{app}/jobs/{job}/runs
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   401: Unauthorized
+//   404: App or job not found
+func listAppJobRuns(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	jobName := r.URL.Query().Get(":job")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppReadJob, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	runs, err := a.JobRuns(jobName)
+	if err != nil {
+		return jobError(err)
+	}
+	if len(runs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(runs)
+}
+
+func jobError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if err == provision.ErrJobNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if _, ok := err.(provision.ProvisionerNotSupported); ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) registerJobProvisioner() (*provisiontest.JobProvisioner, func()) {
+	jobProv := &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}
+	oldProvisioner := provision.DefaultProvisioner
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return jobProv, nil
+	})
+	return jobProv, func() {
+		provision.DefaultProvisioner = oldProvisioner
+		provision.Unregister("jobProv")
+	}
+}
+
+func (s *S) TestSetAppJob(c *check.C) {
+	jobProv, rollback := s.registerJobProvisioner()
+	defer rollback()
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateJobSet,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader(`{"name": "cleanup", "schedule": "@daily", "command": "./cleanup", "timeout": 60}`)
+	request, err := http.NewRequest("POST", "/apps/myapp/jobs", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	jobs, err := jobProv.ListJobs(context.TODO(), &a)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.DeepEquals, []provision.JobSpec{
+		{Name: "cleanup", Schedule: "@daily", Command: "./cleanup", ConcurrencyPolicy: provision.JobConcurrencyAllow, Timeout: 60},
+	})
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget("myapp"),
+		Owner:  token.GetUserName(),
+		Kind:   "app.update.job.set",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":app", "value": a.Name},
+			{"name": "name", "value": "cleanup"},
+			{"name": "schedule", "value": "@daily"},
+			{"name": "command", "value": "./cleanup"},
+			{"name": "timeout", "value": "60"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSetAppJobInvalidSpec(c *check.C) {
+	_, rollback := s.registerJobProvisioner()
+	defer rollback()
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	b := strings.NewReader(`{"name": "cleanup", "schedule": "* * *", "command": "./cleanup"}`)
+	request, err := http.NewRequest("POST", "/apps/myapp/jobs", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Matches, "unable to validate job spec: invalid job schedule.*\n")
+}
+
+func (s *S) TestSetAppJobNotSupported(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	b := strings.NewReader(`{"name": "cleanup", "schedule": "@daily", "command": "./cleanup"}`)
+	request, err := http.NewRequest("POST", "/apps/myapp/jobs", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Matches, ".*does not support scheduled jobs\n")
+}
+
+func (s *S) TestListAppJobs(c *check.C) {
+	_, rollback := s.registerJobProvisioner()
+	defer rollback()
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetJob(provision.JobSpec{Name: "cleanup", Schedule: "@daily", Command: "./cleanup"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadJob,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	request, err := http.NewRequest("GET", "/apps/myapp/jobs", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var jobs []provision.JobSpec
+	err = json.Unmarshal(recorder.Body.Bytes(), &jobs)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.DeepEquals, []provision.JobSpec{
+		{Name: "cleanup", Schedule: "@daily", Command: "./cleanup", ConcurrencyPolicy: provision.JobConcurrencyAllow},
+	})
+}
+
+func (s *S) TestListAppJobsEmpty(c *check.C) {
+	_, rollback := s.registerJobProvisioner()
+	defer rollback()
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/apps/myapp/jobs", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestRemoveAppJob(c *check.C) {
+	jobProv, rollback := s.registerJobProvisioner()
+	defer rollback()
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetJob(provision.JobSpec{Name: "cleanup", Schedule: "@daily", Command: "./cleanup"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateJobRemove,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	request, err := http.NewRequest("DELETE", "/apps/myapp/jobs/cleanup", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	jobs, err := jobProv.ListJobs(context.TODO(), &a)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 0)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget("myapp"),
+		Owner:  token.GetUserName(),
+		Kind:   "app.update.job.remove",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":app", "value": a.Name},
+			{"name": ":job", "value": "cleanup"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRemoveAppJobNotFound(c *check.C) {
+	_, rollback := s.registerJobProvisioner()
+	defer rollback()
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("DELETE", "/apps/myapp/jobs/cleanup", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, "job not found\n")
+}
+
+func (s *S) TestListAppJobRuns(c *check.C) {
+	jobProv, rollback := s.registerJobProvisioner()
+	defer rollback()
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetJob(provision.JobSpec{Name: "cleanup", Schedule: "@daily", Command: "./cleanup"})
+	c.Assert(err, check.IsNil)
+	start := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	exitCode := int32(1)
+	run := provision.JobRun{
+		Name:      "myapp-job-cleanup-1",
+		Job:       "cleanup",
+		Version:   1,
+		Status:    provision.JobRunFailed,
+		ExitCode:  &exitCode,
+		StartTime: start,
+	}
+	jobProv.AddJobRun(&a, run)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadJob,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	request, err := http.NewRequest("GET", "/apps/myapp/jobs/cleanup/runs", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var runs []provision.JobRun
+	err = json.Unmarshal(recorder.Body.Bytes(), &runs)
+	c.Assert(err, check.IsNil)
+	c.Assert(runs, check.DeepEquals, []provision.JobRun{run})
+}
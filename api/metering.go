@@ -0,0 +1,139 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/types/metering"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const defaultMeteringReportPeriod = 30 * 24 * time.Hour
+
+// title: metering report
+// path: /metering/report
+// method: GET
+// produce: application/json, text/csv
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+func meteringReport(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	query := r.URL.Query()
+	opts := metering.ReportOptions{
+		GroupBy: metering.GroupBy(query.Get("groupBy")),
+		UsageFilter: metering.UsageFilter{
+			Teams: query["team"],
+			Pools: query["pool"],
+			Apps:  query["app"],
+		},
+	}
+	if opts.GroupBy != "" && !opts.GroupBy.Valid() {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: metering.ErrInvalidGroupBy.Error()}
+	}
+	var err error
+	opts.End, err = parseMeteringTime(query.Get("end"), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	opts.Start, err = parseMeteringTime(query.Get("start"), opts.End.Add(-defaultMeteringReportPeriod))
+	if err != nil {
+		return err
+	}
+	if !opts.Start.Before(opts.End) {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "start must be before end"}
+	}
+	opts.Teams, err = meteringAllowedTeams(t, opts.Teams)
+	if err != nil {
+		return err
+	}
+	report, err := servicemanager.Metering.Report(r.Context(), opts)
+	if err != nil {
+		return err
+	}
+	if query.Get("format") == "csv" || strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		return writeMeteringCSV(w, report)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(report)
+}
+
+// meteringAllowedTeams restricts the teams in the report to the ones the
+// user is allowed to read. Users without global permission only see their
+// teams, even when no team is requested.
+func meteringAllowedTeams(t auth.Token, requested []string) ([]string, error) {
+	if permission.Check(t, permission.PermMeteringRead) {
+		return requested, nil
+	}
+	ctxs := permission.ContextsForPermission(t, permission.PermMeteringRead, permTypes.CtxTeam)
+	if len(ctxs) == 0 {
+		return nil, permission.ErrUnauthorized
+	}
+	allowed := map[string]struct{}{}
+	for _, c := range ctxs {
+		allowed[c.Value] = struct{}{}
+	}
+	if len(requested) == 0 {
+		teams := make([]string, 0, len(allowed))
+		for team := range allowed {
+			teams = append(teams, team)
+		}
+		return teams, nil
+	}
+	for _, team := range requested {
+		if _, ok := allowed[team]; !ok {
+			return nil, permission.ErrUnauthorized
+		}
+	}
+	return requested, nil
+}
+
+func parseMeteringTime(value string, defaultValue time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, &errors.HTTP{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("invalid date %q, must be in RFC 3339 or YYYY-MM-DD format", value),
+	}
+}
+
+func writeMeteringCSV(w http.ResponseWriter, report *metering.Report) error {
+	w.Header().Set("Content-Type", "text/csv")
+	writer := csv.NewWriter(w)
+	writer.Write([]string{string(report.GroupBy), "unit_hours", "memory_gb_hours", "cpu_hours", "cost", "currency"})
+	for _, entry := range append(report.Entries, report.Total) {
+		writer.Write([]string{
+			entry.Group,
+			formatMeteringValue(entry.UnitHours),
+			formatMeteringValue(entry.MemoryGBHours),
+			formatMeteringValue(entry.CPUHours),
+			formatMeteringValue(entry.Cost),
+			report.Currency,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatMeteringValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"time"
+
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/types/metering"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestMeteringReport(c *check.C) {
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	expected := metering.Report{
+		Start:    start,
+		End:      end,
+		GroupBy:  metering.GroupByApp,
+		Currency: "USD",
+		Entries:  []metering.ReportEntry{{Group: "myapp", UnitHours: 10, Cost: 5}},
+		Total:    metering.ReportEntry{Group: "total", UnitHours: 10, Cost: 5},
+	}
+	s.mockService.Metering.OnReport = func(opts metering.ReportOptions) (*metering.Report, error) {
+		c.Assert(opts, check.DeepEquals, metering.ReportOptions{
+			GroupBy: metering.GroupByApp,
+			UsageFilter: metering.UsageFilter{
+				Start: start,
+				End:   end,
+				Pools: []string{"pool1"},
+			},
+		})
+		return &expected, nil
+	}
+	request, err := http.NewRequest("GET", "/metering/report?groupBy=app&start=2026-05-01&end=2026-06-01T00:00:00Z&pool=pool1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result metering.Report
+	err = json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, expected)
+}
+
+func (s *S) TestMeteringReportCSV(c *check.C) {
+	s.mockService.Metering.OnReport = func(opts metering.ReportOptions) (*metering.Report, error) {
+		c.Assert(opts.End.Sub(opts.Start), check.Equals, 30*24*time.Hour)
+		return &metering.Report{
+			GroupBy:  metering.GroupByTeam,
+			Currency: "USD",
+			Entries: []metering.ReportEntry{
+				{Group: "team1", UnitHours: 10, MemoryGBHours: 5, CPUHours: 2.5, Cost: 1.25},
+				{Group: "team2", UnitHours: 1},
+			},
+			Total: metering.ReportEntry{Group: "total", UnitHours: 11, MemoryGBHours: 5, CPUHours: 2.5, Cost: 1.25},
+		}, nil
+	}
+	request, err := http.NewRequest("GET", "/metering/report", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Accept", "text/csv")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "text/csv")
+	c.Assert(recorder.Body.String(), check.Equals, `team,unit_hours,memory_gb_hours,cpu_hours,cost,currency
+team1,10.0000,5.0000,2.5000,1.2500,USD
+team2,1.0000,0.0000,0.0000,0.0000,USD
+total,11.0000,5.0000,2.5000,1.2500,USD
+`)
+}
+
+func (s *S) TestMeteringReportInvalidParams(c *check.C) {
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{query: "groupBy=plan", message: metering.ErrInvalidGroupBy.Error() + "\n"},
+		{query: "start=yesterday", message: `invalid date "yesterday", must be in RFC 3339 or YYYY-MM-DD format` + "\n"},
+		{query: "start=2026-06-01&end=2026-05-01", message: "start must be before end\n"},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("GET", "/metering/report?"+tt.query, nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Check(recorder.Code, check.Equals, http.StatusBadRequest, check.Commentf(tt.query))
+		c.Check(recorder.Body.String(), check.Equals, tt.message, check.Commentf(tt.query))
+	}
+}
+
+func (s *S) TestMeteringReportRestrictedToUserTeams(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermMeteringRead,
+		Context: permission.Context(permTypes.CtxTeam, "team1"),
+	}, permission.Permission{
+		Scheme:  permission.PermMeteringRead,
+		Context: permission.Context(permTypes.CtxTeam, "team2"),
+	})
+	s.mockService.Metering.OnReport = func(opts metering.ReportOptions) (*metering.Report, error) {
+		sort.Strings(opts.Teams)
+		c.Assert(opts.Teams, check.DeepEquals, []string{"team1", "team2"})
+		return &metering.Report{Entries: []metering.ReportEntry{}}, nil
+	}
+	request, err := http.NewRequest("GET", "/metering/report", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	request, err = http.NewRequest("GET", "/metering/report?team=team3", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestMeteringReportTeamScopedUser(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermMeteringRead,
+		Context: permission.Context(permTypes.CtxTeam, "team1"),
+	})
+	s.mockService.Metering.OnReport = func(opts metering.ReportOptions) (*metering.Report, error) {
+		c.Assert(opts.Teams, check.DeepEquals, []string{"team1"})
+		return &metering.Report{Entries: []metering.ReportEntry{}}, nil
+	}
+	request, err := http.NewRequest("GET", "/metering/report?team=team1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+}
+
+func (s *S) TestMeteringReportRequiresPermission(c *check.C) {
+	token := userWithPermission(c)
+	request, err := http.NewRequest("GET", "/metering/report", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/tsuru/tsuru/servicemanager"
 	"github.com/tsuru/tsuru/set"
 	appTypes "github.com/tsuru/tsuru/types/app"
+	quotaTypes "github.com/tsuru/tsuru/types/quota"
 )
 
 const (
@@ -152,6 +153,8 @@ func errorHandlingMiddleware(w http.ResponseWriter, r *http.Request, next http.H
 			code = http.StatusBadRequest
 		case *tsuruErrors.HTTP:
 			code = t.Code
+		case *quotaTypes.TeamQuotaExceededError:
+			code = http.StatusForbidden
 		}
 		if errors.Cause(err) == appTypes.ErrAppNotFound {
 			code = http.StatusNotFound
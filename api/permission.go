@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 
+	pkgErrors "github.com/pkg/errors"
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
@@ -297,8 +299,12 @@ func addPermissions(w http.ResponseWriter, r *http.Request, t auth.Token) (err e
 	if err != nil {
 		return err
 	}
+	deny, _ := strconv.ParseBool(InputValue(r, "deny"))
 	err = runWithPermSync(ctx, users, func() error {
 		permissions, _ := InputValues(r, "permission")
+		if deny {
+			return role.AddDenyPermissions(permissions...)
+		}
 		return role.AddPermissions(permissions...)
 	})
 	if err == permTypes.ErrInvalidPermissionName {
@@ -361,12 +367,123 @@ func removePermissions(w http.ResponseWriter, r *http.Request, t auth.Token) (er
 	if err != nil {
 		return err
 	}
+	deny, _ := strconv.ParseBool(InputValue(r, "deny"))
 	err = runWithPermSync(ctx, users, func() error {
+		if deny {
+			return role.RemoveDenyPermissions(permName)
+		}
 		return role.RemovePermissions(permName)
 	})
 	return err
 }
 
+// title: add role condition
+// path: /roles/{name}/conditions
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Role not found
+func addRoleCondition(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(t, permission.PermRoleUpdateConditionAdd) {
+		return permission.ErrUnauthorized
+	}
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdateConditionAdd,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	role, err := permission.FindRole(roleName)
+	if err != nil {
+		if err == permTypes.ErrRoleNotFound {
+			return &errors.HTTP{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+		}
+		return err
+	}
+	cond := permission.RoleCondition{
+		Type:  permission.ConditionType(InputValue(r, "type")),
+		Value: InputValue(r, "value"),
+	}
+	users, err := auth.ListUsersWithRole(roleName)
+	if err != nil {
+		return err
+	}
+	err = runWithPermSync(ctx, users, func() error {
+		return role.AddCondition(cond)
+	})
+	if pkgErrors.Cause(err) == permission.ErrInvalidCondition {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	return err
+}
+
+// title: remove role condition
+// path: /roles/{name}/conditions/{type}
+// method: DELETE
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Role not found
+func removeRoleCondition(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(t, permission.PermRoleUpdateConditionRemove) {
+		return permission.ErrUnauthorized
+	}
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdateConditionRemove,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	condType, err := permission.ParseConditionType(r.URL.Query().Get(":type"))
+	if err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	role, err := permission.FindRole(roleName)
+	if err != nil {
+		if err == permTypes.ErrRoleNotFound {
+			return &errors.HTTP{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+		}
+		return err
+	}
+	users, err := auth.ListUsersWithRole(roleName)
+	if err != nil {
+		return err
+	}
+	return runWithPermSync(ctx, users, func() error {
+		return role.RemoveCondition(condType)
+	})
+}
+
 func canUseRole(t auth.Token, roleName, contextValue string) error {
 	role, err := permission.FindRole(roleName)
 	if err != nil {
@@ -384,6 +501,9 @@ func canUseRole(t auth.Token, roleName, contextValue string) error {
 	}
 	perms := role.PermissionsFor(contextValue)
 	for _, p := range perms {
+		if p.Deny {
+			continue
+		}
 		if !permission.CheckFromPermList(userPerms, p.Scheme, p.Context) {
 			return &errors.HTTP{
 				Code:    http.StatusForbidden,
@@ -393,6 +393,152 @@ func (s *S) TestRemovePermissionsFromRole(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestAddDenyPermissionsToARole(c *check.C) {
+	_, err := permission.NewRole("test", "team", "")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	b := bytes.NewBufferString(`permission=app.deploy&deny=true`)
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/permissions", b)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleUpdate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	r, err := permission.FindRole("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.SchemeNames, check.HasLen, 0)
+	c.Assert(r.DenySchemeNames, check.DeepEquals, []string{"app.deploy"})
+}
+
+func (s *S) TestRemoveDenyPermissionsFromRole(c *check.C) {
+	r, err := permission.NewRole("test", "team", "")
+	c.Assert(err, check.IsNil)
+	defer permission.DestroyRole(r.Name)
+	err = r.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = r.AddDenyPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodDelete, "/roles/test/permissions/app.deploy?deny=true", nil)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleUpdate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	r, err = permission.FindRole("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.SchemeNames, check.DeepEquals, []string{"app.deploy"})
+	c.Assert(r.DenySchemeNames, check.DeepEquals, []string{})
+}
+
+func (s *S) TestAddRoleCondition(c *check.C) {
+	_, err := permission.NewRole("test", "team", "")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	b := bytes.NewBufferString(`type=pool&value=prod-*`)
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/conditions", b)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleUpdateConditionAdd,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	r, err := permission.FindRole("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.Conditions, check.DeepEquals, []permission.RoleCondition{
+		{Type: permission.ConditionPool, Value: "prod-*"},
+	})
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeRole, Value: "test"},
+		Owner:  token.GetUserName(),
+		Kind:   "role.update.condition.add",
+		StartCustomData: []map[string]interface{}{
+			{"name": "type", "value": "pool"},
+			{"name": "value", "value": "prod-*"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestAddRoleConditionInvalid(c *check.C) {
+	_, err := permission.NewRole("test", "team", "")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	b := bytes.NewBufferString(`type=time&value=always`)
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/conditions", b)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleUpdateConditionAdd,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(rec.Body.String(), check.Matches, `invalid time window "always".*\n`)
+}
+
+func (s *S) TestAddRoleConditionUnauthorized(c *check.C) {
+	_, err := permission.NewRole("test", "team", "")
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	b := bytes.NewBufferString(`type=pool&value=prod-*`)
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/conditions", b)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleUpdatePermissionAdd,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRemoveRoleCondition(c *check.C) {
+	r, err := permission.NewRole("test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddCondition(permission.RoleCondition{Type: permission.ConditionTag, Value: "frozen"})
+	c.Assert(err, check.IsNil)
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodDelete, "/roles/test/conditions/tag", nil)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleUpdateConditionRemove,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	server := RunServer(true)
+	server.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	r, err = permission.FindRole("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.Conditions, check.HasLen, 0)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeRole, Value: "test"},
+		Owner:  token.GetUserName(),
+		Kind:   "role.update.condition.remove",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": "test"},
+			{"name": ":type", "value": "tag"},
+		},
+	}, eventtest.HasEvent)
+}
+
 func (s *S) TestRemovePermissionsFromRoleSyncGitRepository(c *check.C) {
 	r, err := permission.NewRole("test", "team", "")
 	c.Assert(err, check.IsNil)
@@ -6,6 +6,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -173,3 +174,124 @@ func changeAppQuota(w http.ResponseWriter, r *http.Request, t auth.Token) (err e
 	}
 	return err
 }
+
+// title: team quota
+// path: /teams/{name}/quota
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: Team not found
+func getTeamQuota(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	teamName := r.URL.Query().Get(":name")
+	allowed := permission.Check(t, permission.PermTeamReadQuota, permission.Context(permTypes.CtxTeam, teamName))
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	_, err := servicemanager.Team.FindByName(ctx, teamName)
+	if err == authTypes.ErrTeamNotFound {
+		return &errors.HTTP{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	teamQuota, err := servicemanager.TeamQuota.Get(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(teamQuota)
+}
+
+// title: update team quota
+// path: /teams/{name}/quota
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Quota updated
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Limit lower than allocated value
+//   404: Team not found
+func changeTeamQuota(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	teamName := r.URL.Query().Get(":name")
+	allowed := permission.Check(t, permission.PermTeamUpdateQuota, permission.Context(permTypes.CtxTeam, teamName))
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	_, err = servicemanager.Team.FindByName(ctx, teamName)
+	if err == authTypes.ErrTeamNotFound {
+		return &errors.HTTP{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		}
+	} else if err != nil {
+		return err
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(teamName),
+		Kind:       permission.PermTeamUpdateQuota,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, teamName)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	current, err := servicemanager.TeamQuota.Get(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	limit := current.Limit
+	units, err := teamQuotaLimitValue(r, "units", int64(limit.Units))
+	if err != nil {
+		return err
+	}
+	limit.Memory, err = teamQuotaLimitValue(r, "memory", limit.Memory)
+	if err != nil {
+		return err
+	}
+	cpuMilli, err := teamQuotaLimitValue(r, "cpumilli", int64(limit.CPUMilli))
+	if err != nil {
+		return err
+	}
+	serviceInstances, err := teamQuotaLimitValue(r, "serviceinstances", int64(limit.ServiceInstances))
+	if err != nil {
+		return err
+	}
+	limit.Units = int(units)
+	limit.CPUMilli = int(cpuMilli)
+	limit.ServiceInstances = int(serviceInstances)
+	err = servicemanager.TeamQuota.SetLimit(ctx, teamName, limit)
+	if err == quota.ErrLimitLowerThanAllocated {
+		return &errors.HTTP{
+			Code:    http.StatusForbidden,
+			Message: err.Error(),
+		}
+	}
+	return err
+}
+
+// teamQuotaLimitValue returns the limit sent in the request for the named
+// resource, keeping the current limit when it's not sent.
+func teamQuotaLimitValue(r *http.Request, name string, current int64) (int64, error) {
+	raw := InputValue(r, name)
+	if raw == "" {
+		return current, nil
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Invalid %s limit", name),
+		}
+	}
+	return value, nil
+}
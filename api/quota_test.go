@@ -66,6 +66,12 @@ func (s *QuotaSuite) SetUpTest(c *check.C) {
 	}, permission.Permission{
 		Scheme:  permission.PermUserReadQuota,
 		Context: permission.Context(permTypes.CtxGlobal, ""),
+	}, permission.Permission{
+		Scheme:  permission.PermTeamReadQuota,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermTeamUpdateQuota,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
 	})
 	var err error
 	s.user, err = auth.ConvertNewUser(s.token.User())
@@ -490,3 +496,124 @@ func (s *QuotaSuite) TestChangeAppQuotaLimitLowerThanAllocated(c *check.C) {
 		ErrorMatches: `New limit is less than the current allocated value`,
 	}, eventtest.HasEvent)
 }
+
+func (s *QuotaSuite) TestGetTeamQuota(c *check.C) {
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		c.Assert(name, check.Equals, s.team.Name)
+		return s.team, nil
+	}
+	expected := quota.TeamQuota{
+		Limit: quota.TeamResources{Units: 10, Memory: -1, CPUMilli: 4000, ServiceInstances: 2},
+		InUse: quota.TeamResources{Units: 2, Memory: 2048, CPUMilli: 200, ServiceInstances: 1},
+		Apps:  []quota.TeamAppUsage{{App: "myapp", Plan: "small", Units: 2, Memory: 2048, CPUMilli: 200}},
+	}
+	s.mockService.TeamQuota.OnGet = func(team string) (*quota.TeamQuota, error) {
+		c.Assert(team, check.Equals, s.team.Name)
+		return &expected, nil
+	}
+	request, err := http.NewRequest("GET", "/teams/superteam/quota", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result quota.TeamQuota
+	err = json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, expected)
+}
+
+func (s *QuotaSuite) TestGetTeamQuotaRequiresPermission(c *check.C) {
+	token := userWithPermission(c)
+	request, err := http.NewRequest("GET", "/teams/superteam/quota", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *QuotaSuite) TestGetTeamQuotaTeamNotFound(c *check.C) {
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return nil, authTypes.ErrTeamNotFound
+	}
+	request, err := http.NewRequest("GET", "/teams/superteam/quota", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *QuotaSuite) TestChangeTeamQuota(c *check.C) {
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return s.team, nil
+	}
+	s.mockService.TeamQuota.OnGet = func(team string) (*quota.TeamQuota, error) {
+		return &quota.TeamQuota{Limit: quota.TeamResources{Units: 10, Memory: -1, CPUMilli: 4000, ServiceInstances: 2}}, nil
+	}
+	var limit quota.TeamResources
+	s.mockService.TeamQuota.OnSetLimit = func(team string, l quota.TeamResources) error {
+		c.Assert(team, check.Equals, s.team.Name)
+		limit = l
+		return nil
+	}
+	body := bytes.NewBufferString("units=20&memory=1073741824")
+	request, err := http.NewRequest("PUT", "/teams/superteam/quota", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(limit, check.DeepEquals, quota.TeamResources{Units: 20, Memory: 1073741824, CPUMilli: 4000, ServiceInstances: 2})
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: s.team.Name},
+		Owner:  s.token.GetUserName(),
+		Kind:   "team.update.quota",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": s.team.Name},
+			{"name": "units", "value": "20"},
+			{"name": "memory", "value": "1073741824"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *QuotaSuite) TestChangeTeamQuotaInvalidValue(c *check.C) {
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return s.team, nil
+	}
+	s.mockService.TeamQuota.OnGet = func(team string) (*quota.TeamQuota, error) {
+		return &quota.TeamQuota{Limit: quota.UnlimitedTeamResources}, nil
+	}
+	body := bytes.NewBufferString("cpumilli=lots")
+	request, err := http.NewRequest("PUT", "/teams/superteam/quota", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "Invalid cpumilli limit\n")
+}
+
+func (s *QuotaSuite) TestChangeTeamQuotaLimitLowerThanAllocated(c *check.C) {
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return s.team, nil
+	}
+	s.mockService.TeamQuota.OnGet = func(team string) (*quota.TeamQuota, error) {
+		return &quota.TeamQuota{Limit: quota.UnlimitedTeamResources}, nil
+	}
+	s.mockService.TeamQuota.OnSetLimit = func(team string, l quota.TeamResources) error {
+		return quota.ErrLimitLowerThanAllocated
+	}
+	body := bytes.NewBufferString("units=1")
+	request, err := http.NewRequest("PUT", "/teams/superteam/quota", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	registryTypes "github.com/tsuru/tsuru/types/registry"
+)
+
+func registryCredentialTeam(r *http.Request, t auth.Token, perm *permission.PermissionScheme) (string, error) {
+	teamName := r.URL.Query().Get(":name")
+	if !permission.Check(t, perm, permission.Context(permTypes.CtxTeam, teamName)) {
+		return "", permission.ErrUnauthorized
+	}
+	_, err := servicemanager.Team.FindByName(r.Context(), teamName)
+	if err == authTypes.ErrTeamNotFound {
+		return "", &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return teamName, err
+}
+
+func registryCredentialError(err error) error {
+	switch err {
+	case registryTypes.ErrCredentialNotFound:
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	case registryTypes.ErrCredentialAlreadyExists:
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	return err
+}
+
+// title: registry credential list
+// path: /teams/{name}/registry-credentials
+// method: GET
+// produce: application/json
+// responses:
+//   200: List registry credentials
+//   204: No content
+//   401: Unauthorized
+//   404: Team not found
+func registryCredentialList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	teamName, err := registryCredentialTeam(r, t, permission.PermTeamRegistryCredentialRead)
+	if err != nil {
+		return err
+	}
+	creds, err := servicemanager.RegistryCredential.List(r.Context(), []string{teamName})
+	if err != nil {
+		return err
+	}
+	if len(creds) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(creds)
+}
+
+// title: registry credential create
+// path: /teams/{name}/registry-credentials
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   201: Registry credential created
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Team not found
+//   409: Registry credential already exists
+func registryCredentialCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	teamName, err := registryCredentialTeam(r, t, permission.PermTeamRegistryCredentialCreate)
+	if err != nil {
+		return err
+	}
+	var cred registryTypes.Credential
+	err = ParseInput(r, &cred)
+	if err != nil {
+		return err
+	}
+	cred.Team = teamName
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(teamName),
+		Kind:       permission.PermTeamRegistryCredentialCreate,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r, "token")),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, teamName)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = servicemanager.RegistryCredential.Create(r.Context(), cred)
+	if err != nil {
+		return registryCredentialError(err)
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// title: registry credential update
+// path: /teams/{name}/registry-credentials/{registry}
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Registry credential updated
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Registry credential not found
+func registryCredentialUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	teamName, err := registryCredentialTeam(r, t, permission.PermTeamRegistryCredentialUpdate)
+	if err != nil {
+		return err
+	}
+	var cred registryTypes.Credential
+	err = ParseInput(r, &cred)
+	if err != nil {
+		return err
+	}
+	cred.Team = teamName
+	cred.Registry = r.URL.Query().Get(":registry")
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(teamName),
+		Kind:       permission.PermTeamRegistryCredentialUpdate,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r, "token")),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, teamName)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return registryCredentialError(servicemanager.RegistryCredential.Update(r.Context(), cred))
+}
+
+// title: registry credential delete
+// path: /teams/{name}/registry-credentials/{registry}
+// method: DELETE
+// responses:
+//   200: Registry credential removed
+//   401: Unauthorized
+//   404: Registry credential not found
+func registryCredentialDelete(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	teamName, err := registryCredentialTeam(r, t, permission.PermTeamRegistryCredentialDelete)
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(teamName),
+		Kind:       permission.PermTeamRegistryCredentialDelete,
+		Owner:      t,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, teamName)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	registry := r.URL.Query().Get(":registry")
+	err = removeRegistryCredentialFromProvisioners(r.Context(), teamName, registryTypes.NormalizeRegistry(registry))
+	if err != nil {
+		return err
+	}
+	return registryCredentialError(servicemanager.RegistryCredential.Delete(r.Context(), teamName, registry))
+}
+
+// removeRegistryCredentialFromProvisioners removes the copies of the
+// credential kept by provisioners. It runs before the credential is removed
+// so that failures may be retried.
+func removeRegistryCredentialFromProvisioners(ctx context.Context, team, registry string) error {
+	provs, err := provision.Registry()
+	if err != nil {
+		return err
+	}
+	for _, p := range provs {
+		if credProv, ok := p.(provision.RegistryCredentialProvisioner); ok {
+			err = credProv.RemoveRegistryCredential(ctx, team, registry)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	registryTypes "github.com/tsuru/tsuru/types/registry"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestRegistryCredentialList(c *check.C) {
+	s.mockService.RegistryCredential.OnList = func(teams []string) ([]registryTypes.Credential, error) {
+		c.Assert(teams, check.DeepEquals, []string{s.team.Name})
+		return []registryTypes.Credential{{Team: s.team.Name, Registry: "registry.example.com", Username: "bot"}}, nil
+	}
+	request, err := http.NewRequest("GET", "/teams/tsuruteam/registry-credentials", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result []registryTypes.Credential
+	err = json.NewDecoder(recorder.Body).Decode(&result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []registryTypes.Credential{{Team: s.team.Name, Registry: "registry.example.com", Username: "bot"}})
+}
+
+func (s *S) TestRegistryCredentialListRequiresPermission(c *check.C) {
+	token := userWithPermission(c)
+	request, err := http.NewRequest("GET", "/teams/tsuruteam/registry-credentials", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRegistryCredentialListTeamNotFound(c *check.C) {
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return nil, authTypes.ErrTeamNotFound
+	}
+	request, err := http.NewRequest("GET", "/teams/tsuruteam/registry-credentials", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestRegistryCredentialCreate(c *check.C) {
+	var created registryTypes.Credential
+	s.mockService.RegistryCredential.OnCreate = func(cred registryTypes.Credential) error {
+		created = cred
+		return nil
+	}
+	body := strings.NewReader("registry=registry.example.com&username=bot&token=secret")
+	request, err := http.NewRequest("POST", "/teams/tsuruteam/registry-credentials", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	c.Assert(created, check.DeepEquals, registryTypes.Credential{Team: s.team.Name, Registry: "registry.example.com", Username: "bot", Token: "secret"})
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: s.team.Name},
+		Owner:  s.token.GetUserName(),
+		Kind:   "team.registry-credential.create",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": s.team.Name},
+			{"name": "registry", "value": "registry.example.com"},
+			{"name": "username", "value": "bot"},
+			{"name": "token", "value": "*****"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRegistryCredentialCreateAlreadyExists(c *check.C) {
+	s.mockService.RegistryCredential.OnCreate = func(cred registryTypes.Credential) error {
+		return registryTypes.ErrCredentialAlreadyExists
+	}
+	body := strings.NewReader("registry=registry.example.com&username=bot&token=secret")
+	request, err := http.NewRequest("POST", "/teams/tsuruteam/registry-credentials", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestRegistryCredentialCreateRequiresPermission(c *check.C) {
+	token := userWithPermission(c)
+	body := strings.NewReader("registry=registry.example.com&username=bot&token=secret")
+	request, err := http.NewRequest("POST", "/teams/tsuruteam/registry-credentials", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRegistryCredentialUpdate(c *check.C) {
+	var updated registryTypes.Credential
+	s.mockService.RegistryCredential.OnUpdate = func(cred registryTypes.Credential) error {
+		updated = cred
+		return nil
+	}
+	body := strings.NewReader("username=bot&token=new-secret")
+	request, err := http.NewRequest("PUT", "/teams/tsuruteam/registry-credentials/registry.example.com:5000", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(updated, check.DeepEquals, registryTypes.Credential{Team: s.team.Name, Registry: "registry.example.com:5000", Username: "bot", Token: "new-secret"})
+}
+
+func (s *S) TestRegistryCredentialUpdateNotFound(c *check.C) {
+	s.mockService.RegistryCredential.OnUpdate = func(cred registryTypes.Credential) error {
+		return registryTypes.ErrCredentialNotFound
+	}
+	body := strings.NewReader("username=bot&token=new-secret")
+	request, err := http.NewRequest("PUT", "/teams/tsuruteam/registry-credentials/registry.example.com", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestRegistryCredentialDelete(c *check.C) {
+	var deleted []string
+	s.mockService.RegistryCredential.OnDelete = func(team, registry string) error {
+		deleted = []string{team, registry}
+		return nil
+	}
+	request, err := http.NewRequest("DELETE", "/teams/tsuruteam/registry-credentials/registry.example.com", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(deleted, check.DeepEquals, []string{s.team.Name, "registry.example.com"})
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: s.team.Name},
+		Owner:  s.token.GetUserName(),
+		Kind:   "team.registry-credential.delete",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": s.team.Name},
+			{"name": ":registry", "value": "registry.example.com"},
+		},
+	}, eventtest.HasEvent)
+}
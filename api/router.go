@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
@@ -412,6 +413,7 @@ type setRoutableRequest struct {
 //   400: Bad request
 //   401: Not authorized
 //   404: App not found
+//   409: Version blocked by the pool vulnerability policy
 func appSetRoutable(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
 	var args setRoutableRequest
@@ -448,5 +450,9 @@ func appSetRoutable(w http.ResponseWriter, r *http.Request, t auth.Token) (err e
 		}
 		return err
 	}
-	return a.SetRoutable(ctx, version, args.IsRoutable)
+	err = a.SetRoutable(ctx, version, args.IsRoutable)
+	if vulnErr, ok := err.(*app.ErrVersionVulnerable); ok {
+		return &errors.HTTP{Code: http.StatusConflict, Message: vulnErr.Error()}
+	}
+	return err
 }
@@ -0,0 +1,51 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+)
+
+// title: app version sbom
+// path: /apps/{app}/versions/{version}/sbom
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   400: Invalid version
+//   401: Unauthorized
+//   404: App or SBOM not found
+func appVersionSBOM(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppRead, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	version, err := strconv.Atoi(r.URL.Query().Get(":version"))
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid version: %s", r.URL.Query().Get(":version"))}
+	}
+	sbom, err := app.GetVersionSBOM(a.Name, version)
+	if err == app.ErrVersionSBOMNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(sbom)
+}
@@ -0,0 +1,678 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	apiContext "github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/auth/scim"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const (
+	scimOwnerName = "scim"
+
+	scimGroupCreateKind = "scim-group-create"
+	scimGroupUpdateKind = "scim-group-update"
+	scimGroupDeleteKind = "scim-group-delete"
+)
+
+// scimHandler authenticates requests with the token set in scim:token and
+// writes errors in the format expected by SCIM clients.
+type scimHandler func(http.ResponseWriter, *http.Request) error
+
+func (fn scimHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := checkSCIMToken(r)
+	if err == nil {
+		err = fn(w, r)
+	}
+	if err != nil {
+		writeSCIMError(w, err)
+	}
+}
+
+func checkSCIMToken(r *http.Request) error {
+	expected, _ := config.GetString("scim:token")
+	if expected == "" {
+		return scim.NewError(http.StatusNotFound, "", "SCIM provisioning is not enabled")
+	}
+	token, err := auth.ParseToken(r.Header.Get("Authorization"))
+	if err != nil || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return scim.NewError(http.StatusUnauthorized, "", "invalid SCIM token")
+	}
+	return nil
+}
+
+func writeSCIMError(w http.ResponseWriter, err error) {
+	var scimErr *scim.Error
+	switch e := errors.Cause(err).(type) {
+	case *scim.Error:
+		scimErr = e
+	case *tsuruErrors.HTTP:
+		scimErr = scim.NewError(e.Code, "", e.Message)
+	case *tsuruErrors.ValidationError:
+		scimErr = scim.NewError(http.StatusBadRequest, "invalidValue", e.Message)
+	default:
+		log.Errorf("[scim] unexpected error: %v", err)
+		scimErr = scim.NewError(http.StatusInternalServerError, "", err.Error())
+	}
+	writeSCIM(w, scimErr.StatusCode(), scimErr)
+}
+
+func writeSCIM(w http.ResponseWriter, status int, data interface{}) error {
+	w.Header().Set("Content-Type", scim.ContentType)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(data)
+}
+
+func parseSCIMBody(r *http.Request, dst interface{}) error {
+	data, err := apiContext.GetBody(r)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, dst)
+	if err != nil {
+		return scim.NewError(http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("unable to parse body: %v", err))
+	}
+	return nil
+}
+
+func scimListParams(r *http.Request) (*scim.Filter, int, int, error) {
+	filter, err := scim.ParseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	startIndex, count := 1, -1
+	if v := r.URL.Query().Get("startIndex"); v != "" {
+		startIndex, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, 0, 0, scim.NewError(http.StatusBadRequest, "invalidValue", "invalid startIndex")
+		}
+	}
+	if v := r.URL.Query().Get("count"); v != "" {
+		count, err = strconv.Atoi(v)
+		if err != nil || count < 0 {
+			return nil, 0, 0, scim.NewError(http.StatusBadRequest, "invalidValue", "invalid count")
+		}
+	}
+	return filter, startIndex, count, nil
+}
+
+func scimEvent(target event.Target, kind *permission.PermissionScheme, data interface{}) (*event.Event, error) {
+	return event.New(&event.Opts{
+		Target:     target,
+		Kind:       kind,
+		RawOwner:   event.Owner{Type: event.OwnerTypeInternal, Name: scimOwnerName},
+		CustomData: data,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, target.Value)),
+	})
+}
+
+func scimGroupEvent(group, kind string, data interface{}) (*event.Event, error) {
+	return event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeGroup, Value: group},
+		InternalKind: kind,
+		RawOwner:     event.Owner{Type: event.OwnerTypeInternal, Name: scimOwnerName},
+		CustomData:   data,
+		Allowed:      event.Allowed(permission.PermRoleReadEvents),
+	})
+}
+
+func scimUser(u *auth.User) scim.User {
+	return scim.NewUser(u.Email, !u.Disabled, u.Groups)
+}
+
+func getSCIMUserByID(id string) (*auth.User, error) {
+	u, err := auth.GetUserByEmail(id)
+	if err == authTypes.ErrUserNotFound {
+		return nil, scim.NotFoundError(scim.ResourceTypeUser, id)
+	}
+	return u, err
+}
+
+// setUserActive enables or disables the user, revoking all its tokens when
+// the user is disabled.
+func setUserActive(ctx context.Context, u *auth.User, active bool) (err error) {
+	if u.Disabled == !active {
+		return nil
+	}
+	evt, err := scimEvent(userTarget(u.Email), permission.PermUserUpdate, map[string]bool{"active": active})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	u.Disabled = !active
+	err = u.Update()
+	if err != nil || active {
+		return err
+	}
+	if revocable, ok := app.AuthScheme.(auth.RevocableScheme); ok {
+		return revocable.RevokeTokens(ctx, u)
+	}
+	return nil
+}
+
+// title: scim list users
+// path: /scim/v2/Users
+// method: GET
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   400: Invalid filter
+//   401: Unauthorized
+func listSCIMUsers(w http.ResponseWriter, r *http.Request) error {
+	filter, startIndex, count, err := scimListParams(r)
+	if err != nil {
+		return err
+	}
+	users, err := auth.ListUsers()
+	if err != nil {
+		return err
+	}
+	resources := []interface{}{}
+	for i := range users {
+		resource := scimUser(&users[i])
+		if filter.MatchUser(&resource) {
+			resources = append(resources, resource)
+		}
+	}
+	return writeSCIM(w, http.StatusOK, scim.NewListResponse(resources, startIndex, count))
+}
+
+// title: scim get user
+// path: /scim/v2/Users/{id}
+// method: GET
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: Not found
+func getSCIMUser(w http.ResponseWriter, r *http.Request) error {
+	u, err := getSCIMUserByID(r.URL.Query().Get(":id"))
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusOK, scimUser(u))
+}
+
+// title: scim create user
+// path: /scim/v2/Users
+// method: POST
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//   201: Created
+//   400: Invalid data
+//   401: Unauthorized
+//   409: User already exists
+func createSCIMUser(w http.ResponseWriter, r *http.Request) (err error) {
+	ctx := r.Context()
+	var resource scim.User
+	err = parseSCIMBody(r, &resource)
+	if err != nil {
+		return err
+	}
+	email := resource.Email()
+	if email == "" {
+		return scim.NewError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+	_, err = auth.GetUserByEmail(email)
+	if err == nil {
+		return scim.NewError(http.StatusConflict, "uniqueness", fmt.Sprintf("user %q already exists", email))
+	}
+	if err != authTypes.ErrUserNotFound {
+		return err
+	}
+	password := resource.Password
+	if password == "" {
+		// users provisioned without a password must reset it before using
+		// schemes that rely on passwords.
+		password, err = randomSCIMPassword()
+		if err != nil {
+			return err
+		}
+	}
+	evt, err := scimEvent(userTarget(email), permission.PermUserCreate, map[string]string{"email": email})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	u := &auth.User{Email: email, Password: password}
+	_, err = app.AuthScheme.Create(ctx, u)
+	if err != nil {
+		return handleAuthError(err)
+	}
+	if !resource.IsActive() {
+		u.Disabled = true
+		err = u.Update()
+		if err != nil {
+			return err
+		}
+	}
+	return writeSCIM(w, http.StatusCreated, scimUser(u))
+}
+
+// title: scim replace user
+// path: /scim/v2/Users/{id}
+// method: PUT
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Not found
+func replaceSCIMUser(w http.ResponseWriter, r *http.Request) error {
+	u, err := getSCIMUserByID(r.URL.Query().Get(":id"))
+	if err != nil {
+		return err
+	}
+	var resource scim.User
+	err = parseSCIMBody(r, &resource)
+	if err != nil {
+		return err
+	}
+	return updateSCIMUser(w, r, u, resource)
+}
+
+// title: scim patch user
+// path: /scim/v2/Users/{id}
+// method: PATCH
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Not found
+func patchSCIMUser(w http.ResponseWriter, r *http.Request) error {
+	u, err := getSCIMUserByID(r.URL.Query().Get(":id"))
+	if err != nil {
+		return err
+	}
+	var patch scim.PatchOp
+	err = parseSCIMBody(r, &patch)
+	if err != nil {
+		return err
+	}
+	resource := scimUser(u)
+	err = patch.ApplyUser(&resource)
+	if err != nil {
+		return err
+	}
+	return updateSCIMUser(w, r, u, resource)
+}
+
+func updateSCIMUser(w http.ResponseWriter, r *http.Request, u *auth.User, resource scim.User) error {
+	if resource.Email() != u.Email {
+		return scim.NewError(http.StatusBadRequest, "mutability", "userName cannot be changed")
+	}
+	err := setUserActive(r.Context(), u, resource.IsActive())
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusOK, scimUser(u))
+}
+
+// title: scim delete user
+// path: /scim/v2/Users/{id}
+// method: DELETE
+// responses:
+//   204: No content
+//   401: Unauthorized
+//   404: Not found
+func deleteSCIMUser(w http.ResponseWriter, r *http.Request) (err error) {
+	u, err := getSCIMUserByID(r.URL.Query().Get(":id"))
+	if err != nil {
+		return err
+	}
+	evt, err := scimEvent(userTarget(u.Email), permission.PermUserDelete, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = deleteUser(r.Context(), u)
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// scimGroups returns every known group, either created explicitly or
+// referenced by a user, along with its members.
+func scimGroups() (map[string][]string, error) {
+	groups, err := servicemanager.AuthGroup.List(nil)
+	if err != nil {
+		return nil, err
+	}
+	users, err := auth.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]string)
+	for _, g := range groups {
+		result[g.Name] = nil
+	}
+	for _, u := range users {
+		for _, g := range u.Groups {
+			result[g] = append(result[g], u.Email)
+		}
+	}
+	return result, nil
+}
+
+func getSCIMGroupByID(id string) (scim.Group, error) {
+	groups, err := scimGroups()
+	if err != nil {
+		return scim.Group{}, err
+	}
+	members, ok := groups[id]
+	if !ok {
+		return scim.Group{}, scim.NotFoundError(scim.ResourceTypeGroup, id)
+	}
+	return scim.NewGroup(id, members), nil
+}
+
+// setGroupMembers adds and removes the group from users so that its members
+// match the wanted list. Unknown users result in an error before any change.
+// Callers must record the change in a group event.
+func setGroupMembers(group string, current, wanted []string) error {
+	currentSet := make(map[string]bool)
+	for _, email := range current {
+		currentSet[email] = true
+	}
+	wantedSet := make(map[string]bool)
+	var changed []*auth.User
+	for _, email := range wanted {
+		wantedSet[email] = true
+		if currentSet[email] {
+			continue
+		}
+		u, err := auth.GetUserByEmail(email)
+		if err != nil {
+			if err == authTypes.ErrUserNotFound {
+				return scim.NewError(http.StatusBadRequest, "invalidValue", fmt.Sprintf("user %q not found", email))
+			}
+			return err
+		}
+		u.Groups = append(u.Groups, group)
+		changed = append(changed, u)
+	}
+	for _, email := range current {
+		if wantedSet[email] {
+			continue
+		}
+		u, err := auth.GetUserByEmail(email)
+		if err != nil {
+			return err
+		}
+		u.Groups = removeString(u.Groups, group)
+		changed = append(changed, u)
+	}
+	for _, u := range changed {
+		sort.Strings(u.Groups)
+		err := u.Update()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeString(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// title: scim list groups
+// path: /scim/v2/Groups
+// method: GET
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   400: Invalid filter
+//   401: Unauthorized
+func listSCIMGroups(w http.ResponseWriter, r *http.Request) error {
+	filter, startIndex, count, err := scimListParams(r)
+	if err != nil {
+		return err
+	}
+	groups, err := scimGroups()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resources := []interface{}{}
+	for _, name := range names {
+		resource := scim.NewGroup(name, groups[name])
+		if filter.MatchGroup(&resource) {
+			resources = append(resources, resource)
+		}
+	}
+	return writeSCIM(w, http.StatusOK, scim.NewListResponse(resources, startIndex, count))
+}
+
+// title: scim get group
+// path: /scim/v2/Groups/{id}
+// method: GET
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: Not found
+func getSCIMGroup(w http.ResponseWriter, r *http.Request) error {
+	group, err := getSCIMGroupByID(r.URL.Query().Get(":id"))
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusOK, group)
+}
+
+// title: scim create group
+// path: /scim/v2/Groups
+// method: POST
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//   201: Created
+//   400: Invalid data
+//   401: Unauthorized
+//   409: Group already exists
+func createSCIMGroup(w http.ResponseWriter, r *http.Request) (err error) {
+	var resource scim.Group
+	err = parseSCIMBody(r, &resource)
+	if err != nil {
+		return err
+	}
+	if resource.DisplayName == "" {
+		return scim.NewError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+	groups, err := scimGroups()
+	if err != nil {
+		return err
+	}
+	if _, ok := groups[resource.DisplayName]; ok {
+		return scim.NewError(http.StatusConflict, "uniqueness", fmt.Sprintf("group %q already exists", resource.DisplayName))
+	}
+	members := resource.MemberIDs()
+	evt, err := scimGroupEvent(resource.DisplayName, scimGroupCreateKind, map[string][]string{"members": members})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = servicemanager.AuthGroup.Create(resource.DisplayName)
+	if err != nil {
+		return err
+	}
+	err = setGroupMembers(resource.DisplayName, nil, members)
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusCreated, scim.NewGroup(resource.DisplayName, members))
+}
+
+// title: scim replace group
+// path: /scim/v2/Groups/{id}
+// method: PUT
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Not found
+func replaceSCIMGroup(w http.ResponseWriter, r *http.Request) error {
+	group, err := getSCIMGroupByID(r.URL.Query().Get(":id"))
+	if err != nil {
+		return err
+	}
+	var resource scim.Group
+	err = parseSCIMBody(r, &resource)
+	if err != nil {
+		return err
+	}
+	if resource.DisplayName == "" {
+		resource.DisplayName = group.DisplayName
+	}
+	return updateSCIMGroup(w, group, resource)
+}
+
+// title: scim patch group
+// path: /scim/v2/Groups/{id}
+// method: PATCH
+// consume: application/scim+json
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Not found
+func patchSCIMGroup(w http.ResponseWriter, r *http.Request) error {
+	group, err := getSCIMGroupByID(r.URL.Query().Get(":id"))
+	if err != nil {
+		return err
+	}
+	var patch scim.PatchOp
+	err = parseSCIMBody(r, &patch)
+	if err != nil {
+		return err
+	}
+	resource := group
+	resource.Members = append([]scim.Reference{}, group.Members...)
+	err = patch.ApplyGroup(&resource)
+	if err != nil {
+		return err
+	}
+	return updateSCIMGroup(w, group, resource)
+}
+
+func updateSCIMGroup(w http.ResponseWriter, current, wanted scim.Group) (err error) {
+	if wanted.DisplayName != current.DisplayName {
+		return scim.NewError(http.StatusBadRequest, "mutability", "displayName cannot be changed")
+	}
+	members := wanted.MemberIDs()
+	evt, err := scimGroupEvent(current.ID, scimGroupUpdateKind, map[string][]string{"members": members})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = setGroupMembers(current.ID, current.MemberIDs(), members)
+	if err != nil {
+		return err
+	}
+	return writeSCIM(w, http.StatusOK, scim.NewGroup(current.ID, members))
+}
+
+// title: scim delete group
+// path: /scim/v2/Groups/{id}
+// method: DELETE
+// responses:
+//   204: No content
+//   401: Unauthorized
+//   404: Not found
+func deleteSCIMGroup(w http.ResponseWriter, r *http.Request) (err error) {
+	group, err := getSCIMGroupByID(r.URL.Query().Get(":id"))
+	if err != nil {
+		return err
+	}
+	evt, err := scimGroupEvent(group.ID, scimGroupDeleteKind, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = setGroupMembers(group.ID, group.MemberIDs(), nil)
+	if err != nil {
+		return err
+	}
+	err = servicemanager.AuthGroup.Remove(group.ID)
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// title: scim service provider config
+// path: /scim/v2/ServiceProviderConfig
+// method: GET
+// produce: application/scim+json
+// responses:
+//   200: OK
+//   401: Unauthorized
+func scimServiceProviderConfig(w http.ResponseWriter, r *http.Request) error {
+	supported := func(v bool) map[string]bool { return map[string]bool{"supported": v} }
+	return writeSCIM(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{scim.ServiceProviderConfigSchema},
+		"patch":          supported(true),
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": 0},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]string{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication using the token configured in scim:token",
+		}},
+	})
+}
+
+func randomSCIMPassword() (string, error) {
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
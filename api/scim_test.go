@@ -0,0 +1,200 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/auth/scim"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/servicemanager"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) scimRequest(c *check.C, method, path, body string) *httptest.ResponseRecorder {
+	config.Set("scim:token", "scim-secret")
+	defer config.Unset("scim:token")
+	req, err := http.NewRequest(method, path, strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "Bearer scim-secret")
+	req.Header.Set("Content-Type", scim.ContentType)
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *S) TestSCIMDisabledWithoutToken(c *check.C) {
+	req, err := http.NewRequest(http.MethodGet, "/scim/v2/Users", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "Bearer scim-secret")
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusNotFound)
+	c.Assert(rec.Header().Get("Content-Type"), check.Equals, scim.ContentType)
+}
+
+func (s *S) TestSCIMInvalidToken(c *check.C) {
+	config.Set("scim:token", "scim-secret")
+	defer config.Unset("scim:token")
+	req, err := http.NewRequest(http.MethodGet, "/scim/v2/Users", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "Bearer "+s.token.GetValue())
+	rec := httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusUnauthorized)
+	var scimErr scim.Error
+	err = json.Unmarshal(rec.Body.Bytes(), &scimErr)
+	c.Assert(err, check.IsNil)
+	c.Assert(scimErr.Schemas, check.DeepEquals, []string{scim.ErrorSchema})
+}
+
+func (s *S) TestSCIMCreateUser(c *check.C) {
+	rec := s.scimRequest(c, http.MethodPost, "/scim/v2/Users", `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "provisioned@example.com",
+		"active": true
+	}`)
+	c.Assert(rec.Code, check.Equals, http.StatusCreated)
+	var result scim.User
+	err := json.Unmarshal(rec.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.ID, check.Equals, "provisioned@example.com")
+	c.Assert(result.IsActive(), check.Equals, true)
+	u, err := auth.GetUserByEmail("provisioned@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Disabled, check.Equals, false)
+	rec = s.scimRequest(c, http.MethodPost, "/scim/v2/Users", `{"userName": "provisioned@example.com"}`)
+	c.Assert(rec.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestSCIMListUsersWithFilter(c *check.C) {
+	u := &auth.User{Email: "filtered@example.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	rec := s.scimRequest(c, http.MethodGet, `/scim/v2/Users?filter=userName+eq+%22filtered@example.com%22`, "")
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	var result struct {
+		TotalResults int
+		Resources    []scim.User
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.TotalResults, check.Equals, 1)
+	c.Assert(result.Resources[0].UserName, check.Equals, "filtered@example.com")
+	rec = s.scimRequest(c, http.MethodGet, `/scim/v2/Users?filter=userName+xx`, "")
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestSCIMPatchUserDeactivates(c *check.C) {
+	u := &auth.User{Email: "leaving@example.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	rec := s.scimRequest(c, http.MethodPatch, "/scim/v2/Users/leaving@example.com", `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "path": "active", "value": false}]
+	}`)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	u, err = auth.GetUserByEmail("leaving@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Disabled, check.Equals, true)
+	req, err := http.NewRequest(http.MethodGet, "/users/info", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	rec = httptest.NewRecorder()
+	s.testServer.ServeHTTP(rec, req)
+	c.Assert(rec.Code, check.Equals, http.StatusUnauthorized)
+}
+
+func (s *S) TestSCIMDeleteUser(c *check.C) {
+	u := &auth.User{Email: "removed@example.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	rec := s.scimRequest(c, http.MethodDelete, "/scim/v2/Users/removed@example.com", "")
+	c.Assert(rec.Code, check.Equals, http.StatusNoContent)
+	_, err = auth.GetUserByEmail("removed@example.com")
+	c.Assert(err, check.NotNil)
+	rec = s.scimRequest(c, http.MethodDelete, "/scim/v2/Users/removed@example.com", "")
+	c.Assert(rec.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestSCIMGroupMembership(c *check.C) {
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		_, err := nativeScheme.Create(context.TODO(), &auth.User{Email: email, Password: "123456"})
+		c.Assert(err, check.IsNil)
+	}
+	rec := s.scimRequest(c, http.MethodPost, "/scim/v2/Groups", `{
+		"displayName": "admins",
+		"members": [{"value": "a@example.com"}]
+	}`)
+	c.Assert(rec.Code, check.Equals, http.StatusCreated)
+	groups, err := servicemanager.AuthGroup.List(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.HasLen, 1)
+	c.Assert(groups[0].Name, check.Equals, "admins")
+	rec = s.scimRequest(c, http.MethodPatch, "/scim/v2/Groups/admins", `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "add", "path": "members", "value": [{"value": "b@example.com"}]},
+			{"op": "remove", "path": "members[value eq \"a@example.com\"]"}
+		]
+	}`)
+	c.Assert(rec.Code, check.Equals, http.StatusOK)
+	a, err := auth.GetUserByEmail("a@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Groups, check.HasLen, 0)
+	b, err := auth.GetUserByEmail("b@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(b.Groups, check.DeepEquals, []string{"admins"})
+	rec = s.scimRequest(c, http.MethodPatch, "/scim/v2/Groups/admins", `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "add", "path": "members", "value": [{"value": "unknown@example.com"}]}]
+	}`)
+	c.Assert(rec.Code, check.Equals, http.StatusBadRequest)
+	rec = s.scimRequest(c, http.MethodDelete, "/scim/v2/Groups/admins", "")
+	c.Assert(rec.Code, check.Equals, http.StatusNoContent)
+	b, err = auth.GetUserByEmail("b@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(b.Groups, check.HasLen, 0)
+	groups, err = servicemanager.AuthGroup.List(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.HasLen, 0)
+	target := event.Target{Type: event.TargetTypeGroup, Value: "admins"}
+	c.Assert(eventtest.EventDesc{
+		Target: target,
+		Owner:  scimOwnerName,
+		Kind:   scimGroupCreateKind,
+		StartCustomData: map[string]interface{}{
+			"members": []interface{}{"a@example.com"},
+		},
+	}, eventtest.HasEvent)
+	c.Assert(eventtest.EventDesc{
+		Target: target,
+		Owner:  scimOwnerName,
+		Kind:   scimGroupUpdateKind,
+		StartCustomData: map[string]interface{}{
+			"members": []interface{}{"b@example.com"},
+		},
+	}, eventtest.HasEvent)
+	c.Assert(eventtest.EventDesc{
+		Target:       target,
+		Owner:        scimOwnerName,
+		Kind:         scimGroupUpdateKind,
+		ErrorMatches: `user "unknown@example.com" not found`,
+	}, eventtest.HasEvent)
+	c.Assert(eventtest.EventDesc{
+		Target: target,
+		Owner:  scimOwnerName,
+		Kind:   scimGroupDeleteKind,
+	}, eventtest.HasEvent)
+}
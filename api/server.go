@@ -33,11 +33,13 @@ import (
 	"github.com/tsuru/tsuru/app/bind"
 	"github.com/tsuru/tsuru/app/image"
 	"github.com/tsuru/tsuru/app/image/gc"
+	"github.com/tsuru/tsuru/app/metering"
 	"github.com/tsuru/tsuru/app/version"
 	"github.com/tsuru/tsuru/applog"
 	"github.com/tsuru/tsuru/auth"
 	_ "github.com/tsuru/tsuru/auth/native"
 	_ "github.com/tsuru/tsuru/auth/oauth"
+	_ "github.com/tsuru/tsuru/auth/oidc"
 	_ "github.com/tsuru/tsuru/auth/saml"
 	"github.com/tsuru/tsuru/autoscale"
 	"github.com/tsuru/tsuru/db"
@@ -50,6 +52,7 @@ import (
 	"github.com/tsuru/tsuru/provision/cluster"
 	"github.com/tsuru/tsuru/provision/nodecontainer"
 	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/registry"
 	"github.com/tsuru/tsuru/router"
 	"github.com/tsuru/tsuru/router/rebuild"
 	"github.com/tsuru/tsuru/service"
@@ -138,6 +141,14 @@ func setupServices() error {
 	if err != nil {
 		return err
 	}
+	servicemanager.TeamQuota, err = app.TeamQuotaService()
+	if err != nil {
+		return err
+	}
+	servicemanager.Metering, err = metering.MeteringService()
+	if err != nil {
+		return err
+	}
 	servicemanager.Webhook, err = webhook.WebhookService()
 	if err != nil {
 		return err
@@ -178,6 +189,10 @@ func setupServices() error {
 	if err != nil {
 		return err
 	}
+	servicemanager.RegistryCredential, err = registry.CredentialService()
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -263,6 +278,21 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", "Delete", "/apps/{app}/lock", AuthorizationRequiredHandler(forceDeleteLock))
 	m.Add("1.9", "Post", "/apps/{app}/units/autoscale", AuthorizationRequiredHandler(addAutoScaleUnits))
 	m.Add("1.9", "Delete", "/apps/{app}/units/autoscale", AuthorizationRequiredHandler(removeAutoScaleUnits))
+	m.Add("1.10", "Get", "/apps/{app}/jobs", AuthorizationRequiredHandler(listAppJobs))
+	m.Add("1.10", "Post", "/apps/{app}/jobs", AuthorizationRequiredHandler(setAppJob))
+	m.Add("1.10", "Delete", "/apps/{app}/jobs/{job}", AuthorizationRequiredHandler(removeAppJob))
+	m.Add("1.10", "Get", "/apps/{app}/jobs/{job}/runs", AuthorizationRequiredHandler(listAppJobRuns))
+	m.Add("1.10", "Get", "/apps/{app}/dependencies", AuthorizationRequiredHandler(listAppDependencies))
+	m.Add("1.10", "Post", "/apps/{app}/dependencies", AuthorizationRequiredHandler(addAppDependency))
+	m.Add("1.10", "Delete", "/apps/{app}/dependencies", AuthorizationRequiredHandler(removeAppDependency))
+	m.Add("1.10", "Put", "/apps/{app}/protection", AuthorizationRequiredHandler(appProtectionUpdate))
+	m.Add("1.10", "Get", "/apps/{app}/deploy-hooks", AuthorizationRequiredHandler(listAppDeployHooks))
+	m.Add("1.10", "Put", "/apps/{app}/deploy-hooks", AuthorizationRequiredHandler(setAppDeployHooks))
+	m.Add("1.10", "Get", "/apps/{app}/approvals", AuthorizationRequiredHandler(approvalRequestList))
+	m.Add("1.10", "Get", "/apps/{app}/approvals/{id}", AuthorizationRequiredHandler(approvalRequestInfo))
+	m.Add("1.10", "Post", "/apps/{app}/approvals/{id}/approve", AuthorizationRequiredHandler(approvalRequestApprove))
+	m.Add("1.10", "Post", "/apps/{app}/approvals/{id}/reject", AuthorizationRequiredHandler(approvalRequestReject))
+	m.Add("1.10", "Get", "/apps/{app}/versions/{version}/sbom", AuthorizationRequiredHandler(appVersionSBOM))
 	m.Add("1.0", "Put", "/apps/{app}/units", AuthorizationRequiredHandler(addUnits))
 	m.Add("1.0", "Delete", "/apps/{app}/units", AuthorizationRequiredHandler(removeUnits))
 	registerUnitHandler := AuthorizationRequiredHandler(registerUnit)
@@ -299,6 +329,9 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.3", "Get", "/events/blocks", AuthorizationRequiredHandler(eventBlockList))
 	m.Add("1.3", "Post", "/events/blocks", AuthorizationRequiredHandler(eventBlockAdd))
 	m.Add("1.3", "Delete", "/events/blocks/{uuid}", AuthorizationRequiredHandler(eventBlockRemove))
+	m.Add("1.10", "Get", "/deploy-freezes", AuthorizationRequiredHandler(deployFreezeList))
+	m.Add("1.10", "Post", "/deploy-freezes", AuthorizationRequiredHandler(deployFreezeAdd))
+	m.Add("1.10", "Delete", "/deploy-freezes/{uuid}", AuthorizationRequiredHandler(deployFreezeRemove))
 	m.Add("1.1", "Get", "/events/kinds", AuthorizationRequiredHandler(kindList))
 	m.Add("1.1", "Get", "/events/{uuid}", AuthorizationRequiredHandler(eventInfo))
 	m.Add("1.1", "Post", "/events/{uuid}/cancel", AuthorizationRequiredHandler(eventCancel))
@@ -358,6 +391,14 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", "Delete", "/teams/{name}", AuthorizationRequiredHandler(removeTeam))
 	m.Add("1.6", "Put", "/teams/{name}", AuthorizationRequiredHandler(updateTeam))
 	m.Add("1.4", "Get", "/teams/{name}", AuthorizationRequiredHandler(teamInfo))
+	m.Add("1.10", "Get", "/teams/{name}/quota", AuthorizationRequiredHandler(getTeamQuota))
+	m.Add("1.10", "Put", "/teams/{name}/quota", AuthorizationRequiredHandler(changeTeamQuota))
+	m.Add("1.10", "Get", "/teams/{name}/registry-credentials", AuthorizationRequiredHandler(registryCredentialList))
+	m.Add("1.10", "Post", "/teams/{name}/registry-credentials", AuthorizationRequiredHandler(registryCredentialCreate))
+	m.Add("1.10", "Put", "/teams/{name}/registry-credentials/{registry}", AuthorizationRequiredHandler(registryCredentialUpdate))
+	m.Add("1.10", "Delete", "/teams/{name}/registry-credentials/{registry}", AuthorizationRequiredHandler(registryCredentialDelete))
+
+	m.Add("1.10", "Get", "/metering/report", AuthorizationRequiredHandler(meteringReport))
 
 	m.Add("1.0", "Post", "/swap", AuthorizationRequiredHandler(swap))
 
@@ -393,12 +434,30 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", "Delete", "/roles/{name}", AuthorizationRequiredHandler(removeRole))
 	m.Add("1.0", "Post", "/roles/{name}/permissions", AuthorizationRequiredHandler(addPermissions))
 	m.Add("1.0", "Delete", "/roles/{name}/permissions/{permission}", AuthorizationRequiredHandler(removePermissions))
+	m.Add("1.10", "Post", "/roles/{name}/conditions", AuthorizationRequiredHandler(addRoleCondition))
+	m.Add("1.10", "Delete", "/roles/{name}/conditions/{type}", AuthorizationRequiredHandler(removeRoleCondition))
 	m.Add("1.0", "Post", "/roles/{name}/user", AuthorizationRequiredHandler(assignRole))
 	m.Add("1.0", "Delete", "/roles/{name}/user/{email}", AuthorizationRequiredHandler(dissociateRole))
 	m.Add("1.0", "Get", "/role/default", AuthorizationRequiredHandler(listDefaultRoles))
 	m.Add("1.0", "Post", "/role/default", AuthorizationRequiredHandler(addDefaultRole))
 	m.Add("1.0", "Delete", "/role/default", AuthorizationRequiredHandler(removeDefaultRole))
 	m.Add("1.0", "Get", "/permissions", AuthorizationRequiredHandler(listPermissions))
+	m.Add("1.10", "Get", "/permissions/explain", AuthorizationRequiredHandler(explainPermission))
+	m.Add("1.10", "Get", "/permissions/review", AuthorizationRequiredHandler(accessReview))
+
+	m.Add("1.10", "Get", "/scim/v2/ServiceProviderConfig", scimHandler(scimServiceProviderConfig))
+	m.Add("1.10", "Get", "/scim/v2/Users", scimHandler(listSCIMUsers))
+	m.Add("1.10", "Post", "/scim/v2/Users", scimHandler(createSCIMUser))
+	m.Add("1.10", "Get", "/scim/v2/Users/{id}", scimHandler(getSCIMUser))
+	m.Add("1.10", "Put", "/scim/v2/Users/{id}", scimHandler(replaceSCIMUser))
+	m.Add("1.10", "Patch", "/scim/v2/Users/{id}", scimHandler(patchSCIMUser))
+	m.Add("1.10", "Delete", "/scim/v2/Users/{id}", scimHandler(deleteSCIMUser))
+	m.Add("1.10", "Get", "/scim/v2/Groups", scimHandler(listSCIMGroups))
+	m.Add("1.10", "Post", "/scim/v2/Groups", scimHandler(createSCIMGroup))
+	m.Add("1.10", "Get", "/scim/v2/Groups/{id}", scimHandler(getSCIMGroup))
+	m.Add("1.10", "Put", "/scim/v2/Groups/{id}", scimHandler(replaceSCIMGroup))
+	m.Add("1.10", "Patch", "/scim/v2/Groups/{id}", scimHandler(patchSCIMGroup))
+	m.Add("1.10", "Delete", "/scim/v2/Groups/{id}", scimHandler(deleteSCIMGroup))
 	m.Add("1.6", "Post", "/roles/{name}/token", AuthorizationRequiredHandler(assignRoleToToken))
 	m.Add("1.6", "Delete", "/roles/{name}/token/{token_id}", AuthorizationRequiredHandler(dissociateRoleFromToken))
 	m.Add("1.9", "Post", "/roles/{name}/group", AuthorizationRequiredHandler(assignRoleToGroup))
@@ -461,6 +520,7 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.3", "GET", "/provisioner/clusters", AuthorizationRequiredHandler(listClusters))
 	m.Add("1.8", "GET", "/provisioner/clusters/{name}", AuthorizationRequiredHandler(clusterInfo))
 	m.Add("1.3", "DELETE", "/provisioner/clusters/{name}", AuthorizationRequiredHandler(deleteCluster))
+	m.Add("1.10", "POST", "/provisioner/clusters/{name}/failover", AuthorizationRequiredHandler(failoverCluster))
 
 	m.Add("1.4", "GET", "/volumes", AuthorizationRequiredHandler(volumesList))
 	m.Add("1.4", "GET", "/volumes/{name}", AuthorizationRequiredHandler(volumeInfo))
@@ -679,6 +739,10 @@ func startServer(handler http.Handler) error {
 	if err != nil {
 		return errors.Wrap(err, "unable to initialize old image gc")
 	}
+	err = metering.Initialize()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize metering")
+	}
 	err = service.InitializeSync(bindAppsLister)
 	if err != nil {
 		return err
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"reflect"
 	"regexp"
@@ -49,6 +50,7 @@ import (
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	"github.com/tsuru/tsuru/types/cache"
 	permTypes "github.com/tsuru/tsuru/types/permission"
+	provTypes "github.com/tsuru/tsuru/types/provision"
 	"github.com/tsuru/tsuru/types/quota"
 	routerTypes "github.com/tsuru/tsuru/types/router"
 	"github.com/tsuru/tsuru/validation"
@@ -113,6 +115,23 @@ type App struct {
 	Error           string
 	Routers         []appTypes.AppRouter
 
+	// ProcessPlans holds the plans assigned to specific processes, replacing
+	// Plan for the units of these processes.
+	ProcessPlans map[string]appTypes.Plan `json:",omitempty" bson:",omitempty"`
+
+	// Dependencies are the apps and networks the app reaches, used to build
+	// its network policy.
+	Dependencies appTypes.AppDependencies
+
+	// RequiredApprovals is the number of approvals deploys, env changes and
+	// swaps on the app need before being executed. Zero means the app is not
+	// protected.
+	RequiredApprovals int `json:",omitempty" bson:",omitempty"`
+
+	// DeployHooks are HTTP endpoints called before and after deploys of the
+	// app, besides the hooks configured for its pool.
+	DeployHooks []appTypes.DeployHook `json:",omitempty" bson:",omitempty"`
+
 	// UUID is a v4 UUID lazily generated on the first call to GetUUID()
 	UUID string
 
@@ -120,6 +139,10 @@ type App struct {
 	// it is lazy generated on the first call to FillInternalAddresses
 	InternalAddresses []provision.AppInternalAddress `json:",omitempty" bson:"-"`
 
+	// ProcessContainers is not persisted, it is filled by
+	// FillProcessContainers from the latest successful version.
+	ProcessContainers map[string]ProcessContainers `json:",omitempty" bson:"-"`
+
 	Quota quota.Quota
 
 	ctx         context.Context
@@ -151,7 +174,7 @@ func (app *App) getBuilder() (builder.Builder, error) {
 	if err != nil {
 		return nil, err
 	}
-	app.builder, err = builder.GetForProvisioner(p)
+	app.builder, err = builder.GetForApp(app.Context(), p, app)
 	return app.builder, err
 }
 
@@ -254,6 +277,21 @@ func (app *App) MarshalJSON() ([]byte, error) {
 	if len(app.InternalAddresses) > 0 {
 		result["internalAddresses"] = app.InternalAddresses
 	}
+	if len(app.ProcessPlans) > 0 {
+		result["processPlans"] = app.ProcessPlans
+	}
+	if !app.Dependencies.Empty() {
+		result["dependencies"] = app.Dependencies
+	}
+	if app.RequiredApprovals > 0 {
+		result["requiredApprovals"] = app.RequiredApprovals
+	}
+	if len(app.DeployHooks) > 0 {
+		result["deployHooks"] = withoutSecrets(app.DeployHooks)
+	}
+	if len(app.ProcessContainers) > 0 {
+		result["processContainers"] = app.ProcessContainers
+	}
 	autoscale, err := app.AutoScaleInfo()
 	if err != nil {
 		errMsgs = append(errMsgs, fmt.Sprintf("unable to get autoscale info: %+v", err))
@@ -333,6 +371,11 @@ func CreateApp(ctx context.Context, app *App, user *auth.User) error {
 	if err != nil {
 		return err
 	}
+	// a new app must be able to run at least one unit within the team quota
+	err = servicemanager.TeamQuota.Check(ctx, app.TeamOwner, app.unitsResources(1))
+	if err != nil {
+		return err
+	}
 	actions := []*action.Action{
 		&reserveUserApp,
 		&insertApp,
@@ -419,6 +462,10 @@ func (app *App) Update(args UpdateAppArgs) (err error) {
 		app.Plan = *plan
 	}
 	app.Plan.MergeOverride(args.UpdateData.Plan.Override)
+	err = app.updateProcessPlans(args.UpdateData.ProcessPlans)
+	if err != nil {
+		return err
+	}
 	if teamOwner != "" {
 		team, errTeam := servicemanager.Team.FindByName(app.ctx, teamOwner)
 		if errTeam != nil {
@@ -453,6 +500,10 @@ func (app *App) Update(args UpdateAppArgs) (err error) {
 	if err != nil {
 		return err
 	}
+	err = app.checkTeamQuotaOnUpdate(&oldApp)
+	if err != nil {
+		return err
+	}
 	actions := []*action.Action{
 		&saveApp,
 	}
@@ -471,7 +522,7 @@ func (app *App) Update(args UpdateAppArgs) (err error) {
 			&provisionAppNewProvisioner,
 			&provisionAppAddUnits,
 			&destroyAppOldProvisioner)
-	} else if !reflect.DeepEqual(app.Plan, oldApp.Plan) && args.ShouldRestart {
+	} else if (!reflect.DeepEqual(app.Plan, oldApp.Plan) || !reflect.DeepEqual(app.ProcessPlans, oldApp.ProcessPlans)) && args.ShouldRestart {
 		actions = append(actions, &restartApp)
 	} else if app.Pool != oldApp.Pool {
 		actions = append(actions, &restartApp)
@@ -479,6 +530,82 @@ func (app *App) Update(args UpdateAppArgs) (err error) {
 	return action.NewPipeline(actions...).Execute(app.ctx, app, &oldApp, args.Writer)
 }
 
+// updateProcessPlans changes the plans of the processes in the update data.
+// An entry with an empty plan name changes only the overrides of the current
+// plan of the process, an entry without name nor overrides resets the
+// process to the app plan. The map is copied before being changed, so the
+// previous plans are kept in copies of the app.
+func (app *App) updateProcessPlans(processPlans map[string]appTypes.Plan) error {
+	if len(processPlans) == 0 {
+		return nil
+	}
+	processes, err := app.deployedProcesses()
+	if err != nil {
+		return err
+	}
+	plans := make(map[string]appTypes.Plan, len(app.ProcessPlans))
+	for process, plan := range app.ProcessPlans {
+		plans[process] = plan
+	}
+	for process, update := range processPlans {
+		if update.Name == "" && update.Override == (appTypes.PlanOverride{}) {
+			delete(plans, process)
+			continue
+		}
+		if _, ok := processes[process]; !ok {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("process %q not found in app %q", process, app.Name)}
+		}
+		plan := app.GetProcessPlan(process)
+		if update.Name != "" {
+			found, err := servicemanager.Plan.FindByName(app.ctx, update.Name)
+			if err != nil {
+				return err
+			}
+			plan = *found
+		}
+		plan.MergeOverride(update.Override)
+		plans[process] = plan
+	}
+	if len(plans) == 0 {
+		plans = nil
+	}
+	app.ProcessPlans = plans
+	return nil
+}
+
+// deployedProcesses returns the processes of the latest successful version
+// of the app.
+func (app *App) deployedProcesses() (map[string][]string, error) {
+	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(app.ctx, app)
+	if err != nil {
+		if err == appTypes.ErrNoVersionsAvailable {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return version.Processes()
+}
+
+// checkTeamQuotaOnUpdate ensures the team owning the app is able to allocate
+// the resources required by changes in the app plans or team owner.
+func (app *App) checkTeamQuotaOnUpdate(oldApp *App) error {
+	if app.TeamOwner == oldApp.TeamOwner && reflect.DeepEqual(app.Plan, oldApp.Plan) && reflect.DeepEqual(app.ProcessPlans, oldApp.ProcessPlans) {
+		return nil
+	}
+	units, err := oldApp.Units()
+	if err != nil {
+		return err
+	}
+	requested := app.quotaUnitsResources(units)
+	if app.TeamOwner == oldApp.TeamOwner {
+		current := oldApp.quotaUnitsResources(units)
+		requested.Units = 0
+		requested.Memory -= current.Memory
+		requested.CPUMilli -= current.CPUMilli
+	}
+	return servicemanager.TeamQuota.Check(app.ctx, app.TeamOwner, requested)
+}
+
 func validateVolumes(app *App) error {
 	volumes, err := volume.ListByApp(app.Name)
 	if err != nil {
@@ -611,6 +738,10 @@ func Delete(ctx context.Context, app *App, evt *event.Event, requestID string) e
 	if err != nil {
 		log.Errorf("failed to remove images from registry for app %s: %s", appName, err)
 	}
+	err = removeVersionSBOMs(appName)
+	if err != nil {
+		log.Errorf("failed to remove SBOMs for app %s: %s", appName, err)
+	}
 	if cleanProv, ok := prov.(provision.CleanImageProvisioner); ok {
 		var versions appTypes.AppVersions
 		versions, err = servicemanager.AppVersion.AppVersions(ctx, app)
@@ -692,6 +823,10 @@ func Delete(ctx context.Context, app *App, evt *event.Event, requestID string) e
 	if err != nil {
 		logErr("Unable to remove app from db", err)
 	}
+	err = removeDependencyFromApps(appName)
+	if err != nil {
+		logErr("Unable to remove app from dependencies of other apps", err)
+	}
 	// NOTE: some provisioners hold apps' info on their own (e.g. apps.tsuru.io
 	// CustomResource on Kubernetes). Deleting the app on provisioner as the last
 	// step of removal, we may give time enough to external components
@@ -767,6 +902,10 @@ func (app *App) AddUnits(n uint, process, versionStr string, w io.Writer) error
 	if err != nil {
 		return err
 	}
+	err = servicemanager.TeamQuota.Check(app.ctx, app.TeamOwner, app.processResources(process, int(n)))
+	if err != nil {
+		return err
+	}
 	w = app.withLogWriter(w)
 	err = action.NewPipeline(
 		&reserveUnitsToAdd,
@@ -995,12 +1134,7 @@ func (app *App) Revoke(team *authTypes.Team) error {
 			conn.Apps().Update(bson.M{"name": app.Name}, bson.M{"$addToSet": bson.M{"teams": team.Name}})
 			return err
 		}
-		canDeploy := permission.CheckFromPermList(perms, permission.PermAppDeploy,
-			append(permission.Contexts(permTypes.CtxTeam, app.Teams),
-				permission.Context(permTypes.CtxApp, app.Name),
-				permission.Context(permTypes.CtxPool, app.Pool),
-			)...,
-		)
+		canDeploy := permission.CheckFromPermList(perms, permission.PermAppDeploy, app.PermissionContexts()...)
 		if canDeploy {
 			continue
 		}
@@ -1013,6 +1147,16 @@ func (app *App) Revoke(team *authTypes.Team) error {
 	return nil
 }
 
+// PermissionContexts returns the contexts used to check permissions on the
+// app, including its tags.
+func (app *App) PermissionContexts() []permTypes.PermissionContext {
+	contexts := append(permission.Contexts(permTypes.CtxTeam, app.Teams),
+		permission.Context(permTypes.CtxApp, app.Name),
+		permission.Context(permTypes.CtxPool, app.Pool),
+	)
+	return append(contexts, permission.Contexts(permTypes.CtxTag, app.Tags)...)
+}
+
 // GetTeams returns a slice of teams that have access to the app.
 func (app *App) GetTeams() []authTypes.Team {
 	t, _ := servicemanager.Team.FindByNames(app.ctx, app.Teams)
@@ -1123,6 +1267,12 @@ func (app *App) validatePlan() error {
 		msg := fmt.Sprintf("App plan %q is not allowed on pool %q", app.Plan.Name, pool.Name)
 		return &tsuruErrors.ValidationError{Message: msg}
 	}
+	for process, plan := range app.ProcessPlans {
+		if !planSet.Includes(plan.Name) {
+			msg := fmt.Sprintf("Plan %q of process %q is not allowed on pool %q", plan.Name, process, pool.Name)
+			return &tsuruErrors.ValidationError{Message: msg}
+		}
+	}
 	return nil
 }
 
@@ -1417,17 +1567,46 @@ func (app *App) GetTeamsName() []string {
 
 // GetMemory returns the memory limit (in bytes) for the app.
 func (app *App) GetMemory() int64 {
-	if app.Plan.Override.Memory != nil {
-		return *app.Plan.Override.Memory
-	}
-	return app.Plan.Memory
+	return planMemory(app.Plan)
 }
 
 func (app *App) GetMilliCPU() int {
-	if app.Plan.Override.CPUMilli != nil {
-		return *app.Plan.Override.CPUMilli
+	return planMilliCPU(app.Plan)
+}
+
+// GetProcessMemory returns the memory limit (in bytes) for the units of a
+// process, using the plan of the process when one is assigned.
+func (app *App) GetProcessMemory(process string) int64 {
+	return planMemory(app.GetProcessPlan(process))
+}
+
+// GetProcessMilliCPU returns the cpu limit for the units of a process, using
+// the plan of the process when one is assigned.
+func (app *App) GetProcessMilliCPU(process string) int {
+	return planMilliCPU(app.GetProcessPlan(process))
+}
+
+// GetProcessPlan returns the plan of a process, which is the app plan unless
+// a plan was assigned to the process.
+func (app *App) GetProcessPlan(process string) appTypes.Plan {
+	if plan, ok := app.ProcessPlans[process]; ok {
+		return plan
 	}
-	return app.Plan.CPUMilli
+	return app.Plan
+}
+
+func planMemory(plan appTypes.Plan) int64 {
+	if plan.Override.Memory != nil {
+		return *plan.Override.Memory
+	}
+	return plan.Memory
+}
+
+func planMilliCPU(plan appTypes.Plan) int {
+	if plan.Override.CPUMilli != nil {
+		return *plan.Override.CPUMilli
+	}
+	return plan.CPUMilli
 }
 
 // GetSwap returns the swap limit (in bytes) for the app.
@@ -1477,6 +1656,11 @@ func (app *App) GetQuotaInUse() (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	return countQuotaUnits(units), nil
+}
+
+// countQuotaUnits returns the number of units accounted in quotas.
+func countQuotaUnits(units []provision.Unit) int {
 	counter := 0
 	for _, u := range units {
 		switch u.Status {
@@ -1484,7 +1668,7 @@ func (app *App) GetQuotaInUse() (int, error) {
 			counter++
 		}
 	}
-	return counter, nil
+	return counter
 }
 
 func (app *App) GetQuota() (*quota.Quota, error) {
@@ -1712,6 +1896,10 @@ func (app *App) AddInstance(addArgs bind.AddInstanceArgs) error {
 	if err != nil {
 		return err
 	}
+	err = app.EnsureNetworkPolicy()
+	if err != nil {
+		log.Errorf("unable to update network policy of app %s after binding: %s", app.Name, err)
+	}
 	if addArgs.ShouldRestart {
 		return app.restartIfUnits(addArgs.Writer)
 	}
@@ -1743,6 +1931,10 @@ func (app *App) RemoveInstance(removeArgs bind.RemoveInstanceArgs) error {
 	if err != nil {
 		return err
 	}
+	err = app.EnsureNetworkPolicy()
+	if err != nil {
+		log.Errorf("unable to update network policy of app %s after unbinding: %s", app.Name, err)
+	}
 	if removeArgs.ShouldRestart {
 		return app.restartIfUnits(removeArgs.Writer)
 	}
@@ -2114,6 +2306,34 @@ func (app *App) SetUpdatePlatform(check bool) error {
 	)
 }
 
+// IsProtected returns whether operations on the app must be approved before
+// being executed.
+func (app *App) IsProtected() bool {
+	return app.RequiredApprovals > 0
+}
+
+// SetRequiredApprovals updates the number of approvals required by protected
+// operations on the app. Setting it to zero removes the protection.
+func (app *App) SetRequiredApprovals(n int) error {
+	if n < 0 {
+		return &tsuruErrors.ValidationError{Message: "required approvals must not be negative"}
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Apps().Update(
+		bson.M{"name": app.Name},
+		bson.M{"$set": bson.M{"requiredapprovals": n}},
+	)
+	if err != nil {
+		return err
+	}
+	app.RequiredApprovals = n
+	return nil
+}
+
 func (app *App) GetUpdatePlatform() bool {
 	return app.UpdatePlatform
 }
@@ -2512,7 +2732,58 @@ func RenameTeam(ctx context.Context, oldName, newName string) error {
 	return err
 }
 
+// ProcessContainers holds the init containers, sidecars and shared volumes of
+// a process.
+type ProcessContainers struct {
+	InitContainers []provTypes.TsuruYamlKubernetesContainer    `json:"initContainers,omitempty"`
+	Sidecars       []provTypes.TsuruYamlKubernetesContainer    `json:"sidecars,omitempty"`
+	SharedVolumes  []provTypes.TsuruYamlKubernetesSharedVolume `json:"sharedVolumes,omitempty"`
+}
+
+// FillProcessContainers sets the extra containers declared in the
+// tsuru.yaml of the latest successful version of the app, indexed by
+// process name.
+func (app *App) FillProcessContainers() error {
+	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(app.ctx, app)
+	if err != nil {
+		if err == appTypes.ErrNoVersionsAvailable {
+			err = nil
+		}
+		return err
+	}
+	yamlData, err := version.TsuruYamlData()
+	if err != nil {
+		return err
+	}
+	if yamlData.Kubernetes == nil {
+		return nil
+	}
+	result := map[string]ProcessContainers{}
+	for _, group := range yamlData.Kubernetes.Groups {
+		for process, config := range group {
+			if len(config.InitContainers) == 0 && len(config.Sidecars) == 0 && len(config.SharedVolumes) == 0 {
+				continue
+			}
+			result[process] = ProcessContainers{
+				InitContainers: config.InitContainers,
+				Sidecars:       config.Sidecars,
+				SharedVolumes:  config.SharedVolumes,
+			}
+		}
+	}
+	app.ProcessContainers = result
+	return nil
+}
+
 func (app *App) GetHealthcheckData() (routerTypes.HealthcheckData, error) {
+	return app.healthcheckData(true)
+}
+
+// healthcheckData returns the healthcheck of the latest version of the app.
+// Routers only get a TCP healthcheck when provisionerHC is set and the
+// provisioner of the app handles healthchecks itself, unless the web process
+// declares an http readiness probe.
+func (app *App) healthcheckData(provisionerHC bool) (routerTypes.HealthcheckData, error) {
 	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(app.ctx, app)
 	if err != nil {
 		if err == appTypes.ErrNoVersionsAvailable {
@@ -2524,18 +2795,24 @@ func (app *App) GetHealthcheckData() (routerTypes.HealthcheckData, error) {
 	if err != nil {
 		return routerTypes.HealthcheckData{}, err
 	}
-	prov, err := app.getProvisioner()
+	webProcess, err := version.WebProcess()
 	if err != nil {
 		return routerTypes.HealthcheckData{}, err
 	}
-	if hcProv, ok := prov.(provision.HCProvisioner); ok {
-		if hcProv.HandlesHC() {
-			return routerTypes.HealthcheckData{
-				TCPOnly: true,
-			}, nil
+	if provisionerHC && !yamlData.RouterHCFromProbe(webProcess) {
+		prov, err := app.getProvisioner()
+		if err != nil {
+			return routerTypes.HealthcheckData{}, err
+		}
+		if hcProv, ok := prov.(provision.HCProvisioner); ok {
+			if hcProv.HandlesHC() {
+				return routerTypes.HealthcheckData{
+					TCPOnly: true,
+				}, nil
+			}
 		}
 	}
-	return yamlData.ToRouterHC(), nil
+	return yamlData.ToRouterHC(webProcess), nil
 }
 
 func validateEnv(envName string) error {
@@ -2554,6 +2831,12 @@ func (app *App) SetRoutable(ctx context.Context, version appTypes.AppVersion, is
 	if !ok {
 		return errors.Errorf("provisioner %v does not support setting versions routable", prov.GetName())
 	}
+	if isRoutable {
+		err = checkVersionVulnerabilities(ctx, app, version, ioutil.Discard)
+		if err != nil {
+			return err
+		}
+	}
 	return rprov.ToggleRoutable(ctx, app, version, isRoutable)
 }
 
@@ -2653,9 +2936,33 @@ func (app *App) AutoScale(spec provision.AutoScaleSpec) error {
 	if !ok {
 		return errors.Errorf("provisioner %q does not support native autoscaling", prov.GetName())
 	}
+	err = app.checkTeamQuotaForAutoScale(spec)
+	if err != nil {
+		return err
+	}
 	return autoscaleProv.SetAutoScale(app.ctx, app, spec)
 }
 
+// checkTeamQuotaForAutoScale ensures the team owning the app is able to
+// allocate the units required to scale the process up to its max units.
+func (app *App) checkTeamQuotaForAutoScale(spec provision.AutoScaleSpec) error {
+	units, err := app.Units()
+	if err != nil {
+		return err
+	}
+	var processUnits []provision.Unit
+	for _, u := range units {
+		if spec.Process == "" || u.ProcessName == spec.Process {
+			processUnits = append(processUnits, u)
+		}
+	}
+	missing := int(spec.MaxUnits) - countQuotaUnits(processUnits)
+	if missing <= 0 {
+		return nil
+	}
+	return servicemanager.TeamQuota.Check(app.ctx, app.TeamOwner, app.processResources(spec.Process, missing))
+}
+
 func (app *App) RemoveAutoScale(process string) error {
 	prov, err := app.getProvisioner()
 	if err != nil {
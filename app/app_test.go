@@ -48,6 +48,7 @@ import (
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	"github.com/tsuru/tsuru/types/cache"
 	permTypes "github.com/tsuru/tsuru/types/permission"
+	provTypes "github.com/tsuru/tsuru/types/provision"
 	"github.com/tsuru/tsuru/types/quota"
 	routerTypes "github.com/tsuru/tsuru/types/router"
 	"github.com/tsuru/tsuru/volume"
@@ -3876,6 +3877,21 @@ func (s *S) TestGetMemory(c *check.C) {
 	c.Assert(a.GetMemory(), check.Equals, a.Plan.Memory)
 }
 
+func (s *S) TestGetProcessMemoryAndMilliCPU(c *check.C) {
+	memory := int64(64)
+	a := App{
+		Plan: appTypes.Plan{Name: "medium", Memory: 256, CPUMilli: 500},
+		ProcessPlans: map[string]appTypes.Plan{
+			"worker": {Name: "small", Memory: 128, CPUMilli: 100, Override: appTypes.PlanOverride{Memory: &memory}},
+		},
+	}
+	c.Assert(a.GetProcessMemory("web"), check.Equals, int64(256))
+	c.Assert(a.GetProcessMilliCPU("web"), check.Equals, 500)
+	c.Assert(a.GetProcessMemory("worker"), check.Equals, int64(64))
+	c.Assert(a.GetProcessMilliCPU("worker"), check.Equals, 100)
+	c.Assert(a.GetProcessPlan("worker").Name, check.Equals, "small")
+}
+
 func (s *S) TestGetSwap(c *check.C) {
 	a := App{Plan: appTypes.Plan{Swap: 20}}
 	c.Assert(a.GetSwap(), check.Equals, a.Plan.Swap)
@@ -4838,6 +4854,112 @@ func (s *S) TestUpdatePlanShouldRestart(c *check.C) {
 	c.Assert(s.provisioner.Restarts(dbApp, ""), check.Equals, 1)
 }
 
+func (s *S) TestUpdateProcessPlans(c *check.C) {
+	plans := map[string]appTypes.Plan{
+		"small":  {Name: "small", CPUMilli: 100, Memory: 134217728},
+		"medium": {Name: "medium", CPUMilli: 200, Memory: 268435456},
+	}
+	s.mockService.Plan.OnFindByName = func(name string) (*appTypes.Plan, error) {
+		plan, ok := plans[name]
+		c.Assert(ok, check.Equals, true)
+		return &plan, nil
+	}
+	s.mockService.Plan.OnList = func() ([]appTypes.Plan, error) {
+		return []appTypes.Plan{s.defaultPlan, plans["small"], plans["medium"]}, nil
+	}
+	var quotaChecks int
+	s.mockService.TeamQuota.OnCheck = func(team string, requested quota.TeamResources) error {
+		quotaChecks++
+		return nil
+	}
+	a := App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	err = version.AddData(appTypes.AddVersionDataArgs{
+		Processes: map[string][]string{"web": {"python app.py"}, "worker": {"python worker.py"}},
+	})
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 1, "worker", version, nil)
+	updateData := App{ProcessPlans: map[string]appTypes.Plan{"worker": {Name: "small"}}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer), ShouldRestart: true})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Plan, check.DeepEquals, s.defaultPlan)
+	c.Assert(dbApp.ProcessPlans, check.DeepEquals, map[string]appTypes.Plan{"worker": plans["small"]})
+	c.Assert(s.provisioner.Restarts(dbApp, ""), check.Equals, 1)
+	c.Assert(quotaChecks, check.Equals, 1)
+	updateData = App{ProcessPlans: map[string]appTypes.Plan{"worker": {Name: "medium"}}}
+	err = dbApp.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer), ShouldRestart: true})
+	c.Assert(err, check.IsNil)
+	dbApp, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ProcessPlans, check.DeepEquals, map[string]appTypes.Plan{"worker": plans["medium"]})
+	c.Assert(s.provisioner.Restarts(dbApp, ""), check.Equals, 2)
+	c.Assert(quotaChecks, check.Equals, 2)
+	memory := int64(536870912)
+	updateData = App{ProcessPlans: map[string]appTypes.Plan{"worker": {Override: appTypes.PlanOverride{Memory: &memory}}}}
+	err = dbApp.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	dbApp, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.GetProcessPlan("worker").Name, check.Equals, "medium")
+	c.Assert(dbApp.GetProcessMemory("worker"), check.Equals, memory)
+	updateData = App{ProcessPlans: map[string]appTypes.Plan{"worker": {}}}
+	err = dbApp.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	dbApp, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ProcessPlans, check.IsNil)
+}
+
+func (s *S) TestUpdateProcessPlansUnknownProcess(c *check.C) {
+	plan := appTypes.Plan{Name: "small", CPUMilli: 100, Memory: 134217728}
+	s.mockService.Plan.OnFindByName = func(name string) (*appTypes.Plan, error) {
+		return &plan, nil
+	}
+	a := App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	err = version.AddData(appTypes.AddVersionDataArgs{
+		Processes: map[string][]string{"web": {"python app.py"}},
+	})
+	c.Assert(err, check.IsNil)
+	updateData := App{ProcessPlans: map[string]appTypes.Plan{"worker": {Name: "small"}}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.ErrorMatches, `process "worker" not found in app "my-test-app"`)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ProcessPlans, check.IsNil)
+}
+
+func (s *S) TestUpdateProcessPlansNotAllowedInPool(c *check.C) {
+	plan := appTypes.Plan{Name: "small", CPUMilli: 100, Memory: 134217728}
+	s.mockService.Plan.OnFindByName = func(name string) (*appTypes.Plan, error) {
+		return &plan, nil
+	}
+	err := pool.SetPoolConstraint(&pool.PoolConstraint{
+		PoolExpr:  "pool1",
+		Field:     pool.ConstraintTypePlan,
+		Values:    []string{plan.Name},
+		Blacklist: true,
+	})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	err = version.AddData(appTypes.AddVersionDataArgs{
+		Processes: map[string][]string{"worker": {"python worker.py"}},
+	})
+	c.Assert(err, check.IsNil)
+	updateData := App{ProcessPlans: map[string]appTypes.Plan{"worker": {Name: "small"}}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.ErrorMatches, `Plan "small" of process "worker" is not allowed on pool "pool1"`)
+}
+
 func (s *S) TestUpdatePlanWithConstraint(c *check.C) {
 	plan := appTypes.Plan{Name: "something", CpuShare: 100, Memory: 268435456}
 	s.mockService.Plan.OnFindByName = func(name string) (*appTypes.Plan, error) {
@@ -5466,6 +5588,43 @@ func (s *S) TestGetHealthcheckData(c *check.C) {
 	})
 }
 
+func (s *S) TestFillProcessContainers(c *check.C) {
+	a := App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.FillProcessContainers()
+	c.Assert(err, check.IsNil)
+	c.Assert(a.ProcessContainers, check.HasLen, 0)
+	version := newSuccessfulAppVersion(c, &a)
+	err = version.AddData(appTypes.AddVersionDataArgs{
+		Processes: map[string][]string{"web": {"python app.py"}, "worker": {"python worker.py"}},
+		CustomData: map[string]interface{}{
+			"kubernetes": map[string]interface{}{
+				"groups": map[string]interface{}{
+					"pod1": map[string]interface{}{
+						"web": map[string]interface{}{
+							"sidecars": []interface{}{
+								map[string]interface{}{"name": "proxy", "image": "envoyproxy/envoy"},
+							},
+						},
+						"worker": map[string]interface{}{},
+					},
+				},
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	err = a.FillProcessContainers()
+	c.Assert(err, check.IsNil)
+	c.Assert(a.ProcessContainers, check.DeepEquals, map[string]ProcessContainers{
+		"web": {
+			Sidecars: []provTypes.TsuruYamlKubernetesContainer{
+				{Name: "proxy", Image: "envoyproxy/envoy"},
+			},
+		},
+	})
+}
+
 type hcProv struct {
 	provisiontest.FakeProvisioner
 }
@@ -5493,6 +5652,38 @@ func (s *S) TestGetHealthcheckDataHCProvisioner(c *check.C) {
 	})
 }
 
+func (s *S) TestGetHealthcheckDataHCProvisionerReadinessProbe(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "hcprov"
+	provision.Register("hcprov", func() (provision.Provisioner, error) {
+		return &hcProv{}, nil
+	})
+	defer provision.Unregister("hcprov")
+	a := App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err := s.conn.Apps().Insert(a)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	err = version.AddData(appTypes.AddVersionDataArgs{
+		Processes: map[string][]string{"web": {"python app.py"}},
+		CustomData: map[string]interface{}{
+			"probes": map[string]interface{}{
+				"web": map[string]interface{}{
+					"readiness": map[string]interface{}{
+						"http": map[string]interface{}{"path": "/ready"},
+					},
+				},
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	hcData, err := a.GetHealthcheckData()
+	c.Assert(err, check.IsNil)
+	c.Assert(hcData, check.DeepEquals, routerTypes.HealthcheckData{
+		Path: "/ready",
+	})
+}
+
 func (s *S) TestAutoscaleWithAutoscaleProvisioner(c *check.C) {
 	oldProvisioner := provision.DefaultProvisioner
 	defer func() { provision.DefaultProvisioner = oldProvisioner }()
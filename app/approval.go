@@ -0,0 +1,348 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+)
+
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalExecuted = "executed"
+	ApprovalFailed   = "failed"
+	ApprovalExpired  = "expired"
+
+	defaultApprovalExpiration = 7 * 24 * time.Hour
+)
+
+var (
+	ErrApprovalRequestNotFound = errors.New("approval request not found")
+	ErrApprovalNotPending      = errors.New("approval request is not pending")
+	ErrApprovalAlreadyReviewed = errors.New("user already reviewed this approval request")
+	ErrApprovalSelfReview      = errors.New("users cannot review their own approval requests")
+	ErrApprovalConflict        = errors.New("approval request changed while being reviewed, try again")
+	ErrApprovalExpired         = errors.New("approval request expired")
+	ErrApprovalEncryptionKey   = errors.New("approvals:encryption-key must be set to request approvals of operations with private values")
+)
+
+// ApprovalVote is an approval or rejection given by a user to an approval
+// request.
+type ApprovalVote struct {
+	User    string    `json:"user"`
+	Comment string    `json:"comment,omitempty"`
+	Date    time.Time `json:"date"`
+}
+
+// ApprovalRequest is an operation on protected apps waiting to be approved.
+// The original HTTP request is stored so it can be replayed verbatim once the
+// required number of approvals is reached. Bodies holding private values are
+// stored encrypted and bodies are dropped once the request is done.
+type ApprovalRequest struct {
+	ID        bson.ObjectId            `json:"id" bson:"_id"`
+	Apps      []string                 `json:"apps"`
+	Operation string                   `json:"operation"`
+	Requester string                   `json:"requester"`
+	Input     []map[string]interface{} `json:"input,omitempty"`
+
+	Method        string `json:"-"`
+	URL           string `json:"-"`
+	ContentType   string `json:"-"`
+	Body          []byte `json:"-"`
+	BodyEncrypted bool   `json:"-"`
+
+	RequiredApprovals int            `json:"requiredApprovals"`
+	Approvals         []ApprovalVote `json:"approvals,omitempty"`
+	Rejection         *ApprovalVote  `json:"rejection,omitempty"`
+	Status            string         `json:"status"`
+	Error             string         `json:"error,omitempty"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	ExpiresAt         time.Time      `json:"expiresAt"`
+}
+
+// CreateApprovalRequest stores a new pending approval request, expiring after
+// the approvals:expiration config.
+func CreateApprovalRequest(req *ApprovalRequest) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	expiration, _ := config.GetDuration("approvals:expiration")
+	if expiration <= 0 {
+		expiration = defaultApprovalExpiration
+	}
+	req.ID = bson.NewObjectId()
+	req.Status = ApprovalPending
+	req.CreatedAt = time.Now().UTC()
+	req.ExpiresAt = req.CreatedAt.Add(expiration)
+	return conn.AppApprovalRequests().Insert(req)
+}
+
+// expireApprovalRequests moves the pending requests past their expiration to
+// the expired status, dropping their bodies.
+func expireApprovalRequests(conn *db.Storage) error {
+	_, err := conn.AppApprovalRequests().UpdateAll(bson.M{
+		"status":    ApprovalPending,
+		"expiresat": bson.M{"$lte": time.Now().UTC()},
+	}, bson.M{
+		"$set":   bson.M{"status": ApprovalExpired},
+		"$unset": bson.M{"body": ""},
+	})
+	return err
+}
+
+// GetApprovalRequest returns the approval request with the given id.
+func GetApprovalRequest(id bson.ObjectId) (*ApprovalRequest, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	err = expireApprovalRequests(conn)
+	if err != nil {
+		return nil, err
+	}
+	var req ApprovalRequest
+	err = conn.AppApprovalRequests().FindId(id).One(&req)
+	if err == mgo.ErrNotFound {
+		return nil, ErrApprovalRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ListApprovalRequests returns the approval requests involving the given app,
+// newest first, optionally filtered by status.
+func ListApprovalRequests(appName, status string) ([]ApprovalRequest, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	err = expireApprovalRequests(conn)
+	if err != nil {
+		return nil, err
+	}
+	query := bson.M{"apps": appName}
+	if status != "" {
+		query["status"] = status
+	}
+	var reqs []ApprovalRequest
+	err = conn.AppApprovalRequests().Find(query).Sort("-createdat").All(&reqs)
+	if err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// SetBody sets the body of the original request. Bodies holding private
+// values are encrypted with a key derived from the approvals:encryption-key
+// config.
+func (r *ApprovalRequest) SetBody(body []byte, private bool) error {
+	r.Body = body
+	r.BodyEncrypted = false
+	if !private || len(body) == 0 {
+		return nil
+	}
+	aead, err := approvalCipher()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	r.Body = aead.Seal(nonce, nonce, body, nil)
+	r.BodyEncrypted = true
+	return nil
+}
+
+// RequestBody returns the body of the original request, decrypting it when
+// needed.
+func (r *ApprovalRequest) RequestBody() ([]byte, error) {
+	if !r.BodyEncrypted {
+		return r.Body, nil
+	}
+	aead, err := approvalCipher()
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := aead.NonceSize()
+	if len(r.Body) < nonceSize {
+		return nil, errors.New("invalid encrypted approval request body")
+	}
+	body, err := aead.Open(nil, r.Body[:nonceSize], r.Body[nonceSize:], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decrypt approval request body")
+	}
+	return body, nil
+}
+
+func approvalCipher() (cipher.AEAD, error) {
+	secret, _ := config.GetString("approvals:encryption-key")
+	if secret == "" {
+		return nil, ErrApprovalEncryptionKey
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Involves returns whether the request involves the given app.
+func (r *ApprovalRequest) Involves(appName string) bool {
+	for _, a := range r.Apps {
+		if a == appName {
+			return true
+		}
+	}
+	return false
+}
+
+// Approve adds an approval to the request, moving it to the approved status
+// once the number of required approvals is reached. The returned bool
+// indicates whether the request is ready to be executed.
+func (r *ApprovalRequest) Approve(vote ApprovalVote) (bool, error) {
+	if err := r.canReview(vote); err != nil {
+		return false, err
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	vote.Date = time.Now().UTC()
+	ready := len(r.Approvals)+1 >= r.RequiredApprovals
+	update := bson.M{"$push": bson.M{"approvals": vote}}
+	if ready {
+		update["$set"] = bson.M{"status": ApprovalApproved}
+	}
+	err = conn.AppApprovalRequests().Update(r.pendingQuery(vote.User), update)
+	if err == mgo.ErrNotFound {
+		return false, r.reviewConflict(vote.User)
+	}
+	if err != nil {
+		return false, err
+	}
+	r.Approvals = append(r.Approvals, vote)
+	if ready {
+		r.Status = ApprovalApproved
+	}
+	return ready, nil
+}
+
+// Reject marks the request as rejected, it will never be executed.
+func (r *ApprovalRequest) Reject(vote ApprovalVote) error {
+	if err := r.canReview(vote); err != nil {
+		return err
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	vote.Date = time.Now().UTC()
+	err = conn.AppApprovalRequests().Update(r.pendingQuery(vote.User), bson.M{
+		"$set":   bson.M{"status": ApprovalRejected, "rejection": vote},
+		"$unset": bson.M{"body": ""},
+	})
+	if err == mgo.ErrNotFound {
+		return r.reviewConflict(vote.User)
+	}
+	if err != nil {
+		return err
+	}
+	r.Rejection = &vote
+	r.Status = ApprovalRejected
+	r.Body = nil
+	return nil
+}
+
+// SetResult records the outcome of executing an approved request, dropping
+// its body.
+func (r *ApprovalRequest) SetResult(execErr error) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	r.Status = ApprovalExecuted
+	r.Error = ""
+	if execErr != nil {
+		r.Status = ApprovalFailed
+		r.Error = execErr.Error()
+	}
+	r.Body = nil
+	return conn.AppApprovalRequests().UpdateId(r.ID, bson.M{
+		"$set":   bson.M{"status": r.Status, "error": r.Error},
+		"$unset": bson.M{"body": ""},
+	})
+}
+
+func (r *ApprovalRequest) canReview(vote ApprovalVote) error {
+	if vote.User == r.Requester {
+		return ErrApprovalSelfReview
+	}
+	if r.Status != ApprovalPending {
+		return ErrApprovalNotPending
+	}
+	if !r.ExpiresAt.IsZero() && time.Now().After(r.ExpiresAt) {
+		return ErrApprovalExpired
+	}
+	for _, v := range r.Approvals {
+		if v.User == vote.User {
+			return ErrApprovalAlreadyReviewed
+		}
+	}
+	return nil
+}
+
+// pendingQuery matches the request only while it is still pending, was not
+// reviewed by user and has no approvals besides the ones already loaded, so
+// concurrent reviews cannot both reach the required number of approvals.
+func (r *ApprovalRequest) pendingQuery(user string) bson.M {
+	return bson.M{
+		"_id":            r.ID,
+		"status":         ApprovalPending,
+		"approvals.user": bson.M{"$ne": user},
+		fmt.Sprintf("approvals.%d", len(r.Approvals)): bson.M{"$exists": false},
+	}
+}
+
+// reviewConflict tells why a conditional review update matched nothing,
+// which happens when another review raced with this one.
+func (r *ApprovalRequest) reviewConflict(user string) error {
+	current, err := GetApprovalRequest(r.ID)
+	if err != nil {
+		return err
+	}
+	if current.Status != ApprovalPending {
+		return ErrApprovalNotPending
+	}
+	for _, v := range current.Approvals {
+		if v.User == user {
+			return ErrApprovalAlreadyReviewed
+		}
+	}
+	return ErrApprovalConflict
+}
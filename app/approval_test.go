@@ -0,0 +1,170 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestSetRequiredApprovals(c *check.C) {
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.IsProtected(), check.Equals, false)
+	err = a.SetRequiredApprovals(2)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.IsProtected(), check.Equals, true)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.RequiredApprovals, check.Equals, 2)
+	err = a.SetRequiredApprovals(-1)
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+}
+
+func (s *S) TestApprovalRequestApprove(c *check.C) {
+	req := ApprovalRequest{Apps: []string{"myapp"}, Operation: "deploy", Requester: "dev@tsuru.io", RequiredApprovals: 2}
+	err := CreateApprovalRequest(&req)
+	c.Assert(err, check.IsNil)
+	c.Assert(req.Status, check.Equals, ApprovalPending)
+	_, err = req.Approve(ApprovalVote{User: "dev@tsuru.io"})
+	c.Assert(err, check.Equals, ErrApprovalSelfReview)
+	ready, err := req.Approve(ApprovalVote{User: "lead1@tsuru.io", Comment: "lgtm"})
+	c.Assert(err, check.IsNil)
+	c.Assert(ready, check.Equals, false)
+	_, err = req.Approve(ApprovalVote{User: "lead1@tsuru.io"})
+	c.Assert(err, check.Equals, ErrApprovalAlreadyReviewed)
+	ready, err = req.Approve(ApprovalVote{User: "lead2@tsuru.io"})
+	c.Assert(err, check.IsNil)
+	c.Assert(ready, check.Equals, true)
+	dbReq, err := GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, ApprovalApproved)
+	c.Assert(dbReq.Approvals, check.HasLen, 2)
+	c.Assert(dbReq.Approvals[0].User, check.Equals, "lead1@tsuru.io")
+	c.Assert(dbReq.Approvals[0].Comment, check.Equals, "lgtm")
+	_, err = dbReq.Approve(ApprovalVote{User: "lead3@tsuru.io"})
+	c.Assert(err, check.Equals, ErrApprovalNotPending)
+}
+
+func (s *S) TestApprovalRequestApproveConcurrent(c *check.C) {
+	req := ApprovalRequest{Apps: []string{"myapp"}, Operation: "deploy", Requester: "dev@tsuru.io", RequiredApprovals: 1}
+	err := CreateApprovalRequest(&req)
+	c.Assert(err, check.IsNil)
+	stale := req
+	ready, err := req.Approve(ApprovalVote{User: "lead1@tsuru.io"})
+	c.Assert(err, check.IsNil)
+	c.Assert(ready, check.Equals, true)
+	_, err = stale.Approve(ApprovalVote{User: "lead2@tsuru.io"})
+	c.Assert(err, check.Equals, ErrApprovalNotPending)
+}
+
+func (s *S) TestApprovalRequestReject(c *check.C) {
+	req := ApprovalRequest{Apps: []string{"myapp"}, Operation: "deploy", Requester: "dev@tsuru.io", RequiredApprovals: 2}
+	err := CreateApprovalRequest(&req)
+	c.Assert(err, check.IsNil)
+	err = req.Reject(ApprovalVote{User: "lead1@tsuru.io", Comment: "not now"})
+	c.Assert(err, check.IsNil)
+	dbReq, err := GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, ApprovalRejected)
+	c.Assert(dbReq.Rejection.User, check.Equals, "lead1@tsuru.io")
+	c.Assert(dbReq.Rejection.Comment, check.Equals, "not now")
+	_, err = dbReq.Approve(ApprovalVote{User: "lead2@tsuru.io"})
+	c.Assert(err, check.Equals, ErrApprovalNotPending)
+}
+
+func (s *S) TestApprovalRequestSetResult(c *check.C) {
+	req := ApprovalRequest{Apps: []string{"myapp"}, Operation: "deploy", Requester: "dev@tsuru.io", RequiredApprovals: 1}
+	err := CreateApprovalRequest(&req)
+	c.Assert(err, check.IsNil)
+	err = req.SetResult(errors.New("deploy failed"))
+	c.Assert(err, check.IsNil)
+	dbReq, err := GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, ApprovalFailed)
+	c.Assert(dbReq.Error, check.Equals, "deploy failed")
+	err = req.SetResult(nil)
+	c.Assert(err, check.IsNil)
+	dbReq, err = GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, ApprovalExecuted)
+	c.Assert(dbReq.Error, check.Equals, "")
+	c.Assert(dbReq.Body, check.IsNil)
+}
+
+func (s *S) TestApprovalRequestExpiration(c *check.C) {
+	config.Set("approvals:expiration", "1ms")
+	defer config.Unset("approvals:expiration")
+	req := ApprovalRequest{Apps: []string{"myapp"}, Operation: "deploy", Requester: "dev@tsuru.io", RequiredApprovals: 1, Body: []byte("image=myimage")}
+	err := CreateApprovalRequest(&req)
+	c.Assert(err, check.IsNil)
+	c.Assert(req.ExpiresAt, check.Equals, req.CreatedAt.Add(time.Millisecond))
+	time.Sleep(10 * time.Millisecond)
+	_, err = req.Approve(ApprovalVote{User: "lead@tsuru.io"})
+	c.Assert(err, check.Equals, ErrApprovalExpired)
+	dbReq, err := GetApprovalRequest(req.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbReq.Status, check.Equals, ApprovalExpired)
+	c.Assert(dbReq.Body, check.IsNil)
+	_, err = dbReq.Approve(ApprovalVote{User: "lead@tsuru.io"})
+	c.Assert(err, check.Equals, ErrApprovalNotPending)
+	reqs, err := ListApprovalRequests("myapp", ApprovalExpired)
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.HasLen, 1)
+}
+
+func (s *S) TestApprovalRequestBodyEncryption(c *check.C) {
+	req := ApprovalRequest{}
+	err := req.SetBody([]byte("Envs.0.Value=secret&Private=true"), true)
+	c.Assert(err, check.Equals, ErrApprovalEncryptionKey)
+	err = req.SetBody([]byte("Envs.0.Value=localhost"), false)
+	c.Assert(err, check.IsNil)
+	c.Assert(req.BodyEncrypted, check.Equals, false)
+	body, err := req.RequestBody()
+	c.Assert(err, check.IsNil)
+	c.Assert(string(body), check.Equals, "Envs.0.Value=localhost")
+	config.Set("approvals:encryption-key", "approval-secret")
+	defer config.Unset("approvals:encryption-key")
+	err = req.SetBody([]byte("Envs.0.Value=secret&Private=true"), true)
+	c.Assert(err, check.IsNil)
+	c.Assert(req.BodyEncrypted, check.Equals, true)
+	c.Assert(string(req.Body), check.Not(check.Matches), `(?s).*secret.*`)
+	body, err = req.RequestBody()
+	c.Assert(err, check.IsNil)
+	c.Assert(string(body), check.Equals, "Envs.0.Value=secret&Private=true")
+	config.Set("approvals:encryption-key", "other-secret")
+	_, err = req.RequestBody()
+	c.Assert(err, check.ErrorMatches, "unable to decrypt approval request body: .*")
+}
+
+func (s *S) TestListApprovalRequests(c *check.C) {
+	req1 := ApprovalRequest{Apps: []string{"app1"}, Operation: "deploy", Requester: "dev@tsuru.io", RequiredApprovals: 1}
+	err := CreateApprovalRequest(&req1)
+	c.Assert(err, check.IsNil)
+	req2 := ApprovalRequest{Apps: []string{"app1", "app2"}, Operation: "swap", Requester: "dev@tsuru.io", RequiredApprovals: 1}
+	err = CreateApprovalRequest(&req2)
+	c.Assert(err, check.IsNil)
+	err = req1.Reject(ApprovalVote{User: "lead@tsuru.io"})
+	c.Assert(err, check.IsNil)
+	reqs, err := ListApprovalRequests("app1", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.HasLen, 2)
+	reqs, err = ListApprovalRequests("app1", ApprovalPending)
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.HasLen, 1)
+	c.Assert(reqs[0].ID, check.Equals, req2.ID)
+	reqs, err = ListApprovalRequests("app2", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.HasLen, 1)
+	_, err = GetApprovalRequest(bson.NewObjectId())
+	c.Assert(err, check.Equals, ErrApprovalRequestNotFound)
+}
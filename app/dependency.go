@@ -0,0 +1,285 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/provision"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+// DependenciesInfo holds the dependencies of an app along with the internal
+// addresses of the apps it depends on.
+type DependenciesInfo struct {
+	appTypes.AppDependencies
+	InternalAddresses map[string][]provision.AppInternalAddress `json:"internalAddresses,omitempty"`
+}
+
+// DependenciesInfo returns the dependencies of the app and the internal
+// addresses of the apps it depends on.
+func (app *App) DependenciesInfo() (DependenciesInfo, error) {
+	info := DependenciesInfo{AppDependencies: app.Dependencies}
+	for _, name := range app.Dependencies.Apps {
+		dep, err := GetByName(app.Context(), name)
+		if err != nil {
+			if err == appTypes.ErrAppNotFound {
+				continue
+			}
+			return DependenciesInfo{}, err
+		}
+		err = dep.FillInternalAddresses()
+		if err != nil {
+			return DependenciesInfo{}, err
+		}
+		if len(dep.InternalAddresses) == 0 {
+			continue
+		}
+		if info.InternalAddresses == nil {
+			info.InternalAddresses = map[string][]provision.AppInternalAddress{}
+		}
+		info.InternalAddresses[name] = dep.InternalAddresses
+	}
+	return info, nil
+}
+
+// AddDependency declares that the app reaches the given apps and networks,
+// updating the network policy of the app and of the apps it now depends on.
+func (app *App) AddDependency(deps appTypes.AppDependencies) error {
+	err := deps.Validate()
+	if err != nil {
+		return err
+	}
+	for _, name := range deps.Apps {
+		if name == app.Name {
+			return &tsuruErrors.ValidationError{Message: "an app cannot depend on itself"}
+		}
+		if _, err = GetByName(app.Context(), name); err != nil {
+			return err
+		}
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	update := bson.M{}
+	if len(deps.Apps) > 0 {
+		update["dependencies.apps"] = bson.M{"$each": deps.Apps}
+	}
+	if len(deps.CIDRs) > 0 {
+		update["dependencies.cidrs"] = bson.M{"$each": deps.CIDRs}
+	}
+	if len(update) == 0 {
+		return nil
+	}
+	err = conn.Apps().Update(bson.M{"name": app.Name}, bson.M{"$addToSet": update})
+	if err != nil {
+		return err
+	}
+	app.Dependencies.Apps = appendMissing(app.Dependencies.Apps, deps.Apps)
+	app.Dependencies.CIDRs = appendMissing(app.Dependencies.CIDRs, deps.CIDRs)
+	return app.ensureNetworkPolicies(deps.Apps)
+}
+
+// RemoveDependency removes apps and networks from the dependencies of the
+// app, updating the network policy of the app and of the removed apps.
+func (app *App) RemoveDependency(deps appTypes.AppDependencies) error {
+	for _, name := range deps.Apps {
+		if !containsString(app.Dependencies.Apps, name) {
+			return appTypes.ErrDependencyNotFound
+		}
+	}
+	for _, cidr := range deps.CIDRs {
+		if !containsString(app.Dependencies.CIDRs, cidr) {
+			return appTypes.ErrDependencyNotFound
+		}
+	}
+	if deps.Empty() {
+		return nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	update := bson.M{}
+	if len(deps.Apps) > 0 {
+		update["dependencies.apps"] = deps.Apps
+	}
+	if len(deps.CIDRs) > 0 {
+		update["dependencies.cidrs"] = deps.CIDRs
+	}
+	err = conn.Apps().Update(bson.M{"name": app.Name}, bson.M{"$pullAll": update})
+	if err != nil {
+		return err
+	}
+	app.Dependencies.Apps = removeAll(app.Dependencies.Apps, deps.Apps)
+	app.Dependencies.CIDRs = removeAll(app.Dependencies.CIDRs, deps.CIDRs)
+	return app.ensureNetworkPolicies(deps.Apps)
+}
+
+func (app *App) ensureNetworkPolicies(otherApps []string) error {
+	err := app.EnsureNetworkPolicy()
+	if err != nil {
+		return err
+	}
+	for _, name := range otherApps {
+		other, err := GetByName(app.Context(), name)
+		if err != nil {
+			if err == appTypes.ErrAppNotFound {
+				continue
+			}
+			return err
+		}
+		err = other.EnsureNetworkPolicy()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeDependencyFromApps(name string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Apps().UpdateAll(bson.M{"dependencies.apps": name}, bson.M{"$pull": bson.M{"dependencies.apps": name}})
+	return err
+}
+
+// NetworkPolicy returns the traffic allowed to and from the units of the
+// app: apps declaring a dependency on it may reach it, while it may only
+// reach the apps and networks it depends on and the addresses of its bound
+// service instances.
+func (app *App) NetworkPolicy() (provision.NetworkPolicy, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return provision.NetworkPolicy{}, err
+	}
+	defer conn.Close()
+	var dependents []App
+	err = conn.Apps().Find(bson.M{"dependencies.apps": app.Name}).Select(bson.M{"name": 1}).All(&dependents)
+	if err != nil {
+		return provision.NetworkPolicy{}, err
+	}
+	var policy provision.NetworkPolicy
+	for _, dependent := range dependents {
+		policy.IngressApps = append(policy.IngressApps, dependent.Name)
+	}
+	sort.Strings(policy.IngressApps)
+	policy.EgressApps = append(policy.EgressApps, app.Dependencies.Apps...)
+	sort.Strings(policy.EgressApps)
+	cidrs := map[string]struct{}{}
+	for _, cidr := range app.Dependencies.CIDRs {
+		cidrs[cidr] = struct{}{}
+	}
+	for _, cidr := range app.serviceCIDRs() {
+		cidrs[cidr] = struct{}{}
+	}
+	for cidr := range cidrs {
+		policy.EgressCIDRs = append(policy.EgressCIDRs, cidr)
+	}
+	sort.Strings(policy.EgressCIDRs)
+	return policy, nil
+}
+
+// EnsureNetworkPolicy applies the network policy of the app in its
+// provisioner. It's a no-op for provisioners without network policies.
+func (app *App) EnsureNetworkPolicy() error {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return err
+	}
+	policyProv, ok := prov.(provision.NetworkPolicyProvisioner)
+	if !ok {
+		return nil
+	}
+	policy, err := app.NetworkPolicy()
+	if err != nil {
+		return err
+	}
+	return policyProv.SetNetworkPolicy(app.Context(), app, policy)
+}
+
+// serviceCIDRs returns the IP addresses found in the environment variables
+// of the service instances bound to the app. Values are considered hosts
+// when they are URLs, host:port pairs or the value of variables named
+// *_HOST. Host names are not resolved, as the addresses seen by the API may
+// differ from the ones seen by the units, they must be allowed with the
+// egress CIDRs of the pool instead.
+func (app *App) serviceCIDRs() []string {
+	var cidrs []string
+	for _, se := range app.ServiceEnvs {
+		ip := net.ParseIP(hostFromEnv(se.Name, se.Value))
+		if ip != nil {
+			cidrs = append(cidrs, ipCIDR(ip))
+		}
+	}
+	return cidrs
+}
+
+func hostFromEnv(name, value string) string {
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+		return u.Hostname()
+	}
+	if host, port, err := net.SplitHostPort(value); err == nil {
+		if _, err = strconv.Atoi(port); err == nil {
+			return host
+		}
+	}
+	if strings.HasSuffix(name, "_HOST") {
+		return value
+	}
+	return ""
+}
+
+func ipCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.String() + "/32"
+	}
+	return ip.String() + "/128"
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func appendMissing(values, added []string) []string {
+	for _, v := range added {
+		if !containsString(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func removeAll(values, removed []string) []string {
+	var result []string
+	for _, v := range values {
+		if !containsString(removed, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
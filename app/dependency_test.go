@@ -0,0 +1,113 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) registerNetworkPolicyProvisioner() (*provisiontest.NetworkPolicyProvisioner, func()) {
+	oldProvisioner := provision.DefaultProvisioner
+	policyProv := &provisiontest.NetworkPolicyProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}
+	provision.DefaultProvisioner = "policyProv"
+	provision.Register("policyProv", func() (provision.Provisioner, error) {
+		return policyProv, nil
+	})
+	return policyProv, func() {
+		provision.DefaultProvisioner = oldProvisioner
+		provision.Unregister("policyProv")
+	}
+}
+
+func (s *S) TestAppAddDependency(c *check.C) {
+	policyProv, rollback := s.registerNetworkPolicyProvisioner()
+	defer rollback()
+	frontend := App{Name: "frontend", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &frontend, s.user)
+	c.Assert(err, check.IsNil)
+	backend := App{Name: "backend", Platform: "python", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &backend, s.user)
+	c.Assert(err, check.IsNil)
+	err = frontend.AddDependency(appTypes.AppDependencies{Apps: []string{"backend"}, CIDRs: []string{"10.0.0.0/8"}})
+	c.Assert(err, check.IsNil)
+	err = frontend.AddDependency(appTypes.AppDependencies{Apps: []string{"backend"}})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), "frontend")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Dependencies, check.DeepEquals, appTypes.AppDependencies{Apps: []string{"backend"}, CIDRs: []string{"10.0.0.0/8"}})
+	c.Assert(frontend.Dependencies, check.DeepEquals, dbApp.Dependencies)
+	c.Assert(policyProv.NetworkPolicy("frontend"), check.DeepEquals, provision.NetworkPolicy{
+		EgressApps:  []string{"backend"},
+		EgressCIDRs: []string{"10.0.0.0/8"},
+	})
+	c.Assert(policyProv.NetworkPolicy("backend"), check.DeepEquals, provision.NetworkPolicy{
+		IngressApps: []string{"frontend"},
+	})
+	err = frontend.RemoveDependency(appTypes.AppDependencies{Apps: []string{"backend"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(policyProv.NetworkPolicy("frontend"), check.DeepEquals, provision.NetworkPolicy{
+		EgressCIDRs: []string{"10.0.0.0/8"},
+	})
+	c.Assert(policyProv.NetworkPolicy("backend"), check.DeepEquals, provision.NetworkPolicy{})
+	err = frontend.RemoveDependency(appTypes.AppDependencies{Apps: []string{"backend"}})
+	c.Assert(err, check.Equals, appTypes.ErrDependencyNotFound)
+}
+
+func (s *S) TestAppAddDependencyInvalid(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.AddDependency(appTypes.AppDependencies{Apps: []string{"myapp"}})
+	c.Assert(err, check.ErrorMatches, "an app cannot depend on itself")
+	err = a.AddDependency(appTypes.AppDependencies{Apps: []string{"unknown"}})
+	c.Assert(err, check.Equals, appTypes.ErrAppNotFound)
+	err = a.AddDependency(appTypes.AppDependencies{CIDRs: []string{"10.0.0.1"}})
+	c.Assert(err, check.ErrorMatches, "invalid cidr 10.0.0.1")
+}
+
+func (s *S) TestAppNetworkPolicyServiceHosts(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	a.ServiceEnvs = []bind.ServiceEnvVar{
+		{EnvVar: bind.EnvVar{Name: "MYSQL_HOST", Value: "mysql.example.com"}, ServiceName: "mysql", InstanceName: "db"},
+		{EnvVar: bind.EnvVar{Name: "MYSQL_PASSWORD", Value: "secret"}, ServiceName: "mysql", InstanceName: "db"},
+		{EnvVar: bind.EnvVar{Name: "REDIS_URL", Value: "redis://10.0.0.5:6379/0"}, ServiceName: "redis", InstanceName: "cache"},
+		{EnvVar: bind.EnvVar{Name: "MEMCACHED", Value: "10.0.0.6:11211"}, ServiceName: "memcached", InstanceName: "mc"},
+		{EnvVar: bind.EnvVar{Name: "ES_HOST", Value: "fd00::10"}, ServiceName: "es", InstanceName: "search"},
+	}
+	policy, err := a.NetworkPolicy()
+	c.Assert(err, check.IsNil)
+	c.Assert(policy.EgressCIDRs, check.DeepEquals, []string{
+		"10.0.0.5/32",
+		"10.0.0.6/32",
+		"fd00::10/128",
+	})
+}
+
+func (s *S) TestAppAddInstanceNetworkPolicyFailure(c *check.C) {
+	policyProv, rollback := s.registerNetworkPolicyProvisioner()
+	defer rollback()
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	policyProv.PrepareFailure("SetNetworkPolicy", errors.New("cluster unavailable"))
+	err = a.AddInstance(bind.AddInstanceArgs{
+		Envs: []bind.ServiceEnvVar{
+			{EnvVar: bind.EnvVar{Name: "MYSQL_HOST", Value: "10.0.0.5"}, ServiceName: "mysql", InstanceName: "db"},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ServiceEnvs, check.HasLen, 1)
+}
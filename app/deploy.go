@@ -15,6 +15,7 @@ import (
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/pkg/errors"
+	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/builder"
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/event"
@@ -22,11 +23,11 @@ import (
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/registry"
 	"github.com/tsuru/tsuru/router/rebuild"
 	"github.com/tsuru/tsuru/servicemanager"
 	"github.com/tsuru/tsuru/set"
 	appTypes "github.com/tsuru/tsuru/types/app"
-	permTypes "github.com/tsuru/tsuru/types/permission"
 )
 
 type DeployKind string
@@ -52,6 +53,7 @@ type DeployData struct {
 	Commit      string
 	Error       string
 	Image       string
+	ImageDigest string
 	Log         string
 	User        string
 	Origin      string
@@ -160,6 +162,9 @@ func eventToDeployData(evt *event.Event, validImages set.Set, full bool) *Deploy
 	var endData map[string]string
 	if err = evt.EndData(&endData); err == nil {
 		data.Image = endData["image"]
+		if full {
+			data.ImageDigest = deployImageDigest(data.App, data.Image)
+		}
 		if validImages != nil {
 			data.CanRollback = validImages.Includes(data.Image)
 			if reImageVersion.MatchString(data.Image) {
@@ -173,6 +178,27 @@ func eventToDeployData(evt *event.Event, validImages set.Set, full bool) *Deploy
 	return data
 }
 
+// deployImageDigest returns the digest the version deployed with the image was
+// pinned to, if any.
+func deployImageDigest(appName, image string) string {
+	if image == "" {
+		return ""
+	}
+	allVersions, err := servicemanager.AppVersion.AllAppVersions(context.TODO(), appName)
+	if err != nil {
+		log.Errorf("unable to get versions for app %s: %v", appName, err)
+		return ""
+	}
+	for _, av := range allVersions {
+		for _, version := range av.Versions {
+			if version.DeployImage == image {
+				return version.DeployDigest
+			}
+		}
+	}
+	return ""
+}
+
 type DeployOptions struct {
 	App              *App
 	Commit           string
@@ -191,6 +217,7 @@ type DeployOptions struct {
 	Build            bool
 	NewVersion       bool
 	OverrideVersions bool
+	FreezeOverride   string
 }
 
 func (o *DeployOptions) GetOrigin() string {
@@ -339,6 +366,10 @@ func Deploy(ctx context.Context, opts DeployOptions) (string, error) {
 	logWriter.Async()
 	defer logWriter.Close()
 	opts.Event.SetLogWriter(io.MultiWriter(&tsuruIo.NoErrorWriter{Writer: opts.OutputStream}, &logWriter))
+	err = checkDeployFreeze(&opts)
+	if err != nil {
+		return "", err
+	}
 	imageID, err := deployToProvisioner(ctx, &opts, opts.Event)
 	rebuild.RoutesRebuildOrEnqueueWithProgress(opts.App.Name, opts.Event)
 	if err != nil {
@@ -348,6 +379,10 @@ func Deploy(ctx context.Context, opts DeployOptions) (string, error) {
 	if err != nil {
 		log.Errorf("WARNING: couldn't increment deploy count, deploy opts: %#v", opts)
 	}
+	err = opts.App.EnsureNetworkPolicy()
+	if err != nil {
+		log.Errorf("WARNING: couldn't update network policy for app %q: %v", opts.App.Name, err)
+	}
 	if opts.Kind == DeployImage || opts.Kind == DeployRollback {
 		if !opts.App.UpdatePlatform {
 			opts.App.SetUpdatePlatform(true)
@@ -384,6 +419,7 @@ func deployToProvisioner(ctx context.Context, opts *DeployOptions, evt *event.Ev
 	}
 
 	var version appTypes.AppVersion
+	isImageDeploy := opts.Kind == DeployImage
 	if opts.Kind == DeployRollback {
 		version, err = servicemanager.AppVersion.VersionByImageOrVersion(ctx, opts.App, opts.Image)
 		if err != nil {
@@ -396,17 +432,79 @@ func deployToProvisioner(ctx context.Context, opts *DeployOptions, evt *event.Ev
 			return "", errors.Errorf("the selected version is disabled for rollback: %s", version.VersionInfo().DisabledReason)
 		}
 	} else {
+		if isImageDeploy {
+			// The builder pulls the verified digest, a tag moved after the
+			// verification is never deployed.
+			opts.Image, err = verifyDeployImage(ctx, opts.App, opts.Image, evt)
+			if err != nil {
+				return "", err
+			}
+		}
 		version, err = builderDeploy(ctx, deployer, opts, evt)
 		if err != nil {
 			return "", err
 		}
 	}
-	return deployer.Deploy(ctx, provision.DeployArgs{
+	args := provision.DeployArgs{
 		App:              opts.App,
 		Version:          version,
 		Event:            evt,
 		PreserveVersions: opts.NewVersion,
-	})
+	}
+	imageReady := func(ctx context.Context, version appTypes.AppVersion) error {
+		// Provisioners building the version image only set it right before
+		// calling imageReady, so new versions are pinned here.
+		if opts.Kind != DeployRollback {
+			err := pinDeployImage(ctx, version, evt)
+			if err != nil {
+				return err
+			}
+		}
+		// Images built by tsuru only exist once pushed to the registry,
+		// versions being rolled back to are verified again against the
+		// current pool constraints.
+		if !isImageDeploy {
+			_, err := verifyDeployImage(ctx, opts.App, version.VersionInfo().DeployImageReference(), evt)
+			if err != nil {
+				return err
+			}
+		}
+		if opts.Kind != DeployRollback {
+			err := recordImageArchitectures(ctx, version, evt)
+			if err != nil {
+				return err
+			}
+		}
+		err := scanVersion(ctx, opts, version, evt)
+		if err != nil {
+			return err
+		}
+		return runDeployHooks(ctx, appTypes.DeployHookPreDeploy, opts, version, evt)
+	}
+	args.Verify = func(ctx context.Context, version appTypes.AppVersion) error {
+		return runDeployHooks(ctx, appTypes.DeployHookPostDeploy, opts, version, evt)
+	}
+	if version.VersionInfo().DeployImage == "" {
+		args.ImageReady = imageReady
+	} else {
+		err = imageReady(ctx, version)
+		if err != nil {
+			return "", err
+		}
+	}
+	return deployer.Deploy(ctx, args)
+}
+
+// scanVersion generates the SBOM of new versions and checks the vulnerabilities
+// of versions becoming routable with the deploy.
+func scanVersion(ctx context.Context, opts *DeployOptions, version appTypes.AppVersion, w io.Writer) error {
+	if opts.Kind != DeployRollback {
+		recordVersionSBOM(ctx, opts.App, version, w)
+	}
+	if opts.NewVersion {
+		return nil
+	}
+	return checkVersionVulnerabilities(ctx, opts.App, version, w)
 }
 
 func builderDeploy(ctx context.Context, prov provision.BuilderDeploy, opts *DeployOptions, evt *event.Event) (appTypes.AppVersion, error) {
@@ -432,6 +530,47 @@ func builderDeploy(ctx context.Context, prov provision.BuilderDeploy, opts *Depl
 	return version, err
 }
 
+// pinDeployImage resolves the digest of the version image in the tsuru
+// registry and stores it in the version, so the version keeps running the same
+// image even if its tag is overwritten later. Images outside the tsuru
+// registry and registries unable to answer are left unpinned.
+func pinDeployImage(ctx context.Context, version appTypes.AppVersion, w io.Writer) error {
+	deployImage := version.VersionInfo().DeployImage
+	registryHost, _ := config.GetString("docker:registry")
+	if deployImage == "" || registryHost == "" || !strings.HasPrefix(deployImage, registryHost+"/") {
+		return nil
+	}
+	digest, err := registry.ImageDigest(ctx, deployImage, nil)
+	if err != nil {
+		log.Errorf("unable to resolve digest for image %s: %v", deployImage, err)
+		fmt.Fprintf(w, " ---> Unable to resolve digest for image %s, running it by tag\n", deployImage)
+		return nil
+	}
+	fmt.Fprintf(w, " ---> Pinning image %s to digest %s\n", deployImage, digest)
+	return version.CommitDeployDigest(digest)
+}
+
+// recordImageArchitectures stores in the version the architectures its image
+// in the tsuru registry is available for, so its units are only scheduled on
+// nodes able to run it.
+func recordImageArchitectures(ctx context.Context, version appTypes.AppVersion, w io.Writer) error {
+	deployImage := version.VersionInfo().DeployImage
+	registryHost, _ := config.GetString("docker:registry")
+	if deployImage == "" || registryHost == "" || !strings.HasPrefix(deployImage, registryHost+"/") {
+		return nil
+	}
+	archs, err := registry.ImageArchitectures(ctx, version.VersionInfo().DeployImageReference(), nil)
+	if err != nil {
+		log.Errorf("unable to get architectures of image %s: %v", deployImage, err)
+		return nil
+	}
+	if len(archs) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, " ---> Image %s available for architectures: %s\n", deployImage, strings.Join(archs, ", "))
+	return version.CommitArchitectures(archs)
+}
+
 func ValidateOrigin(origin string) bool {
 	originList := []string{"app-deploy", "git", "rollback", "drag-and-drop", "image", "rebuild"}
 	for _, ol := range originList {
@@ -473,10 +612,7 @@ func deployDataToEvent(data *DeployData) error {
 	evt.RemoveDate = data.RemoveDate
 	a, err := GetByName(context.TODO(), data.App)
 	if err == nil {
-		evt.Allowed = event.Allowed(permission.PermAppReadEvents, append(permission.Contexts(permTypes.CtxTeam, a.Teams),
-			permission.Context(permTypes.CtxApp, a.Name),
-			permission.Context(permTypes.CtxPool, a.Pool),
-		)...)
+		evt.Allowed = event.Allowed(permission.PermAppReadEvents, a.PermissionContexts()...)
 	} else {
 		evt.Allowed = event.Allowed(permission.PermAppReadEvents)
 	}
@@ -0,0 +1,47 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const deployFreezeOverrideKind = "deploy-freeze-override"
+
+// checkDeployFreeze fails deploys happening inside an active freeze window,
+// unless the deploy carries a freeze override justification, which is
+// recorded in an internal event targeting the app. Overrides that can't be
+// recorded fail the deploy.
+func checkDeployFreeze(opts *DeployOptions) error {
+	err := event.CheckDeployFreeze(opts.App.Pool, opts.App.Tags, time.Now())
+	frozenErr, ok := err.(*event.ErrDeployFrozen)
+	if !ok || opts.FreezeOverride == "" {
+		return err
+	}
+	fmt.Fprintf(opts.Event, "---- Overriding %s by request of %s: %s ----\n", frozenErr.Window, opts.User, opts.FreezeOverride)
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: opts.App.Name},
+		InternalKind: deployFreezeOverrideKind,
+		CustomData: map[string]interface{}{
+			"window":        frozenErr.Window.Name,
+			"windowEnd":     frozenErr.End,
+			"user":          opts.User,
+			"justification": opts.FreezeOverride,
+			"deployEvent":   opts.Event.UniqueID.Hex(),
+		},
+		DisableLock: true,
+		Allowed:     event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, opts.App.Name)),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to record deploy freeze override for app %q", opts.App.Name)
+	}
+	return evt.Done(nil)
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestDeployAppFrozen(c *check.C) {
+	err := event.AddFreezeWindow(&event.FreezeWindow{Name: "incident", Schedule: "* * * * *", Duration: time.Hour, Pools: []string{s.Pool}, Reason: "ongoing incident"})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.FitsTypeOf, &event.ErrDeployFrozen{})
+	c.Assert(err, check.ErrorMatches, `deploys are frozen by freeze window "incident" \(ongoing incident\) until .*`)
+	c.Assert(writer.String(), check.Not(check.Matches), "(?s).*Builder deploy called.*")
+}
+
+func (s *S) TestDeployAppFrozenOtherPool(c *check.C) {
+	err := event.AddFreezeWindow(&event.FreezeWindow{Name: "incident", Schedule: "* * * * *", Duration: time.Hour, Pools: []string{"other-pool"}, Reason: "ongoing incident"})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		OutputStream: &bytes.Buffer{},
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestDeployAppFrozenOverride(c *check.C) {
+	err := event.AddFreezeWindow(&event.FreezeWindow{Name: "incident", Schedule: "* * * * *", Duration: time.Hour, Tags: []string{"critical"}, Reason: "ongoing incident"})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake", Tags: []string{"critical"}}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	evt := s.newDeployEvent(c, &a)
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:            &a,
+		Image:          "myimage",
+		User:           s.user.Email,
+		OutputStream:   writer,
+		Event:          evt,
+		FreezeOverride: "hotfix for the incident",
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.String(), check.Matches, `(?s).*Overriding freeze window "incident" \(ongoing incident\) by request of `+s.user.Email+`: hotfix for the incident.*Builder deploy called.*`)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		Kind:   deployFreezeOverrideKind,
+		StartCustomData: map[string]interface{}{
+			"window":        "incident",
+			"user":          s.user.Email,
+			"justification": "hotfix for the incident",
+			"deployEvent":   evt.UniqueID.Hex(),
+		},
+	}, eventtest.HasEvent)
+}
@@ -0,0 +1,490 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+const (
+	defaultDeployHookTimeout    = 30 * time.Second
+	defaultDeployHookMaxTimeout = 5 * time.Minute
+	deployHookSignatureHeader   = "X-Tsuru-Signature"
+	maxDeployHookResponseSize   = 4096
+	maxDeployHookReasonSize     = 256
+)
+
+var (
+	// deployHookClient calls the hooks set in the tsuru config.
+	deployHookClient = newDeployHookClient(nil)
+
+	// appDeployHookClient calls the hooks set by app users, refusing to
+	// connect to addresses not allowed by the deploy-hook-targets config.
+	appDeployHookClient = newDeployHookClient(checkDeployHookAddress)
+
+	// privateNetworks are the networks app hooks can't reach unless they're
+	// allowed by the deploy-hook-targets config.
+	privateNetworks = mustParseCIDRs("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7")
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	result := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		result[i] = network
+	}
+	return result
+}
+
+// newDeployHookClient returns the client calling deploy hooks, which never
+// follows redirects, so hooks can't send requests to other hosts.
+func newDeployHookClient(control func(network, address string, c syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   15 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 15 * time.Second,
+		MaxIdleConnsPerHost: 5,
+		IdleConnTimeout:     15 * time.Second,
+	}
+	if control == nil {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// deployHookTargets holds the deploy-hook-targets config, the hosts and
+// networks app hooks may reach. Loopback, link-local and private addresses
+// are refused unless allowed.
+type deployHookTargets struct {
+	hosts    []string
+	networks []*net.IPNet
+}
+
+func loadDeployHookTargets() (deployHookTargets, error) {
+	var targets deployHookTargets
+	entries, err := config.GetList("deploy-hook-targets")
+	if err != nil {
+		if _, ok := err.(config.ErrKeyNotFound); ok {
+			return targets, nil
+		}
+		return targets, err
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return targets, errors.Wrapf(err, "invalid deploy-hook-targets entry %q", entry)
+			}
+			targets.networks = append(targets.networks, network)
+			continue
+		}
+		targets.hosts = append(targets.hosts, strings.ToLower(entry))
+	}
+	return targets, nil
+}
+
+// allowsHost returns whether the host is in the allowed hosts, either by its
+// name or by a "*." prefixed domain.
+func (t deployHookTargets) allowsHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range t.hosts {
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsIP returns whether the address is in the allowed networks. When
+// there are no allowed hosts nor networks, every public address is allowed.
+func (t deployHookTargets) allowsIP(ip net.IP) bool {
+	for _, network := range t.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	if len(t.hosts) > 0 || len(t.networks) > 0 {
+		return false
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkHookURL returns an error when the URL of an app hook targets a host
+// not allowed by the deploy-hook-targets config. It's called again for the
+// resolved addresses when connecting, see checkDeployHookAddress.
+func checkHookURL(hookURL string) error {
+	targets, err := loadDeployHookTargets()
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(hookURL)
+	if err != nil {
+		return err
+	}
+	if targets.allowsHost(u.Hostname()) {
+		return nil
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !targets.allowsIP(ip) {
+		return errors.Errorf("address %s is not allowed for deploy hooks", ip)
+	}
+	if ip := net.ParseIP(u.Hostname()); ip == nil && len(targets.hosts) > 0 && len(targets.networks) == 0 {
+		return errors.Errorf("host %s is not allowed for deploy hooks", u.Hostname())
+	}
+	return nil
+}
+
+// appHookClient returns the client calling an app hook. Hooks targeting
+// hosts allowed by name may connect to any address, all others are checked
+// when connecting.
+func appHookClient(hookURL string) (*http.Client, error) {
+	targets, err := loadDeployHookTargets()
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(hookURL)
+	if err != nil {
+		return nil, err
+	}
+	if targets.allowsHost(u.Hostname()) {
+		return deployHookClient, nil
+	}
+	return appDeployHookClient, nil
+}
+
+func checkDeployHookAddress(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.Errorf("invalid address %s", address)
+	}
+	targets, err := loadDeployHookTargets()
+	if err != nil {
+		return err
+	}
+	if !targets.allowsIP(ip) {
+		return errors.Errorf("address %s is not allowed for deploy hooks", ip)
+	}
+	return nil
+}
+
+// deployHookMaxTimeout returns the longest time tsuru waits for a hook, set
+// in seconds by the deploy-hook-max-timeout config.
+func deployHookMaxTimeout() time.Duration {
+	seconds, err := config.GetInt("deploy-hook-max-timeout")
+	if err != nil || seconds <= 0 {
+		return defaultDeployHookMaxTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// ErrDeployHookFailed is returned when a deploy hook rejects a deploy, fails
+// to respond in time or can't be reached.
+type ErrDeployHookFailed struct {
+	Hook   string
+	Stage  string
+	Reason string
+}
+
+func (e *ErrDeployHookFailed) Error() string {
+	return fmt.Sprintf("%s hook %q failed: %s", e.Stage, e.Hook, e.Reason)
+}
+
+// DeployHooksInfo holds the deploy hooks called on deploys of an app, with
+// their secrets omitted.
+type DeployHooksInfo struct {
+	Pool []appTypes.DeployHook `json:"pool"`
+	App  []appTypes.DeployHook `json:"app"`
+}
+
+// deployHookPayload is the body of the requests sent to deploy hooks.
+type deployHookPayload struct {
+	Stage      string `json:"stage"`
+	App        string `json:"app"`
+	Pool       string `json:"pool"`
+	Team       string `json:"team"`
+	Version    int    `json:"version"`
+	Image      string `json:"image"`
+	Digest     string `json:"digest,omitempty"`
+	Kind       string `json:"kind"`
+	User       string `json:"user,omitempty"`
+	Origin     string `json:"origin,omitempty"`
+	Message    string `json:"message,omitempty"`
+	NewVersion bool   `json:"newVersion"`
+	EventID    string `json:"eventID,omitempty"`
+}
+
+// DeployHooksInfo returns the hooks configured for the pool of the app and
+// the ones set on the app itself.
+func (app *App) DeployHooksInfo() (DeployHooksInfo, error) {
+	poolHooks, err := poolDeployHooks(app.Pool)
+	if err != nil {
+		return DeployHooksInfo{}, err
+	}
+	return DeployHooksInfo{
+		Pool: withoutSecrets(poolHooks),
+		App:  withoutSecrets(app.DeployHooks),
+	}, nil
+}
+
+// SetDeployHooks replaces the deploy hooks of the app.
+func (app *App) SetDeployHooks(hooks []appTypes.DeployHook) error {
+	names := map[string]struct{}{}
+	for _, hook := range hooks {
+		if err := hook.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[hook.Name]; ok {
+			return &tsuruErrors.ValidationError{Message: "duplicated deploy hook " + hook.Name}
+		}
+		names[hook.Name] = struct{}{}
+		if maxTimeout := deployHookMaxTimeout(); time.Duration(hook.Timeout)*time.Second > maxTimeout {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("timeout for deploy hook %s must not exceed %v", hook.Name, maxTimeout)}
+		}
+		if err := checkHookURL(hook.URL); err != nil {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid url for deploy hook %s: %v", hook.Name, err)}
+		}
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Apps().Update(
+		bson.M{"name": app.Name},
+		bson.M{"$set": bson.M{"deployhooks": hooks}},
+	)
+	if err != nil {
+		return err
+	}
+	app.DeployHooks = hooks
+	return nil
+}
+
+func withoutSecrets(hooks []appTypes.DeployHook) []appTypes.DeployHook {
+	result := make([]appTypes.DeployHook, len(hooks))
+	for i, hook := range hooks {
+		hook.Secret = ""
+		result[i] = hook
+	}
+	return result
+}
+
+// poolDeployHooks returns the hooks in the deploy-hooks config that apply to
+// the pool, entries without a pool apply to every pool.
+func poolDeployHooks(pool string) ([]appTypes.DeployHook, error) {
+	raw, err := config.Get("deploy-hooks")
+	if err != nil {
+		return nil, nil
+	}
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.New("deploy-hooks must be a list")
+	}
+	var hooks []appTypes.DeployHook
+	for i, entry := range entries {
+		fields, ok := entry.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.Errorf("deploy-hooks[%d] must be a map", i)
+		}
+		var hookPool string
+		var hook appTypes.DeployHook
+		for k, v := range fields {
+			value := fmt.Sprint(v)
+			switch fmt.Sprint(k) {
+			case "pool":
+				hookPool = value
+			case "name":
+				hook.Name = value
+			case "url":
+				hook.URL = value
+			case "stage":
+				hook.Stage = value
+			case "secret":
+				hook.Secret = value
+			case "timeout":
+				hook.Timeout, err = strconv.Atoi(value)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid deploy-hooks[%d]:timeout", i)
+				}
+			}
+		}
+		if err = hook.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid deploy-hooks[%d]", i)
+		}
+		if hookPool == "" || hookPool == pool {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+// runDeployHooks calls the pool and app hooks of the given stage, in this
+// order, stopping on the first failure.
+func runDeployHooks(ctx context.Context, stage string, opts *DeployOptions, version appTypes.AppVersion, evt *event.Event) error {
+	poolHooks, err := poolDeployHooks(opts.App.Pool)
+	if err != nil {
+		return err
+	}
+	hooks := make([]deployHookCall, 0, len(poolHooks)+len(opts.App.DeployHooks))
+	for _, hook := range poolHooks {
+		hooks = append(hooks, deployHookCall{DeployHook: hook, client: deployHookClient})
+	}
+	for _, hook := range opts.App.DeployHooks {
+		client, err := appHookClient(hook.URL)
+		if err != nil {
+			return err
+		}
+		hooks = append(hooks, deployHookCall{DeployHook: hook, client: client})
+	}
+	versionInfo := version.VersionInfo()
+	payload := deployHookPayload{
+		Stage:      stage,
+		App:        opts.App.Name,
+		Pool:       opts.App.Pool,
+		Team:       opts.App.TeamOwner,
+		Version:    version.Version(),
+		Image:      versionInfo.DeployImage,
+		Digest:     versionInfo.DeployDigest,
+		Kind:       string(opts.Kind),
+		User:       opts.User,
+		Origin:     opts.GetOrigin(),
+		Message:    opts.Message,
+		NewVersion: opts.NewVersion,
+	}
+	if evt != nil {
+		payload.EventID = evt.UniqueID.Hex()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = ioutil.Discard
+	if evt != nil {
+		w = evt
+	}
+	for _, hook := range hooks {
+		if hook.Stage != stage {
+			continue
+		}
+		fmt.Fprintf(w, "---- Calling %s hook %q ----\n", stage, hook.Name)
+		err = callDeployHook(ctx, hook.client, hook.DeployHook, body)
+		if err != nil {
+			fmt.Fprintf(w, " ---> %v\n", err)
+			return err
+		}
+		fmt.Fprintf(w, " ---> %s hook %q succeeded\n", stage, hook.Name)
+	}
+	return nil
+}
+
+type deployHookCall struct {
+	appTypes.DeployHook
+	client *http.Client
+}
+
+func callDeployHook(ctx context.Context, client *http.Client, hook appTypes.DeployHook, body []byte) error {
+	timeout := defaultDeployHookTimeout
+	if hook.Timeout > 0 {
+		timeout = time.Duration(hook.Timeout) * time.Second
+	}
+	if maxTimeout := deployHookMaxTimeout(); timeout > maxTimeout {
+		timeout = maxTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	hookErr := &ErrDeployHookFailed{Hook: hook.Name, Stage: hook.Stage}
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		hookErr.Reason = err.Error()
+		return hookErr
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set(deployHookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	rsp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			hookErr.Reason = fmt.Sprintf("no response after %v", timeout)
+		} else {
+			hookErr.Reason = err.Error()
+		}
+		return hookErr
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode >= 200 && rsp.StatusCode < 300 {
+		return nil
+	}
+	// Only the message field of a JSON response is shown, so hooks can't be
+	// used to read arbitrary responses into the deploy log.
+	data, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, maxDeployHookResponseSize))
+	var result struct {
+		Message string `json:"message"`
+	}
+	var reason string
+	if json.Unmarshal(data, &result) == nil {
+		reason = strings.TrimSpace(result.Message)
+		if len(reason) > maxDeployHookReasonSize {
+			reason = reason[:maxDeployHookReasonSize] + "..."
+		}
+	}
+	if reason == "" {
+		reason = rsp.Status
+	} else {
+		reason = fmt.Sprintf("%s: %s", rsp.Status, reason)
+	}
+	hookErr.Reason = reason
+	return hookErr
+}
@@ -0,0 +1,337 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/builder"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+type hookRequest struct {
+	path      string
+	signature string
+	payload   deployHookPayload
+}
+
+type hookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []hookRequest
+	status   map[string]int
+}
+
+func (s *S) newHookServer(c *check.C, status map[string]int) *hookServer {
+	hs := &hookServer{status: status}
+	hs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		c.Check(err, check.IsNil)
+		req := hookRequest{path: r.URL.Path, signature: r.Header.Get(deployHookSignatureHeader)}
+		c.Check(json.Unmarshal(body, &req.payload), check.IsNil)
+		hs.mu.Lock()
+		hs.requests = append(hs.requests, req)
+		hs.mu.Unlock()
+		if code, ok := hs.status[r.URL.Path]; ok {
+			w.WriteHeader(code)
+			w.Write([]byte(`{"message": "rejected by ` + r.URL.Path + `"}`))
+		}
+	}))
+	config.Set("deploy-hook-targets", []interface{}{"127.0.0.1/32"})
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+		if err != nil {
+			return nil, err
+		}
+		return version, version.CommitBaseImage()
+	}
+	return hs
+}
+
+func (s *S) TestDeployAppDeployHooks(c *check.C) {
+	defer config.Unset("deploy-hook-targets")
+	hs := s.newHookServer(c, nil)
+	defer hs.Close()
+	config.Set("deploy-hooks", []interface{}{
+		map[interface{}]interface{}{"name": "change-window", "url": hs.URL + "/window", "stage": "pre-deploy", "secret": "abc"},
+		map[interface{}]interface{}{"name": "other-pool", "url": hs.URL + "/other", "stage": "pre-deploy", "pool": "other"},
+	})
+	defer config.Unset("deploy-hooks")
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetDeployHooks([]appTypes.DeployHook{
+		{Name: "smoke", URL: hs.URL + "/smoke", Stage: appTypes.DeployHookPostDeploy, Timeout: 10},
+	})
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	evt := s.newDeployEvent(c, &a)
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		User:         s.user.Email,
+		OutputStream: writer,
+		Event:        evt,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.String(), check.Matches, `(?s).*pre-deploy hook "change-window" succeeded.*Builder deploy called.*post-deploy hook "smoke" succeeded.*`)
+	c.Assert(hs.requests, check.HasLen, 2)
+	c.Assert(hs.requests[0].path, check.Equals, "/window")
+	c.Assert(hs.requests[1].path, check.Equals, "/smoke")
+	c.Assert(hs.requests[1].signature, check.Equals, "")
+	payload := hs.requests[0].payload
+	c.Assert(payload.Stage, check.Equals, appTypes.DeployHookPreDeploy)
+	c.Assert(payload.App, check.Equals, a.Name)
+	c.Assert(payload.Pool, check.Equals, s.Pool)
+	c.Assert(payload.Version, check.Equals, 1)
+	c.Assert(payload.Kind, check.Equals, string(DeployImage))
+	c.Assert(payload.User, check.Equals, s.user.Email)
+	c.Assert(payload.EventID, check.Equals, evt.UniqueID.Hex())
+	body, err := json.Marshal(payload)
+	c.Assert(err, check.IsNil)
+	mac := hmac.New(sha256.New, []byte("abc"))
+	mac.Write(body)
+	c.Assert(hs.requests[0].signature, check.Equals, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	c.Assert(hs.requests[1].payload.Stage, check.Equals, appTypes.DeployHookPostDeploy)
+}
+
+func (s *S) TestDeployAppPreDeployHookVeto(c *check.C) {
+	defer config.Unset("deploy-hook-targets")
+	hs := s.newHookServer(c, map[string]int{"/window": http.StatusForbidden})
+	defer hs.Close()
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetDeployHooks([]appTypes.DeployHook{
+		{Name: "change-window", URL: hs.URL + "/window", Stage: appTypes.DeployHookPreDeploy},
+		{Name: "smoke", URL: hs.URL + "/smoke", Stage: appTypes.DeployHookPostDeploy},
+	})
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.NotNil)
+	hookErr, ok := errors.Cause(err).(*ErrDeployHookFailed)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(hookErr, check.DeepEquals, &ErrDeployHookFailed{
+		Hook:   "change-window",
+		Stage:  appTypes.DeployHookPreDeploy,
+		Reason: "403 Forbidden: rejected by /window",
+	})
+	c.Assert(writer.String(), check.Not(check.Matches), `(?s).*Builder deploy called.*`)
+	c.Assert(hs.requests, check.HasLen, 1)
+}
+
+func (s *S) TestDeployAppPostDeployHookFailure(c *check.C) {
+	defer config.Unset("deploy-hook-targets")
+	hs := s.newHookServer(c, map[string]int{"/smoke": http.StatusInternalServerError})
+	defer hs.Close()
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetDeployHooks([]appTypes.DeployHook{
+		{Name: "smoke", URL: hs.URL + "/smoke", Stage: appTypes.DeployHookPostDeploy},
+	})
+	c.Assert(err, check.IsNil)
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		OutputStream: ioutil.Discard,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.ErrorMatches, `(?s).*post-deploy hook "smoke" failed: 500 Internal Server Error: rejected by /smoke.*`)
+	version, err := servicemanager.AppVersion.VersionByImageOrVersion(context.TODO(), &a, "1")
+	c.Assert(err, check.IsNil)
+	c.Assert(version.VersionInfo().DeploySuccessful, check.Equals, false)
+}
+
+func (s *S) TestCallDeployHookTimeout(c *check.C) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	hook := appTypes.DeployHook{Name: "slow", URL: server.URL, Stage: appTypes.DeployHookPreDeploy, Timeout: 1}
+	err := callDeployHook(context.TODO(), deployHookClient, hook, []byte("{}"))
+	c.Assert(err, check.DeepEquals, &ErrDeployHookFailed{
+		Hook:   "slow",
+		Stage:  appTypes.DeployHookPreDeploy,
+		Reason: "no response after 1s",
+	})
+}
+
+func (s *S) TestCallDeployHookTimeoutLimit(c *check.C) {
+	config.Set("deploy-hook-max-timeout", 1)
+	defer config.Unset("deploy-hook-max-timeout")
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	hook := appTypes.DeployHook{Name: "slow", URL: server.URL, Stage: appTypes.DeployHookPreDeploy, Timeout: 3600}
+	err := callDeployHook(context.TODO(), deployHookClient, hook, []byte("{}"))
+	c.Assert(err, check.DeepEquals, &ErrDeployHookFailed{
+		Hook:   "slow",
+		Stage:  appTypes.DeployHookPreDeploy,
+		Reason: "no response after 1s",
+	})
+}
+
+func (s *S) TestCallDeployHookDoesNotShowResponse(c *check.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("some internal data"))
+	}))
+	defer server.Close()
+	hook := appTypes.DeployHook{Name: "check", URL: server.URL, Stage: appTypes.DeployHookPreDeploy}
+	err := callDeployHook(context.TODO(), deployHookClient, hook, []byte("{}"))
+	c.Assert(err, check.DeepEquals, &ErrDeployHookFailed{
+		Hook:   "check",
+		Stage:  appTypes.DeployHookPreDeploy,
+		Reason: "400 Bad Request",
+	})
+}
+
+func (s *S) TestCallDeployHookDoesNotFollowRedirects(c *check.C) {
+	var called bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer server.Close()
+	hook := appTypes.DeployHook{Name: "check", URL: server.URL, Stage: appTypes.DeployHookPreDeploy}
+	err := callDeployHook(context.TODO(), deployHookClient, hook, []byte("{}"))
+	c.Assert(err, check.DeepEquals, &ErrDeployHookFailed{
+		Hook:   "check",
+		Stage:  appTypes.DeployHookPreDeploy,
+		Reason: "302 Found",
+	})
+	c.Assert(called, check.Equals, false)
+}
+
+func (s *S) TestCallAppDeployHookPrivateAddress(c *check.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	hook := appTypes.DeployHook{Name: "check", URL: server.URL, Stage: appTypes.DeployHookPreDeploy}
+	client, err := appHookClient(hook.URL)
+	c.Assert(err, check.IsNil)
+	err = callDeployHook(context.TODO(), client, hook, []byte("{}"))
+	c.Assert(err, check.ErrorMatches, `pre-deploy hook "check" failed: .*address 127.0.0.1 is not allowed for deploy hooks`)
+	config.Set("deploy-hook-targets", []interface{}{"127.0.0.1/32"})
+	defer config.Unset("deploy-hook-targets")
+	err = callDeployHook(context.TODO(), client, hook, []byte("{}"))
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestSetDeployHooksRestrictedTargets(c *check.C) {
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetDeployHooks([]appTypes.DeployHook{
+		{Name: "metadata", URL: "http://169.254.169.254/latest/meta-data", Stage: appTypes.DeployHookPreDeploy},
+	})
+	c.Assert(err, check.ErrorMatches, `invalid url for deploy hook metadata: address 169.254.169.254 is not allowed for deploy hooks`)
+	err = a.SetDeployHooks([]appTypes.DeployHook{
+		{Name: "slow", URL: "http://checks.example.com", Stage: appTypes.DeployHookPreDeploy, Timeout: 301},
+	})
+	c.Assert(err, check.ErrorMatches, `timeout for deploy hook slow must not exceed 5m0s`)
+	config.Set("deploy-hook-targets", []interface{}{"*.example.com"})
+	defer config.Unset("deploy-hook-targets")
+	err = a.SetDeployHooks([]appTypes.DeployHook{
+		{Name: "other", URL: "http://checks.example.org", Stage: appTypes.DeployHookPreDeploy},
+	})
+	c.Assert(err, check.ErrorMatches, `invalid url for deploy hook other: host checks.example.org is not allowed for deploy hooks`)
+	err = a.SetDeployHooks([]appTypes.DeployHook{
+		{Name: "check", URL: "http://checks.example.com", Stage: appTypes.DeployHookPreDeploy, Timeout: 300},
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestDeployHookTargets(c *check.C) {
+	tests := []struct {
+		targets []interface{}
+		ip      string
+		allowed bool
+	}{
+		{ip: "8.8.8.8", allowed: true},
+		{ip: "2001:4860:4860::8888", allowed: true},
+		{ip: "127.0.0.1"},
+		{ip: "::1"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "10.1.2.3"},
+		{ip: "172.20.0.1"},
+		{ip: "192.168.0.1"},
+		{ip: "fd00::1"},
+		{ip: "0.0.0.0"},
+		{targets: []interface{}{"10.0.0.0/16"}, ip: "10.0.1.1", allowed: true},
+		{targets: []interface{}{"10.0.0.0/16"}, ip: "10.1.1.1"},
+		{targets: []interface{}{"10.0.0.0/16"}, ip: "8.8.8.8"},
+		{targets: []interface{}{"checks.example.com"}, ip: "8.8.8.8"},
+	}
+	defer config.Unset("deploy-hook-targets")
+	for i, tt := range tests {
+		config.Unset("deploy-hook-targets")
+		if tt.targets != nil {
+			config.Set("deploy-hook-targets", tt.targets)
+		}
+		err := checkDeployHookAddress("tcp", net.JoinHostPort(tt.ip, "80"), nil)
+		c.Check(err == nil, check.Equals, tt.allowed, check.Commentf("test %d", i))
+	}
+	config.Set("deploy-hook-targets", []interface{}{"checks.example.com", "*.hooks.example.com"})
+	targets, err := loadDeployHookTargets()
+	c.Assert(err, check.IsNil)
+	c.Assert(targets.allowsHost("checks.example.com"), check.Equals, true)
+	c.Assert(targets.allowsHost("a.hooks.example.com"), check.Equals, true)
+	c.Assert(targets.allowsHost("hooks.example.com"), check.Equals, false)
+	c.Assert(targets.allowsHost("checks.example.com.evil.com"), check.Equals, false)
+}
+
+func (s *S) TestPoolDeployHooks(c *check.C) {
+	config.Set("deploy-hooks", []interface{}{
+		map[interface{}]interface{}{"name": "all", "url": "http://checks.example.com/all", "stage": "pre-deploy"},
+		map[interface{}]interface{}{"name": "prod", "url": "https://checks.example.com/prod", "stage": "post-deploy", "pool": "prod", "timeout": 120},
+	})
+	defer config.Unset("deploy-hooks")
+	hooks, err := poolDeployHooks("dev")
+	c.Assert(err, check.IsNil)
+	c.Assert(hooks, check.DeepEquals, []appTypes.DeployHook{
+		{Name: "all", URL: "http://checks.example.com/all", Stage: appTypes.DeployHookPreDeploy},
+	})
+	hooks, err = poolDeployHooks("prod")
+	c.Assert(err, check.IsNil)
+	c.Assert(hooks, check.DeepEquals, []appTypes.DeployHook{
+		{Name: "all", URL: "http://checks.example.com/all", Stage: appTypes.DeployHookPreDeploy},
+		{Name: "prod", URL: "https://checks.example.com/prod", Stage: appTypes.DeployHookPostDeploy, Timeout: 120},
+	})
+	config.Set("deploy-hooks", []interface{}{
+		map[interface{}]interface{}{"name": "broken", "url": "ftp://checks.example.com", "stage": "pre-deploy"},
+	})
+	_, err = poolDeployHooks("prod")
+	c.Assert(err, check.ErrorMatches, `invalid deploy-hooks\[0\]: invalid url for deploy hook broken`)
+}
@@ -16,11 +16,14 @@ import (
 
 	"github.com/globalsign/mgo/bson"
 	"github.com/pkg/errors"
+	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/builder"
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision"
+	registrytest "github.com/tsuru/tsuru/registry/testing"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
@@ -271,6 +274,108 @@ func (s *S) TestGetDeploy(c *check.C) {
 	c.Assert(lastDeploy.Timestamp, check.Equals, newDeploy.Timestamp)
 }
 
+func (s *S) TestDeployAppPinsImageDigest(c *check.C) {
+	server, err := registrytest.NewServer("127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	defer server.Stop()
+	config.Set("docker:registry", server.Addr())
+	defer config.Set("docker:registry", "registry.somewhere")
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+		if err != nil {
+			return nil, err
+		}
+		return version, version.CommitBaseImage()
+	}
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	digest := server.PushManifest("tsuru/app-some-app", "v1", []byte(`{"schemaVersion": 2}`))
+	writer := &bytes.Buffer{}
+	evt := s.newDeployEvent(c, &a)
+	imageID, err := Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		OutputStream: writer,
+		Event:        evt,
+	})
+	c.Assert(err, check.IsNil)
+	image := server.Addr() + "/tsuru/app-some-app:v1"
+	c.Assert(imageID, check.Equals, image)
+	c.Assert(writer.String(), check.Matches, `(?s).*Pinning image `+image+` to digest `+digest+`.*`)
+	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(context.TODO(), &a)
+	c.Assert(err, check.IsNil)
+	c.Assert(version.VersionInfo().DeployDigest, check.Equals, digest)
+	c.Assert(version.VersionInfo().DeployImageReference(), check.Equals, server.Addr()+"/tsuru/app-some-app@"+digest)
+	err = evt.DoneCustomData(nil, map[string]string{"image": imageID})
+	c.Assert(err, check.IsNil)
+	deploy, err := GetDeploy(evt.UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(deploy.Image, check.Equals, image)
+	c.Assert(deploy.ImageDigest, check.Equals, digest)
+}
+
+func (s *S) TestDeployAppPinsImageBuiltByProvisioner(c *check.C) {
+	server, err := registrytest.NewServer("127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	defer server.Stop()
+	config.Set("docker:registry", server.Addr())
+	defer config.Set("docker:registry", "registry.somewhere")
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+		if err != nil {
+			return nil, err
+		}
+		return version, version.CommitBuildImage()
+	}
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	digest := server.PushManifest("tsuru/app-some-app", "v1", []byte(`{"schemaVersion": 2}`))
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		ArchiveURL:   "https://example.com/app.tar.gz",
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.String(), check.Matches, `(?s).*Pinning image .*/tsuru/app-some-app:v1 to digest `+digest+`.*Builder deploy called.*`)
+	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(context.TODO(), &a)
+	c.Assert(err, check.IsNil)
+	c.Assert(version.VersionInfo().DeployDigest, check.Equals, digest)
+}
+
+func (s *S) TestDeployAppImageDigestUnavailable(c *check.C) {
+	server, err := registrytest.NewServer("127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	defer server.Stop()
+	config.Set("docker:registry", server.Addr())
+	defer config.Set("docker:registry", "registry.somewhere")
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+		if err != nil {
+			return nil, err
+		}
+		return version, version.CommitBaseImage()
+	}
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.String(), check.Matches, `(?s).*Unable to resolve digest for image .*/tsuru/app-some-app:v1, running it by tag.*`)
+	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(context.TODO(), &a)
+	c.Assert(err, check.IsNil)
+	c.Assert(version.VersionInfo().DeployDigest, check.Equals, "")
+}
+
 func (s *S) TestGetDeployNotFound(c *check.C) {
 	idTest := bson.NewObjectId()
 	deploy, err := GetDeploy(idTest.Hex())
@@ -536,18 +536,22 @@ func pruneAllVersionsByApp(ctx context.Context, appVersions appTypes.AppVersions
 func pruneVersionFromRegistry(ctx context.Context, version appTypes.AppVersionInfo) error {
 	multi := tsuruErrors.NewMultiError()
 
-	if version.DeployImage != "" {
-		err := pruneImageFromRegistry(ctx, version.DeployImage)
-		if err != nil {
-			multi.Add(err)
+	for _, img := range []string{version.DeployImage, version.BuildImage} {
+		if img == "" {
+			continue
 		}
-	}
-
-	if version.BuildImage != "" {
-		err := pruneImageFromRegistry(ctx, version.BuildImage)
+		err := pruneImageFromRegistry(ctx, img)
 		if err != nil {
 			multi.Add(err)
 		}
+		// multi-architecture images are manifest lists pointing to an
+		// image tagged for each architecture.
+		for _, arch := range version.Architectures {
+			err = pruneImageFromRegistry(ctx, image.ArchitectureImage(img, arch))
+			if err != nil {
+				multi.Add(err)
+			}
+		}
 	}
 
 	return multi.ToError()
@@ -317,6 +317,39 @@ func (s *S) TestGCStartWithApp(c *check.C) {
 	})
 }
 
+func (s *S) TestPruneVersionFromRegistryWithArchitectures(c *check.C) {
+	var regDeleteCalls []string
+	registrySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			w.Header().Set("Docker-Content-Digest", r.URL.Path)
+			return
+		}
+		if r.Method == "DELETE" {
+			regDeleteCalls = append(regDeleteCalls, r.URL.Path)
+		}
+	}))
+	defer registrySrv.Close()
+	u, _ := url.Parse(registrySrv.URL)
+	config.Set("docker:registry", u.Host)
+	defer config.Unset("docker:registry")
+	err := pruneVersionFromRegistry(context.TODO(), appTypes.AppVersionInfo{
+		Version:       2,
+		DeployImage:   u.Host + "/tsuru/app-myapp:v2",
+		BuildImage:    u.Host + "/tsuru/app-myapp:v2-builder",
+		Architectures: []string{"amd64", "arm64"},
+	})
+	c.Assert(err, check.IsNil)
+	sort.Strings(regDeleteCalls)
+	c.Assert(regDeleteCalls, check.DeepEquals, []string{
+		"/v2/tsuru/app-myapp/manifests//v2/tsuru/app-myapp/manifests/v2",
+		"/v2/tsuru/app-myapp/manifests//v2/tsuru/app-myapp/manifests/v2-amd64",
+		"/v2/tsuru/app-myapp/manifests//v2/tsuru/app-myapp/manifests/v2-arm64",
+		"/v2/tsuru/app-myapp/manifests//v2/tsuru/app-myapp/manifests/v2-builder",
+		"/v2/tsuru/app-myapp/manifests//v2/tsuru/app-myapp/manifests/v2-builder-amd64",
+		"/v2/tsuru/app-myapp/manifests//v2/tsuru/app-myapp/manifests/v2-builder-arm64",
+	})
+}
+
 func (s *S) TestGCStartWithRunningEvent(c *check.C) {
 	s.mockService.Team.OnList = func() ([]authTypes.Team, error) {
 		return []authTypes.Team{{Name: s.team}}, nil
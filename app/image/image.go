@@ -43,6 +43,13 @@ func SplitImageName(imageName string) (repo, tag string) {
 	return
 }
 
+// ArchitectureImage returns the image tagged with the architecture suffix,
+// used for each architecture of multi-architecture images.
+func ArchitectureImage(img, arch string) string {
+	repository, tag := SplitImageName(img)
+	return fmt.Sprintf("%s:%s-%s", repository, tag, arch)
+}
+
 func AppBasicImageName(appName string) string {
 	return fmt.Sprintf("%s/app-%s", basicImageName("tsuru"), appName)
 }
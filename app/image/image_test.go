@@ -33,6 +33,11 @@ func (s *S) TestSplitImageName(c *check.C) {
 	}
 }
 
+func (s *S) TestArchitectureImage(c *check.C) {
+	c.Assert(image.ArchitectureImage("registry.somewhere/tsuru/app-myapp:v1-builder", "arm64"), check.Equals, "registry.somewhere/tsuru/app-myapp:v1-builder-arm64")
+	c.Assert(image.ArchitectureImage("tsuru/python", "amd64"), check.Equals, "tsuru/python:latest-amd64")
+}
+
 func (s *S) TestGetBuildImage(c *check.C) {
 	s.mockService.PlatformImage.OnFindImage = func(name, version string) (string, error) {
 		return "tsuru/" + name + ":" + version, nil
@@ -0,0 +1,144 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/registry"
+	"github.com/tsuru/tsuru/servicemanager"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	registryTypes "github.com/tsuru/tsuru/types/registry"
+)
+
+const imageVerificationKind = "image-verification"
+
+// verifyDeployImage enforces the image-signer constraint of the app pool,
+// requiring the image to be signed by one of the keys listed in the
+// constraint. Failures are recorded in an internal event targeting the app.
+// It returns the image pinned to the verified digest, so the image used
+// afterwards is the one verified even if its tag is moved meanwhile. Images
+// are returned unchanged when the pool requires no signatures.
+func verifyDeployImage(ctx context.Context, app *App, image string, evt *event.Event) (string, error) {
+	p := pool.Pool{Name: app.Pool}
+	signers, err := p.GetImageSigners()
+	if err != nil {
+		return "", err
+	}
+	if len(signers) == 0 {
+		return image, nil
+	}
+	if evt != nil {
+		fmt.Fprintf(evt, "---- Verifying signature of image %s ----\n", image)
+	}
+	keys, err := imageVerificationKeys(signers)
+	if err == nil && image == "" {
+		err = errors.New("no image to verify")
+	}
+	if err == nil {
+		var sig *registry.VerifiedSignature
+		sig, err = registry.VerifyImageSignature(ctx, image, imageVerificationCredentials(ctx, app, image), keys)
+		if err == nil {
+			if evt != nil {
+				fmt.Fprintf(evt, " ---> Image %s signed by %q\n", sig.Digest, sig.Key)
+			}
+			return imageWithDigest(image, sig.Digest), nil
+		}
+	}
+	auditErr := recordImageVerificationFailure(app, image, signers, err)
+	if auditErr != nil {
+		log.Errorf("unable to record image verification failure for app %q: %v", app.Name, auditErr)
+	}
+	return "", errors.Wrapf(err, "image verification failed for pool %q", app.Pool)
+}
+
+// imageWithDigest replaces the tag or digest of the image with the given
+// digest.
+func imageWithDigest(image, digest string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image + "@" + digest
+}
+
+// imageVerificationKeys loads the public keys with the given names from the
+// image-verification:keys config, each one being either a PEM encoded key or
+// the path to a file holding it.
+func imageVerificationKeys(names []string) (map[string]crypto.PublicKey, error) {
+	keys := make(map[string]crypto.PublicKey, len(names))
+	for _, name := range names {
+		value, err := config.GetString("image-verification:keys:" + name)
+		if err != nil {
+			return nil, errors.Errorf("image signing key %q is not configured", name)
+		}
+		data := []byte(value)
+		if !strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+			data, err = ioutil.ReadFile(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to read image signing key %q", name)
+			}
+		}
+		keys[name], err = registry.ParsePublicKey(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid image signing key %q", name)
+		}
+	}
+	return keys, nil
+}
+
+// imageVerificationCredentials returns the credentials used to read the image
+// signatures: tsuru registry images use the tsuru registry credentials, other
+// images use the app team owner credential for the image registry, if any.
+func imageVerificationCredentials(ctx context.Context, app *App, image string) *registry.Credentials {
+	imageRegistry := registryTypes.ImageRegistry(image)
+	if tsuruRegistry, _ := config.GetString("docker:registry"); tsuruRegistry != "" && registryTypes.NormalizeRegistry(tsuruRegistry) == imageRegistry {
+		return nil
+	}
+	cred, err := servicemanager.RegistryCredential.FindForImage(ctx, []string{app.TeamOwner}, image)
+	if err != nil {
+		if err != registryTypes.ErrCredentialNotFound {
+			log.Errorf("unable to get registry credential for image %q: %v", image, err)
+		}
+		return &registry.Credentials{}
+	}
+	return &registry.Credentials{Username: cred.Username, Password: cred.Token}
+}
+
+func recordImageVerificationFailure(app *App, image string, signers []string, verifyErr error) error {
+	data := map[string]interface{}{
+		"image":   image,
+		"pool":    app.Pool,
+		"signers": signers,
+		"reason":  verifyErr.Error(),
+	}
+	if sigErr, ok := errors.Cause(verifyErr).(*registry.SignatureError); ok {
+		data["digest"] = sigErr.Digest
+		data["reason"] = sigErr.Reason
+	}
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: app.Name},
+		InternalKind: imageVerificationKind,
+		CustomData:   data,
+		DisableLock:  true,
+		Allowed:      event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, app.Name)),
+	})
+	if err != nil {
+		return err
+	}
+	return evt.Done(verifyErr)
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/builder"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	registrytest "github.com/tsuru/tsuru/registry/testing"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) setupImageSigner(c *check.C) (*registrytest.RegistryServer, *ecdsa.PrivateKey) {
+	server, err := registrytest.NewServer("127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	c.Assert(err, check.IsNil)
+	config.Set("image-verification:keys:release", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	err = pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: s.Pool, Field: pool.ConstraintTypeImageSigner, Values: []string{"release"}})
+	c.Assert(err, check.IsNil)
+	return server, key
+}
+
+func (s *S) newDeployEvent(c *check.C, a *App) *event.Event {
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: a.Name},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	return evt
+}
+
+func (s *S) TestDeployAppImageSigned(c *check.C) {
+	server, key := s.setupImageSigner(c)
+	defer server.Stop()
+	defer config.Unset("image-verification")
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	digest := server.PushManifest("tsuru/myimage", "v1", []byte(`{"schemaVersion": 2}`))
+	err = server.Sign("tsuru/myimage", digest, server.Addr()+"/tsuru/myimage", key)
+	c.Assert(err, check.IsNil)
+	var builtImage string
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		builtImage = opts.ImageID
+		version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+		if err != nil {
+			return nil, err
+		}
+		return version, version.CommitBaseImage()
+	}
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        server.Addr() + "/tsuru/myimage:v1",
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.String(), check.Matches, `(?s).*Image `+digest+` signed by "release".*Builder deploy called.*`)
+	c.Assert(builtImage, check.Equals, server.Addr()+"/tsuru/myimage@"+digest)
+}
+
+func (s *S) TestImageWithDigest(c *check.C) {
+	tests := []struct {
+		image, expected string
+	}{
+		{"myimage", "myimage@sha256:abc"},
+		{"myimage:v1", "myimage@sha256:abc"},
+		{"localhost:5000/tsuru/myimage", "localhost:5000/tsuru/myimage@sha256:abc"},
+		{"localhost:5000/tsuru/myimage:v1", "localhost:5000/tsuru/myimage@sha256:abc"},
+		{"registry.example.com/myimage@sha256:def", "registry.example.com/myimage@sha256:abc"},
+	}
+	for _, tt := range tests {
+		c.Check(imageWithDigest(tt.image, "sha256:abc"), check.Equals, tt.expected)
+	}
+}
+
+func (s *S) TestDeployAppImageNotSigned(c *check.C) {
+	server, _ := s.setupImageSigner(c)
+	defer server.Stop()
+	defer config.Unset("image-verification")
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	digest := server.PushManifest("tsuru/myimage", "v1", []byte(`{"schemaVersion": 2}`))
+	image := server.Addr() + "/tsuru/myimage:v1"
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        image,
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.ErrorMatches, `image verification failed for pool "`+s.Pool+`": image .* failed signature verification: no signature found`)
+	c.Assert(writer.String(), check.Not(check.Matches), "(?s).*Builder deploy called.*")
+	c.Assert(eventtest.EventDesc{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		Kind:         imageVerificationKind,
+		ErrorMatches: ".*no signature found",
+		StartCustomData: map[string]interface{}{
+			"image":   image,
+			"digest":  digest,
+			"pool":    s.Pool,
+			"signers": []interface{}{"release"},
+			"reason":  "no signature found",
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestDeployAppBuiltImageVerified(c *check.C) {
+	server, key := s.setupImageSigner(c)
+	defer server.Stop()
+	defer config.Unset("image-verification")
+	config.Set("docker:registry", server.Addr())
+	defer config.Unset("docker:registry")
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+		if err != nil {
+			return nil, err
+		}
+		return version, version.CommitBaseImage()
+	}
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newDeployEvent(c, &a)
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		ArchiveURL:   "https://example.com/app.tar.gz",
+		OutputStream: &bytes.Buffer{},
+		Event:        evt,
+	})
+	evt.Done(err)
+	c.Assert(err, check.ErrorMatches, `image verification failed for pool .*: unable to get digest for image .*/tsuru/app-some-app:v1: image not found`)
+	digest := server.PushManifest("tsuru/app-some-app", "v2", []byte(`{"schemaVersion": 2}`))
+	err = server.Sign("tsuru/app-some-app", digest, server.Addr()+"/tsuru/app-some-app", key)
+	c.Assert(err, check.IsNil)
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		ArchiveURL:   "https://example.com/app.tar.gz",
+		OutputStream: &bytes.Buffer{},
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestDeployAppSourceBuildVerifiedOncePushed(c *check.C) {
+	server, key := s.setupImageSigner(c)
+	defer server.Stop()
+	defer config.Unset("image-verification")
+	config.Set("docker:registry", server.Addr())
+	defer config.Unset("docker:registry")
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+		if err != nil {
+			return nil, err
+		}
+		return version, version.CommitBuildImage()
+	}
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	digest := server.PushManifest("tsuru/app-some-app", "v1", []byte(`{"schemaVersion": 2}`))
+	err = server.Sign("tsuru/app-some-app", digest, server.Addr()+"/tsuru/app-some-app", key)
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		ArchiveURL:   "https://example.com/app.tar.gz",
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.String(), check.Matches, `(?s).*Image `+digest+` signed by "release".*Builder deploy called.*`)
+}
+
+func (s *S) TestRollbackImageVerified(c *check.C) {
+	server, _ := s.setupImageSigner(c)
+	defer server.Stop()
+	defer config.Unset("image-verification")
+	config.Set("docker:registry", server.Addr())
+	defer config.Unset("docker:registry")
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	server.PushManifest("tsuru/app-some-app", "v1", []byte(`{"schemaVersion": 2}`))
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        fmt.Sprintf("v%d", version.Version()),
+		Rollback:     true,
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.ErrorMatches, `image verification failed for pool "`+s.Pool+`": .*no signature found`)
+	c.Assert(writer.String(), check.Not(check.Matches), "(?s).*Builder deploy called.*")
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"github.com/tsuru/tsuru/provision"
+)
+
+func (app *App) jobProvisioner() (provision.JobProvisioner, error) {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return nil, err
+	}
+	jobProv, ok := prov.(provision.JobProvisioner)
+	if !ok {
+		return nil, provision.ProvisionerNotSupported{Prov: prov, Action: "scheduled jobs"}
+	}
+	return jobProv, nil
+}
+
+// Jobs returns the scheduled jobs of the app.
+func (app *App) Jobs() ([]provision.JobSpec, error) {
+	jobProv, err := app.jobProvisioner()
+	if err != nil {
+		return nil, err
+	}
+	return jobProv.ListJobs(app.ctx, app)
+}
+
+// SetJob creates or replaces a scheduled job in the app.
+func (app *App) SetJob(spec provision.JobSpec) error {
+	err := spec.Validate()
+	if err != nil {
+		return err
+	}
+	jobProv, err := app.jobProvisioner()
+	if err != nil {
+		return err
+	}
+	return jobProv.SetJob(app.ctx, app, spec)
+}
+
+// RemoveJob removes a scheduled job from the app, runs in progress are
+// interrupted.
+func (app *App) RemoveJob(name string) error {
+	jobProv, err := app.jobProvisioner()
+	if err != nil {
+		return err
+	}
+	return jobProv.RemoveJob(app.ctx, app, name)
+}
+
+// JobRuns returns the most recent runs of a job, newest first.
+func (app *App) JobRuns(name string) ([]provision.JobRun, error) {
+	jobProv, err := app.jobProvisioner()
+	if err != nil {
+		return nil, err
+	}
+	return jobProv.ListJobRuns(app.ctx, app, name)
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestAppSetJob(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	jobProv := &provisiontest.JobProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}
+	provision.DefaultProvisioner = "jobProv"
+	provision.Register("jobProv", func() (provision.Provisioner, error) {
+		return jobProv, nil
+	})
+	defer provision.Unregister("jobProv")
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetJob(provision.JobSpec{Name: "cleanup", Schedule: "@hourly", Command: "./cleanup"})
+	c.Assert(err, check.IsNil)
+	jobs, err := a.Jobs()
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.DeepEquals, []provision.JobSpec{
+		{Name: "cleanup", Schedule: "@hourly", Command: "./cleanup", ConcurrencyPolicy: provision.JobConcurrencyAllow},
+	})
+	err = a.SetJob(provision.JobSpec{Name: "Invalid", Schedule: "@hourly", Command: "./cleanup"})
+	c.Assert(err, check.ErrorMatches, `invalid job name "Invalid".*`)
+	err = a.RemoveJob("cleanup")
+	c.Assert(err, check.IsNil)
+	err = a.RemoveJob("cleanup")
+	c.Assert(err, check.Equals, provision.ErrJobNotFound)
+}
+
+func (s *S) TestAppSetJobNotSupported(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetJob(provision.JobSpec{Name: "cleanup", Schedule: "@hourly", Command: "./cleanup"})
+	c.Assert(err, check.FitsTypeOf, provision.ProvisionerNotSupported{})
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metering
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/storage"
+	"github.com/tsuru/tsuru/types/metering"
+)
+
+const defaultCurrency = "USD"
+
+var _ metering.MeteringService = &meteringService{}
+
+type meteringService struct {
+	storage metering.MeteringStorage
+}
+
+// MeteringService returns the service generating cost reports from the
+// usage sampled by the metering task.
+func MeteringService() (metering.MeteringService, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &meteringService{storage: dbDriver.MeteringStorage}, nil
+}
+
+// Price is the cost of each unit hour, GB hour of memory and cpu hour of
+// units in a pool and plan. Empty Pool and Plan match any value.
+type Price struct {
+	Pool         string
+	Plan         string
+	UnitHour     float64
+	MemoryGBHour float64
+	CPUHour      float64
+}
+
+func (p Price) matches(key metering.UsageKey) bool {
+	return (p.Pool == "" || p.Pool == key.Pool) && (p.Plan == "" || p.Plan == key.Plan)
+}
+
+func (p Price) specificity() int {
+	var s int
+	if p.Plan != "" {
+		s += 2
+	}
+	if p.Pool != "" {
+		s++
+	}
+	return s
+}
+
+type priceTable []Price
+
+// find returns the most specific price matching the key. A price for both
+// pool and plan wins over a price for the plan, which wins over a price for
+// the pool.
+func (t priceTable) find(key metering.UsageKey) Price {
+	best := Price{}
+	bestSpecificity := -1
+	for _, p := range t {
+		if !p.matches(key) {
+			continue
+		}
+		if s := p.specificity(); s > bestSpecificity {
+			best = p
+			bestSpecificity = s
+		}
+	}
+	return best
+}
+
+func loadPriceTable() (priceTable, error) {
+	raw, err := config.Get("metering:prices")
+	if err != nil {
+		return nil, nil
+	}
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil, errors.New("metering:prices must be a list")
+	}
+	table := make(priceTable, len(entries))
+	for i, entry := range entries {
+		fields, ok := entry.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.Errorf("metering:prices[%d] must be a map", i)
+		}
+		var p Price
+		for k, v := range fields {
+			name := fmt.Sprint(k)
+			switch name {
+			case "pool":
+				p.Pool = fmt.Sprint(v)
+			case "plan":
+				p.Plan = fmt.Sprint(v)
+			case "unit-hour", "memory-gb-hour", "cpu-hour":
+				value, err := priceValue(v)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid metering:prices[%d]:%s", i, name)
+				}
+				switch name {
+				case "unit-hour":
+					p.UnitHour = value
+				case "memory-gb-hour":
+					p.MemoryGBHour = value
+				default:
+					p.CPUHour = value
+				}
+			}
+		}
+		table[i] = p
+	}
+	return table, nil
+}
+
+func priceValue(v interface{}) (float64, error) {
+	switch value := v.(type) {
+	case int:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	}
+	return 0, errors.Errorf("%v is not a number", v)
+}
+
+func currency() string {
+	if c, _ := config.GetString("metering:currency"); c != "" {
+		return c
+	}
+	return defaultCurrency
+}
+
+// Report aggregates the usage in the interval by team, pool or app and
+// calculates its cost using the prices in the config file.
+func (s *meteringService) Report(ctx context.Context, opts metering.ReportOptions) (*metering.Report, error) {
+	if opts.GroupBy == "" {
+		opts.GroupBy = metering.GroupByTeam
+	}
+	if !opts.GroupBy.Valid() {
+		return nil, metering.ErrInvalidGroupBy
+	}
+	prices, err := loadPriceTable()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.storage.FindUsage(ctx, opts.UsageFilter)
+	if err != nil {
+		return nil, err
+	}
+	report := &metering.Report{
+		Start:    opts.Start,
+		End:      opts.End,
+		GroupBy:  opts.GroupBy,
+		Currency: currency(),
+		Entries:  []metering.ReportEntry{},
+		Total:    metering.ReportEntry{Group: "total"},
+	}
+	groups := map[string]*metering.ReportEntry{}
+	for _, u := range usage {
+		group := groupName(u.UsageKey, opts.GroupBy)
+		entry, ok := groups[group]
+		if !ok {
+			entry = &metering.ReportEntry{Group: group}
+			groups[group] = entry
+		}
+		cost := usageCost(u, prices.find(u.UsageKey))
+		addCost(entry, cost)
+		addCost(&report.Total, cost)
+	}
+	for _, entry := range groups {
+		report.Entries = append(report.Entries, *entry)
+	}
+	sort.Slice(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Group < report.Entries[j].Group
+	})
+	return report, nil
+}
+
+func groupName(key metering.UsageKey, groupBy metering.GroupBy) string {
+	switch groupBy {
+	case metering.GroupByPool:
+		return key.Pool
+	case metering.GroupByApp:
+		return key.App
+	}
+	return key.Team
+}
+
+func addCost(entry *metering.ReportEntry, cost metering.ReportEntry) {
+	entry.UnitHours += cost.UnitHours
+	entry.MemoryGBHours += cost.MemoryGBHours
+	entry.CPUHours += cost.CPUHours
+	entry.Cost += cost.Cost
+}
+
+func usageCost(u metering.HourlyUsage, price Price) metering.ReportEntry {
+	unitHours := float64(u.UnitSeconds) / 3600
+	memoryGBHours := unitHours * float64(u.Memory) / (1024 * 1024 * 1024)
+	cpuHours := unitHours * float64(u.CPUMilli) / 1000
+	return metering.ReportEntry{
+		UnitHours:     unitHours,
+		MemoryGBHours: memoryGBHours,
+		CPUHours:      cpuHours,
+		Cost:          unitHours*price.UnitHour + memoryGBHours*price.MemoryGBHour + cpuHours*price.CPUHour,
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metering
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/types/metering"
+	check "gopkg.in/check.v1"
+)
+
+const gigabyte = 1024 * 1024 * 1024
+
+func setPrices() {
+	config.Set("metering:currency", "BRL")
+	config.Set("metering:prices", []interface{}{
+		map[interface{}]interface{}{"unit-hour": 1},
+		map[interface{}]interface{}{"pool": "p2", "unit-hour": 2},
+		map[interface{}]interface{}{"plan": "large", "unit-hour": 3, "memory-gb-hour": 0.5},
+		map[interface{}]interface{}{"pool": "p2", "plan": "large", "unit-hour": 4, "cpu-hour": 0.25},
+	})
+}
+
+func (s *S) TestPriceTableFind(c *check.C) {
+	setPrices()
+	table, err := loadPriceTable()
+	c.Assert(err, check.IsNil)
+	c.Assert(table, check.HasLen, 4)
+	tests := []struct {
+		key      metering.UsageKey
+		expected float64
+	}{
+		{key: metering.UsageKey{Pool: "p1", Plan: "small"}, expected: 1},
+		{key: metering.UsageKey{Pool: "p2", Plan: "small"}, expected: 2},
+		{key: metering.UsageKey{Pool: "p1", Plan: "large"}, expected: 3},
+		{key: metering.UsageKey{Pool: "p2", Plan: "large"}, expected: 4},
+	}
+	for _, tt := range tests {
+		c.Check(table.find(tt.key).UnitHour, check.Equals, tt.expected, check.Commentf("%#v", tt.key))
+	}
+}
+
+func (s *S) TestPriceTableInvalid(c *check.C) {
+	config.Set("metering:prices", []interface{}{
+		map[interface{}]interface{}{"plan": "large", "unit-hour": "a lot"},
+	})
+	_, err := loadPriceTable()
+	c.Assert(err, check.ErrorMatches, `invalid metering:prices\[0\]:unit-hour: a lot is not a number`)
+}
+
+func (s *S) TestReport(c *check.C) {
+	setPrices()
+	hour := time.Date(2026, 5, 10, 14, 0, 0, 0, time.UTC)
+	svc := &meteringService{storage: &metering.MockMeteringStorage{
+		OnFindUsage: func(filter metering.UsageFilter) ([]metering.HourlyUsage, error) {
+			c.Assert(filter.Teams, check.DeepEquals, []string{"team1", "team2"})
+			return []metering.HourlyUsage{
+				{UsageKey: metering.UsageKey{App: "app1", Team: "team1", Pool: "p1", Plan: "small"}, Hour: hour, UnitSeconds: 7200, Memory: gigabyte, CPUMilli: 500},
+				{UsageKey: metering.UsageKey{App: "app2", Team: "team1", Pool: "p2", Plan: "large"}, Hour: hour, UnitSeconds: 3600, Memory: 2 * gigabyte, CPUMilli: 2000},
+				{UsageKey: metering.UsageKey{App: "app3", Team: "team2", Pool: "p1", Plan: "large"}, Hour: hour, UnitSeconds: 1800, Memory: 4 * gigabyte, CPUMilli: 1000},
+			}, nil
+		},
+	}}
+	report, err := svc.Report(context.TODO(), metering.ReportOptions{
+		UsageFilter: metering.UsageFilter{Start: hour, End: hour.Add(time.Hour), Teams: []string{"team1", "team2"}},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(report.GroupBy, check.Equals, metering.GroupByTeam)
+	c.Assert(report.Currency, check.Equals, "BRL")
+	c.Assert(report.Entries, check.DeepEquals, []metering.ReportEntry{
+		{Group: "team1", UnitHours: 3, MemoryGBHours: 4, CPUHours: 3, Cost: 2 + 4 + 0.5},
+		{Group: "team2", UnitHours: 0.5, MemoryGBHours: 2, CPUHours: 0.5, Cost: 1.5 + 1},
+	})
+	c.Assert(report.Total, check.DeepEquals, metering.ReportEntry{Group: "total", UnitHours: 3.5, MemoryGBHours: 6, CPUHours: 3.5, Cost: 9})
+}
+
+func (s *S) TestReportGroupByPool(c *check.C) {
+	svc := &meteringService{storage: &metering.MockMeteringStorage{
+		OnFindUsage: func(filter metering.UsageFilter) ([]metering.HourlyUsage, error) {
+			return []metering.HourlyUsage{
+				{UsageKey: metering.UsageKey{App: "app1", Team: "team1", Pool: "p2"}, UnitSeconds: 3600},
+				{UsageKey: metering.UsageKey{App: "app2", Team: "team1", Pool: "p1"}, UnitSeconds: 3600},
+				{UsageKey: metering.UsageKey{App: "app3", Team: "team2", Pool: "p2"}, UnitSeconds: 3600},
+			}, nil
+		},
+	}}
+	report, err := svc.Report(context.TODO(), metering.ReportOptions{GroupBy: metering.GroupByPool})
+	c.Assert(err, check.IsNil)
+	c.Assert(report.Currency, check.Equals, "USD")
+	c.Assert(report.Entries, check.DeepEquals, []metering.ReportEntry{
+		{Group: "p1", UnitHours: 1},
+		{Group: "p2", UnitHours: 2},
+	})
+}
+
+func (s *S) TestReportInvalidGroupBy(c *check.C) {
+	svc := &meteringService{storage: &metering.MockMeteringStorage{}}
+	_, err := svc.Report(context.TODO(), metering.ReportOptions{GroupBy: "plan"})
+	c.Assert(err, check.Equals, metering.ErrInvalidGroupBy)
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metering
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/app"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/storage"
+	"github.com/tsuru/tsuru/types/metering"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const (
+	defaultSampleInterval = 5 * time.Minute
+	minSampleInterval     = time.Minute
+)
+
+func sampleInterval() time.Duration {
+	interval, _ := config.GetDuration("metering:interval")
+	if interval <= 0 {
+		return defaultSampleInterval
+	}
+	if interval < minSampleInterval {
+		return minSampleInterval
+	}
+	return interval
+}
+
+// Initialize starts sampling the running units of all apps when metering is
+// enabled in the config file.
+func Initialize() error {
+	enabled, _ := config.GetBool("metering:enabled")
+	if !enabled {
+		return nil
+	}
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return err
+		}
+	}
+	interval := sampleInterval()
+	event.SetThrottling(event.ThrottlingSpec{
+		TargetType: event.TargetTypeMetering,
+		KindName:   "metering",
+		Time:       interval,
+		Max:        1,
+		AllTargets: true,
+		WaitFinish: true,
+	})
+	s := &sampler{
+		once:     &sync.Once{},
+		storage:  dbDriver.MeteringStorage,
+		interval: interval,
+	}
+	s.start()
+	shutdown.Register(s)
+	return nil
+}
+
+type sampler struct {
+	once     *sync.Once
+	stopCh   chan struct{}
+	storage  metering.MeteringStorage
+	interval time.Duration
+}
+
+func (s *sampler) start() {
+	s.once.Do(func() {
+		s.stopCh = make(chan struct{})
+		go s.spin()
+	})
+}
+
+func (s *sampler) Shutdown(ctx context.Context) error {
+	if s.stopCh == nil {
+		return nil
+	}
+	s.stopCh <- struct{}{}
+	s.stopCh = nil
+	s.once = &sync.Once{}
+	return nil
+}
+
+func (s *sampler) spin() {
+	for {
+		s.runPeriodicSample()
+
+		select {
+		case <-s.stopCh:
+			return
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+func (s *sampler) runPeriodicSample() (err error) {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeMetering, Value: "global"},
+		InternalKind: "metering",
+		Allowed:      event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxGlobal, "")),
+	})
+	defer func() {
+		if err != nil {
+			log.Errorf("[metering] %v", err)
+		}
+		if evt == nil {
+			return
+		}
+		if err == nil {
+			evt.Abort()
+		} else {
+			evt.Done(err)
+		}
+	}()
+	if err != nil {
+		_, isThrottled := err.(event.ErrThrottled)
+		_, isLocked := err.(event.ErrEventLocked)
+		if isThrottled || isLocked {
+			err = nil
+			return
+		}
+		err = errors.Wrap(err, "could not create event")
+		return
+	}
+	err = s.sample(context.Background(), time.Now())
+	return
+}
+
+// sample stores the running units of each app process. The sample time is
+// aligned to the interval, so concurrent samplers in different API instances
+// write to the same slot instead of counting the units twice.
+func (s *sampler) sample(ctx context.Context, now time.Time) error {
+	slot := now.UTC().Truncate(s.interval)
+	apps, err := app.List(ctx, nil)
+	if err != nil {
+		return err
+	}
+	appUnits, err := app.Units(ctx, apps)
+	if err != nil {
+		return err
+	}
+	multi := tsuruErrors.NewMultiError()
+	for i := range apps {
+		a := &apps[i]
+		rsp := appUnits[a.Name]
+		if rsp.Err != nil {
+			multi.Add(errors.Wrapf(rsp.Err, "unable to list units for app %q", a.Name))
+			continue
+		}
+		for process, units := range runningUnitsByProcess(rsp.Units) {
+			err = s.storage.AddSample(ctx, metering.UsageSample{
+				UsageKey: metering.UsageKey{
+					App:     a.Name,
+					Process: process,
+					Team:    a.TeamOwner,
+					Pool:    a.Pool,
+					Plan:    a.GetProcessPlan(process).Name,
+				},
+				Time:     slot,
+				Interval: s.interval,
+				Units:    units,
+				Memory:   a.GetProcessMemory(process),
+				CPUMilli: a.GetProcessMilliCPU(process),
+			})
+			if err != nil {
+				multi.Add(errors.Wrapf(err, "unable to store usage for app %q", a.Name))
+			}
+		}
+	}
+	return multi.ToError()
+}
+
+func runningUnitsByProcess(units []provision.Unit) map[string]int {
+	result := map[string]int{}
+	for _, u := range units {
+		if u.Status == provision.StatusStarted || u.Status == provision.StatusStarting {
+			result[u.ProcessName]++
+		}
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metering
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	"github.com/tsuru/tsuru/types/metering"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestSample(c *check.C) {
+	plan := appTypes.Plan{Name: "small", Memory: gigabyte, CPUMilli: 250}
+	a1 := app.App{Name: "app1", TeamOwner: "team1", Pool: "p1", Plan: plan}
+	a2 := app.App{Name: "app2", TeamOwner: "team2", Pool: "p1", Plan: plan}
+	for _, a := range []*app.App{&a1, &a2} {
+		err := s.storage.Apps().Insert(a)
+		c.Assert(err, check.IsNil)
+		err = provisiontest.ProvisionerInstance.Provision(context.TODO(), a)
+		c.Assert(err, check.IsNil)
+	}
+	err := provisiontest.ProvisionerInstance.AddUnits(context.TODO(), &a1, 2, "web", nil, nil)
+	c.Assert(err, check.IsNil)
+	err = provisiontest.ProvisionerInstance.AddUnits(context.TODO(), &a1, 1, "worker", nil, nil)
+	c.Assert(err, check.IsNil)
+	err = provisiontest.ProvisionerInstance.AddUnits(context.TODO(), &a2, 1, "web", nil, nil)
+	c.Assert(err, check.IsNil)
+	units, err := provisiontest.ProvisionerInstance.Units(context.TODO(), &a2)
+	c.Assert(err, check.IsNil)
+	err = provisiontest.ProvisionerInstance.SetUnitStatus(units[0], provision.StatusStopped)
+	c.Assert(err, check.IsNil)
+	var samples []metering.UsageSample
+	smp := &sampler{
+		interval: 5 * time.Minute,
+		storage: &metering.MockMeteringStorage{
+			OnAddSample: func(sample metering.UsageSample) error {
+				samples = append(samples, sample)
+				return nil
+			},
+		},
+	}
+	now := time.Date(2026, 5, 10, 14, 7, 31, 0, time.UTC)
+	err = smp.sample(context.TODO(), now)
+	c.Assert(err, check.IsNil)
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Process < samples[j].Process
+	})
+	slot := time.Date(2026, 5, 10, 14, 5, 0, 0, time.UTC)
+	c.Assert(samples, check.DeepEquals, []metering.UsageSample{
+		{
+			UsageKey: metering.UsageKey{App: "app1", Process: "web", Team: "team1", Pool: "p1", Plan: "small"},
+			Time:     slot,
+			Interval: 5 * time.Minute,
+			Units:    2,
+			Memory:   gigabyte,
+			CPUMilli: 250,
+		},
+		{
+			UsageKey: metering.UsageKey{App: "app1", Process: "worker", Team: "team1", Pool: "p1", Plan: "small"},
+			Time:     slot,
+			Interval: 5 * time.Minute,
+			Units:    1,
+			Memory:   gigabyte,
+			CPUMilli: 250,
+		},
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metering
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/dbtest"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	servicemock "github.com/tsuru/tsuru/servicemanager/mock"
+	_ "github.com/tsuru/tsuru/storage/mongodb"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct {
+	storage     *db.Storage
+	mockService servicemock.MockService
+}
+
+var _ = check.Suite(&S{})
+
+func (s *S) SetUpSuite(c *check.C) {
+	config.Set("log:disable-syslog", true)
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "app_metering_tests")
+	var err error
+	s.storage, err = db.Conn()
+	c.Assert(err, check.IsNil)
+	provision.DefaultProvisioner = "fake"
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	provisiontest.ProvisionerInstance.Reset()
+	servicemock.SetMockService(&s.mockService)
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "p1"})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	config.Unset("metering")
+	err := dbtest.ClearAllCollections(s.storage.Apps().Database)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	dbtest.ClearAllCollections(s.storage.Apps().Database)
+	s.storage.Close()
+}
@@ -5,7 +5,11 @@
 package app
 
 import (
+	"context"
+
+	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/quota"
+	"github.com/tsuru/tsuru/service"
 	"github.com/tsuru/tsuru/storage"
 	quotaTypes "github.com/tsuru/tsuru/types/quota"
 )
@@ -22,3 +26,92 @@ func QuotaService() (quotaTypes.QuotaService, error) {
 		Storage: dbDriver.AppQuotaStorage,
 	}, nil
 }
+
+// TeamQuotaService returns the service limiting the resources allocated by
+// the apps and service instances owned by each team.
+func TeamQuotaService() (quotaTypes.TeamQuotaService, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &quota.TeamQuotaService{
+		Storage:     dbDriver.TeamQuotaStorage,
+		UsageReader: &teamUsageReader{},
+	}, nil
+}
+
+type teamUsageReader struct{}
+
+// GetTeamUsage calculates the usage of a team from the units of its apps,
+// multiplied by the memory and cpu of the plan of each process, and from the
+// number of service instances owned by the team.
+func (r *teamUsageReader) GetTeamUsage(ctx context.Context, team string) (*quotaTypes.TeamUsage, error) {
+	apps, err := List(ctx, &Filter{TeamOwner: team})
+	if err != nil {
+		return nil, err
+	}
+	appUnits, err := Units(ctx, apps)
+	if err != nil {
+		return nil, err
+	}
+	usage := &quotaTypes.TeamUsage{}
+	for i := range apps {
+		a := &apps[i]
+		rsp := appUnits[a.Name]
+		if rsp.Err != nil {
+			return nil, rsp.Err
+		}
+		resources := a.quotaUnitsResources(rsp.Units)
+		usage.Apps = append(usage.Apps, quotaTypes.TeamAppUsage{
+			App:      a.Name,
+			Plan:     a.Plan.Name,
+			Units:    resources.Units,
+			Memory:   resources.Memory,
+			CPUMilli: resources.CPUMilli,
+		})
+	}
+	usage.ServiceInstances, err = service.CountServiceInstancesByTeamOwner(team)
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// unitsResources returns the team resources required by n units of the app.
+func (app *App) unitsResources(n int) quotaTypes.TeamResources {
+	return quotaTypes.TeamResources{
+		Units:    n,
+		Memory:   int64(n) * app.GetMemory(),
+		CPUMilli: n * app.GetMilliCPU(),
+	}
+}
+
+// processResources returns the team resources required by n units of a
+// process of the app.
+func (app *App) processResources(process string, n int) quotaTypes.TeamResources {
+	return quotaTypes.TeamResources{
+		Units:    n,
+		Memory:   int64(n) * app.GetProcessMemory(process),
+		CPUMilli: n * app.GetProcessMilliCPU(process),
+	}
+}
+
+// quotaUnitsResources returns the team resources used by the units counted
+// in the quota, using the plan of the process of each unit.
+func (app *App) quotaUnitsResources(units []provision.Unit) quotaTypes.TeamResources {
+	unitsByProcess := map[string][]provision.Unit{}
+	for _, u := range units {
+		unitsByProcess[u.ProcessName] = append(unitsByProcess[u.ProcessName], u)
+	}
+	var total quotaTypes.TeamResources
+	for process, processUnits := range unitsByProcess {
+		resources := app.processResources(process, countQuotaUnits(processUnits))
+		total.Units += resources.Units
+		total.Memory += resources.Memory
+		total.CPUMilli += resources.CPUMilli
+	}
+	return total
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestGetTeamUsage(c *check.C) {
+	plan := appTypes.Plan{Name: "large", Memory: 4096, CPUMilli: 1000}
+	a1 := App{Name: "app1", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a1, s.user)
+	c.Assert(err, check.IsNil)
+	a2 := App{Name: "app2", Platform: "python", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &a2, s.user)
+	c.Assert(err, check.IsNil)
+	err = s.conn.Apps().Update(bson.M{"name": a2.Name}, bson.M{"$set": bson.M{"plan": plan}})
+	c.Assert(err, check.IsNil)
+	other := App{Name: "other", Platform: "python", TeamOwner: "other-team"}
+	err = s.conn.Apps().Insert(other)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a1, 2, "web", newSuccessfulAppVersion(c, &a1), nil)
+	s.provisioner.AddUnits(context.TODO(), &a2, 1, "web", newSuccessfulAppVersion(c, &a2), nil)
+	s.provisioner.AddUnits(context.TODO(), &other, 3, "web", newSuccessfulAppVersion(c, &other), nil)
+	err = s.conn.ServiceInstances().Insert(
+		bson.M{"name": "db1", "service_name": "mysql", "teamowner": s.team.Name},
+		bson.M{"name": "db2", "service_name": "mysql", "teamowner": "other-team"},
+	)
+	c.Assert(err, check.IsNil)
+	usage, err := (&teamUsageReader{}).GetTeamUsage(context.TODO(), s.team.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(usage.ServiceInstances, check.Equals, 1)
+	c.Assert(usage.Apps, check.HasLen, 2)
+	byApp := map[string]quota.TeamAppUsage{}
+	for _, u := range usage.Apps {
+		byApp[u.App] = u
+	}
+	c.Assert(byApp["app1"], check.DeepEquals, quota.TeamAppUsage{App: "app1", Plan: s.defaultPlan.Name, Units: 2, Memory: 2 * s.defaultPlan.Memory})
+	c.Assert(byApp["app2"], check.DeepEquals, quota.TeamAppUsage{App: "app2", Plan: "large", Units: 1, Memory: 4096, CPUMilli: 1000})
+	c.Assert(usage.Total(), check.DeepEquals, quota.TeamResources{
+		Units:            3,
+		Memory:           2*s.defaultPlan.Memory + 4096,
+		CPUMilli:         1000,
+		ServiceInstances: 1,
+	})
+}
+
+func (s *S) TestCreateAppTeamQuotaExceeded(c *check.C) {
+	a := App{Name: "america", Platform: "python", TeamOwner: s.team.Name}
+	s.mockService.TeamQuota.OnCheck = func(team string, requested quota.TeamResources) error {
+		c.Assert(team, check.Equals, s.team.Name)
+		c.Assert(requested, check.DeepEquals, quota.TeamResources{Units: 1, Memory: s.defaultPlan.Memory})
+		return &quota.TeamQuotaExceededError{Team: team, Resource: "units", Requested: 1}
+	}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.FitsTypeOf, &quota.TeamQuotaExceededError{})
+	_, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.Equals, appTypes.ErrAppNotFound)
+}
+
+func (s *S) TestAddUnitsTeamQuotaExceeded(c *check.C) {
+	a := App{Name: "warpaint", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	s.mockService.TeamQuota.OnCheck = func(team string, requested quota.TeamResources) error {
+		c.Assert(team, check.Equals, s.team.Name)
+		c.Assert(requested, check.DeepEquals, quota.TeamResources{Units: 3, Memory: 3 * s.defaultPlan.Memory})
+		return &quota.TeamQuotaExceededError{Team: team, Resource: "units", Requested: 3, Available: 2}
+	}
+	err = a.AddUnits(3, "web", "", nil)
+	c.Assert(err, check.FitsTypeOf, &quota.TeamQuotaExceededError{})
+	c.Assert(s.provisioner.GetUnits(&a), check.HasLen, 0)
+}
+
+func (s *S) TestUpdatePlanTeamQuotaExceeded(c *check.C) {
+	plan := appTypes.Plan{Name: "large", Memory: 4096, CPUMilli: 500}
+	s.mockService.Plan.OnFindByName = func(name string) (*appTypes.Plan, error) {
+		return &plan, nil
+	}
+	s.mockService.Plan.OnList = func() ([]appTypes.Plan, error) {
+		return []appTypes.Plan{s.defaultPlan, plan}, nil
+	}
+	a := App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 3, "web", newSuccessfulAppVersion(c, &a), nil)
+	s.mockService.TeamQuota.OnCheck = func(team string, requested quota.TeamResources) error {
+		c.Assert(team, check.Equals, s.team.Name)
+		c.Assert(requested, check.DeepEquals, quota.TeamResources{Memory: 3 * (4096 - s.defaultPlan.Memory), CPUMilli: 1500})
+		return &quota.TeamQuotaExceededError{Team: team, Resource: "memory"}
+	}
+	err = a.Update(UpdateAppArgs{UpdateData: App{Plan: appTypes.Plan{Name: "large"}}, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.FitsTypeOf, &quota.TeamQuotaExceededError{})
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Plan, check.DeepEquals, s.defaultPlan)
+}
+
+func (s *S) TestUpdateProcessPlanTeamQuotaExceeded(c *check.C) {
+	plan := appTypes.Plan{Name: "large", Memory: 4096, CPUMilli: 500}
+	s.mockService.Plan.OnFindByName = func(name string) (*appTypes.Plan, error) {
+		return &plan, nil
+	}
+	s.mockService.Plan.OnList = func() ([]appTypes.Plan, error) {
+		return []appTypes.Plan{s.defaultPlan, plan}, nil
+	}
+	a := App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	err = version.AddData(appTypes.AddVersionDataArgs{
+		Processes: map[string][]string{"web": {"python app.py"}, "worker": {"python worker.py"}},
+	})
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 3, "web", version, nil)
+	s.provisioner.AddUnits(context.TODO(), &a, 2, "worker", version, nil)
+	s.mockService.TeamQuota.OnCheck = func(team string, requested quota.TeamResources) error {
+		c.Assert(team, check.Equals, s.team.Name)
+		c.Assert(requested, check.DeepEquals, quota.TeamResources{Memory: 2 * (4096 - s.defaultPlan.Memory), CPUMilli: 1000})
+		return &quota.TeamQuotaExceededError{Team: team, Resource: "memory"}
+	}
+	updateData := App{ProcessPlans: map[string]appTypes.Plan{"worker": {Name: "large"}}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.FitsTypeOf, &quota.TeamQuotaExceededError{})
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ProcessPlans, check.IsNil)
+}
+
+func (s *S) TestAutoScaleTeamQuotaExceeded(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "autoscaleProv"
+	provision.Register("autoscaleProv", func() (provision.Provisioner, error) {
+		return &provisiontest.AutoScaleProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("autoscaleProv")
+	a := App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 2, "web", newSuccessfulAppVersion(c, &a), nil)
+	s.mockService.TeamQuota.OnCheck = func(team string, requested quota.TeamResources) error {
+		c.Assert(requested, check.DeepEquals, quota.TeamResources{Units: 8, Memory: 8 * s.defaultPlan.Memory})
+		return &quota.TeamQuotaExceededError{Team: team, Resource: "units"}
+	}
+	err = a.AutoScale(provision.AutoScaleSpec{Process: "web", MinUnits: 1, MaxUnits: 10, AverageCPU: "70"})
+	c.Assert(err, check.FitsTypeOf, &quota.TeamQuotaExceededError{})
+}
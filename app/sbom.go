@@ -0,0 +1,423 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/registry"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const vulnerabilityGateKind = "vulnerability-gate"
+
+var (
+	ErrVersionSBOMNotFound = errors.New("SBOM not found for app version")
+
+	severityRanks = map[string]int{
+		"low":      1,
+		"medium":   2,
+		"high":     3,
+		"critical": 4,
+	}
+
+	vulnerabilityDB vulnerabilityDatabase
+)
+
+// VersionSBOM is the SBOM generated from the image of an app version.
+type VersionSBOM struct {
+	App           string    `json:"app"`
+	Version       int       `json:"version"`
+	GeneratedAt   time.Time `json:"generatedAt"`
+	registry.SBOM `bson:",inline"`
+}
+
+// Vulnerability is an entry of the vulnerability database. Versions of the
+// package lower than FixedVersion are affected, every version is affected
+// when there's no fixed version.
+type Vulnerability struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Package      string `json:"package"`
+	Severity     string `json:"severity"`
+	FixedVersion string `json:"fixedVersion,omitempty"`
+}
+
+// VersionVulnerability is a vulnerability affecting a package installed in
+// the image of an app version.
+type VersionVulnerability struct {
+	Vulnerability
+	InstalledVersion string `json:"installedVersion"`
+}
+
+// ErrVersionVulnerable is returned when an app version can't become routable
+// because its image has vulnerabilities at or above the severity set by the
+// vulnerability-severity constraint of the app pool.
+type ErrVersionVulnerable struct {
+	App             string
+	Version         int
+	Pool            string
+	Severity        string
+	Vulnerabilities []VersionVulnerability
+}
+
+func (e *ErrVersionVulnerable) Error() string {
+	ids := make([]string, len(e.Vulnerabilities))
+	for i, v := range e.Vulnerabilities {
+		ids[i] = v.ID
+	}
+	return fmt.Sprintf("version %d of app %q has vulnerabilities with severity %s or higher, blocked by pool %q: %s", e.Version, e.App, e.Severity, e.Pool, strings.Join(ids, ", "))
+}
+
+// GetVersionSBOM returns the SBOM stored for the app version.
+func GetVersionSBOM(appName string, version int) (*VersionSBOM, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var sbom VersionSBOM
+	err = conn.AppVersionSBOMs().Find(bson.M{"app": appName, "version": version}).One(&sbom)
+	if err == mgo.ErrNotFound {
+		return nil, ErrVersionSBOMNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sbom, nil
+}
+
+// generateVersionSBOM generates the SBOM of the version image in the tsuru
+// registry and stores it, removing the SBOMs of versions of the app that no
+// longer exist.
+func generateVersionSBOM(ctx context.Context, app *App, version appTypes.AppVersion, w io.Writer) (*VersionSBOM, error) {
+	deployImage := version.VersionInfo().DeployImage
+	registryHost, _ := config.GetString("docker:registry")
+	if deployImage == "" || registryHost == "" || !strings.HasPrefix(deployImage, registryHost+"/") {
+		return nil, errors.Errorf("image %q is not in the tsuru registry", deployImage)
+	}
+	sbom, err := registry.ImageSBOM(ctx, version.VersionInfo().DeployImageReference(), nil)
+	if err != nil {
+		return nil, err
+	}
+	versionSBOM := &VersionSBOM{
+		App:         app.Name,
+		Version:     version.Version(),
+		GeneratedAt: time.Now().UTC(),
+		SBOM:        *sbom,
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_, err = conn.AppVersionSBOMs().Upsert(bson.M{"app": app.Name, "version": versionSBOM.Version}, versionSBOM)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(w, " ---> SBOM generated for image %s with %d packages\n", deployImage, len(sbom.Packages))
+	versions, err := servicemanager.AppVersion.AppVersions(ctx, app)
+	if err == nil {
+		existing := []int{}
+		for v := range versions.Versions {
+			existing = append(existing, v)
+		}
+		_, err = conn.AppVersionSBOMs().RemoveAll(bson.M{"app": app.Name, "version": bson.M{"$nin": existing}})
+	}
+	if err != nil {
+		log.Errorf("unable to remove stale SBOMs of app %s: %v", app.Name, err)
+	}
+	return versionSBOM, nil
+}
+
+// recordVersionSBOM generates the SBOM of a new version. Failures don't fail
+// the deploy, versions without SBOM are scanned again before becoming
+// routable in pools requiring it.
+func recordVersionSBOM(ctx context.Context, app *App, version appTypes.AppVersion, w io.Writer) {
+	_, err := generateVersionSBOM(ctx, app, version, w)
+	if err != nil {
+		log.Errorf("unable to generate SBOM for version %d of app %s: %v", version.Version(), app.Name, err)
+		fmt.Fprintf(w, " ---> Unable to generate SBOM for version %d\n", version.Version())
+	}
+}
+
+// checkVersionVulnerabilities enforces the vulnerability-severity constraint
+// of the app pool before the version becomes routable, matching the version
+// SBOM against the vulnerability database. Blocked versions are recorded in
+// an internal event targeting the app.
+func checkVersionVulnerabilities(ctx context.Context, app *App, version appTypes.AppVersion, w io.Writer) error {
+	p := pool.Pool{Name: app.Pool}
+	severity, err := p.GetVulnerabilitySeverity()
+	if err != nil {
+		return err
+	}
+	if severity == "" {
+		return nil
+	}
+	threshold, ok := severityRanks[severity]
+	if !ok {
+		return errors.Errorf("invalid vulnerability severity %q for pool %q", severity, app.Pool)
+	}
+	fmt.Fprintf(w, "---- Checking vulnerabilities of version %d ----\n", version.Version())
+	sbom, err := GetVersionSBOM(app.Name, version.Version())
+	if err == ErrVersionSBOMNotFound {
+		sbom, err = generateVersionSBOM(ctx, app, version, w)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to get SBOM for version %d, required by pool %q", version.Version(), app.Pool)
+	}
+	vulns, err := vulnerabilityDB.match(sbom.Packages)
+	if err != nil {
+		return err
+	}
+	var blocking []VersionVulnerability
+	for _, v := range vulns {
+		if severityRanks[v.Severity] >= threshold {
+			blocking = append(blocking, v)
+		}
+	}
+	if len(blocking) == 0 {
+		fmt.Fprintf(w, " ---> No vulnerabilities with severity %s or higher found\n", severity)
+		return nil
+	}
+	for _, v := range blocking {
+		fmt.Fprintf(w, " ---> %s (%s): %s %s %s, fixed in %q\n", v.ID, v.Severity, v.Type, v.Package, v.InstalledVersion, v.FixedVersion)
+	}
+	vulnErr := &ErrVersionVulnerable{
+		App:             app.Name,
+		Version:         version.Version(),
+		Pool:            app.Pool,
+		Severity:        severity,
+		Vulnerabilities: blocking,
+	}
+	auditErr := recordVulnerabilityGate(sbom, vulnErr)
+	if auditErr != nil {
+		log.Errorf("unable to record vulnerability gate for app %q: %v", app.Name, auditErr)
+	}
+	return vulnErr
+}
+
+func recordVulnerabilityGate(sbom *VersionSBOM, vulnErr *ErrVersionVulnerable) error {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: vulnErr.App},
+		InternalKind: vulnerabilityGateKind,
+		CustomData: map[string]interface{}{
+			"version":         vulnErr.Version,
+			"image":           sbom.Image,
+			"digest":          sbom.Digest,
+			"pool":            vulnErr.Pool,
+			"severity":        vulnErr.Severity,
+			"vulnerabilities": vulnErr.Vulnerabilities,
+		},
+		DisableLock: true,
+		Allowed:     event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, vulnErr.App)),
+	})
+	if err != nil {
+		return err
+	}
+	return evt.Done(vulnErr)
+}
+
+// removeVersionSBOMs removes the SBOMs of every version of the app.
+func removeVersionSBOMs(appName string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.AppVersionSBOMs().RemoveAll(bson.M{"app": appName})
+	return err
+}
+
+// vulnerabilityDatabase is the vulnerability database loaded from the file
+// in the sbom:vulnerability-database config, reloaded when the file changes.
+type vulnerabilityDatabase struct {
+	sync.Mutex
+	path     string
+	modTime  time.Time
+	packages map[string][]Vulnerability
+}
+
+func (d *vulnerabilityDatabase) load() (map[string][]Vulnerability, error) {
+	path, err := config.GetString("sbom:vulnerability-database")
+	if err != nil {
+		return nil, errors.New("vulnerability database is not configured")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read vulnerability database")
+	}
+	d.Lock()
+	defer d.Unlock()
+	if d.packages != nil && d.path == path && d.modTime.Equal(info.ModTime()) {
+		return d.packages, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read vulnerability database")
+	}
+	var content struct {
+		Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	}
+	err = json.Unmarshal(data, &content)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid vulnerability database")
+	}
+	packages := map[string][]Vulnerability{}
+	for _, v := range content.Vulnerabilities {
+		v.Severity = strings.ToLower(v.Severity)
+		key := v.Type + "/" + v.Package
+		packages[key] = append(packages[key], v)
+	}
+	d.path, d.modTime, d.packages = path, info.ModTime(), packages
+	return packages, nil
+}
+
+// match returns the vulnerabilities affecting the packages.
+func (d *vulnerabilityDatabase) match(pkgs []registry.SBOMPackage) ([]VersionVulnerability, error) {
+	packages, err := d.load()
+	if err != nil {
+		return nil, err
+	}
+	var result []VersionVulnerability
+	for _, pkg := range pkgs {
+		for _, v := range packages[pkg.Type+"/"+pkg.Name] {
+			if v.FixedVersion == "" || compareVersions(pkg.Version, v.FixedVersion) < 0 {
+				result = append(result, VersionVulnerability{Vulnerability: v, InstalledVersion: pkg.Version})
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return severityRanks[result[i].Severity] > severityRanks[result[j].Severity]
+	})
+	return result, nil
+}
+
+// compareVersions compares package versions using the dpkg rules, which also
+// order apk and most python versions: an optional numeric epoch, followed by
+// the upstream version and an optional revision after the last dash.
+func compareVersions(a, b string) int {
+	aEpoch, a := splitEpoch(a)
+	bEpoch, b := splitEpoch(b)
+	if aEpoch != bEpoch {
+		if aEpoch < bEpoch {
+			return -1
+		}
+		return 1
+	}
+	aUpstream, aRevision := splitRevision(a)
+	bUpstream, bRevision := splitRevision(b)
+	if c := compareVersionPart(aUpstream, bUpstream); c != 0 {
+		return c
+	}
+	return compareVersionPart(aRevision, bRevision)
+}
+
+func splitEpoch(version string) (int, string) {
+	idx := strings.Index(version, ":")
+	if idx <= 0 {
+		return 0, version
+	}
+	epoch := 0
+	for _, c := range version[:idx] {
+		if c < '0' || c > '9' {
+			return 0, version
+		}
+		epoch = epoch*10 + int(c-'0')
+	}
+	return epoch, version[idx+1:]
+}
+
+func splitRevision(version string) (string, string) {
+	idx := strings.LastIndex(version, "-")
+	if idx < 0 {
+		return version, ""
+	}
+	return version[:idx], version[idx+1:]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// versionCharOrder sorts tildes before anything, even the end of the version,
+// and letters before other characters.
+func versionCharOrder(s string, i int) int {
+	if i >= len(s) || isDigit(s[i]) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case c == '~':
+		return -1
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	}
+	return int(c) + 256
+}
+
+func compareVersionPart(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := versionCharOrder(a, i), versionCharOrder(b, j)
+			if ac != bc {
+				if ac < bc {
+					return -1
+				}
+				return 1
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			if firstDiff < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/builder"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/registry"
+	registrytest "github.com/tsuru/tsuru/registry/testing"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) setupVulnerabilityGate(c *check.C) (*registrytest.RegistryServer, func()) {
+	server, err := registrytest.NewServer("127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	config.Set("docker:registry", server.Addr())
+	dir, err := ioutil.TempDir("", "vulndb")
+	c.Assert(err, check.IsNil)
+	dbPath := filepath.Join(dir, "vulnerabilities.json")
+	err = ioutil.WriteFile(dbPath, []byte(`{"vulnerabilities": [
+		{"id": "CVE-2023-0001", "type": "deb", "package": "openssl", "severity": "critical", "fixedVersion": "3.0.11-1~deb12u2"},
+		{"id": "CVE-2023-0002", "type": "deb", "package": "openssl", "severity": "high", "fixedVersion": "3.0.9"},
+		{"id": "CVE-2023-0003", "type": "python", "package": "requests", "severity": "low"}
+	]}`), 0644)
+	c.Assert(err, check.IsNil)
+	config.Set("sbom:vulnerability-database", dbPath)
+	var layer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&layer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	files := map[string]string{
+		"var/lib/dpkg/status": "Package: openssl\nStatus: install ok installed\nVersion: 3.0.11-1~deb12u1\n",
+		"usr/lib/python3/site-packages/requests-2.31.0.dist-info/METADATA": "Name: requests\nVersion: 2.31.0\n",
+	}
+	for name, content := range files {
+		err = tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		c.Assert(err, check.IsNil)
+		_, err = tarWriter.Write([]byte(content))
+		c.Assert(err, check.IsNil)
+	}
+	c.Assert(tarWriter.Close(), check.IsNil)
+	c.Assert(gzipWriter.Close(), check.IsNil)
+	layerDigest := server.PushBlob("tsuru/app-some-app", layer.Bytes())
+	server.PushManifest("tsuru/app-some-app", "v1", []byte(fmt.Sprintf(`{"schemaVersion": 2, "layers": [{"digest": %q}]}`, layerDigest)))
+	s.builder.OnBuild = func(p provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+		version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+		if err != nil {
+			return nil, err
+		}
+		return version, version.CommitBaseImage()
+	}
+	return server, func() {
+		server.Stop()
+		os.RemoveAll(dir)
+		config.Set("docker:registry", "registry.somewhere")
+		config.Unset("sbom")
+	}
+}
+
+func (s *S) TestDeployAppGeneratesSBOM(c *check.C) {
+	_, cleanup := s.setupVulnerabilityGate(c)
+	defer cleanup()
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.String(), check.Matches, `(?s).*SBOM generated for image .*/tsuru/app-some-app:v1 with 2 packages.*`)
+	sbom, err := GetVersionSBOM(a.Name, 1)
+	c.Assert(err, check.IsNil)
+	c.Assert(sbom.Packages, check.DeepEquals, []registry.SBOMPackage{
+		{Name: "openssl", Version: "3.0.11-1~deb12u1", Type: registry.PackageTypeDeb},
+		{Name: "requests", Version: "2.31.0", Type: registry.PackageTypePython},
+	})
+	_, err = GetVersionSBOM(a.Name, 2)
+	c.Assert(err, check.Equals, ErrVersionSBOMNotFound)
+}
+
+func (s *S) TestDeployAppVulnerabilityGate(c *check.C) {
+	_, cleanup := s.setupVulnerabilityGate(c)
+	defer cleanup()
+	err := pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: s.Pool, Field: pool.ConstraintTypeVulnerabilitySeverity, Values: []string{"high"}})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.NotNil)
+	vulnErr, ok := errors.Cause(err).(*ErrVersionVulnerable)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(vulnErr.Vulnerabilities, check.DeepEquals, []VersionVulnerability{
+		{
+			Vulnerability:    Vulnerability{ID: "CVE-2023-0001", Type: "deb", Package: "openssl", Severity: "critical", FixedVersion: "3.0.11-1~deb12u2"},
+			InstalledVersion: "3.0.11-1~deb12u1",
+		},
+	})
+	c.Assert(writer.String(), check.Matches, `(?s).*CVE-2023-0001 \(critical\): deb openssl 3.0.11-1~deb12u1.*`)
+	c.Assert(writer.String(), check.Not(check.Matches), `(?s).*Builder deploy called.*`)
+	evts, err := event.List(&event.Filter{KindNames: []string{vulnerabilityGateKind}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Target, check.Equals, event.Target{Type: event.TargetTypeApp, Value: a.Name})
+}
+
+func (s *S) TestDeployAppVulnerabilityGateNewVersion(c *check.C) {
+	_, cleanup := s.setupVulnerabilityGate(c)
+	defer cleanup()
+	err := pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: s.Pool, Field: pool.ConstraintTypeVulnerabilitySeverity, Values: []string{"high"}})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		Image:        "myimage",
+		NewVersion:   true,
+		OutputStream: writer,
+		Event:        s.newDeployEvent(c, &a),
+	})
+	c.Assert(err, check.IsNil)
+	version, err := servicemanager.AppVersion.VersionByImageOrVersion(context.TODO(), &a, "1")
+	c.Assert(err, check.IsNil)
+	err = checkVersionVulnerabilities(context.TODO(), &a, version, ioutil.Discard)
+	c.Assert(err, check.FitsTypeOf, &ErrVersionVulnerable{})
+}
+
+func (s *S) TestCheckVersionVulnerabilitiesPolicy(c *check.C) {
+	_, cleanup := s.setupVulnerabilityGate(c)
+	defer cleanup()
+	config.Set("sbom:vulnerability-database", "/dev/null/missing")
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: &a})
+	c.Assert(err, check.IsNil)
+	err = version.CommitBaseImage()
+	c.Assert(err, check.IsNil)
+	err = checkVersionVulnerabilities(context.TODO(), &a, version, ioutil.Discard)
+	c.Assert(err, check.IsNil)
+	err = pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: s.Pool, Field: pool.ConstraintTypeVulnerabilitySeverity, Values: []string{"severe"}})
+	c.Assert(err, check.IsNil)
+	err = checkVersionVulnerabilities(context.TODO(), &a, version, ioutil.Discard)
+	c.Assert(err, check.ErrorMatches, `invalid vulnerability severity "severe" for pool .*`)
+	err = pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: s.Pool, Field: pool.ConstraintTypeVulnerabilitySeverity, Values: []string{"critical"}})
+	c.Assert(err, check.IsNil)
+	err = checkVersionVulnerabilities(context.TODO(), &a, version, ioutil.Discard)
+	c.Assert(err, check.ErrorMatches, `unable to read vulnerability database: .*`)
+}
+
+func (s *S) TestCompareVersions(c *check.C) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.1", -1},
+		{"1.10", "1.9", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0", "1.0a", -1},
+		{"1:1.0", "2.0", 1},
+		{"3.0.11-1~deb12u1", "3.0.11-1~deb12u2", -1},
+		{"3.0.11-1", "3.0.11-1~deb12u2", 1},
+		{"1.2.4-r2", "1.2.4-r10", -1},
+		{"2.31.0", "2.31", 1},
+		{"1.01", "1.1", 0},
+	}
+	for _, tt := range tests {
+		c.Check(compareVersions(tt.a, tt.b), check.Equals, tt.expected, check.Commentf("%s vs %s", tt.a, tt.b))
+		c.Check(compareVersions(tt.b, tt.a), check.Equals, -tt.expected, check.Commentf("%s vs %s", tt.b, tt.a))
+	}
+}
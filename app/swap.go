@@ -0,0 +1,205 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	tsuruNet "github.com/tsuru/tsuru/net"
+	"github.com/tsuru/tsuru/provision"
+	routerTypes "github.com/tsuru/tsuru/types/router"
+)
+
+const (
+	defaultSwapMonitorInterval = 5 * time.Second
+
+	// swapRevertTimeout bounds the time spent reverting a swap, and is also
+	// added to the monitor duration to let the last healthcheck finish.
+	swapRevertTimeout = time.Minute
+)
+
+var swapHealthcheckClient = tsuruNet.Dial15Full60ClientNoKeepAlive
+
+// GuardedSwapArgs holds the options used by GuardedSwap. The target app is
+// the one whose units will receive the traffic previously served by the
+// source app.
+type GuardedSwapArgs struct {
+	CNameOnly       bool
+	SmokeTest       string
+	MonitorDuration time.Duration
+	MonitorInterval time.Duration
+	Writer          io.Writer
+}
+
+// SwapVerificationError is returned by GuardedSwap when one of the
+// verification steps fails. Reverted indicates whether the swap had already
+// been performed and was rolled back.
+type SwapVerificationError struct {
+	App      string
+	Step     string
+	Reverted bool
+	Err      error
+}
+
+func (e *SwapVerificationError) Error() string {
+	msg := fmt.Sprintf("swap %s verification failed for app %q: %v", e.Step, e.App, e.Err)
+	if e.Reverted {
+		msg += ", swap reverted"
+	}
+	return msg
+}
+
+func (e *SwapVerificationError) Cause() error {
+	return e.Err
+}
+
+// GuardedSwap verifies the target app healthcheck through its router and
+// optionally runs a smoke test command in it before swapping source and
+// target. If a monitor duration is set, the healthcheck is run periodically
+// against the new backend after the swap and the apps are swapped back on
+// the first failure.
+func GuardedSwap(ctx context.Context, source, target *App, args GuardedSwapArgs) error {
+	w := args.Writer
+	if w == nil {
+		w = ioutil.Discard
+	}
+	fmt.Fprintf(w, "---- Verifying healthcheck of app %q ----\n", target.Name)
+	err := checkRouterHealthcheck(ctx, target, w)
+	if err != nil {
+		return &SwapVerificationError{App: target.Name, Step: "healthcheck", Err: err}
+	}
+	if args.SmokeTest != "" {
+		fmt.Fprintf(w, "---- Running smoke test in app %q ----\n", target.Name)
+		err = target.Run(args.SmokeTest, w, provision.RunArgs{Once: true})
+		if err != nil {
+			return &SwapVerificationError{App: target.Name, Step: "smoke test", Err: err}
+		}
+	}
+	fmt.Fprintf(w, "---- Swapping apps %q and %q ----\n", source.Name, target.Name)
+	err = Swap(ctx, source, target, args.CNameOnly)
+	if err != nil {
+		return err
+	}
+	if args.MonitorDuration <= 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "---- Monitoring app %q for %s ----\n", target.Name, args.MonitorDuration)
+	// The swap is already done, monitoring and reverting it must not be
+	// interrupted when the client goes away.
+	monitorCtx, cancel := context.WithTimeout(context.Background(), args.MonitorDuration+swapRevertTimeout)
+	defer cancel()
+	err = monitorSwap(monitorCtx, target, args.MonitorDuration, args.MonitorInterval, w)
+	if err == nil {
+		return nil
+	}
+	fmt.Fprintf(w, "---- Healthcheck failed after swap, reverting: %v ----\n", err)
+	revertCtx, revertCancel := context.WithTimeout(context.Background(), swapRevertTimeout)
+	defer revertCancel()
+	revertErr := Swap(revertCtx, source, target, args.CNameOnly)
+	if revertErr != nil {
+		return errors.Wrapf(revertErr, "unable to revert swap after verification failure: %v", err)
+	}
+	return &SwapVerificationError{App: target.Name, Step: "post-swap", Reverted: true, Err: err}
+}
+
+func monitorSwap(ctx context.Context, a *App, duration, interval time.Duration, w io.Writer) error {
+	if interval <= 0 {
+		interval = defaultSwapMonitorInterval
+	}
+	timeout := time.After(duration)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return nil
+		case <-ticker.C:
+			err := checkRouterHealthcheck(ctx, a, w)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkRouterHealthcheck runs the http healthcheck of the app against each
+// of its router addresses, even when the provisioner of the app handles
+// healthchecks itself.
+func checkRouterHealthcheck(ctx context.Context, a *App, w io.Writer) error {
+	hcData, err := a.healthcheckData(false)
+	if err != nil {
+		return err
+	}
+	addrs, err := a.GetAddresses()
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return errors.New("no router addresses available")
+	}
+	for _, addr := range addrs {
+		fmt.Fprintf(w, " ---> Checking %s (%s)\n", addr, hcData.String())
+		err = checkAddress(ctx, addr, hcData)
+		if err != nil {
+			return errors.Wrapf(err, "healthcheck failed for %q", addr)
+		}
+	}
+	return nil
+}
+
+func checkAddress(ctx context.Context, addr string, hcData routerTypes.HealthcheckData) error {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return err
+	}
+	if hcData.Scheme != "" {
+		u.Scheme = strings.ToLower(hcData.Scheme)
+	}
+	u.Path = hcData.Path
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range hcData.Headers {
+		req.Header.Set(k, v)
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+	rsp, err := swapHealthcheckClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if hcData.Status != 0 && rsp.StatusCode != hcData.Status {
+		return errors.Errorf("unexpected status code %d, expected %d", rsp.StatusCode, hcData.Status)
+	}
+	if hcData.Status == 0 && rsp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("unexpected status code %d", rsp.StatusCode)
+	}
+	if hcData.Body != "" {
+		data, err := ioutil.ReadAll(rsp.Body)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(data), hcData.Body) {
+			return errors.Errorf("response body does not contain %q", hcData.Body)
+		}
+	}
+	return nil
+}
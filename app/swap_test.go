@@ -0,0 +1,188 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/router/routertest"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createSwapApps(c *check.C, srvURL string) (*App, *App) {
+	app1 := &App{Name: "app1", CName: []string{"app1.cname"}, TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := &App{Name: "app2", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), app2, s.user)
+	c.Assert(err, check.IsNil)
+	u, err := url.Parse(srvURL)
+	c.Assert(err, check.IsNil)
+	routertest.FakeRouter.SetBackendAddr(context.TODO(), app1.Name, u.Host)
+	routertest.FakeRouter.SetBackendAddr(context.TODO(), app2.Name, u.Host)
+	return app1, app2
+}
+
+func (s *S) TestGuardedSwap(c *check.C) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	app1, app2 := s.createSwapApps(c, srv.URL)
+	buf := bytes.Buffer{}
+	err := GuardedSwap(context.TODO(), app1, app2, GuardedSwapArgs{Writer: &buf})
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(1))
+	c.Assert(app1.CName, check.IsNil)
+	c.Assert(app2.CName, check.DeepEquals, []string{"app1.cname"})
+	c.Assert(buf.String(), check.Matches, `(?s).*Verifying healthcheck of app "app2".*Swapping apps "app1" and "app2".*`)
+}
+
+func (s *S) TestGuardedSwapHealthcheckFailure(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	app1, app2 := s.createSwapApps(c, srv.URL)
+	err := GuardedSwap(context.TODO(), app1, app2, GuardedSwapArgs{})
+	c.Assert(err, check.FitsTypeOf, &SwapVerificationError{})
+	verifyErr := err.(*SwapVerificationError)
+	c.Assert(verifyErr.Step, check.Equals, "healthcheck")
+	c.Assert(verifyErr.Reverted, check.Equals, false)
+	c.Assert(app1.CName, check.DeepEquals, []string{"app1.cname"})
+	c.Assert(app2.CName, check.IsNil)
+}
+
+func (s *S) TestGuardedSwapSmokeTest(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	app1, app2 := s.createSwapApps(c, srv.URL)
+	version := newSuccessfulAppVersion(c, app2)
+	err := s.provisioner.AddUnits(context.TODO(), app2, 1, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	s.provisioner.PrepareOutput([]byte("smoke ok"))
+	buf := bytes.Buffer{}
+	err = GuardedSwap(context.TODO(), app1, app2, GuardedSwapArgs{SmokeTest: "./smoke.sh", Writer: &buf})
+	c.Assert(err, check.IsNil)
+	units, err := app2.Units()
+	c.Assert(err, check.IsNil)
+	execs := s.provisioner.Execs(units[0].ID)
+	c.Assert(execs, check.HasLen, 1)
+	c.Assert(execs[0].Cmds[2], check.Matches, `.*\./smoke\.sh$`)
+	c.Assert(buf.String(), check.Matches, `(?s).*smoke ok.*`)
+	c.Assert(app2.CName, check.DeepEquals, []string{"app1.cname"})
+}
+
+func (s *S) TestGuardedSwapSmokeTestFailure(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	app1, app2 := s.createSwapApps(c, srv.URL)
+	version := newSuccessfulAppVersion(c, app2)
+	err := s.provisioner.AddUnits(context.TODO(), app2, 1, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	s.provisioner.PrepareFailure("ExecuteCommand", errors.New("smoke failed"))
+	err = GuardedSwap(context.TODO(), app1, app2, GuardedSwapArgs{SmokeTest: "./smoke.sh"})
+	c.Assert(err, check.FitsTypeOf, &SwapVerificationError{})
+	c.Assert(err.(*SwapVerificationError).Step, check.Equals, "smoke test")
+	c.Assert(app1.CName, check.DeepEquals, []string{"app1.cname"})
+	c.Assert(app2.CName, check.IsNil)
+}
+
+func (s *S) TestGuardedSwapRevertOnMonitorFailure(c *check.C) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	app1, app2 := s.createSwapApps(c, srv.URL)
+	err := GuardedSwap(context.TODO(), app1, app2, GuardedSwapArgs{
+		MonitorDuration: time.Second,
+		MonitorInterval: 50 * time.Millisecond,
+	})
+	c.Assert(err, check.FitsTypeOf, &SwapVerificationError{})
+	verifyErr := err.(*SwapVerificationError)
+	c.Assert(verifyErr.Step, check.Equals, "post-swap")
+	c.Assert(verifyErr.Reverted, check.Equals, true)
+	c.Assert(app1.CName, check.DeepEquals, []string{"app1.cname"})
+	c.Assert(app2.CName, check.IsNil)
+	c.Assert(atomic.LoadInt32(&requests), check.Equals, int32(2))
+}
+
+func (s *S) TestGuardedSwapMonitorSuccess(c *check.C) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	app1, app2 := s.createSwapApps(c, srv.URL)
+	err := GuardedSwap(context.TODO(), app1, app2, GuardedSwapArgs{
+		MonitorDuration: 300 * time.Millisecond,
+		MonitorInterval: 50 * time.Millisecond,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&requests) > 1, check.Equals, true)
+	c.Assert(app2.CName, check.DeepEquals, []string{"app1.cname"})
+}
+
+func (s *S) TestGuardedSwapHCProvisionerChecksHTTP(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "hcprov"
+	provision.Register("hcprov", func() (provision.Provisioner, error) {
+		return &hcProv{}, nil
+	})
+	defer provision.Unregister("hcprov")
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	app1, app2 := s.createSwapApps(c, srv.URL)
+	newSuccessfulAppVersion(c, app2)
+	err := GuardedSwap(context.TODO(), app1, app2, GuardedSwapArgs{})
+	c.Assert(err, check.IsNil)
+	c.Assert(paths, check.DeepEquals, []string{"/"})
+}
+
+func (s *S) TestGuardedSwapMonitorIgnoresClientDisconnect(c *check.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 2 {
+			cancel()
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	app1, app2 := s.createSwapApps(c, srv.URL)
+	err := GuardedSwap(ctx, app1, app2, GuardedSwapArgs{
+		MonitorDuration: 300 * time.Millisecond,
+		MonitorInterval: 50 * time.Millisecond,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&requests) > 2, check.Equals, true)
+	c.Assert(app2.CName, check.DeepEquals, []string{"app1.cname"})
+}
@@ -22,6 +22,7 @@ type customData struct {
 	Hooks       *provTypes.TsuruYamlHooks
 	Healthcheck *provTypes.TsuruYamlHealthcheck
 	Kubernetes  *tsuruYamlKubernetesConfig
+	Probes      map[string]provTypes.TsuruYamlProbes
 }
 
 type tsuruYamlKubernetesConfig struct {
@@ -34,8 +35,13 @@ type tsuruYamlKubernetesGroup struct {
 }
 
 type tsuruYamlKubernetesProcess struct {
-	Name  string
-	Ports []tsuruYamlKubernetesProcessPortConfig
+	Name             string
+	Ports            []tsuruYamlKubernetesProcessPortConfig
+	InitContainers   []provTypes.TsuruYamlKubernetesContainer       `json:"init_containers,omitempty" bson:"init_containers,omitempty"`
+	Sidecars         []provTypes.TsuruYamlKubernetesContainer       `json:"sidecars,omitempty" bson:",omitempty"`
+	SharedVolumes    []provTypes.TsuruYamlKubernetesSharedVolume    `json:"shared_volumes,omitempty" bson:"shared_volumes,omitempty"`
+	DisruptionBudget *provTypes.TsuruYamlKubernetesDisruptionBudget `json:"disruption_budget,omitempty" bson:"disruption_budget,omitempty"`
+	TopologySpread   *provTypes.TsuruYamlKubernetesTopologySpread   `json:"topology_spread,omitempty" bson:"topology_spread,omitempty"`
 }
 
 type tsuruYamlKubernetesProcessPortConfig struct {
@@ -62,6 +68,7 @@ func unmarshalYamlData(data map[string]interface{}) (provTypes.TsuruYamlData, er
 	result := provTypes.TsuruYamlData{
 		Hooks:       custom.Hooks,
 		Healthcheck: custom.Healthcheck,
+		Probes:      custom.Probes,
 	}
 	if custom.Kubernetes == nil {
 		return result, nil
@@ -72,7 +79,12 @@ func unmarshalYamlData(data map[string]interface{}) (provTypes.TsuruYamlData, er
 		group := provTypes.TsuruYamlKubernetesGroup{}
 		for _, proc := range g.Processes {
 			group[proc.Name] = provTypes.TsuruYamlKubernetesProcessConfig{
-				Ports: make([]provTypes.TsuruYamlKubernetesProcessPortConfig, len(proc.Ports)),
+				Ports:            make([]provTypes.TsuruYamlKubernetesProcessPortConfig, len(proc.Ports)),
+				InitContainers:   proc.InitContainers,
+				Sidecars:         proc.Sidecars,
+				SharedVolumes:    proc.SharedVolumes,
+				DisruptionBudget: proc.DisruptionBudget,
+				TopologySpread:   proc.TopologySpread,
 			}
 			for i, port := range proc.Ports {
 				group[proc.Name].Ports[i] = provTypes.TsuruYamlKubernetesProcessPortConfig(port)
@@ -111,15 +123,33 @@ func marshalCustomData(data map[string]interface{}) (map[string]interface{}, err
 	}
 	result["hooks"] = yamlData.Hooks
 	result["healthcheck"] = yamlData.Healthcheck
+	if yamlData.Probes != nil {
+		err = yamlData.ValidateProbes()
+		if err != nil {
+			return nil, err
+		}
+		result["probes"] = yamlData.Probes
+	}
 	if yamlData.Kubernetes == nil {
 		return result, nil
 	}
+	err = yamlData.ValidateKubernetes()
+	if err != nil {
+		return nil, err
+	}
 	kubeConfig := &tsuruYamlKubernetesConfig{}
 
 	for groupName, groupData := range yamlData.Kubernetes.Groups {
 		group := tsuruYamlKubernetesGroup{Name: groupName}
 		for procName, procData := range groupData {
-			proc := tsuruYamlKubernetesProcess{Name: procName}
+			proc := tsuruYamlKubernetesProcess{
+				Name:             procName,
+				InitContainers:   procData.InitContainers,
+				Sidecars:         procData.Sidecars,
+				SharedVolumes:    procData.SharedVolumes,
+				DisruptionBudget: procData.DisruptionBudget,
+				TopologySpread:   procData.TopologySpread,
+			}
 			for _, port := range procData.Ports {
 				proc.Ports = append(proc.Ports, tsuruYamlKubernetesProcessPortConfig(port))
 			}
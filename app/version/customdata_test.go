@@ -5,6 +5,8 @@
 package version
 
 import (
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	routerTypes "github.com/tsuru/tsuru/types/router"
 	check "gopkg.in/check.v1"
 )
 
@@ -37,3 +39,183 @@ func (s *S) TestGetProcessesFromProcfile(c *check.C) {
 		c.Check(v, check.DeepEquals, t.expected, check.Commentf("failed test %d", i))
 	}
 }
+
+func (s *S) TestUnmarshalYamlDataProbes(c *check.C) {
+	data, err := marshalCustomData(map[string]interface{}{
+		"probes": map[string]interface{}{
+			"web": map[string]interface{}{
+				"readiness": map[string]interface{}{
+					"http":           map[string]interface{}{"path": "/ready"},
+					"period_seconds": 5,
+				},
+				"startup": map[string]interface{}{
+					"command": []string{"ls"},
+				},
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	yamlData, err := unmarshalYamlData(data)
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData.Probes, check.DeepEquals, map[string]provTypes.TsuruYamlProbes{
+		"web": {
+			Readiness: &provTypes.TsuruYamlProbe{
+				HTTP:          &provTypes.TsuruYamlProbeHTTP{Path: "/ready"},
+				PeriodSeconds: 5,
+			},
+			Startup: &provTypes.TsuruYamlProbe{
+				Command: []string{"ls"},
+			},
+		},
+	})
+	c.Assert(yamlData.ToRouterHC("web"), check.DeepEquals, routerTypes.HealthcheckData{Path: "/ready"})
+	c.Assert(yamlData.ToRouterHC("worker"), check.DeepEquals, routerTypes.HealthcheckData{Path: "/"})
+}
+
+func (s *S) TestToRouterHCWebProcessProbe(c *check.C) {
+	data, err := marshalCustomData(map[string]interface{}{
+		"probes": map[string]interface{}{
+			"api": map[string]interface{}{
+				"readiness": map[string]interface{}{
+					"http": map[string]interface{}{
+						"path":    "/ready",
+						"scheme":  "https",
+						"headers": map[string]string{"Host": "api.example.com"},
+					},
+				},
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	yamlData, err := unmarshalYamlData(data)
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData.ToRouterHC("api"), check.DeepEquals, routerTypes.HealthcheckData{
+		Path:    "/ready",
+		Scheme:  "https",
+		Headers: map[string]string{"Host": "api.example.com"},
+	})
+	c.Assert(yamlData.RouterHCFromProbe("api"), check.Equals, true)
+	c.Assert(yamlData.RouterHCFromProbe("web"), check.Equals, false)
+}
+
+func (s *S) TestToRouterHCLegacyHealthcheck(c *check.C) {
+	yamlData := provTypes.TsuruYamlData{
+		Healthcheck: &provTypes.TsuruYamlHealthcheck{
+			Path:        "/status",
+			Status:      200,
+			Scheme:      "https",
+			Headers:     map[string]string{"Host": "api.example.com"},
+			RouterBody:  "WORKING",
+			UseInRouter: true,
+		},
+	}
+	c.Assert(yamlData.ToRouterHC("web"), check.DeepEquals, routerTypes.HealthcheckData{
+		Path:   "/status",
+		Status: 200,
+		Body:   "WORKING",
+	})
+	c.Assert(yamlData.RouterHCFromProbe("web"), check.Equals, false)
+}
+
+func (s *S) TestMarshalCustomDataInvalidProbes(c *check.C) {
+	_, err := marshalCustomData(map[string]interface{}{
+		"probes": map[string]interface{}{
+			"web": map[string]interface{}{
+				"liveness": map[string]interface{}{
+					"http":    map[string]interface{}{"path": "/ready"},
+					"command": []string{"ls"},
+				},
+			},
+		},
+	})
+	c.Assert(err, check.ErrorMatches, `invalid probes for process "web": liveness probe: exactly one of http, tcp or command must be set`)
+}
+
+func (s *S) TestUnmarshalYamlDataContainers(c *check.C) {
+	data, err := marshalCustomData(map[string]interface{}{
+		"kubernetes": map[string]interface{}{
+			"groups": map[string]interface{}{
+				"pod1": map[string]interface{}{
+					"web": map[string]interface{}{
+						"shared_volumes": []interface{}{
+							map[string]interface{}{"name": "data", "mount_path": "/data"},
+						},
+						"init_containers": []interface{}{
+							map[string]interface{}{"name": "setup", "image": "busybox", "command": []string{"touch", "/shared/ok"}},
+						},
+						"sidecars": []interface{}{
+							map[string]interface{}{
+								"name":          "proxy",
+								"image":         "envoyproxy/envoy",
+								"env":           map[string]interface{}{"LEVEL": "info"},
+								"volume_mounts": []interface{}{map[string]interface{}{"name": "data", "mount_path": "/shared"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	yamlData, err := unmarshalYamlData(data)
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData.Kubernetes.GetProcessConfigs("web"), check.DeepEquals, &provTypes.TsuruYamlKubernetesProcessConfig{
+		Ports: []provTypes.TsuruYamlKubernetesProcessPortConfig{},
+		SharedVolumes: []provTypes.TsuruYamlKubernetesSharedVolume{
+			{Name: "data", MountPath: "/data"},
+		},
+		InitContainers: []provTypes.TsuruYamlKubernetesContainer{
+			{Name: "setup", Image: "busybox", Command: []string{"touch", "/shared/ok"}},
+		},
+		Sidecars: []provTypes.TsuruYamlKubernetesContainer{
+			{
+				Name:         "proxy",
+				Image:        "envoyproxy/envoy",
+				Env:          map[string]string{"LEVEL": "info"},
+				VolumeMounts: []provTypes.TsuruYamlKubernetesVolumeMount{{Name: "data", MountPath: "/shared"}},
+			},
+		},
+	})
+}
+
+func (s *S) TestMarshalCustomDataInvalidContainers(c *check.C) {
+	_, err := marshalCustomData(map[string]interface{}{
+		"kubernetes": map[string]interface{}{
+			"groups": map[string]interface{}{
+				"pod1": map[string]interface{}{
+					"web": map[string]interface{}{
+						"sidecars": []interface{}{
+							map[string]interface{}{
+								"name":          "proxy",
+								"image":         "envoyproxy/envoy",
+								"volume_mounts": []interface{}{map[string]interface{}{"name": "data", "mount_path": "/shared"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	c.Assert(err, check.ErrorMatches, `invalid containers for process "web": container "proxy": shared volume "data" not found`)
+}
+
+func (s *S) TestUnmarshalYamlDataPlacement(c *check.C) {
+	data, err := marshalCustomData(map[string]interface{}{
+		"kubernetes": map[string]interface{}{
+			"groups": map[string]interface{}{
+				"pod1": map[string]interface{}{
+					"web": map[string]interface{}{
+						"disruption_budget": map[string]interface{}{"max_unavailable": 1},
+						"topology_spread":   map[string]interface{}{"zone": "hard", "node": "soft"},
+					},
+				},
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	yamlData, err := unmarshalYamlData(data)
+	c.Assert(err, check.IsNil)
+	config := yamlData.Kubernetes.GetProcessConfigs("web")
+	c.Assert(config.DisruptionBudget, check.DeepEquals, &provTypes.TsuruYamlKubernetesDisruptionBudget{MaxUnavailable: "1"})
+	c.Assert(config.TopologySpread, check.DeepEquals, &provTypes.TsuruYamlKubernetesTopologySpread{Zone: "hard", Node: "soft"})
+}
@@ -90,7 +90,8 @@ func (s *appVersionService) VersionByImageOrVersion(ctx context.Context, app app
 		if v.DeploySuccessful &&
 			v.DeployImage == imageOrVersion ||
 			strconv.Itoa(v.Version) == imageOrVersion ||
-			strings.HasSuffix(v.DeployImage, imageOrVersion) {
+			strings.HasSuffix(v.DeployImage, imageOrVersion) ||
+			(v.DeployDigest != "" && strings.HasSuffix(v.DeployImageReference(), imageOrVersion)) {
 			return &appVersionImpl{
 				app:         app,
 				storage:     s.storage,
@@ -263,4 +263,16 @@ func (s *S) TestAppVersionService_VersionByImageOrVersion(c *check.C) {
 	version, err = svc.VersionByImageOrVersion(context.TODO(), app, "v1")
 	c.Assert(err, check.IsNil)
 	c.Assert(version.Version(), check.Equals, 1)
+
+	digest := "sha256:e6b8b4e8a8f6a1fb4b4b1c8e5f4b8b3b7c4e9d1f2a3b4c5d6e7f8091a2b3c4d5"
+	_, err = svc.VersionByImageOrVersion(context.TODO(), app, digest)
+	c.Assert(err, check.Equals, appTypes.ErrInvalidVersion{Version: digest})
+	err = newVersion.CommitDeployDigest(digest)
+	c.Assert(err, check.IsNil)
+	version, err = svc.VersionByImageOrVersion(context.TODO(), app, digest)
+	c.Assert(err, check.IsNil)
+	c.Assert(version.Version(), check.Equals, 1)
+	version, err = svc.VersionByImageOrVersion(context.TODO(), app, "tsuru/app-myapp@"+digest)
+	c.Assert(err, check.IsNil)
+	c.Assert(version.Version(), check.Equals, 1)
 }
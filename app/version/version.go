@@ -50,6 +50,24 @@ func (v *appVersionImpl) CommitBaseImage() error {
 	return v.storage.UpdateVersion(v.ctx, v.app.GetName(), v.versionInfo)
 }
 
+func (v *appVersionImpl) CommitDeployDigest(digest string) error {
+	err := v.refresh()
+	if err != nil {
+		return err
+	}
+	v.versionInfo.DeployDigest = digest
+	return v.storage.UpdateVersion(v.ctx, v.app.GetName(), v.versionInfo)
+}
+
+func (v *appVersionImpl) CommitArchitectures(architectures []string) error {
+	err := v.refresh()
+	if err != nil {
+		return err
+	}
+	v.versionInfo.Architectures = architectures
+	return v.storage.UpdateVersion(v.ctx, v.app.GetName(), v.versionInfo)
+}
+
 func (v *appVersionImpl) VersionInfo() appTypes.AppVersionInfo {
 	return *v.versionInfo
 }
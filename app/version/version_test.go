@@ -332,3 +332,53 @@ func (s *S) TestAppVersionImpl_ToggleEnabled(c *check.C) {
 	c.Assert(version.VersionInfo().Disabled, check.Equals, true)
 	c.Assert(version.VersionInfo().DisabledReason, check.Equals, "other reason")
 }
+
+func (s *S) TestAppVersionImpl_CommitDeployDigest(c *check.C) {
+	svc, err := AppVersionService()
+	c.Assert(err, check.IsNil)
+	version, err := svc.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{
+		App: &appTypes.MockApp{Name: "myapp"},
+	})
+	c.Assert(err, check.IsNil)
+	err = version.CommitBaseImage()
+	c.Assert(err, check.IsNil)
+	c.Assert(version.VersionInfo().DeployImageReference(), check.Equals, "tsuru/app-myapp:v1")
+	err = version.CommitDeployDigest("sha256:abc")
+	c.Assert(err, check.IsNil)
+	c.Assert(version.VersionInfo().DeployDigest, check.Equals, "sha256:abc")
+	c.Assert(version.VersionInfo().DeployImageReference(), check.Equals, "tsuru/app-myapp@sha256:abc")
+	versions, err := svc.AppVersions(context.TODO(), &appTypes.MockApp{Name: "myapp"})
+	c.Assert(err, check.IsNil)
+	c.Assert(versions.Versions[1].DeployDigest, check.Equals, "sha256:abc")
+}
+
+func (s *S) TestAppVersionImpl_CommitArchitectures(c *check.C) {
+	svc, err := AppVersionService()
+	c.Assert(err, check.IsNil)
+	version, err := svc.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{
+		App: &appTypes.MockApp{Name: "myapp"},
+	})
+	c.Assert(err, check.IsNil)
+	err = version.CommitArchitectures([]string{"amd64", "arm64"})
+	c.Assert(err, check.IsNil)
+	c.Assert(version.VersionInfo().Architectures, check.DeepEquals, []string{"amd64", "arm64"})
+	versions, err := svc.AppVersions(context.TODO(), &appTypes.MockApp{Name: "myapp"})
+	c.Assert(err, check.IsNil)
+	c.Assert(versions.Versions[1].Architectures, check.DeepEquals, []string{"amd64", "arm64"})
+}
+
+func (s *S) TestAppVersionInfoDeployImageReference(c *check.C) {
+	tests := []struct {
+		info     appTypes.AppVersionInfo
+		expected string
+	}{
+		{appTypes.AppVersionInfo{}, ""},
+		{appTypes.AppVersionInfo{DeployImage: "tsuru/app-myapp:v1"}, "tsuru/app-myapp:v1"},
+		{appTypes.AppVersionInfo{DeployImage: "tsuru/app-myapp:v1", DeployDigest: "sha256:abc"}, "tsuru/app-myapp@sha256:abc"},
+		{appTypes.AppVersionInfo{DeployImage: "localhost:5000/tsuru/app-myapp:v1", DeployDigest: "sha256:abc"}, "localhost:5000/tsuru/app-myapp@sha256:abc"},
+		{appTypes.AppVersionInfo{DeployImage: "localhost:5000/tsuru/app-myapp", DeployDigest: "sha256:abc"}, "localhost:5000/tsuru/app-myapp@sha256:abc"},
+	}
+	for _, tt := range tests {
+		c.Check(tt.info.DeployImageReference(), check.Equals, tt.expected)
+	}
+}
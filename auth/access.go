@@ -0,0 +1,246 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const (
+	PrincipalKindUser      = "user"
+	PrincipalKindTeamToken = "team-token"
+)
+
+// PermissionSource identifies the role instance responsible for a
+// permission. An empty Role indicates the implicit permission every user has
+// over itself.
+type PermissionSource struct {
+	Role         string `json:"role"`
+	ContextType  string `json:"contextType"`
+	ContextValue string `json:"contextValue"`
+	Group        string `json:"group,omitempty"`
+}
+
+type ExplainedPermission struct {
+	Permission     string           `json:"permission"`
+	Source         PermissionSource `json:"source"`
+	Deny           bool             `json:"deny"`
+	ContextMatch   bool             `json:"contextMatch"`
+	ConditionMatch bool             `json:"conditionMatch"`
+	Applied        bool             `json:"applied"`
+}
+
+// CheckExplanation describes how a permission check was decided for a
+// principal. Only permissions related to the checked scheme are listed.
+// Inactive principals, disabled users and expired team tokens, are never
+// allowed regardless of their permissions.
+type CheckExplanation struct {
+	Kind        string                        `json:"kind"`
+	Principal   string                        `json:"principal"`
+	Permission  string                        `json:"permission"`
+	Contexts    []permTypes.PermissionContext `json:"contexts"`
+	Groups      []string                      `json:"groups,omitempty"`
+	Inactive    bool                          `json:"inactive,omitempty"`
+	Allowed     bool                          `json:"allowed"`
+	Permissions []ExplainedPermission         `json:"permissions"`
+}
+
+type AccessReviewEntry struct {
+	Kind    string             `json:"kind"`
+	Name    string             `json:"name"`
+	Sources []PermissionSource `json:"sources"`
+}
+
+// Principal is anything that may hold roles in tsuru, either a user,
+// directly or through its groups, or a team token.
+type Principal struct {
+	Kind     string
+	Name     string
+	Groups   []string
+	Inactive bool
+	perms    []sourcedPermission
+}
+
+type sourcedPermission struct {
+	permission.Permission
+	source PermissionSource
+}
+
+type roleCache map[string]*permission.Role
+
+func (c roleCache) permissionsFor(roleInstance authTypes.RoleInstance, group string) ([]sourcedPermission, error) {
+	role := c[roleInstance.Name]
+	if role == nil {
+		foundRole, err := permission.FindRole(roleInstance.Name)
+		if err != nil && err != permTypes.ErrRoleNotFound {
+			return nil, err
+		}
+		role = &foundRole
+		c[roleInstance.Name] = role
+	}
+	source := PermissionSource{
+		Role:         roleInstance.Name,
+		ContextType:  string(role.ContextType),
+		ContextValue: roleInstance.ContextValue,
+		Group:        group,
+	}
+	var result []sourcedPermission
+	for _, perm := range role.PermissionsFor(roleInstance.ContextValue) {
+		result = append(result, sourcedPermission{Permission: perm, source: source})
+	}
+	return result, nil
+}
+
+func UserPrincipal(u *User) (*Principal, error) {
+	return userPrincipal(u, roleCache{})
+}
+
+func userPrincipal(u *User, cache roleCache) (*Principal, error) {
+	p := &Principal{
+		Kind:     PrincipalKindUser,
+		Name:     u.Email,
+		Inactive: u.Disabled,
+		perms: []sourcedPermission{{
+			Permission: permission.Permission{
+				Scheme:  permission.PermUser,
+				Context: permission.Context(permTypes.CtxUser, u.Email),
+			},
+			source: PermissionSource{
+				ContextType:  string(permTypes.CtxUser),
+				ContextValue: u.Email,
+			},
+		}},
+	}
+	for _, roleInstance := range u.Roles {
+		perms, err := cache.permissionsFor(roleInstance, "")
+		if err != nil {
+			return nil, err
+		}
+		p.perms = append(p.perms, perms...)
+	}
+	groups, err := u.UserGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		p.Groups = append(p.Groups, group.Name)
+		for _, roleInstance := range group.Roles {
+			perms, err := cache.permissionsFor(roleInstance, group.Name)
+			if err != nil {
+				return nil, err
+			}
+			p.perms = append(p.perms, perms...)
+		}
+	}
+	return p, nil
+}
+
+func TeamTokenPrincipal(t authTypes.TeamToken) (*Principal, error) {
+	return teamTokenPrincipal(t, roleCache{})
+}
+
+func teamTokenPrincipal(t authTypes.TeamToken, cache roleCache) (*Principal, error) {
+	p := &Principal{
+		Kind:     PrincipalKindTeamToken,
+		Name:     t.TokenID,
+		Inactive: !t.ExpiresAt.IsZero() && t.ExpiresAt.Before(time.Now()),
+	}
+	for _, roleInstance := range t.Roles {
+		perms, err := cache.permissionsFor(roleInstance, "")
+		if err != nil {
+			return nil, err
+		}
+		p.perms = append(p.perms, perms...)
+	}
+	return p, nil
+}
+
+// Explain evaluates the permission check exactly as permission.Check would,
+// recording the role instances and contexts involved in the decision.
+func (p *Principal) Explain(scheme *permission.PermissionScheme, contexts ...permTypes.PermissionContext) *CheckExplanation {
+	explanation := &CheckExplanation{
+		Kind:        p.Kind,
+		Principal:   p.Name,
+		Permission:  scheme.FullName(),
+		Contexts:    contexts,
+		Groups:      p.Groups,
+		Inactive:    p.Inactive,
+		Permissions: []ExplainedPermission{},
+	}
+	perms := make([]permission.Permission, len(p.perms))
+	for i, perm := range p.perms {
+		perms[i] = perm.Permission
+		match := perm.Match(scheme, contexts...)
+		if !match.Scheme {
+			continue
+		}
+		explanation.Permissions = append(explanation.Permissions, ExplainedPermission{
+			Permission:     perm.String(),
+			Source:         perm.source,
+			Deny:           perm.Deny,
+			ContextMatch:   match.Context,
+			ConditionMatch: match.Conditions,
+			Applied:        match.Applies(),
+		})
+	}
+	explanation.Allowed = !p.Inactive && permission.CheckFromPermList(perms, scheme, contexts...)
+	return explanation
+}
+
+// AccessReview lists every active user and team token allowed to use scheme
+// in the given contexts along with the role instances granting it.
+func AccessReview(ctx context.Context, scheme *permission.PermissionScheme, contexts ...permTypes.PermissionContext) ([]AccessReviewEntry, error) {
+	cache := roleCache{}
+	var principals []*Principal
+	users, err := ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if users[i].Disabled {
+			continue
+		}
+		p, err := userPrincipal(&users[i], cache)
+		if err != nil {
+			return nil, err
+		}
+		principals = append(principals, p)
+	}
+	tokens, err := servicemanager.TeamToken.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tokens {
+		p, err := teamTokenPrincipal(t, cache)
+		if err != nil {
+			return nil, err
+		}
+		if p.Inactive {
+			continue
+		}
+		principals = append(principals, p)
+	}
+	entries := []AccessReviewEntry{}
+	for _, p := range principals {
+		explanation := p.Explain(scheme, contexts...)
+		if !explanation.Allowed {
+			continue
+		}
+		entry := AccessReviewEntry{Kind: p.Kind, Name: p.Name}
+		for _, perm := range explanation.Permissions {
+			if perm.Applied && !perm.Deny {
+				entry.Sources = append(entry.Sources, perm.Source)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
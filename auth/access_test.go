@@ -0,0 +1,166 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestUserPrincipalExplain(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123", Groups: []string{"g1"}}
+	err := u.Create()
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole("r1", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole("r1", "myapp")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole("g1", "r1", "myapp2")
+	c.Assert(err, check.IsNil)
+	p, err := UserPrincipal(&u)
+	c.Assert(err, check.IsNil)
+	c.Assert(p.Groups, check.DeepEquals, []string{"g1"})
+	explanation := p.Explain(permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp2"))
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Kind, check.Equals, PrincipalKindUser)
+	c.Assert(explanation.Principal, check.Equals, "me@tsuru.com")
+	c.Assert(explanation.Permissions, check.DeepEquals, []ExplainedPermission{
+		{
+			Permission:     "app.deploy(app myapp)",
+			Source:         PermissionSource{Role: "r1", ContextType: "app", ContextValue: "myapp"},
+			ConditionMatch: true,
+		},
+		{
+			Permission:     "app.deploy(app myapp2)",
+			Source:         PermissionSource{Role: "r1", ContextType: "app", ContextValue: "myapp2", Group: "g1"},
+			ContextMatch:   true,
+			ConditionMatch: true,
+			Applied:        true,
+		},
+	})
+}
+
+func (s *S) TestUserPrincipalExplainDeny(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create()
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole("r1", "global", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions("app")
+	c.Assert(err, check.IsNil)
+	r2, err := permission.NewRole("r2", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r2.AddDenyPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole("r1", "")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole("r2", "myapp")
+	c.Assert(err, check.IsNil)
+	p, err := UserPrincipal(&u)
+	c.Assert(err, check.IsNil)
+	explanation := p.Explain(permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp"))
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Permissions, check.HasLen, 2)
+	c.Assert(explanation.Permissions[0].Applied, check.Equals, true)
+	c.Assert(explanation.Permissions[0].Deny, check.Equals, false)
+	c.Assert(explanation.Permissions[1].Applied, check.Equals, true)
+	c.Assert(explanation.Permissions[1].Deny, check.Equals, true)
+	c.Assert(explanation.Permissions[1].Source.Role, check.Equals, "r2")
+	explanation = p.Explain(permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "otherapp"))
+	c.Assert(explanation.Allowed, check.Equals, true)
+}
+
+func (s *S) TestUserPrincipalExplainDisabled(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123", Disabled: true}
+	err := u.Create()
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole("r1", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole("r1", "myapp")
+	c.Assert(err, check.IsNil)
+	p, err := UserPrincipal(&u)
+	c.Assert(err, check.IsNil)
+	explanation := p.Explain(permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp"))
+	c.Assert(explanation.Inactive, check.Equals, true)
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Permissions, check.HasLen, 1)
+	c.Assert(explanation.Permissions[0].Applied, check.Equals, true)
+}
+
+func (s *S) TestTeamTokenPrincipalExplain(c *check.C) {
+	r1, err := permission.NewRole("r1", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions("app.read")
+	c.Assert(err, check.IsNil)
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRole(context.TODO(), token.TokenID, "r1", s.team.Name)
+	c.Assert(err, check.IsNil)
+	token, err = servicemanager.TeamToken.FindByTokenID(context.TODO(), token.TokenID)
+	c.Assert(err, check.IsNil)
+	p, err := TeamTokenPrincipal(token)
+	c.Assert(err, check.IsNil)
+	explanation := p.Explain(permission.PermAppRead, permission.Context(permTypes.CtxTeam, s.team.Name))
+	c.Assert(explanation.Allowed, check.Equals, true)
+	c.Assert(explanation.Kind, check.Equals, PrincipalKindTeamToken)
+	c.Assert(explanation.Principal, check.Equals, token.TokenID)
+	c.Assert(explanation.Permissions, check.HasLen, 1)
+	explanation = p.Explain(permission.PermAppDeploy, permission.Context(permTypes.CtxTeam, s.team.Name))
+	c.Assert(explanation.Allowed, check.Equals, false)
+	c.Assert(explanation.Permissions, check.HasLen, 0)
+}
+
+func (s *S) TestAccessReview(c *check.C) {
+	r1, err := permission.NewRole("r1", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123", Groups: []string{"g1"}}
+	err = u.Create()
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AuthGroup.AddRole("g1", "r1", "myapp")
+	c.Assert(err, check.IsNil)
+	other := User{Email: "other@tsuru.com", Password: "123"}
+	err = other.Create()
+	c.Assert(err, check.IsNil)
+	err = other.AddRole("r1", "otherapp")
+	c.Assert(err, check.IsNil)
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRole(context.TODO(), token.TokenID, "r1", "myapp")
+	c.Assert(err, check.IsNil)
+	disabled := User{Email: "disabled@tsuru.com", Password: "123", Disabled: true}
+	err = disabled.Create()
+	c.Assert(err, check.IsNil)
+	err = disabled.AddRole("r1", "myapp")
+	c.Assert(err, check.IsNil)
+	expired, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, ExpiresIn: -1}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRole(context.TODO(), expired.TokenID, "r1", "myapp")
+	c.Assert(err, check.IsNil)
+	entries, err := AccessReview(context.TODO(), permission.PermAppDeploy, permission.Context(permTypes.CtxApp, "myapp"))
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.DeepEquals, []AccessReviewEntry{
+		{
+			Kind:    PrincipalKindUser,
+			Name:    "me@tsuru.com",
+			Sources: []PermissionSource{{Role: "r1", ContextType: "app", ContextValue: "myapp", Group: "g1"}},
+		},
+		{
+			Kind:    PrincipalKindTeamToken,
+			Name:    token.TokenID,
+			Sources: []PermissionSource{{Role: "r1", ContextType: "app", ContextValue: "myapp"}},
+		},
+	})
+}
@@ -55,3 +55,17 @@ func (s *groupService) RemoveRole(name, roleName, contextValue string) error {
 	}
 	return s.storage.RemoveRole(name, roleName, contextValue)
 }
+
+func (s *groupService) Create(name string) error {
+	if name == "" {
+		return errGroupNameEmpty
+	}
+	return s.storage.Create(name)
+}
+
+func (s *groupService) Remove(name string) error {
+	if name == "" {
+		return errGroupNameEmpty
+	}
+	return s.storage.Remove(name)
+}
@@ -29,8 +29,9 @@ func init() {
 }
 
 var (
-	_ auth.Scheme        = &NativeScheme{}
-	_ auth.ManagedScheme = &NativeScheme{}
+	_ auth.Scheme          = &NativeScheme{}
+	_ auth.ManagedScheme   = &NativeScheme{}
+	_ auth.RevocableScheme = &NativeScheme{}
 )
 
 func (s NativeScheme) Login(ctx context.Context, params map[string]string) (auth.Token, error) {
@@ -149,6 +150,10 @@ func (s NativeScheme) Remove(ctx context.Context, u *auth.User) error {
 	return u.Delete()
 }
 
+func (s NativeScheme) RevokeTokens(ctx context.Context, u *auth.User) error {
+	return deleteAllTokens(u.Email)
+}
+
 func (s NativeScheme) Name() string {
 	return "native"
 }
@@ -40,7 +40,8 @@ var (
 		Help: "The total number of oauth request errors.",
 	})
 
-	_ auth.Scheme = &oAuthScheme{}
+	_ auth.Scheme          = &oAuthScheme{}
+	_ auth.RevocableScheme = &oAuthScheme{}
 )
 
 type oAuthScheme struct {
@@ -215,6 +216,10 @@ func (s *oAuthScheme) Auth(ctx context.Context, header string) (auth.Token, erro
 	return token, nil
 }
 
+func (s *oAuthScheme) RevokeTokens(ctx context.Context, u *auth.User) error {
+	return deleteAllTokens(u.Email)
+}
+
 func (s *oAuthScheme) Name() string {
 	return "oauth"
 }
@@ -0,0 +1,314 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/auth/native"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/set"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultEmailClaim  = "email"
+	defaultGroupsClaim = "groups"
+)
+
+var (
+	ErrMissingCodeError       = &tsuruErrors.ValidationError{Message: "You must provide code to login"}
+	ErrMissingCodeRedirectURL = &tsuruErrors.ValidationError{Message: "You must provide the used redirect url to login"}
+	ErrMissingIDToken         = &tsuruErrors.NotAuthorizedError{Message: "Provider did not return an id token."}
+	ErrEmptyUserEmail         = &tsuruErrors.NotAuthorizedError{Message: "Couldn't parse user email."}
+	ErrUnverifiedEmail        = &tsuruErrors.NotAuthorizedError{Message: "User email is not verified by the provider."}
+
+	_ auth.Scheme          = &oidcScheme{}
+	_ auth.RevocableScheme = &oidcScheme{}
+)
+
+type oidcScheme struct {
+	mu       sync.Mutex
+	provider *provider
+}
+
+type schemeConfig struct {
+	oauth2.Config
+	emailClaim   string
+	groupsClaim  string
+	callbackPort int
+}
+
+func init() {
+	auth.RegisterScheme("oidc", &oidcScheme{})
+}
+
+func (s *oidcScheme) getProvider(issuer string) *provider {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil || s.provider.issuer != newProvider(issuer).issuer {
+		s.provider = newProvider(issuer)
+	}
+	return s.provider
+}
+
+// loadConfig reads the scheme settings and the provider endpoints from the
+// discovery document of the configured issuer.
+func (s *oidcScheme) loadConfig(ctx context.Context) (*schemeConfig, *provider, error) {
+	issuer, err := config.GetString("auth:oidc:issuer")
+	if err != nil {
+		return nil, nil, err
+	}
+	clientID, err := config.GetString("auth:oidc:client-id")
+	if err != nil {
+		return nil, nil, err
+	}
+	// client-secret is optional, public clients rely solely on PKCE.
+	clientSecret, _ := config.GetString("auth:oidc:client-secret")
+	scopes, err := config.GetList("auth:oidc:scopes")
+	if err != nil || len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile", "groups"}
+	}
+	emailClaim, err := config.GetString("auth:oidc:email-claim")
+	if err != nil {
+		emailClaim = defaultEmailClaim
+	}
+	groupsClaim, err := config.GetString("auth:oidc:groups-claim")
+	if err != nil {
+		groupsClaim = defaultGroupsClaim
+	}
+	callbackPort, err := config.GetInt("auth:oidc:callback-port")
+	if err != nil {
+		log.Debugf("auth:oidc:callback-port not found using random port: %s", err)
+	}
+	p := s.getProvider(issuer)
+	doc, err := p.document(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &schemeConfig{
+		Config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scopes:       scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  doc.AuthorizationEndpoint,
+				TokenURL: doc.TokenEndpoint,
+			},
+		},
+		emailClaim:   emailClaim,
+		groupsClaim:  groupsClaim,
+		callbackPort: callbackPort,
+	}, p, nil
+}
+
+// Login exchanges the authorization code for tokens, verifies the returned
+// ID token and syncs the user groups with the groups claim. The CLI must
+// send the PKCE code verifier as codeVerifier when it used a code challenge
+// in the authorization request.
+func (s *oidcScheme) Login(ctx context.Context, params map[string]string) (auth.Token, error) {
+	conf, p, err := s.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	code, ok := params["code"]
+	if !ok {
+		return nil, ErrMissingCodeError
+	}
+	redirectURL, ok := params["redirectUrl"]
+	if !ok {
+		return nil, ErrMissingCodeRedirectURL
+	}
+	conf.RedirectURL = redirectURL
+	var opts []oauth2.AuthCodeOption
+	if verifier := params["codeVerifier"]; verifier != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("code_verifier", verifier))
+	}
+	clientCtx := context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	oauthToken, err := conf.Exchange(clientCtx, code, opts...)
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, _ := oauthToken.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+	claims, err := p.verify(ctx, rawIDToken, conf.ClientID)
+	if err != nil {
+		return nil, &tsuruErrors.NotAuthorizedError{Message: err.Error()}
+	}
+	err = s.fillUserInfoClaims(ctx, conf, p, oauthToken, claims)
+	if err != nil {
+		return nil, err
+	}
+	email := claims.str(conf.emailClaim)
+	if email == "" {
+		return nil, ErrEmptyUserEmail
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return nil, ErrUnverifiedEmail
+	}
+	groups, hasGroups := claims.stringList(conf.groupsClaim)
+	err = syncUser(email, groups, hasGroups)
+	if err != nil {
+		return nil, err
+	}
+	token := tokenWrapper{Token: *oauthToken, UserEmail: email}
+	err = token.save()
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// fillUserInfoClaims fetches the email and groups claims from the userinfo
+// endpoint when the provider doesn't include them in the ID token.
+func (s *oidcScheme) fillUserInfoClaims(ctx context.Context, conf *schemeConfig, p *provider, t *oauth2.Token, claims idTokenClaims) error {
+	_, hasEmail := claims[conf.emailClaim]
+	_, hasGroups := claims[conf.groupsClaim]
+	if hasEmail && hasGroups {
+		return nil
+	}
+	doc, err := p.document(ctx)
+	if err != nil {
+		return err
+	}
+	if doc.UserInfoEndpoint == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodGet, doc.UserInfoEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	t.SetAuthHeader(req)
+	rsp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "unable to fetch openid userinfo")
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected userinfo response %d", rsp.StatusCode)
+	}
+	var userInfo idTokenClaims
+	err = json.NewDecoder(rsp.Body).Decode(&userInfo)
+	if err != nil {
+		return errors.Wrap(err, "unable to parse openid userinfo")
+	}
+	if userInfo.str("sub") != claims.str("sub") {
+		return &tsuruErrors.NotAuthorizedError{Message: "userinfo subject does not match id token subject"}
+	}
+	for _, name := range []string{conf.emailClaim, conf.groupsClaim, "email_verified"} {
+		if _, ok := claims[name]; !ok {
+			if v, ok := userInfo[name]; ok {
+				claims[name] = v
+			}
+		}
+	}
+	return nil
+}
+
+// syncUser creates the user when registration is enabled and replaces its
+// groups with the ones received from the provider. Groups are left untouched
+// when the provider doesn't send the groups claim at all.
+func syncUser(email string, groups []string, syncGroups bool) error {
+	sort.Strings(groups)
+	dbUser, err := auth.GetUserByEmail(email)
+	if err != nil {
+		if err != authTypes.ErrUserNotFound {
+			return err
+		}
+		registrationEnabled, _ := config.GetBool("auth:user-registration")
+		if !registrationEnabled {
+			return err
+		}
+		dbUser = &auth.User{Email: email, Groups: groups}
+		return dbUser.Create()
+	}
+	if !syncGroups || set.FromSlice(dbUser.Groups).Equal(set.FromSlice(groups)) {
+		return nil
+	}
+	dbUser.Groups = groups
+	return dbUser.Update()
+}
+
+func (s *oidcScheme) AppLogin(ctx context.Context, appName string) (auth.Token, error) {
+	nativeScheme := native.NativeScheme{}
+	return nativeScheme.AppLogin(ctx, appName)
+}
+
+func (s *oidcScheme) AppLogout(ctx context.Context, token string) error {
+	nativeScheme := native.NativeScheme{}
+	return nativeScheme.AppLogout(ctx, token)
+}
+
+func (s *oidcScheme) Logout(ctx context.Context, token string) error {
+	return deleteToken(token)
+}
+
+func (s *oidcScheme) Auth(ctx context.Context, header string) (auth.Token, error) {
+	token, err := getToken(header)
+	if err != nil {
+		nativeScheme := native.NativeScheme{}
+		token, nativeErr := nativeScheme.Auth(ctx, header)
+		if nativeErr == nil && token.IsAppToken() {
+			return token, nil
+		}
+		return nil, err
+	}
+	if !token.Token.Valid() {
+		return token, auth.ErrInvalidToken
+	}
+	return token, nil
+}
+
+func (s *oidcScheme) RevokeTokens(ctx context.Context, u *auth.User) error {
+	return deleteAllTokens(u.Email)
+}
+
+func (s *oidcScheme) Name() string {
+	return "oidc"
+}
+
+// Info returns the authorization URL to be used by the CLI. The CLI is
+// expected to add a S256 code_challenge to it, as described in rfc7636.
+func (s *oidcScheme) Info(ctx context.Context) (auth.SchemeInfo, error) {
+	conf, _, err := s.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conf.RedirectURL = "__redirect_url__"
+	return auth.SchemeInfo{
+		"authorizeUrl":        conf.AuthCodeURL(""),
+		"port":                strconv.Itoa(conf.callbackPort),
+		"codeChallengeMethod": "S256",
+	}, nil
+}
+
+func (s *oidcScheme) Create(ctx context.Context, user *auth.User) (*auth.User, error) {
+	user.Password = ""
+	err := user.Create()
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *oidcScheme) Remove(ctx context.Context, u *auth.User) error {
+	err := deleteAllTokens(u.Email)
+	if err != nil {
+		return err
+	}
+	return u.Delete()
+}
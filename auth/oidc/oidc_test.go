@@ -0,0 +1,167 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestOIDCLogin(c *check.C) {
+	scheme := oidcScheme{}
+	s.idToken = s.signToken(c, "key1", s.claims(map[string]interface{}{
+		"email":  "rand@example.com",
+		"groups": []string{"g2", "g1"},
+	}))
+	params := map[string]string{
+		"code":         "abcdefg",
+		"redirectUrl":  "http://localhost",
+		"codeVerifier": "my-verifier",
+	}
+	token, err := scheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	c.Assert(token.GetValue(), check.Equals, "my-access-token")
+	c.Assert(token.GetUserName(), check.Equals, "rand@example.com")
+	user, err := auth.GetUserByEmail("rand@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(user.Groups, check.DeepEquals, []string{"g1", "g2"})
+	var tokenBody url.Values
+	for i, r := range s.reqs {
+		if r.URL.Path == "/token" {
+			tokenBody, err = url.ParseQuery(s.bodies[i])
+			c.Assert(err, check.IsNil)
+		}
+	}
+	c.Assert(tokenBody.Get("code"), check.Equals, "abcdefg")
+	c.Assert(tokenBody.Get("code_verifier"), check.Equals, "my-verifier")
+	c.Assert(tokenBody.Get("redirect_uri"), check.Equals, "http://localhost")
+}
+
+func (s *S) TestOIDCLoginSyncsGroups(c *check.C) {
+	user := &auth.User{Email: "rand@example.com", Groups: []string{"old"}}
+	err := user.Create()
+	c.Assert(err, check.IsNil)
+	scheme := oidcScheme{}
+	s.idToken = s.signToken(c, "key1", s.claims(map[string]interface{}{
+		"email":  "rand@example.com",
+		"groups": []string{"new"},
+	}))
+	_, err = scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.IsNil)
+	user, err = auth.GetUserByEmail("rand@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(user.Groups, check.DeepEquals, []string{"new"})
+}
+
+func (s *S) TestOIDCLoginUserInfoClaims(c *check.C) {
+	config.Set("auth:oidc:groups-claim", "roles")
+	defer config.Unset("auth:oidc:groups-claim")
+	scheme := oidcScheme{}
+	s.idToken = s.signToken(c, "key1", s.claims(nil))
+	s.userInfo = map[string]interface{}{
+		"sub":   "user-123",
+		"email": "rand@example.com",
+		"roles": "admins",
+	}
+	_, err := scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.IsNil)
+	user, err := auth.GetUserByEmail("rand@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(user.Groups, check.DeepEquals, []string{"admins"})
+}
+
+func (s *S) TestOIDCLoginUserInfoSubjectMismatch(c *check.C) {
+	scheme := oidcScheme{}
+	s.idToken = s.signToken(c, "key1", s.claims(nil))
+	s.userInfo = map[string]interface{}{
+		"sub":   "someone-else",
+		"email": "rand@example.com",
+	}
+	_, err := scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.ErrorMatches, "userinfo subject does not match id token subject")
+}
+
+func (s *S) TestOIDCLoginUnverifiedEmail(c *check.C) {
+	scheme := oidcScheme{}
+	s.idToken = s.signToken(c, "key1", s.claims(map[string]interface{}{
+		"email":          "rand@example.com",
+		"email_verified": false,
+		"groups":         []string{},
+	}))
+	_, err := scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.Equals, ErrUnverifiedEmail)
+}
+
+func (s *S) TestOIDCLoginInvalidIDToken(c *check.C) {
+	scheme := oidcScheme{}
+	s.idToken = s.signToken(c, "key1", s.claims(map[string]interface{}{
+		"aud":   "other-client",
+		"email": "rand@example.com",
+	}))
+	_, err := scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.NotAuthorizedError{})
+}
+
+func (s *S) TestOIDCLoginMissingIDToken(c *check.C) {
+	scheme := oidcScheme{}
+	_, err := scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.Equals, ErrMissingIDToken)
+}
+
+func (s *S) TestOIDCLoginRegistrationDisabled(c *check.C) {
+	config.Set("auth:user-registration", false)
+	defer config.Set("auth:user-registration", true)
+	scheme := oidcScheme{}
+	s.idToken = s.signToken(c, "key1", s.claims(map[string]interface{}{"email": "rand@example.com", "groups": []string{}}))
+	_, err := scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.Equals, authTypes.ErrUserNotFound)
+}
+
+func (s *S) TestOIDCLoginMissingCode(c *check.C) {
+	scheme := oidcScheme{}
+	_, err := scheme.Login(context.TODO(), map[string]string{"redirectUrl": "http://localhost"})
+	c.Assert(err, check.Equals, ErrMissingCodeError)
+}
+
+func (s *S) TestOIDCAuth(c *check.C) {
+	scheme := oidcScheme{}
+	s.idToken = s.signToken(c, "key1", s.claims(map[string]interface{}{"email": "rand@example.com", "groups": []string{}}))
+	_, err := scheme.Login(context.TODO(), map[string]string{"code": "abcdefg", "redirectUrl": "http://localhost"})
+	c.Assert(err, check.IsNil)
+	token, err := scheme.Auth(context.TODO(), "bearer my-access-token")
+	c.Assert(err, check.IsNil)
+	c.Assert(token.GetUserName(), check.Equals, "rand@example.com")
+	err = scheme.Logout(context.TODO(), "my-access-token")
+	c.Assert(err, check.IsNil)
+	_, err = scheme.Auth(context.TODO(), "bearer my-access-token")
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
+
+func (s *S) TestOIDCInfo(c *check.C) {
+	config.Set("auth:oidc:callback-port", 4242)
+	defer config.Unset("auth:oidc:callback-port")
+	scheme := oidcScheme{}
+	info, err := scheme.Info(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(info["port"], check.Equals, "4242")
+	c.Assert(info["codeChallengeMethod"], check.Equals, "S256")
+	authURL, err := url.Parse(info["authorizeUrl"].(string))
+	c.Assert(err, check.IsNil)
+	c.Assert(authURL.Path, check.Equals, "/authorize")
+	c.Assert(authURL.Query().Get("client_id"), check.Equals, "tsuru")
+	c.Assert(authURL.Query().Get("redirect_uri"), check.Equals, "__redirect_url__")
+	c.Assert(authURL.Query().Get("scope"), check.Equals, "openid email profile groups")
+}
+
+func (s *S) TestOIDCName(c *check.C) {
+	scheme := oidcScheme{}
+	c.Assert(scheme.Name(), check.Equals, "oidc")
+}
@@ -0,0 +1,369 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	tsuruNet "github.com/tsuru/tsuru/net"
+)
+
+const allowedClockSkew = time.Minute
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+
+	httpClient = tsuruNet.Dial15Full60ClientWithPool
+	timeNow    = time.Now
+)
+
+// discoveryDocument holds the subset of the OpenID Provider metadata used
+// by tsuru.
+type discoveryDocument struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	UserInfoEndpoint      string   `json:"userinfo_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// provider caches the discovery document and signing keys of an OpenID
+// provider. Keys are fetched again whenever a token is signed by an unknown
+// key, which handles key rotation in the provider.
+type provider struct {
+	issuer string
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]crypto.PublicKey
+}
+
+func newProvider(issuer string) *provider {
+	return &provider{issuer: strings.TrimSuffix(issuer, "/")}
+}
+
+func (p *provider) document(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	var doc discoveryDocument
+	err := getJSON(ctx, p.issuer+"/.well-known/openid-configuration", &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to fetch openid discovery document")
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != p.issuer {
+		return nil, errors.Errorf("discovery document issuer %q does not match configured issuer %q", doc.Issuer, p.issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing required endpoints")
+	}
+	p.discovery = &doc
+	return p.discovery, nil
+}
+
+func (p *provider) key(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	doc, err := p.document(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key := findKey(p.keys, kid, alg); key != nil {
+		return key, nil
+	}
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	err = getJSON(ctx, doc.JWKSURI, &keySet)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to fetch openid signing keys")
+	}
+	p.keys = make(map[string]crypto.PublicKey)
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		pub, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		p.keys[jwk.Kid] = pub
+	}
+	if key := findKey(p.keys, kid, alg); key != nil {
+		return key, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidIDToken, "no signing key found for kid %q", kid)
+}
+
+func findKey(keys map[string]crypto.PublicKey, kid, alg string) crypto.PublicKey {
+	if kid != "" {
+		if key := keys[kid]; key != nil && keyMatchesAlg(key, alg) {
+			return key
+		}
+		return nil
+	}
+	var found crypto.PublicKey
+	for _, key := range keys {
+		if !keyMatchesAlg(key, alg) {
+			continue
+		}
+		if found != nil {
+			// tokens without kid are only accepted when the key is unambiguous
+			return nil
+		}
+		found = key
+	}
+	return found
+}
+
+func keyMatchesAlg(key crypto.PublicKey, alg string) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ES")
+	}
+	return false
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported key type %q", k.Kty)
+}
+
+type idTokenClaims map[string]interface{}
+
+func (c idTokenClaims) str(name string) string {
+	v, _ := c[name].(string)
+	return v
+}
+
+// stringList returns the claim as a list of strings, accepting both a single
+// string and an array.
+func (c idTokenClaims) stringList(name string) ([]string, bool) {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}, true
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func (c idTokenClaims) unixTime(name string) (time.Time, bool) {
+	v, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v), 0), true
+}
+
+// verify checks the signature and the standard claims of a raw ID token as
+// described in OpenID Connect Core 1.0, section 3.1.3.7.
+func (p *provider) verify(ctx context.Context, rawToken, clientID string) (idTokenClaims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.Wrap(ErrInvalidIDToken, "malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidIDToken, "malformed token header")
+	}
+	hash, err := algHash(header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidIDToken, "malformed token signature")
+	}
+	key, err := p.key(ctx, header.Kid, header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	err = verifySignature(key, hash, h.Sum(nil), signature)
+	if err != nil {
+		return nil, err
+	}
+	var claims idTokenClaims
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidIDToken, "malformed token claims")
+	}
+	if strings.TrimSuffix(claims.str("iss"), "/") != p.issuer {
+		return nil, errors.Wrapf(ErrInvalidIDToken, "unexpected issuer %q", claims.str("iss"))
+	}
+	audiences, _ := claims.stringList("aud")
+	if !contains(audiences, clientID) {
+		return nil, errors.Wrapf(ErrInvalidIDToken, "token audience %v does not include %q", audiences, clientID)
+	}
+	if azp := claims.str("azp"); len(audiences) > 1 && azp != clientID {
+		return nil, errors.Wrapf(ErrInvalidIDToken, "unexpected authorized party %q", azp)
+	}
+	now := timeNow()
+	exp, ok := claims.unixTime("exp")
+	if !ok || now.After(exp.Add(allowedClockSkew)) {
+		return nil, errors.Wrap(ErrInvalidIDToken, "token is expired")
+	}
+	if nbf, ok := claims.unixTime("nbf"); ok && now.Add(allowedClockSkew).Before(nbf) {
+		return nil, errors.Wrap(ErrInvalidIDToken, "token is not valid yet")
+	}
+	if claims.str("sub") == "" {
+		return nil, errors.Wrap(ErrInvalidIDToken, "missing subject")
+	}
+	return claims, nil
+}
+
+func algHash(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "RS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "ES512":
+		return crypto.SHA512, nil
+	}
+	return 0, errors.Wrapf(ErrInvalidIDToken, "unsupported signing algorithm %q", alg)
+}
+
+func verifySignature(key crypto.PublicKey, hash crypto.Hash, digest, signature []byte) error {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(pub, hash, digest, signature) != nil {
+			return errors.Wrap(ErrInvalidIDToken, "invalid signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.Wrap(ErrInvalidIDToken, "invalid signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.Wrap(ErrInvalidIDToken, "invalid signature")
+		}
+		return nil
+	}
+	return errors.Wrap(ErrInvalidIDToken, "unsupported key type")
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	rsp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	data, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected response %d from %s: %s", rsp.StatusCode, url, data)
+	}
+	return json.Unmarshal(data, v)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestProviderVerify(c *check.C) {
+	p := newProvider(s.server.URL)
+	raw := s.signToken(c, "key1", s.claims(map[string]interface{}{"email": "me@example.com"}))
+	claims, err := p.verify(context.TODO(), raw, "tsuru")
+	c.Assert(err, check.IsNil)
+	c.Assert(claims.str("email"), check.Equals, "me@example.com")
+	c.Assert(claims.str("sub"), check.Equals, "user-123")
+}
+
+func (s *S) TestProviderVerifyAudienceList(c *check.C) {
+	p := newProvider(s.server.URL)
+	raw := s.signToken(c, "key1", s.claims(map[string]interface{}{"aud": []string{"other", "tsuru"}, "azp": "tsuru"}))
+	_, err := p.verify(context.TODO(), raw, "tsuru")
+	c.Assert(err, check.IsNil)
+	raw = s.signToken(c, "key1", s.claims(map[string]interface{}{"aud": []string{"other", "tsuru"}, "azp": "other"}))
+	_, err = p.verify(context.TODO(), raw, "tsuru")
+	c.Assert(errors.Cause(err), check.Equals, ErrInvalidIDToken)
+}
+
+func (s *S) TestProviderVerifyInvalidClaims(c *check.C) {
+	p := newProvider(s.server.URL)
+	tests := []map[string]interface{}{
+		{"aud": "other"},
+		{"iss": "https://evil.example.com"},
+		{"exp": timeNow().Add(-time.Hour).Unix()},
+		{"nbf": timeNow().Add(time.Hour).Unix()},
+		{"sub": ""},
+	}
+	for _, tt := range tests {
+		raw := s.signToken(c, "key1", s.claims(tt))
+		_, err := p.verify(context.TODO(), raw, "tsuru")
+		c.Check(errors.Cause(err), check.Equals, ErrInvalidIDToken, check.Commentf("claims: %v", tt))
+	}
+}
+
+func (s *S) TestProviderVerifyInvalidSignature(c *check.C) {
+	p := newProvider(s.server.URL)
+	raw := s.signToken(c, "key1", s.claims(nil))
+	parts := strings.Split(raw, ".")
+	payload, err := json.Marshal(s.claims(map[string]interface{}{"sub": "admin"}))
+	c.Assert(err, check.IsNil)
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	_, err = p.verify(context.TODO(), strings.Join(parts, "."), "tsuru")
+	c.Assert(errors.Cause(err), check.Equals, ErrInvalidIDToken)
+	c.Assert(err, check.ErrorMatches, `invalid signature: invalid id token`)
+}
+
+func (s *S) TestProviderVerifyRejectsUnsignedTokens(c *check.C) {
+	p := newProvider(s.server.URL)
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload, err := json.Marshal(s.claims(nil))
+	c.Assert(err, check.IsNil)
+	raw := header + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+	_, err = p.verify(context.TODO(), raw, "tsuru")
+	c.Assert(err, check.ErrorMatches, `unsupported signing algorithm "none": invalid id token`)
+}
+
+func (s *S) TestProviderVerifyKeyRotation(c *check.C) {
+	p := newProvider(s.server.URL)
+	_, err := p.verify(context.TODO(), s.signToken(c, "key1", s.claims(nil)), "tsuru")
+	c.Assert(err, check.IsNil)
+	s.kid = "key2"
+	s.reqs = nil
+	_, err = p.verify(context.TODO(), s.signToken(c, "key2", s.claims(nil)), "tsuru")
+	c.Assert(err, check.IsNil)
+	c.Assert(s.reqs, check.HasLen, 1)
+	c.Assert(s.reqs[0].URL.Path, check.Equals, "/keys")
+	_, err = p.verify(context.TODO(), s.signToken(c, "key1", s.claims(nil)), "tsuru")
+	c.Assert(err, check.ErrorMatches, `no signing key found for kid "key1": invalid id token`)
+}
+
+func (s *S) TestProviderVerifyIssuerMismatch(c *check.C) {
+	p := newProvider(s.server.URL + "/other")
+	_, err := p.verify(context.TODO(), s.signToken(c, "key1", s.claims(nil)), "tsuru")
+	c.Assert(err, check.NotNil)
+}
+
+func (s *S) TestVerifyECDSASignature(c *check.C) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte("data"))
+	sum := digest.Sum(nil)
+	r, sig, err := ecdsa.Sign(rand.Reader, key, sum)
+	c.Assert(err, check.IsNil)
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	sig.FillBytes(signature[32:])
+	err = verifySignature(&key.PublicKey, crypto.SHA256, sum, signature)
+	c.Assert(err, check.IsNil)
+	signature[0] ^= 0xff
+	err = verifySignature(&key.PublicKey, crypto.SHA256, sum, signature)
+	c.Assert(errors.Cause(err), check.Equals, ErrInvalidIDToken)
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/dbtest"
+	"github.com/tsuru/tsuru/repository/repositorytest"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct {
+	conn     *db.Storage
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	reqs     []*http.Request
+	bodies   []string
+	idToken  string
+	userInfo map[string]interface{}
+}
+
+var _ = check.Suite(&S{})
+
+func (s *S) SetUpSuite(c *check.C) {
+	var err error
+	s.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, check.IsNil)
+	s.server = httptest.NewServer(http.HandlerFunc(s.handleIdP))
+	config.Set("auth:oidc:issuer", s.server.URL)
+	config.Set("auth:oidc:client-id", "tsuru")
+	config.Set("auth:oidc:client-secret", "secret")
+	config.Set("auth:oidc:collection", "oidc_token")
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "tsuru_auth_oidc_test")
+	config.Set("auth:user-registration", true)
+	config.Set("repo-manager", "fake")
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	s.conn, _ = db.Conn()
+	s.kid = "key1"
+	s.reqs = nil
+	s.bodies = nil
+	s.idToken = ""
+	s.userInfo = nil
+	repositorytest.Reset()
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	err := dbtest.ClearAllCollections(s.conn.Users().Database)
+	c.Assert(err, check.IsNil)
+	s.conn.Close()
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	s.server.Close()
+	conn, err := db.Conn()
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	dbtest.ClearAllCollections(conn.Users().Database)
+}
+
+func (s *S) handleIdP(w http.ResponseWriter, r *http.Request) {
+	b, _ := ioutil.ReadAll(r.Body)
+	s.reqs = append(s.reqs, r)
+	s.bodies = append(s.bodies, string(b))
+	var rsp interface{}
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		rsp = map[string]interface{}{
+			"issuer":                           s.server.URL,
+			"authorization_endpoint":           s.server.URL + "/authorize",
+			"token_endpoint":                   s.server.URL + "/token",
+			"userinfo_endpoint":                s.server.URL + "/userinfo",
+			"jwks_uri":                         s.server.URL + "/keys",
+			"code_challenge_methods_supported": []string{"S256"},
+		}
+	case "/keys":
+		rsp = map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": s.kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+			}},
+		}
+	case "/token":
+		rsp = map[string]interface{}{
+			"access_token": "my-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     s.idToken,
+		}
+	case "/userinfo":
+		if s.userInfo == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rsp = s.userInfo
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rsp)
+}
+
+func (s *S) signToken(c *check.C, kid string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	c.Assert(err, check.IsNil)
+	payload, err := json.Marshal(claims)
+	c.Assert(err, check.IsNil)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	h := crypto.SHA256.New()
+	h.Write([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, h.Sum(nil))
+	c.Assert(err, check.IsNil)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (s *S) claims(extra map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"iss": s.server.URL,
+		"aud": "tsuru",
+		"sub": "user-123",
+		"exp": timeNow().Add(time.Hour).Unix(),
+		"iat": timeNow().Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
@@ -359,6 +359,10 @@ func (s *SAMLAuthScheme) Create(ctx context.Context, user *auth.User) (*auth.Use
 	return user, nil
 }
 
+func (s *SAMLAuthScheme) RevokeTokens(ctx context.Context, u *auth.User) error {
+	return deleteAllTokens(u.Email)
+}
+
 func (s *SAMLAuthScheme) Remove(ctx context.Context, u *auth.User) error {
 	if err := deleteAllTokens(u.Email); err != nil {
 		return err
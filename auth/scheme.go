@@ -32,6 +32,13 @@ type ManagedScheme interface {
 	ChangePassword(ctx context.Context, token Token, oldPassword string, newPassword string) error
 }
 
+// RevocableScheme is implemented by schemes able to invalidate every token
+// issued to a user without removing the user.
+type RevocableScheme interface {
+	Scheme
+	RevokeTokens(ctx context.Context, user *User) error
+}
+
 type AuthenticationFailure struct {
 	Message string
 }
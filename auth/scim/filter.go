@@ -0,0 +1,325 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed SCIM filter expression, as described in rfc7644,
+// section 3.4.2.2. Attribute names and string values are compared ignoring
+// case. A nil filter matches every resource.
+type Filter struct {
+	root filterNode
+}
+
+type filterNode interface {
+	match(attrs map[string][]string) bool
+}
+
+type logicalNode struct {
+	op          string
+	left, right filterNode
+}
+
+func (n *logicalNode) match(attrs map[string][]string) bool {
+	if n.op == "and" {
+		return n.left.match(attrs) && n.right.match(attrs)
+	}
+	return n.left.match(attrs) || n.right.match(attrs)
+}
+
+type notNode struct {
+	node filterNode
+}
+
+func (n *notNode) match(attrs map[string][]string) bool {
+	return !n.node.match(attrs)
+}
+
+type compareNode struct {
+	attr  string
+	op    string
+	value *string
+}
+
+func (n *compareNode) match(attrs map[string][]string) bool {
+	var values []string
+	for _, v := range attrs[n.attr] {
+		if v != "" {
+			values = append(values, strings.ToLower(v))
+		}
+	}
+	if n.op == "pr" {
+		return len(values) > 0
+	}
+	if n.value == nil {
+		// comparisons against null are true only for missing attributes
+		return (n.op == "eq") == (len(values) == 0)
+	}
+	expected := strings.ToLower(*n.value)
+	if n.op == "ne" {
+		for _, v := range values {
+			if v == expected {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range values {
+		if compareValue(n.op, v, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareValue(op, v, expected string) bool {
+	switch op {
+	case "eq":
+		return v == expected
+	case "co":
+		return strings.Contains(v, expected)
+	case "sw":
+		return strings.HasPrefix(v, expected)
+	case "ew":
+		return strings.HasSuffix(v, expected)
+	case "gt":
+		return v > expected
+	case "ge":
+		return v >= expected
+	case "lt":
+		return v < expected
+	case "le":
+		return v <= expected
+	}
+	return false
+}
+
+var compareOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+func InvalidFilterError(detail string) *Error {
+	return NewError(http.StatusBadRequest, "invalidFilter", detail)
+}
+
+// ParseFilter parses a filter expression. An empty expression results in a
+// nil filter.
+func ParseFilter(expr string) (*Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, InvalidFilterError(fmt.Sprintf("unexpected %q in filter", p.peek()))
+	}
+	return &Filter{root: root}, nil
+}
+
+func (f *Filter) MatchUser(u *User) bool {
+	return f == nil || f.root.match(u.attributes())
+}
+
+func (f *Filter) MatchGroup(g *Group) bool {
+	return f == nil || f.root.match(g.attributes())
+}
+
+type filterParser struct {
+	tokens []string
+	pos    int
+	prefix string
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *filterParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		return InvalidFilterError(fmt.Sprintf("expected %q in filter, got %q", tok, got))
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseTerm() (filterNode, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, InvalidFilterError("unexpected end of filter")
+	case strings.EqualFold(tok, "not"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: node}, p.expect(")")
+	case tok == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+	attr := normalizeAttribute(tok)
+	if p.prefix != "" {
+		attr = p.prefix + "." + attr
+	}
+	if p.peek() == "[" {
+		if p.prefix != "" {
+			return nil, InvalidFilterError("nested value filters are not supported")
+		}
+		p.next()
+		p.prefix = attr
+		node, err := p.parseOr()
+		p.prefix = ""
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect("]")
+	}
+	op := strings.ToLower(p.next())
+	if op == "pr" {
+		return &compareNode{attr: attr, op: op}, nil
+	}
+	if !compareOperators[op] {
+		return nil, InvalidFilterError(fmt.Sprintf("invalid operator %q in filter", op))
+	}
+	value, err := parseFilterValue(p.next())
+	if err != nil {
+		return nil, err
+	}
+	return &compareNode{attr: attr, op: op, value: value}, nil
+}
+
+func parseFilterValue(tok string) (*string, error) {
+	if tok == "" {
+		return nil, InvalidFilterError("missing value in filter")
+	}
+	if strings.HasPrefix(tok, `"`) {
+		var value string
+		if err := json.Unmarshal([]byte(tok), &value); err != nil {
+			return nil, InvalidFilterError(fmt.Sprintf("invalid string %s in filter", tok))
+		}
+		return &value, nil
+	}
+	lower := strings.ToLower(tok)
+	if lower == "null" {
+		return nil, nil
+	}
+	if lower == "true" || lower == "false" {
+		return &lower, nil
+	}
+	var number json.Number
+	if err := json.Unmarshal([]byte(tok), &number); err != nil {
+		return nil, InvalidFilterError(fmt.Sprintf("invalid value %q in filter", tok))
+	}
+	value := number.String()
+	return &value, nil
+}
+
+// normalizeAttribute strips schema URN prefixes from attribute paths and
+// lowercases them, e.g.
+// "urn:ietf:params:scim:schemas:core:2.0:User:userName" becomes "username".
+func normalizeAttribute(attr string) string {
+	if idx := strings.LastIndex(attr, ":"); idx != -1 {
+		attr = attr[idx+1:]
+	}
+	return strings.ToLower(attr)
+}
+
+func tokenizeFilter(expr string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("()[]", r):
+			tokens = append(tokens, string(r))
+			i++
+		case r == '"':
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(runes) {
+				return nil, InvalidFilterError("unterminated string in filter")
+			}
+			tokens = append(tokens, string(runes[i:j+1]))
+			i = j + 1
+		default:
+			j := i
+			for ; j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune(`()[]"`, runes[j]); j++ {
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		}
+	}
+	return tokens, nil
+}
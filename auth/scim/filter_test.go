@@ -0,0 +1,91 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestParseFilterMatchUser(c *check.C) {
+	user := NewUser("me@example.com", true, []string{"admins", "devs"})
+	tests := []struct {
+		filter string
+		match  bool
+	}{
+		{`userName eq "me@example.com"`, true},
+		{`userName eq "ME@Example.com"`, true},
+		{`userName eq "other@example.com"`, false},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "me@example.com"`, true},
+		{`userName sw "me@"`, true},
+		{`userName ew "@example.com"`, true},
+		{`userName co "example"`, true},
+		{`userName ne "me@example.com"`, false},
+		{`emails.value eq "me@example.com"`, true},
+		{`emails[value eq "me@example.com"]`, true},
+		{`active eq true`, true},
+		{`active eq false`, false},
+		{`groups pr`, false},
+		{`groups.value pr`, true},
+		{`groups.value eq "devs"`, true},
+		{`userName pr and active eq false`, false},
+		{`userName eq "x" or active eq true`, true},
+		{`not (active eq true)`, false},
+		{`(userName eq "x" or userName eq "me@example.com") and active eq true`, true},
+		{`id eq null`, false},
+	}
+	for _, tt := range tests {
+		filter, err := ParseFilter(tt.filter)
+		c.Assert(err, check.IsNil, check.Commentf("filter: %s", tt.filter))
+		c.Check(filter.MatchUser(&user), check.Equals, tt.match, check.Commentf("filter: %s", tt.filter))
+	}
+}
+
+func (s *S) TestParseFilterMatchGroup(c *check.C) {
+	group := NewGroup("admins", []string{"me@example.com"})
+	tests := []struct {
+		filter string
+		match  bool
+	}{
+		{`displayName eq "admins"`, true},
+		{`displayName eq "devs"`, false},
+		{`members[value eq "me@example.com"]`, true},
+		{`members.value eq "other@example.com"`, false},
+	}
+	for _, tt := range tests {
+		filter, err := ParseFilter(tt.filter)
+		c.Assert(err, check.IsNil, check.Commentf("filter: %s", tt.filter))
+		c.Check(filter.MatchGroup(&group), check.Equals, tt.match, check.Commentf("filter: %s", tt.filter))
+	}
+}
+
+func (s *S) TestParseFilterEmpty(c *check.C) {
+	filter, err := ParseFilter("  ")
+	c.Assert(err, check.IsNil)
+	c.Assert(filter, check.IsNil)
+	user := NewUser("me@example.com", true, nil)
+	c.Assert(filter.MatchUser(&user), check.Equals, true)
+}
+
+func (s *S) TestParseFilterInvalid(c *check.C) {
+	tests := []string{
+		`userName`,
+		`userName xx "a"`,
+		`userName eq`,
+		`userName eq "a`,
+		`(userName eq "a"`,
+		`userName eq "a" and`,
+		`userName eq "a" "b"`,
+		`emails[value eq "a"`,
+		`userName eq abc`,
+	}
+	for _, tt := range tests {
+		_, err := ParseFilter(tt)
+		c.Check(err, check.FitsTypeOf, &Error{}, check.Commentf("filter: %s", tt))
+		if scimErr, ok := err.(*Error); ok {
+			c.Check(scimErr.ScimType, check.Equals, "invalidFilter")
+			c.Check(scimErr.StatusCode(), check.Equals, 400)
+		}
+	}
+}
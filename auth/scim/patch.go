@@ -0,0 +1,246 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PatchOp is the body of a PATCH request, as described in rfc7644, section
+// 3.5.2.
+type PatchOp struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func invalidPatchError(scimType, format string, args ...interface{}) *Error {
+	return NewError(http.StatusBadRequest, scimType, fmt.Sprintf(format, args...))
+}
+
+func (p *PatchOp) Validate() error {
+	var hasSchema bool
+	for _, s := range p.Schemas {
+		hasSchema = hasSchema || s == PatchOpSchema
+	}
+	if !hasSchema {
+		return invalidPatchError("invalidSyntax", "patch request must use the %s schema", PatchOpSchema)
+	}
+	if len(p.Operations) == 0 {
+		return invalidPatchError("invalidSyntax", "patch request must have at least one operation")
+	}
+	for _, op := range p.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if len(op.Value) == 0 {
+				return invalidPatchError("invalidValue", "value is required for %s operations", op.Op)
+			}
+		case "remove":
+			if op.Path == "" {
+				return invalidPatchError("noTarget", "path is required for remove operations")
+			}
+		default:
+			return invalidPatchError("invalidSyntax", "invalid patch operation %q", op.Op)
+		}
+	}
+	return nil
+}
+
+// ApplyUser applies the operations to the user. Attributes not stored by
+// tsuru, like names, are ignored.
+func (p *PatchOp) ApplyUser(u *User) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	for _, op := range p.Operations {
+		opName := strings.ToLower(op.Op)
+		attrs, err := operationAttributes(op)
+		if err != nil {
+			return err
+		}
+		for attr, value := range attrs {
+			switch attr {
+			case "active":
+				active := true
+				if opName != "remove" {
+					active, err = parseBool(value)
+					if err != nil {
+						return err
+					}
+				}
+				u.Active = &active
+			case "username":
+				if opName == "remove" {
+					return invalidPatchError("mutability", "userName cannot be removed")
+				}
+				err = json.Unmarshal(value, &u.UserName)
+				if err != nil {
+					return invalidPatchError("invalidValue", "invalid userName: %v", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyGroup applies the operations to the group members and display name.
+func (p *PatchOp) ApplyGroup(g *Group) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	for _, op := range p.Operations {
+		opName := strings.ToLower(op.Op)
+		attr, valueFilter, err := parsePatchPath(op.Path)
+		if err != nil {
+			return err
+		}
+		if attr == "" {
+			attrs, err := operationAttributes(op)
+			if err != nil {
+				return err
+			}
+			for name, value := range attrs {
+				err = applyGroupAttribute(g, opName, name, nil, value)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		err = applyGroupAttribute(g, opName, attr, valueFilter, op.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyGroupAttribute(g *Group, op, attr string, valueFilter *Filter, value json.RawMessage) error {
+	switch attr {
+	case "displayname":
+		if op == "remove" {
+			return invalidPatchError("mutability", "displayName cannot be removed")
+		}
+		err := json.Unmarshal(value, &g.DisplayName)
+		if err != nil {
+			return invalidPatchError("invalidValue", "invalid displayName: %v", err)
+		}
+	case "members":
+		var members []Reference
+		if len(value) > 0 {
+			err := json.Unmarshal(value, &members)
+			if err != nil {
+				var member Reference
+				if json.Unmarshal(value, &member) != nil {
+					return invalidPatchError("invalidValue", "invalid members: %v", err)
+				}
+				members = []Reference{member}
+			}
+		}
+		switch op {
+		case "add":
+			g.Members = append(g.Members, members...)
+		case "replace":
+			g.Members = members
+		case "remove":
+			g.Members = removeMembers(g.Members, members, valueFilter)
+		}
+	}
+	return nil
+}
+
+func removeMembers(current, toRemove []Reference, valueFilter *Filter) []Reference {
+	removeIDs := make(map[string]bool)
+	for _, m := range toRemove {
+		removeIDs[m.Value] = true
+	}
+	result := []Reference{}
+	for _, m := range current {
+		var remove bool
+		switch {
+		case valueFilter != nil:
+			remove = valueFilter.root.match(map[string][]string{
+				"value":   {m.Value},
+				"display": {m.Display},
+			})
+		case len(toRemove) > 0:
+			remove = removeIDs[m.Value]
+		default:
+			remove = true
+		}
+		if !remove {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// operationAttributes returns the attributes changed by the operation. When
+// the path is empty the value must be an object with the attributes as keys.
+func operationAttributes(op PatchOperation) (map[string]json.RawMessage, error) {
+	if op.Path != "" {
+		attr, _, err := parsePatchPath(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]json.RawMessage{attr: op.Value}, nil
+	}
+	var values map[string]json.RawMessage
+	err := json.Unmarshal(op.Value, &values)
+	if err != nil {
+		return nil, invalidPatchError("invalidValue", "value must be an object when path is not set")
+	}
+	attrs := make(map[string]json.RawMessage, len(values))
+	for k, v := range values {
+		attrs[normalizeAttribute(k)] = v
+	}
+	return attrs, nil
+}
+
+// parsePatchPath splits paths like `members[value eq "id"]` into the
+// attribute name and the value filter.
+func parsePatchPath(path string) (string, *Filter, error) {
+	if path == "" {
+		return "", nil, nil
+	}
+	idx := strings.Index(path, "[")
+	if idx == -1 {
+		return normalizeAttribute(path), nil, nil
+	}
+	end := strings.LastIndex(path, "]")
+	if end < idx {
+		return "", nil, invalidPatchError("invalidPath", "invalid path %q", path)
+	}
+	valueFilter, err := ParseFilter(path[idx+1 : end])
+	if err != nil {
+		return "", nil, invalidPatchError("invalidPath", "invalid path %q: %v", path, err)
+	}
+	return normalizeAttribute(path[:idx]), valueFilter, nil
+}
+
+// parseBool accepts both JSON booleans and strings, as some providers send
+// "True" and "False".
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, invalidPatchError("invalidValue", "invalid boolean value %s", value)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"encoding/json"
+
+	check "gopkg.in/check.v1"
+)
+
+func parsePatch(c *check.C, data string) *PatchOp {
+	var op PatchOp
+	err := json.Unmarshal([]byte(data), &op)
+	c.Assert(err, check.IsNil)
+	return &op
+}
+
+func (s *S) TestPatchUserActive(c *check.C) {
+	user := NewUser("me@example.com", true, nil)
+	op := parsePatch(c, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "path": "active", "value": false}]
+	}`)
+	err := op.ApplyUser(&user)
+	c.Assert(err, check.IsNil)
+	c.Assert(user.IsActive(), check.Equals, false)
+}
+
+func (s *S) TestPatchUserWithoutPath(c *check.C) {
+	user := NewUser("me@example.com", false, nil)
+	op := parsePatch(c, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "Replace", "value": {"active": "True", "displayName": "Me"}}]
+	}`)
+	err := op.ApplyUser(&user)
+	c.Assert(err, check.IsNil)
+	c.Assert(user.IsActive(), check.Equals, true)
+}
+
+func (s *S) TestPatchUserInvalidActive(c *check.C) {
+	user := NewUser("me@example.com", true, nil)
+	op := parsePatch(c, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "path": "active", "value": "maybe"}]
+	}`)
+	err := op.ApplyUser(&user)
+	c.Assert(err, check.FitsTypeOf, &Error{})
+	c.Assert(err.(*Error).ScimType, check.Equals, "invalidValue")
+}
+
+func (s *S) TestPatchValidate(c *check.C) {
+	tests := []string{
+		`{"Operations": [{"op": "add", "path": "active", "value": true}]}`,
+		`{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": []}`,
+		`{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [{"op": "move", "path": "a"}]}`,
+		`{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [{"op": "remove"}]}`,
+		`{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [{"op": "add", "path": "members"}]}`,
+	}
+	for _, tt := range tests {
+		err := parsePatch(c, tt).Validate()
+		c.Check(err, check.FitsTypeOf, &Error{}, check.Commentf("patch: %s", tt))
+	}
+}
+
+func (s *S) TestPatchGroupMembers(c *check.C) {
+	group := NewGroup("admins", []string{"a@example.com", "b@example.com"})
+	op := parsePatch(c, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "add", "path": "members", "value": [{"value": "c@example.com"}]},
+			{"op": "remove", "path": "members[value eq \"a@example.com\"]"}
+		]
+	}`)
+	err := op.ApplyGroup(&group)
+	c.Assert(err, check.IsNil)
+	c.Assert(group.MemberIDs(), check.DeepEquals, []string{"b@example.com", "c@example.com"})
+}
+
+func (s *S) TestPatchGroupRemoveMembersByValue(c *check.C) {
+	group := NewGroup("admins", []string{"a@example.com", "b@example.com"})
+	op := parsePatch(c, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "remove", "path": "members", "value": [{"value": "b@example.com"}]}]
+	}`)
+	err := op.ApplyGroup(&group)
+	c.Assert(err, check.IsNil)
+	c.Assert(group.MemberIDs(), check.DeepEquals, []string{"a@example.com"})
+	op = parsePatch(c, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "remove", "path": "members"}]
+	}`)
+	err = op.ApplyGroup(&group)
+	c.Assert(err, check.IsNil)
+	c.Assert(group.MemberIDs(), check.DeepEquals, []string{})
+}
+
+func (s *S) TestPatchGroupReplace(c *check.C) {
+	group := NewGroup("admins", []string{"a@example.com"})
+	op := parsePatch(c, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "value": {"displayName": "ops", "members": [{"value": "z@example.com"}]}}]
+	}`)
+	err := op.ApplyGroup(&group)
+	c.Assert(err, check.IsNil)
+	c.Assert(group.DisplayName, check.Equals, "ops")
+	c.Assert(group.MemberIDs(), check.DeepEquals, []string{"z@example.com"})
+}
+
+func (s *S) TestNewListResponse(c *check.C) {
+	resources := []interface{}{"a", "b", "c"}
+	rsp := NewListResponse(resources, 2, 1)
+	c.Assert(rsp.TotalResults, check.Equals, 3)
+	c.Assert(rsp.StartIndex, check.Equals, 2)
+	c.Assert(rsp.ItemsPerPage, check.Equals, 1)
+	c.Assert(rsp.Resources, check.DeepEquals, []interface{}{"b"})
+	rsp = NewListResponse(resources, 0, -1)
+	c.Assert(rsp.StartIndex, check.Equals, 1)
+	c.Assert(rsp.Resources, check.DeepEquals, resources)
+	rsp = NewListResponse(resources, 10, 5)
+	c.Assert(rsp.ItemsPerPage, check.Equals, 0)
+	c.Assert(rsp.Resources, check.DeepEquals, []interface{}{})
+}
+
+func (s *S) TestUserEmail(c *check.C) {
+	u := User{UserName: "me@example.com"}
+	c.Assert(u.Email(), check.Equals, "me@example.com")
+	u = User{UserName: "me", Emails: []Email{{Value: "other@example.com"}, {Value: "me@example.com", Primary: true}}}
+	c.Assert(u.Email(), check.Equals, "me@example.com")
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package scim implements the resources, filters and patch operations of the
+// SCIM 2.0 protocol (rfc7643 and rfc7644) used to provision tsuru users and
+// groups.
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	UserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	ContentType = "application/scim+json"
+
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+)
+
+// Error is a SCIM error response as described in rfc7644, section 3.12.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+func (e *Error) StatusCode() int {
+	var code int
+	fmt.Sscanf(e.Status, "%d", &code)
+	return code
+}
+
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{ErrorSchema},
+		Status:   fmt.Sprint(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+func NotFoundError(resourceType, id string) *Error {
+	return NewError(http.StatusNotFound, "", fmt.Sprintf("%s %q not found", resourceType, id))
+}
+
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Reference points to another resource, e.g. a group member or the groups
+// of a user.
+type Reference struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// User is the SCIM representation of a tsuru user. The user email is used
+// both as its id and userName.
+type User struct {
+	Schemas  []string    `json:"schemas"`
+	ID       string      `json:"id"`
+	UserName string      `json:"userName"`
+	Active   *bool       `json:"active,omitempty"`
+	Emails   []Email     `json:"emails,omitempty"`
+	Groups   []Reference `json:"groups,omitempty"`
+	Password string      `json:"password,omitempty"`
+	Meta     *Meta       `json:"meta,omitempty"`
+}
+
+// Email returns the email of the user, which is the userName or, when it
+// doesn't look like an email, the primary email address.
+func (u *User) Email() string {
+	if strings.Contains(u.UserName, "@") || len(u.Emails) == 0 {
+		return u.UserName
+	}
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	return u.Emails[0].Value
+}
+
+func (u *User) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+func (u *User) attributes() map[string][]string {
+	return map[string][]string{
+		"id":           {u.ID},
+		"username":     {u.UserName},
+		"active":       {fmt.Sprint(u.IsActive())},
+		"emails.value": emailValues(u.Emails),
+		"groups.value": referenceValues(u.Groups),
+	}
+}
+
+// Group is the SCIM representation of a tsuru group. Group names are used as
+// both id and displayName.
+type Group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+func (g *Group) attributes() map[string][]string {
+	return map[string][]string{
+		"id":            {g.ID},
+		"displayname":   {g.DisplayName},
+		"members.value": referenceValues(g.Members),
+	}
+}
+
+// MemberIDs returns the sorted list of unique member ids.
+func (g *Group) MemberIDs() []string {
+	ids := make(map[string]struct{})
+	for _, m := range g.Members {
+		ids[m.Value] = struct{}{}
+	}
+	result := make([]string, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func NewUser(email string, active bool, groups []string) User {
+	u := User{
+		Schemas:  []string{UserSchema},
+		ID:       email,
+		UserName: email,
+		Active:   &active,
+		Emails:   []Email{{Value: email, Primary: true}},
+		Meta:     &Meta{ResourceType: ResourceTypeUser},
+	}
+	for _, g := range groups {
+		u.Groups = append(u.Groups, Reference{Value: g, Display: g})
+	}
+	return u
+}
+
+func NewGroup(name string, members []string) Group {
+	g := Group{
+		Schemas:     []string{GroupSchema},
+		ID:          name,
+		DisplayName: name,
+		Members:     []Reference{},
+		Meta:        &Meta{ResourceType: ResourceTypeGroup},
+	}
+	for _, m := range members {
+		g.Members = append(g.Members, Reference{Value: m, Display: m})
+	}
+	return g
+}
+
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// NewListResponse paginates resources using the 1-based startIndex and count
+// query parameters. A negative count returns every resource.
+func NewListResponse(resources []interface{}, startIndex, count int) ListResponse {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	page := []interface{}{}
+	if start := startIndex - 1; start < len(resources) {
+		end := len(resources)
+		if count >= 0 && start+count < end {
+			end = start + count
+		}
+		page = resources[start:end]
+	}
+	return ListResponse{
+		Schemas:      []string{ListResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+func emailValues(emails []Email) []string {
+	values := make([]string, len(emails))
+	for i, e := range emails {
+		values[i] = e.Value
+	}
+	return values
+}
+
+func referenceValues(refs []Reference) []string {
+	values := make([]string, len(refs))
+	for i, r := range refs {
+		values[i] = r.Value
+	}
+	return values
+}
@@ -257,6 +257,10 @@ func (s *teamTokenService) FindByUserToken(ctx context.Context, t authTypes.Toke
 	return teamTokens, nil
 }
 
+func (s *teamTokenService) List(ctx context.Context) ([]authTypes.TeamToken, error) {
+	return s.storage.FindByTeams(ctx, nil)
+}
+
 func canUseRole(userPerms []permission.Permission, roleName, contextValue string) (bool, error) {
 	role, err := permission.FindRole(roleName)
 	if err != nil {
@@ -264,6 +268,9 @@ func canUseRole(userPerms []permission.Permission, roleName, contextValue string
 	}
 	perms := role.PermissionsFor(contextValue)
 	for _, p := range perms {
+		if p.Deny {
+			continue
+		}
 		if !permission.CheckFromPermList(userPerms, p.Scheme, p.Context) {
 			return false, nil
 		}
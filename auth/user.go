@@ -34,6 +34,7 @@ type User struct {
 	APIKey   string
 	Roles    []authTypes.RoleInstance `bson:",omitempty"`
 	Groups   []string                 `bson:",omitempty"`
+	Disabled bool                     `bson:",omitempty"`
 }
 
 func listUsers(filter bson.M) ([]User, error) {
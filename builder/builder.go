@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"sort"
 
 	"github.com/pkg/errors"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
@@ -47,6 +48,13 @@ type PlatformBuilder interface {
 	PlatformRemove(ctx context.Context, name string) error
 }
 
+// AppBuilder is a builder used only by some apps, taking precedence over the
+// builder of their provisioner.
+type AppBuilder interface {
+	Builder
+	HandlesApp(ctx context.Context, p provision.Provisioner, app provision.App) (bool, error)
+}
+
 // Register registers a new builder in the Builder registry.
 func Register(name string, builder Builder) {
 	builders[name] = builder
@@ -65,6 +73,30 @@ func GetForProvisioner(p provision.Provisioner) (Builder, error) {
 	return builder, err
 }
 
+// GetForApp gets the builder used by the app, which is either an AppBuilder
+// handling the app or the builder required by its provisioner.
+func GetForApp(ctx context.Context, p provision.Provisioner, app provision.App) (Builder, error) {
+	names := make([]string, 0, len(builders))
+	for name := range builders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		appBuilder, ok := builders[name].(AppBuilder)
+		if !ok {
+			continue
+		}
+		handles, err := appBuilder.HandlesApp(ctx, p, app)
+		if err != nil {
+			return nil, err
+		}
+		if handles {
+			return appBuilder, nil
+		}
+	}
+	return GetForProvisioner(p)
+}
+
 // get gets the named builder from the registry.
 func get(name string) (Builder, error) {
 	b, ok := builders[name]
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	check "gopkg.in/check.v1"
 )
@@ -116,3 +118,31 @@ func (s S) TestPlatformRemoveNoBuilder(c *check.C) {
 	err := PlatformRemove(context.TODO(), "platform-name")
 	c.Assert(err, check.ErrorMatches, "No builder available")
 }
+
+type appBuilder struct {
+	MockBuilder
+	apps []string
+}
+
+func (b *appBuilder) HandlesApp(ctx context.Context, p provision.Provisioner, app provision.App) (bool, error) {
+	for _, name := range b.apps {
+		if name == app.GetName() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s S) TestGetForApp(c *check.C) {
+	provBuilder := &MockBuilder{}
+	specialBuilder := &appBuilder{apps: []string{"special"}}
+	Register("fake", provBuilder)
+	Register("special-builder", specialBuilder)
+	p := provisiontest.ProvisionerInstance
+	b, err := GetForApp(context.TODO(), p, provisiontest.NewFakeApp("special", "python", 0))
+	c.Assert(err, check.IsNil)
+	c.Assert(b, check.Equals, specialBuilder)
+	b, err = GetForApp(context.TODO(), p, provisiontest.NewFakeApp("other", "python", 0))
+	c.Assert(err, check.IsNil)
+	c.Assert(b, check.Equals, provBuilder)
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cnb
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"path"
+
+	"github.com/pkg/errors"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var tsuruYamlFiles = []string{"tsuru.yml", "tsuru.yaml", "app.yml", "app.yaml"}
+
+type archiveResult struct {
+	data *provTypes.TsuruYamlData
+	err  error
+}
+
+// inspectArchive looks for the tsuru.yaml file in the app archive while it's
+// read by the build. The returned function must be called once the build is
+// done, returning the parsed file, or nil if the archive has none.
+func inspectArchive(archive io.Reader) (io.Reader, func() (*provTypes.TsuruYamlData, error)) {
+	pr, pw := io.Pipe()
+	resultCh := make(chan archiveResult, 1)
+	go func() {
+		data, err := tsuruYamlFromArchive(pr)
+		io.Copy(ioutil.Discard, pr)
+		resultCh <- archiveResult{data: data, err: err}
+	}()
+	return io.TeeReader(archive, pw), func() (*provTypes.TsuruYamlData, error) {
+		pw.Close()
+		result := <-resultCh
+		return result.data, result.err
+	}
+}
+
+func tsuruYamlFromArchive(archive io.Reader) (*provTypes.TsuruYamlData, error) {
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read app archive")
+	}
+	defer gzipReader.Close()
+	files := map[string][]byte{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read app archive")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		for _, yamlFile := range tsuruYamlFiles {
+			if name == yamlFile {
+				files[name], err = ioutil.ReadAll(tarReader)
+				if err != nil {
+					return nil, errors.Wrapf(err, "unable to read %s from app archive", yamlFile)
+				}
+			}
+		}
+	}
+	for _, yamlFile := range tsuruYamlFiles {
+		content, ok := files[yamlFile]
+		if !ok {
+			continue
+		}
+		var data provTypes.TsuruYamlData
+		err = yaml.Unmarshal(content, &data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", yamlFile)
+		}
+		return &data, nil
+	}
+	return nil, nil
+}
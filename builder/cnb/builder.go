@@ -0,0 +1,269 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cnb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/builder"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/net"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+const (
+	buildMetadataLabel = "io.buildpacks.build.metadata"
+	processBinDir      = "/cnb/process/"
+)
+
+var _ builder.AppBuilder = &cnbBuilder{}
+
+// cnbBuilder builds apps from source with Cloud Native Buildpacks. It's used
+// by the apps with a buildpacks builder image configured, either in the app
+// or in its pool.
+type cnbBuilder struct{}
+
+func init() {
+	builder.Register("cnb", &cnbBuilder{})
+}
+
+func cnbClient(prov interface{}, app provision.App) (provision.BuilderKubeClient, provision.BuilderKubeClientCNB, bool, error) {
+	p, ok := prov.(provision.BuilderDeployKubeClient)
+	if !ok {
+		return nil, nil, false, nil
+	}
+	client, err := p.GetClient(app)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	cnbClient, ok := client.(provision.BuilderKubeClientCNB)
+	return client, cnbClient, ok, nil
+}
+
+func (b *cnbBuilder) HandlesApp(ctx context.Context, p provision.Provisioner, app provision.App) (bool, error) {
+	_, client, ok, err := cnbClient(p, app)
+	if err != nil || !ok {
+		return false, err
+	}
+	builderImage, err := client.CNBBuilderImage(ctx, app)
+	if err != nil {
+		return false, err
+	}
+	return builderImage != "", nil
+}
+
+func (b *cnbBuilder) Build(ctx context.Context, prov provision.BuilderDeploy, app provision.App, evt *event.Event, opts *builder.BuildOpts) (appTypes.AppVersion, error) {
+	kubeClient, client, ok, err := cnbClient(prov, app)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("provisioner not supported")
+	}
+	if opts.ImageID != "" {
+		// Deploying an image doesn't run buildpacks, it's handled by the
+		// provisioner builder.
+		p, ok := prov.(provision.Provisioner)
+		if !ok {
+			return nil, errors.New("provisioner not supported")
+		}
+		provBuilder, err := builder.GetForProvisioner(p)
+		if err != nil {
+			return nil, err
+		}
+		return provBuilder.Build(ctx, prov, app, evt, opts)
+	}
+	if opts.BuildFromFile {
+		return nil, errors.New("build image from Dockerfile is not supported by cnb builder")
+	}
+	builderImage, err := client.CNBBuilderImage(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	if builderImage == "" {
+		return nil, errors.Errorf("no buildpacks builder image configured for app %q", app.GetName())
+	}
+	var previousVersion appTypes.AppVersion
+	archiveFile := opts.ArchiveFile
+	if opts.Rebuild {
+		previousVersion, err = servicemanager.AppVersion.LatestSuccessfulVersion(ctx, app)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if opts.ArchiveURL != "" {
+			var tarFile io.ReadCloser
+			tarFile, err = downloadFromURL(ctx, opts.ArchiveURL)
+			if err != nil {
+				return nil, err
+			}
+			defer tarFile.Close()
+			archiveFile = tarFile
+		}
+		if archiveFile == nil {
+			return nil, errors.New("no valid files found")
+		}
+	}
+	newVersion, err := servicemanager.AppVersion.NewAppVersion(ctx, appTypes.NewVersionArgs{
+		App:            app,
+		EventID:        evt.UniqueID.Hex(),
+		CustomBuildTag: opts.Tag,
+		Description:    opts.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var customData map[string]interface{}
+	if previousVersion != nil {
+		// The source of the app isn't kept in the image, so rebuilds rebase
+		// the image of the previous version on the current run image.
+		previousImage := previousVersion.VersionInfo().DeployImageReference()
+		fmt.Fprintf(evt, "---- Rebasing %q with buildpacks from %q ----\n", previousImage, builderImage)
+		err = client.RebaseCNBPod(ctx, app, evt, previousImage, newVersion, builderImage)
+		if err != nil {
+			return nil, err
+		}
+		customData = tsuruYamlCustomData(previousVersion.VersionInfo().CustomData)
+	} else {
+		fmt.Fprintf(evt, "---- Building with buildpacks from %q ----\n", builderImage)
+		archiveFile, yamlResult := inspectArchive(archiveFile)
+		err = client.BuildCNBPod(ctx, app, evt, archiveFile, newVersion, builderImage)
+		tsuruYaml, yamlErr := yamlResult()
+		if err != nil {
+			return nil, err
+		}
+		if yamlErr != nil {
+			return nil, yamlErr
+		}
+		customData = tsuruYamlToCustomData(tsuruYaml)
+	}
+	err = newVersion.CommitBaseImage()
+	if err != nil {
+		return nil, err
+	}
+	inspectData, err := kubeClient.ImageTagPushAndInspect(ctx, app, evt, newVersion.BaseImageName(), newVersion)
+	if err != nil {
+		return nil, err
+	}
+	var labels map[string]string
+	if inspectData.Image.Config != nil {
+		labels = inspectData.Image.Config.Labels
+	}
+	processes, err := processesFromLabels(labels)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(processes))
+	for name := range processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(evt, " ---> Process %q found with commands: %q\n", name, processes[name])
+	}
+	versionData := appTypes.AddVersionDataArgs{
+		Processes:  processes,
+		CustomData: customData,
+	}
+	if inspectData.Image.Config != nil {
+		for port := range inspectData.Image.Config.ExposedPorts {
+			versionData.ExposedPorts = append(versionData.ExposedPorts, string(port))
+		}
+		sort.Strings(versionData.ExposedPorts)
+	}
+	err = newVersion.AddData(versionData)
+	if err != nil {
+		return nil, err
+	}
+	return newVersion, nil
+}
+
+// processesFromLabels returns the processes declared by the buildpacks in
+// the image build metadata, each one running the launcher for its type.
+func processesFromLabels(labels map[string]string) (map[string][]string, error) {
+	rawMetadata := labels[buildMetadataLabel]
+	if rawMetadata == "" {
+		return nil, errors.Errorf("image has no %q label, was it built by buildpacks?", buildMetadataLabel)
+	}
+	var metadata struct {
+		Processes []struct {
+			Type string `json:"type"`
+		} `json:"processes"`
+	}
+	err := json.Unmarshal([]byte(rawMetadata), &metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid buildpacks build metadata")
+	}
+	processes := map[string][]string{}
+	for _, p := range metadata.Processes {
+		if p.Type == "" {
+			continue
+		}
+		processes[p.Type] = []string{processBinDir + p.Type}
+	}
+	if len(processes) == 0 {
+		return nil, errors.New("no processes declared by buildpacks")
+	}
+	return processes, nil
+}
+
+func tsuruYamlToCustomData(yaml *provTypes.TsuruYamlData) map[string]interface{} {
+	if yaml == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"healthcheck": yaml.Healthcheck,
+		"hooks":       yaml.Hooks,
+		"kubernetes":  yaml.Kubernetes,
+	}
+}
+
+// tsuruYamlCustomData returns the tsuru.yaml entries in the custom data of a
+// version built with buildpacks.
+func tsuruYamlCustomData(data map[string]interface{}) map[string]interface{} {
+	var result map[string]interface{}
+	for _, key := range []string{"healthcheck", "hooks", "kubernetes"} {
+		if value, ok := data[key]; ok {
+			if result == nil {
+				result = map[string]interface{}{}
+			}
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func downloadFromURL(ctx context.Context, url string) (io.ReadCloser, error) {
+	var out bytes.Buffer
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	resp, err := net.Dial15Full300Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	s, err := io.Copy(&out, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if s == 0 {
+		return nil, errors.New("archive file is empty")
+	}
+	return ioutil.NopCloser(&out), nil
+}
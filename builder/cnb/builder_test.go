@@ -0,0 +1,123 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cnb
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+)
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) {
+	check.TestingT(t)
+}
+
+func archiveWithFiles(c *check.C, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		c.Assert(err, check.IsNil)
+		_, err = tarWriter.Write([]byte(content))
+		c.Assert(err, check.IsNil)
+	}
+	c.Assert(tarWriter.Close(), check.IsNil)
+	c.Assert(gzipWriter.Close(), check.IsNil)
+	return buf.Bytes()
+}
+
+func (s *S) TestInspectArchive(c *check.C) {
+	data := archiveWithFiles(c, map[string]string{
+		"./app.yaml":   "healthcheck:\n  path: /ignored\n",
+		"./tsuru.yaml": "healthcheck:\n  path: /health\n",
+		"main.go":      "package main",
+	})
+	reader, result := inspectArchive(bytes.NewReader(data))
+	read, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	c.Assert(read, check.DeepEquals, data)
+	yamlData, err := result()
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData, check.DeepEquals, &provTypes.TsuruYamlData{
+		Healthcheck: &provTypes.TsuruYamlHealthcheck{Path: "/health"},
+	})
+}
+
+func (s *S) TestInspectArchivePartiallyRead(c *check.C) {
+	data := archiveWithFiles(c, map[string]string{"main.go": "package main"})
+	reader, result := inspectArchive(bytes.NewReader(data))
+	_, err := reader.Read(make([]byte, 10))
+	c.Assert(err, check.IsNil)
+	yamlData, err := result()
+	c.Assert(err, check.ErrorMatches, "unable to read app archive: .*")
+	c.Assert(yamlData, check.IsNil)
+}
+
+func (s *S) TestInspectArchiveNotGzip(c *check.C) {
+	reader, result := inspectArchive(bytes.NewReader([]byte("not an archive")))
+	_, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	_, err = result()
+	c.Assert(err, check.ErrorMatches, "unable to read app archive: .*")
+}
+
+func (s *S) TestInspectArchiveWithoutTsuruYaml(c *check.C) {
+	data := archiveWithFiles(c, map[string]string{"main.go": "package main"})
+	reader, result := inspectArchive(bytes.NewReader(data))
+	_, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	yamlData, err := result()
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData, check.IsNil)
+}
+
+func (s *S) TestInspectArchiveInvalidYaml(c *check.C) {
+	data := archiveWithFiles(c, map[string]string{"tsuru.yml": "healthcheck: ["})
+	reader, result := inspectArchive(bytes.NewReader(data))
+	_, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	_, err = result()
+	c.Assert(err, check.ErrorMatches, "invalid tsuru.yml: .*")
+}
+
+func (s *S) TestProcessesFromLabels(c *check.C) {
+	processes, err := processesFromLabels(map[string]string{
+		buildMetadataLabel: `{"processes": [{"type": "web", "command": "node", "args": ["index.js"]}, {"type": "worker", "command": "node"}]}`,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(processes, check.DeepEquals, map[string][]string{
+		"web":    {"/cnb/process/web"},
+		"worker": {"/cnb/process/worker"},
+	})
+	_, err = processesFromLabels(nil)
+	c.Assert(err, check.ErrorMatches, `image has no "io.buildpacks.build.metadata" label, was it built by buildpacks\?`)
+	_, err = processesFromLabels(map[string]string{buildMetadataLabel: `{"processes": []}`})
+	c.Assert(err, check.ErrorMatches, "no processes declared by buildpacks")
+}
+
+func (s *S) TestTsuruYamlCustomData(c *check.C) {
+	c.Assert(tsuruYamlCustomData(nil), check.IsNil)
+	c.Assert(tsuruYamlCustomData(map[string]interface{}{
+		"healthcheck": map[string]interface{}{"path": "/health"},
+		"other":       "ignored",
+	}), check.DeepEquals, map[string]interface{}{
+		"healthcheck": map[string]interface{}{"path": "/health"},
+	})
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	provTypes "github.com/tsuru/tsuru/types/provision"
+	registryTypes "github.com/tsuru/tsuru/types/registry"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -122,7 +123,7 @@ func imageBuild(ctx context.Context, client provision.BuilderDockerClient, app p
 	fmt.Fprintln(evt, "---- Getting process from image ----")
 	cmd := generateCatCommand([]string{procfileFileName}, dirPaths)
 	var procfileBuf bytes.Buffer
-	containerID, err := runCommandInContainer(client, evt, imageID, cmd, app, &procfileBuf, nil)
+	containerID, err := runCommandInContainer(ctx, client, evt, imageID, cmd, app, &procfileBuf, nil)
 	defer removeContainer(client, containerID)
 	if err != nil {
 		return nil, err
@@ -147,12 +148,12 @@ func imageBuild(ctx context.Context, client provision.BuilderDockerClient, app p
 		fmt.Fprintf(evt, "  ---> Process %q found with commands: %q\n", k, v)
 	}
 	fmt.Fprintln(evt, "---- Getting tsuru.yaml from image ----")
-	yaml, containerID, err := loadTsuruYaml(client, app, imageID, evt)
+	yaml, containerID, err := loadTsuruYaml(ctx, client, app, imageID, evt)
 	defer removeContainer(client, containerID)
 	if err != nil {
 		return nil, err
 	}
-	containerID, err = runBuildHooks(client, app, imageID, evt, yaml)
+	containerID, err = runBuildHooks(ctx, client, app, imageID, evt, yaml)
 	defer removeContainer(client, containerID)
 	if err != nil {
 		return nil, err
@@ -217,10 +218,10 @@ func pushImageToRegistry(ctx context.Context, client provision.BuilderDockerClie
 	return newVersion, nil
 }
 
-func loadTsuruYaml(client provision.BuilderDockerClient, app provision.App, imageID string, evt *event.Event) (*provTypes.TsuruYamlData, string, error) {
+func loadTsuruYaml(ctx context.Context, client provision.BuilderDockerClient, app provision.App, imageID string, evt *event.Event) (*provTypes.TsuruYamlData, string, error) {
 	cmd := generateCatCommand(tsuruYamlFiles, dirPaths)
 	var buf bytes.Buffer
-	containerID, err := runCommandInContainer(client, evt, imageID, cmd, app, &buf, nil)
+	containerID, err := runCommandInContainer(ctx, client, evt, imageID, cmd, app, &buf, nil)
 	if err != nil {
 		return nil, containerID, err
 	}
@@ -243,14 +244,14 @@ func tsuruYamlToCustomData(yaml *provTypes.TsuruYamlData) map[string]interface{}
 	}
 }
 
-func runBuildHooks(client provision.BuilderDockerClient, app provision.App, imageID string, evt *event.Event, tsuruYamlData *provTypes.TsuruYamlData) (string, error) {
+func runBuildHooks(ctx context.Context, client provision.BuilderDockerClient, app provision.App, imageID string, evt *event.Event, tsuruYamlData *provTypes.TsuruYamlData) (string, error) {
 	if tsuruYamlData == nil || tsuruYamlData.Hooks == nil || len(tsuruYamlData.Hooks.Build) == 0 {
 		return "", nil
 	}
 	cmd := strings.Join(tsuruYamlData.Hooks.Build, " && ")
 	fmt.Fprintln(evt, "---- Running build hooks ----")
 	fmt.Fprintf(evt, " ---> Running %q\n", cmd)
-	containerID, err := runCommandInContainer(client, evt, imageID, cmd, app, evt, evt)
+	containerID, err := runCommandInContainer(ctx, client, evt, imageID, cmd, app, evt, evt)
 	if err != nil {
 		return containerID, err
 	}
@@ -267,7 +268,11 @@ func runBuildHooks(client provision.BuilderDockerClient, app provision.App, imag
 	return newImage.ID, nil
 }
 
-func runCommandInContainer(client provision.BuilderDockerClient, evt *event.Event, imageID string, command string, app provision.App, stdout, stderr io.Writer) (string, error) {
+func runCommandInContainer(ctx context.Context, client provision.BuilderDockerClient, evt *event.Event, imageID string, command string, app provision.App, stdout, stderr io.Writer) (string, error) {
+	pullCtx, err := teamPullAuthContext(ctx, app, imageID)
+	if err != nil {
+		return "", err
+	}
 	createOptions := docker.CreateContainerOptions{
 		Config: &docker.Config{
 			AttachStdout: true,
@@ -276,6 +281,7 @@ func runCommandInContainer(client provision.BuilderDockerClient, evt *event.Even
 			Entrypoint:   []string{"/bin/sh", "-c"},
 			Cmd:          []string{command},
 		},
+		Context: pullCtx,
 	}
 	cont, _, err := client.PullAndCreateContainer(createOptions, evt)
 	if err != nil {
@@ -304,6 +310,24 @@ func runCommandInContainer(client provision.BuilderDockerClient, evt *event.Even
 	return cont.ID, nil
 }
 
+// teamPullAuthContext returns a context carrying the registry credential of
+// the app team owner for the image, to be used when pulling it. It returns a
+// nil context when the team has no credential for the image registry.
+func teamPullAuthContext(ctx context.Context, app provision.App, imageID string) (context.Context, error) {
+	cred, err := servicemanager.RegistryCredential.FindForImage(ctx, []string{app.GetTeamOwner()}, imageID)
+	if err == registryTypes.ErrCredentialNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dockercommon.WithPullAuth(ctx, docker.AuthConfiguration{
+		ServerAddress: registryTypes.ServerAddress(cred.Registry),
+		Username:      cred.Username,
+		Password:      cred.Token,
+	}), nil
+}
+
 func removeContainer(client provision.BuilderDockerClient, containerID string) error {
 	if containerID == "" {
 		return nil
@@ -12,6 +12,7 @@ import (
 	"github.com/google/gops/agent"
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/api"
+	_ "github.com/tsuru/tsuru/builder/cnb"
 	_ "github.com/tsuru/tsuru/builder/docker"
 	_ "github.com/tsuru/tsuru/builder/kubernetes"
 	"github.com/tsuru/tsuru/cmd"
@@ -14,6 +14,7 @@ import (
 	"github.com/tsuru/tsuru/auth"
 	_ "github.com/tsuru/tsuru/auth/native"
 	_ "github.com/tsuru/tsuru/auth/oauth"
+	_ "github.com/tsuru/tsuru/auth/oidc"
 	"github.com/tsuru/tsuru/cmd"
 	"github.com/tsuru/tsuru/permission"
 	_ "github.com/tsuru/tsuru/storage/mongodb"
@@ -184,6 +184,27 @@ func (s *Storage) EventBlocks() *storage.Collection {
 	return c
 }
 
+func (s *Storage) EventFreezeWindows() *storage.Collection {
+	nameIndex := mgo.Index{Key: []string{"name"}, Unique: true}
+	c := s.Collection("event_freeze_windows")
+	c.EnsureIndex(nameIndex)
+	return c
+}
+
+func (s *Storage) AppApprovalRequests() *storage.Collection {
+	appsIndex := mgo.Index{Key: []string{"apps", "-createdat"}}
+	c := s.Collection("app_approval_requests")
+	c.EnsureIndex(appsIndex)
+	return c
+}
+
+func (s *Storage) AppVersionSBOMs() *storage.Collection {
+	keyIndex := mgo.Index{Key: []string{"app", "version"}, Unique: true}
+	c := s.Collection("app_version_sboms")
+	c.EnsureIndex(keyIndex)
+	return c
+}
+
 func (s *Storage) InstallHosts() *storage.Collection {
 	nameIndex := mgo.Index{Key: []string{"name"}, Unique: true}
 	c := s.Collection("install_hosts")
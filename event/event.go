@@ -101,6 +101,7 @@ var (
 	TargetTypeServiceBroker   = TargetType("service-broker")
 	TargetTypeTeam            = TargetType("team")
 	TargetTypeUser            = TargetType("user")
+	TargetTypeGroup           = TargetType("group")
 	TargetTypeIaas            = TargetType("iaas")
 	TargetTypeRole            = TargetType("role")
 	TargetTypePlatform        = TargetType("platform")
@@ -108,11 +109,13 @@ var (
 	TargetTypeNodeContainer   = TargetType("node-container")
 	TargetTypeInstallHost     = TargetType("install-host")
 	TargetTypeEventBlock      = TargetType("event-block")
+	TargetTypeDeployFreeze    = TargetType("deploy-freeze")
 	TargetTypeCluster         = TargetType("cluster")
 	TargetTypeVolume          = TargetType("volume")
 	TargetTypeWebhook         = TargetType("webhook")
 	TargetTypeGC              = TargetType("gc")
 	TargetTypeRouter          = TargetType("router")
+	TargetTypeMetering        = TargetType("metering")
 )
 
 const (
@@ -260,6 +263,8 @@ func GetTargetType(t string) (TargetType, error) {
 		return TargetTypeTeam, nil
 	case "user":
 		return TargetTypeUser, nil
+	case "group":
+		return TargetTypeGroup, nil
 	case "iaas":
 		return TargetTypeIaas, nil
 	case "role":
@@ -274,6 +279,8 @@ func GetTargetType(t string) (TargetType, error) {
 		return TargetTypeInstallHost, nil
 	case "event-block":
 		return TargetTypeEventBlock, nil
+	case "deploy-freeze":
+		return TargetTypeDeployFreeze, nil
 	case "cluster":
 		return TargetTypeCluster, nil
 	case "volume":
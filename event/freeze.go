@@ -0,0 +1,200 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+)
+
+const maxFreezeDuration = 31 * 24 * time.Hour
+
+var (
+	ErrFreezeWindowNotFound      = errors.New("freeze window not found")
+	ErrFreezeWindowAlreadyExists = errors.New("freeze window already exists")
+)
+
+// ErrDeployFrozen is returned when a deploy happens inside an active freeze
+// window.
+type ErrDeployFrozen struct {
+	Window *FreezeWindow
+	End    time.Time
+}
+
+func (e *ErrDeployFrozen) Error() string {
+	return fmt.Sprintf("deploys are frozen by %s until %s", e.Window, e.End.Format(time.RFC3339))
+}
+
+// FreezeWindow is a recurring period in which deploys are blocked. Each
+// window starts whenever the cron Schedule fires, in the given Timezone, and
+// lasts for Duration. The window applies to apps in any of the Pools or with
+// any of the Tags, or to every app when both are empty.
+type FreezeWindow struct {
+	ID        bson.ObjectId `bson:"_id,omitempty"`
+	Name      string
+	Schedule  string
+	Duration  time.Duration
+	Timezone  string
+	Pools     []string `bson:",omitempty"`
+	Tags      []string `bson:",omitempty"`
+	Reason    string
+	Owner     string
+	CreatedAt time.Time
+}
+
+func (w *FreezeWindow) String() string {
+	return fmt.Sprintf("freeze window %q (%s)", w.Name, w.Reason)
+}
+
+func (w *FreezeWindow) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.Timezone)
+}
+
+func (w *FreezeWindow) Validate() error {
+	if w.Name == "" {
+		return &tsuruErrors.ValidationError{Message: "name is required"}
+	}
+	if w.Reason == "" {
+		return &tsuruErrors.ValidationError{Message: "reason is required"}
+	}
+	if _, err := parseSchedule(w.Schedule); err != nil {
+		return &tsuruErrors.ValidationError{Message: err.Error()}
+	}
+	if w.Duration < time.Minute || w.Duration > maxFreezeDuration {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("duration must be between 1m and %v", maxFreezeDuration)}
+	}
+	if _, err := w.location(); err != nil {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid timezone %q", w.Timezone)}
+	}
+	return nil
+}
+
+// Applies returns whether the window covers apps in the pool with the tags.
+func (w *FreezeWindow) Applies(pool string, tags []string) bool {
+	if len(w.Pools) == 0 && len(w.Tags) == 0 {
+		return true
+	}
+	for _, p := range w.Pools {
+		if p == pool {
+			return true
+		}
+	}
+	for _, wt := range w.Tags {
+		for _, t := range tags {
+			if wt == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ActiveAt returns the end of the window occurrence active at t, if any.
+func (w *FreezeWindow) ActiveAt(t time.Time) (time.Time, bool) {
+	s, err := parseSchedule(w.Schedule)
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc, err := w.location()
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, ok := s.lastStart(t, w.Duration, loc)
+	if !ok {
+		return time.Time{}, false
+	}
+	return start.Add(w.Duration), true
+}
+
+func AddFreezeWindow(w *FreezeWindow) error {
+	w.Name = strings.TrimSpace(w.Name)
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	w.ID = bson.NewObjectId()
+	w.CreatedAt = time.Now()
+	err = conn.EventFreezeWindows().Insert(w)
+	if mgo.IsDup(err) {
+		return ErrFreezeWindowAlreadyExists
+	}
+	return err
+}
+
+func RemoveFreezeWindow(id bson.ObjectId) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.EventFreezeWindows().RemoveId(id)
+	if err == mgo.ErrNotFound {
+		return ErrFreezeWindowNotFound
+	}
+	return err
+}
+
+func GetFreezeWindow(id bson.ObjectId) (*FreezeWindow, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var w FreezeWindow
+	err = conn.EventFreezeWindows().FindId(id).One(&w)
+	if err == mgo.ErrNotFound {
+		return nil, ErrFreezeWindowNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func ListFreezeWindows() ([]FreezeWindow, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var windows []FreezeWindow
+	err = conn.EventFreezeWindows().Find(nil).Sort("name").All(&windows)
+	if err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// CheckDeployFreeze returns an *ErrDeployFrozen error when a freeze window
+// covering apps in the pool with the tags is active at now.
+func CheckDeployFreeze(pool string, tags []string, now time.Time) error {
+	windows, err := ListFreezeWindows()
+	if err != nil {
+		return err
+	}
+	for i := range windows {
+		if !windows[i].Applies(pool, tags) {
+			continue
+		}
+		if end, ok := windows[i].ActiveAt(now); ok {
+			return &ErrDeployFrozen{Window: &windows[i], End: end}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestParseScheduleInvalid(c *check.C) {
+	tests := []struct {
+		schedule string
+		err      string
+	}{
+		{"* * * *", `invalid schedule "\* \* \* \*", must be a cron expression with 5 fields`},
+		{"60 * * * *", `invalid schedule .*: value out of range in "60", must be between 0 and 59`},
+		{"* * 0 * *", `invalid schedule .*: value out of range in "0", must be between 1 and 31`},
+		{"* * * * 5-1", `invalid schedule .*: value out of range in "5-1", must be between 0 and 7`},
+		{"*/0 * * * *", `invalid schedule .*: invalid step in "\*/0"`},
+		{"a * * * *", `invalid schedule .*: invalid value in "a"`},
+	}
+	for _, tt := range tests {
+		_, err := parseSchedule(tt.schedule)
+		c.Check(err, check.ErrorMatches, tt.err)
+	}
+}
+
+func (s *S) TestScheduleMatches(c *check.C) {
+	tests := []struct {
+		schedule string
+		time     time.Time
+		expected bool
+	}{
+		{"0 18 * * 5", time.Date(2026, 10, 23, 18, 0, 0, 0, time.UTC), true},
+		{"0 18 * * 5", time.Date(2026, 10, 23, 18, 1, 0, 0, time.UTC), false},
+		{"0 18 * * 5", time.Date(2026, 10, 22, 18, 0, 0, 0, time.UTC), false},
+		{"*/15 9-17 * * 1-5", time.Date(2026, 10, 19, 9, 45, 0, 0, time.UTC), true},
+		{"*/15 9-17 * * 1-5", time.Date(2026, 10, 18, 9, 45, 0, 0, time.UTC), false},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 1,15 * 1", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 1,15 * 1", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 1,15 * 1", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), false},
+		{"@yearly", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		sched, err := parseSchedule(tt.schedule)
+		c.Assert(err, check.IsNil)
+		c.Check(sched.matches(tt.time), check.Equals, tt.expected, check.Commentf("%s at %v", tt.schedule, tt.time))
+	}
+}
+
+func (s *S) TestFreezeWindowActiveAt(c *check.C) {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	c.Assert(err, check.IsNil)
+	w := FreezeWindow{Schedule: "0 18 * * 5", Duration: 15 * time.Hour, Timezone: "America/Sao_Paulo"}
+	end := time.Date(2026, 10, 24, 9, 0, 0, 0, loc)
+	tests := []struct {
+		time   time.Time
+		active bool
+	}{
+		{time.Date(2026, 10, 23, 17, 59, 0, 0, loc), false},
+		{time.Date(2026, 10, 23, 18, 0, 0, 0, loc), true},
+		{time.Date(2026, 10, 23, 21, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 24, 8, 59, 59, 0, loc), true},
+		{time.Date(2026, 10, 24, 9, 0, 0, 0, loc), false},
+	}
+	for _, tt := range tests {
+		windowEnd, active := w.ActiveAt(tt.time)
+		c.Check(active, check.Equals, tt.active, check.Commentf("at %v", tt.time))
+		if tt.active {
+			c.Check(windowEnd.Equal(end), check.Equals, true, check.Commentf("at %v: %v", tt.time, windowEnd))
+		}
+	}
+	holidays := FreezeWindow{Schedule: "0 0 24 12 *", Duration: 9 * 24 * time.Hour}
+	windowEnd, active := holidays.ActiveAt(time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC))
+	c.Assert(active, check.Equals, true)
+	c.Assert(windowEnd, check.DeepEquals, time.Date(2027, 1, 2, 0, 0, 0, 0, time.UTC))
+}
+
+func (s *S) TestFreezeWindowApplies(c *check.C) {
+	c.Assert((&FreezeWindow{}).Applies("pool1", nil), check.Equals, true)
+	w := FreezeWindow{Pools: []string{"prod"}, Tags: []string{"critical"}}
+	c.Assert(w.Applies("prod", nil), check.Equals, true)
+	c.Assert(w.Applies("dev", []string{"web", "critical"}), check.Equals, true)
+	c.Assert(w.Applies("dev", []string{"web"}), check.Equals, false)
+}
+
+func (s *S) TestAddFreezeWindow(c *check.C) {
+	w := &FreezeWindow{Name: "friday", Schedule: "0 18 * * 5", Duration: 15 * time.Hour, Timezone: "America/Sao_Paulo", Pools: []string{"prod"}, Reason: "weekend"}
+	err := AddFreezeWindow(w)
+	c.Assert(err, check.IsNil)
+	c.Assert(w.ID.Valid(), check.Equals, true)
+	windows, err := ListFreezeWindows()
+	c.Assert(err, check.IsNil)
+	c.Assert(windows, check.HasLen, 1)
+	c.Assert(windows[0].Name, check.Equals, "friday")
+	c.Assert(windows[0].Pools, check.DeepEquals, []string{"prod"})
+	err = AddFreezeWindow(&FreezeWindow{Name: "friday", Schedule: "@daily", Duration: time.Hour, Reason: "other"})
+	c.Assert(err, check.Equals, ErrFreezeWindowAlreadyExists)
+}
+
+func (s *S) TestAddFreezeWindowInvalid(c *check.C) {
+	tests := []struct {
+		window FreezeWindow
+		err    string
+	}{
+		{FreezeWindow{Schedule: "@daily", Duration: time.Hour, Reason: "r"}, "name is required"},
+		{FreezeWindow{Name: "w", Schedule: "@daily", Duration: time.Hour}, "reason is required"},
+		{FreezeWindow{Name: "w", Schedule: "@never", Duration: time.Hour, Reason: "r"}, "invalid schedule.*"},
+		{FreezeWindow{Name: "w", Schedule: "@daily", Reason: "r"}, "duration must be between 1m and 744h0m0s"},
+		{FreezeWindow{Name: "w", Schedule: "@daily", Duration: time.Hour, Timezone: "Mars/Olympus", Reason: "r"}, `invalid timezone "Mars/Olympus"`},
+	}
+	for _, tt := range tests {
+		err := AddFreezeWindow(&tt.window)
+		c.Check(err, check.ErrorMatches, tt.err)
+		c.Check(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+	}
+}
+
+func (s *S) TestRemoveFreezeWindow(c *check.C) {
+	w := &FreezeWindow{Name: "friday", Schedule: "0 18 * * 5", Duration: time.Hour, Reason: "weekend"}
+	err := AddFreezeWindow(w)
+	c.Assert(err, check.IsNil)
+	err = RemoveFreezeWindow(w.ID)
+	c.Assert(err, check.IsNil)
+	windows, err := ListFreezeWindows()
+	c.Assert(err, check.IsNil)
+	c.Assert(windows, check.HasLen, 0)
+	err = RemoveFreezeWindow(bson.NewObjectId())
+	c.Assert(err, check.Equals, ErrFreezeWindowNotFound)
+}
+
+func (s *S) TestCheckDeployFreeze(c *check.C) {
+	err := AddFreezeWindow(&FreezeWindow{Name: "always", Schedule: "* * * * *", Duration: time.Hour, Tags: []string{"critical"}, Reason: "incident"})
+	c.Assert(err, check.IsNil)
+	now := time.Now()
+	err = CheckDeployFreeze("prod", []string{"web"}, now)
+	c.Assert(err, check.IsNil)
+	err = CheckDeployFreeze("prod", []string{"critical"}, now)
+	c.Assert(err, check.FitsTypeOf, &ErrDeployFrozen{})
+	c.Assert(err, check.ErrorMatches, `deploys are frozen by freeze window "always" \(incident\) until .*`)
+}
+
+func (s *S) TestGetFreezeWindow(c *check.C) {
+	w := &FreezeWindow{Name: "friday", Schedule: "0 18 * * 5", Duration: time.Hour, Reason: "weekend", Pools: []string{"prod"}}
+	err := AddFreezeWindow(w)
+	c.Assert(err, check.IsNil)
+	dbWindow, err := GetFreezeWindow(w.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbWindow.Name, check.Equals, "friday")
+	c.Assert(dbWindow.Pools, check.DeepEquals, []string{"prod"})
+	_, err = GetFreezeWindow(bson.NewObjectId())
+	c.Assert(err, check.Equals, ErrFreezeWindowNotFound)
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var scheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type scheduleField struct {
+	min, max int
+	values   map[int]bool
+	all      bool
+}
+
+// schedule is a parsed cron expression with the standard five fields:
+// minute, hour, day of month, month and day of week.
+type schedule struct {
+	minute, hour, dom, month, dow scheduleField
+}
+
+func parseSchedule(expr string) (*schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := scheduleMacros[expr]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("invalid schedule %q, must be a cron expression with 5 fields", expr)
+	}
+	s := &schedule{
+		minute: scheduleField{min: 0, max: 59},
+		hour:   scheduleField{min: 0, max: 23},
+		dom:    scheduleField{min: 1, max: 31},
+		month:  scheduleField{min: 1, max: 12},
+		dow:    scheduleField{min: 0, max: 7},
+	}
+	for i, f := range []*scheduleField{&s.minute, &s.hour, &s.dom, &s.month, &s.dow} {
+		if err := f.parse(fields[i]); err != nil {
+			return nil, errors.Wrapf(err, "invalid schedule %q", expr)
+		}
+	}
+	if s.dow.values[7] {
+		s.dow.values[0] = true
+	}
+	return s, nil
+}
+
+func (f *scheduleField) parse(expr string) error {
+	f.values = map[int]bool{}
+	f.all = strings.HasPrefix(expr, "*")
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeExpr = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return errors.Errorf("invalid step in %q", part)
+			}
+		}
+		start, end := f.min, f.max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			start, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return errors.Errorf("invalid value in %q", part)
+			}
+			end = start
+			if len(bounds) == 2 {
+				end, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return errors.Errorf("invalid value in %q", part)
+				}
+			} else if step > 1 {
+				end = f.max
+			}
+		}
+		if start < f.min || end > f.max || start > end {
+			return errors.Errorf("value out of range in %q, must be between %d and %d", part, f.min, f.max)
+		}
+		for v := start; v <= end; v += step {
+			f.values[v] = true
+		}
+	}
+	return nil
+}
+
+// matches returns whether the schedule fires at the minute of t. As in cron,
+// when both day of month and day of week are restricted, matching either one
+// is enough.
+func (s *schedule) matches(t time.Time) bool {
+	if !s.minute.values[t.Minute()] || !s.hour.values[t.Hour()] || !s.month.values[int(t.Month())] {
+		return false
+	}
+	domMatch := s.dom.values[t.Day()]
+	dowMatch := s.dow.values[int(t.Weekday())]
+	if s.dom.all || s.dow.all {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// lastStart returns the latest time in the interval (t - d, t] at which the
+// schedule fires, evaluated in the given location.
+func (s *schedule) lastStart(t time.Time, d time.Duration, loc *time.Location) (time.Time, bool) {
+	t = t.In(loc).Truncate(time.Minute)
+	limit := t.Add(-d)
+	for current := t; current.After(limit); current = current.Add(-time.Minute) {
+		if s.matches(current.In(loc)) {
+			return current, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+type ConditionType string
+
+var (
+	// ConditionTag restricts a role to objects carrying the given tag.
+	ConditionTag = ConditionType("tag")
+	// ConditionPool restricts a role to objects in pools matching the given
+	// glob pattern.
+	ConditionPool = ConditionType("pool")
+	// ConditionTime restricts a role to a daily time window in the format
+	// "HH:MM-HH:MM", optionally followed by a time zone name, e.g.
+	// "09:00-18:00 America/Sao_Paulo". Windows are evaluated in UTC when no
+	// time zone is given.
+	ConditionTime = ConditionType("time")
+
+	conditionTypes = []ConditionType{ConditionTag, ConditionPool, ConditionTime}
+
+	ErrInvalidCondition = errors.New("invalid role condition")
+
+	timeNow = time.Now
+)
+
+// RoleCondition restricts when the permissions of a role, both allowed and
+// denied, are applied. All conditions in a role must match.
+type RoleCondition struct {
+	Type  ConditionType `json:"type"`
+	Value string        `json:"value"`
+}
+
+func ParseConditionType(t string) (ConditionType, error) {
+	for _, ct := range conditionTypes {
+		if string(ct) == t {
+			return ct, nil
+		}
+	}
+	return "", errors.Wrapf(ErrInvalidCondition, "unknown condition type %q", t)
+}
+
+func (c RoleCondition) Validate() error {
+	if _, err := ParseConditionType(string(c.Type)); err != nil {
+		return err
+	}
+	if c.Value == "" {
+		return errors.Wrapf(ErrInvalidCondition, "value is required for condition %q", c.Type)
+	}
+	switch c.Type {
+	case ConditionPool:
+		if _, err := path.Match(c.Value, ""); err != nil {
+			return errors.Wrapf(ErrInvalidCondition, "invalid pool pattern %q", c.Value)
+		}
+	case ConditionTime:
+		if _, err := parseTimeWindow(c.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c RoleCondition) String() string {
+	return string(c.Type) + "=" + c.Value
+}
+
+// match returns whether the condition holds for the given contexts. The
+// second return value is false when the contexts carry no information that
+// would allow evaluating the condition.
+func (c RoleCondition) match(contexts []permTypes.PermissionContext, now time.Time) (bool, bool) {
+	switch c.Type {
+	case ConditionTag:
+		matched, found := matchContexts(contexts, permTypes.CtxTag, func(v string) bool {
+			return v == c.Value
+		})
+		// Apps carry every tag along with their app and pool contexts, so
+		// an app without the tag is known not to match it.
+		return matched, found || hasContext(contexts, permTypes.CtxApp) || hasContext(contexts, permTypes.CtxPool)
+	case ConditionPool:
+		return matchContexts(contexts, permTypes.CtxPool, func(v string) bool {
+			matched, _ := path.Match(c.Value, v)
+			return matched
+		})
+	case ConditionTime:
+		window, err := parseTimeWindow(c.Value)
+		if err != nil {
+			return false, false
+		}
+		return window.contains(now), true
+	}
+	return false, false
+}
+
+func matchContexts(contexts []permTypes.PermissionContext, ctxType permTypes.ContextType, fn func(string) bool) (bool, bool) {
+	var found bool
+	for _, ctx := range contexts {
+		if ctx.CtxType != ctxType {
+			continue
+		}
+		found = true
+		if fn(ctx.Value) {
+			return true, true
+		}
+	}
+	return false, found
+}
+
+func hasContext(contexts []permTypes.PermissionContext, ctxType permTypes.ContextType) bool {
+	for _, ctx := range contexts {
+		if ctx.CtxType == ctxType {
+			return true
+		}
+	}
+	return false
+}
+
+type timeWindow struct {
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+func parseTimeWindow(value string) (*timeWindow, error) {
+	invalidErr := errors.Wrapf(ErrInvalidCondition, "invalid time window %q, expected HH:MM-HH:MM [time zone]", value)
+	parts := strings.Fields(value)
+	if len(parts) == 0 || len(parts) > 2 {
+		return nil, invalidErr
+	}
+	window := timeWindow{location: time.UTC}
+	if len(parts) == 2 {
+		loc, err := time.LoadLocation(parts[1])
+		if err != nil {
+			return nil, invalidErr
+		}
+		window.location = loc
+	}
+	bounds := strings.Split(parts[0], "-")
+	if len(bounds) != 2 {
+		return nil, invalidErr
+	}
+	for i, dst := range []*time.Duration{&window.start, &window.end} {
+		t, err := time.Parse("15:04", bounds[i])
+		if err != nil {
+			return nil, invalidErr
+		}
+		*dst = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return &window, nil
+}
+
+func (w *timeWindow) contains(t time.Time) bool {
+	t = t.In(w.location)
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if w.start <= w.end {
+		return offset >= w.start && offset < w.end
+	}
+	// windows crossing midnight, e.g. 22:00-06:00
+	return offset >= w.start || offset < w.end
+}
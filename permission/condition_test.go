@@ -0,0 +1,59 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	"time"
+
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestRoleConditionValidate(c *check.C) {
+	tests := []struct {
+		cond RoleCondition
+		err  string
+	}{
+		{RoleCondition{Type: ConditionTag, Value: "prod"}, ""},
+		{RoleCondition{Type: ConditionPool, Value: "prod-*"}, ""},
+		{RoleCondition{Type: ConditionTime, Value: "09:00-18:00"}, ""},
+		{RoleCondition{Type: ConditionTime, Value: "22:00-06:00 America/Sao_Paulo"}, ""},
+		{RoleCondition{Type: ConditionTag}, `value is required for condition "tag": invalid role condition`},
+		{RoleCondition{Type: ConditionPool, Value: "prod-["}, `invalid pool pattern "prod-\[": invalid role condition`},
+		{RoleCondition{Type: ConditionTime, Value: "09:00"}, `invalid time window "09:00".*`},
+		{RoleCondition{Type: ConditionTime, Value: "09:00-18:00 Nowhere/City"}, `invalid time window .*`},
+		{RoleCondition{Type: "other", Value: "x"}, `unknown condition type "other": invalid role condition`},
+	}
+	for _, tt := range tests {
+		err := tt.cond.Validate()
+		if tt.err == "" {
+			c.Check(err, check.IsNil)
+		} else {
+			c.Check(err, check.ErrorMatches, tt.err)
+		}
+	}
+}
+
+func (s *S) TestTimeWindowContains(c *check.C) {
+	tests := []struct {
+		window   string
+		at       time.Time
+		expected bool
+	}{
+		{"09:00-18:00", time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC), true},
+		{"09:00-18:00", time.Date(2020, 1, 1, 17, 59, 0, 0, time.UTC), true},
+		{"09:00-18:00", time.Date(2020, 1, 1, 18, 0, 0, 0, time.UTC), false},
+		{"09:00-18:00", time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC), false},
+		{"22:00-06:00", time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC), true},
+		{"22:00-06:00", time.Date(2020, 1, 1, 5, 0, 0, 0, time.UTC), true},
+		{"22:00-06:00", time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC), false},
+		{"09:00-18:00 America/Sao_Paulo", time.Date(2020, 1, 1, 11, 0, 0, 0, time.UTC), false},
+		{"09:00-18:00 America/Sao_Paulo", time.Date(2020, 1, 1, 13, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		window, err := parseTimeWindow(tt.window)
+		c.Assert(err, check.IsNil)
+		c.Check(window.contains(tt.at), check.Equals, tt.expected, check.Commentf("%s at %s", tt.window, tt.at))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
@@ -120,8 +121,10 @@ func (s *PermissionScheme) AllowedContexts() []permTypes.ContextType {
 }
 
 type Permission struct {
-	Scheme  *PermissionScheme
-	Context permTypes.PermissionContext
+	Scheme     *PermissionScheme
+	Context    permTypes.PermissionContext
+	Deny       bool
+	Conditions []RoleCondition
 }
 
 func (p *Permission) String() string {
@@ -129,7 +132,100 @@ func (p *Permission) String() string {
 	if value != "" {
 		value = " " + value
 	}
-	return fmt.Sprintf("%s(%s%s)", p.Scheme.FullName(), p.Context.CtxType, value)
+	str := fmt.Sprintf("%s(%s%s)", p.Scheme.FullName(), p.Context.CtxType, value)
+	if p.Deny {
+		str = "!" + str
+	}
+	if len(p.Conditions) > 0 {
+		conds := make([]string, len(p.Conditions))
+		for i, c := range p.Conditions {
+			conds[i] = c.String()
+		}
+		str += fmt.Sprintf(" if [%s]", strings.Join(conds, ", "))
+	}
+	return str
+}
+
+// PermissionMatch describes how a permission relates to a checked scheme and
+// set of contexts.
+type PermissionMatch struct {
+	Scheme     bool
+	Context    bool
+	Conditions bool
+}
+
+// Applies returns whether the permission takes part in the check decision.
+func (m PermissionMatch) Applies() bool {
+	return m.Scheme && m.Context && m.Conditions
+}
+
+func (p *Permission) Match(scheme *PermissionScheme, contexts ...permTypes.PermissionContext) PermissionMatch {
+	return p.match(scheme, contexts, timeNow())
+}
+
+func (p *Permission) match(scheme *PermissionScheme, contexts []permTypes.PermissionContext, now time.Time) PermissionMatch {
+	return PermissionMatch{
+		Scheme:     p.Scheme.IsParent(scheme),
+		Context:    p.matchContexts(contexts),
+		Conditions: p.matchConditions(contexts, now),
+	}
+}
+
+func (p *Permission) matchContexts(contexts []permTypes.PermissionContext) bool {
+	if p.Context.CtxType == permTypes.CtxGlobal {
+		return true
+	}
+	for _, ctx := range contexts {
+		if ctx.CtxType == p.Context.CtxType && ctx.Value == p.Context.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchConditions evaluates the permission conditions against its own
+// context and the given contexts. Conditions that cannot be evaluated with
+// the available contexts are considered as not matching for allowed
+// permissions and as matching for denied ones, so that denials fail closed.
+func (p *Permission) matchConditions(contexts []permTypes.PermissionContext, now time.Time) bool {
+	if len(p.Conditions) == 0 {
+		return true
+	}
+	allContexts := append([]permTypes.PermissionContext{p.Context}, contexts...)
+	for _, c := range p.Conditions {
+		condContexts := allContexts
+		if c.Type == ConditionTag {
+			// Tags belong to the object being checked, never to the
+			// permission itself.
+			condContexts = contexts
+		}
+		matched, evaluated := c.match(condContexts, now)
+		if !evaluated && p.Deny {
+			continue
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchListConditions evaluates the permission conditions when listing
+// contexts, with no object to check against.
+func (p *Permission) matchListConditions(now time.Time) bool {
+	if p.Deny {
+		return p.matchConditions(nil, now)
+	}
+	for _, c := range p.Conditions {
+		if c.Type == ConditionTag {
+			continue
+		}
+		matched, _ := c.match([]permTypes.PermissionContext{p.Context}, now)
+		if !matched {
+			return false
+		}
+	}
+	return true
 }
 
 type Token interface {
@@ -151,23 +247,55 @@ func ListContextValues(t Token, scheme *PermissionScheme, failIfEmpty bool) ([]s
 	return values, nil
 }
 
+// ContextsFromListForPermission returns the contexts in which the scheme is
+// allowed by perms. Conditional permissions are only considered when their
+// conditions can be evaluated using the permission context alone, except for
+// tag conditions of allowed permissions, which depend on each object and are
+// left to Check. Conditional denials always remove their contexts. Check
+// remains authoritative for each individual object.
 func ContextsFromListForPermission(perms []Permission, scheme *PermissionScheme, ctxTypes ...permTypes.ContextType) []permTypes.PermissionContext {
-	var contexts []permTypes.PermissionContext
+	now := timeNow()
+	var contexts, denied []permTypes.PermissionContext
 	for _, perm := range perms {
-		if perm.Scheme.IsParent(scheme) {
-			if len(ctxTypes) > 0 {
-				for _, t := range ctxTypes {
-					if t == perm.Context.CtxType {
-						contexts = append(contexts, perm.Context)
-					}
+		if !perm.Scheme.IsParent(scheme) || !perm.matchListConditions(now) {
+			continue
+		}
+		if perm.Deny {
+			denied = append(denied, perm.Context)
+			continue
+		}
+		if len(ctxTypes) > 0 {
+			for _, t := range ctxTypes {
+				if t == perm.Context.CtxType {
+					contexts = append(contexts, perm.Context)
 				}
-			} else {
-				contexts = append(contexts, perm.Context)
+			}
+		} else {
+			contexts = append(contexts, perm.Context)
 
+		}
+	}
+	return removeDeniedContexts(contexts, denied)
+}
+
+func removeDeniedContexts(contexts, denied []permTypes.PermissionContext) []permTypes.PermissionContext {
+	if len(denied) == 0 {
+		return contexts
+	}
+	var result []permTypes.PermissionContext
+	for _, ctx := range contexts {
+		var isDenied bool
+		for _, d := range denied {
+			if d.CtxType == permTypes.CtxGlobal || d == ctx {
+				isDenied = true
+				break
 			}
 		}
+		if !isDenied {
+			result = append(result, ctx)
+		}
 	}
-	return contexts
+	return result
 }
 
 func ContextsForPermission(token Token, scheme *PermissionScheme, ctxTypes ...permTypes.ContextType) []permTypes.PermissionContext {
@@ -187,20 +315,21 @@ func Check(token Token, scheme *PermissionScheme, contexts ...permTypes.Permissi
 	return CheckFromPermList(perms, scheme, contexts...)
 }
 
+// CheckFromPermList returns whether the scheme is allowed by perms in any of
+// the given contexts. Denied permissions take precedence over allowed ones.
 func CheckFromPermList(perms []Permission, scheme *PermissionScheme, contexts ...permTypes.PermissionContext) bool {
+	now := timeNow()
+	var allowed bool
 	for _, perm := range perms {
-		if perm.Scheme.IsParent(scheme) {
-			if perm.Context.CtxType == permTypes.CtxGlobal {
-				return true
-			}
-			for _, ctx := range contexts {
-				if ctx.CtxType == perm.Context.CtxType && ctx.Value == perm.Context.Value {
-					return true
-				}
-			}
+		if !perm.match(scheme, contexts, now).Applies() {
+			continue
+		}
+		if perm.Deny {
+			return false
 		}
+		allowed = true
 	}
-	return false
+	return allowed
 }
 
 func TeamForPermission(t Token, scheme *PermissionScheme) (string, error) {
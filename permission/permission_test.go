@@ -5,6 +5,8 @@
 package permission
 
 import (
+	"time"
+
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	check "gopkg.in/check.v1"
 )
@@ -93,6 +95,186 @@ func (s *S) TestCheckSuperToken(c *check.C) {
 	c.Assert(Check(t, PermAppUpdateEnvUnset), check.Equals, true)
 }
 
+func (s *S) TestCheckDeny(c *check.C) {
+	t := &userToken{
+		permissions: []Permission{
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+			{Scheme: PermAppDeploy, Context: permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "prod"}, Deny: true},
+			{Scheme: PermAppUpdateEnv, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}, Deny: true},
+		},
+	}
+	team1 := permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}
+	c.Assert(Check(t, PermAppDeploy, team1), check.Equals, true)
+	c.Assert(Check(t, PermAppDeploy, team1, permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "dev"}), check.Equals, true)
+	c.Assert(Check(t, PermAppDeploy, team1, permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "prod"}), check.Equals, false)
+	c.Assert(Check(t, PermAppDeployImage, team1, permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "prod"}), check.Equals, false)
+	c.Assert(Check(t, PermAppUpdateEnvSet, team1), check.Equals, false)
+	c.Assert(Check(t, PermAppUpdate, team1), check.Equals, true)
+}
+
+func (s *S) TestCheckConditions(c *check.C) {
+	t := &userToken{
+		permissions: []Permission{
+			{
+				Scheme:     PermAppDeploy,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"},
+				Conditions: []RoleCondition{{Type: ConditionPool, Value: "dev-*"}},
+			},
+			{
+				Scheme:     PermAppUpdate,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"},
+				Conditions: []RoleCondition{{Type: ConditionTag, Value: "sandbox"}},
+			},
+			{
+				Scheme:     PermApp,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+				Deny:       true,
+				Conditions: []RoleCondition{{Type: ConditionTag, Value: "frozen"}},
+			},
+		},
+	}
+	team1 := permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}
+	devPool := permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "dev-1"}
+	prodPool := permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "prod-1"}
+	sandbox := permTypes.PermissionContext{CtxType: permTypes.CtxTag, Value: "sandbox"}
+	frozen := permTypes.PermissionContext{CtxType: permTypes.CtxTag, Value: "frozen"}
+	c.Assert(Check(t, PermAppDeploy, team1), check.Equals, false)
+	c.Assert(Check(t, PermAppDeploy, team1, devPool), check.Equals, true)
+	c.Assert(Check(t, PermAppDeploy, team1, prodPool), check.Equals, false)
+	c.Assert(Check(t, PermAppDeploy, team1, devPool, frozen), check.Equals, false)
+	c.Assert(Check(t, PermAppUpdateEnvSet, team1, prodPool), check.Equals, false)
+	c.Assert(Check(t, PermAppUpdateEnvSet, team1, prodPool, sandbox), check.Equals, true)
+}
+
+func (s *S) TestCheckConditionsWithAppContext(c *check.C) {
+	t := &userToken{
+		permissions: []Permission{
+			{
+				Scheme:     PermAppDeploy,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"},
+				Conditions: []RoleCondition{{Type: ConditionPool, Value: "dev-*"}},
+			},
+			{
+				Scheme:     PermAppUpdate,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"},
+				Conditions: []RoleCondition{{Type: ConditionTag, Value: "sandbox"}},
+			},
+			{
+				Scheme:     PermApp,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+				Deny:       true,
+				Conditions: []RoleCondition{{Type: ConditionTag, Value: "frozen"}},
+			},
+		},
+	}
+	team1 := permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}
+	devPool := permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "dev-1"}
+	prodPool := permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "prod-1"}
+	sandbox := permTypes.PermissionContext{CtxType: permTypes.CtxTag, Value: "sandbox"}
+	frozen := permTypes.PermissionContext{CtxType: permTypes.CtxTag, Value: "frozen"}
+	app1 := permTypes.PermissionContext{CtxType: permTypes.CtxApp, Value: "app1"}
+	c.Assert(Check(t, PermAppDeploy, team1, app1), check.Equals, false)
+	c.Assert(Check(t, PermAppDeploy, team1, app1, devPool), check.Equals, true)
+	c.Assert(Check(t, PermAppDeploy, team1, app1, prodPool), check.Equals, false)
+	c.Assert(Check(t, PermAppDeploy, team1, app1, devPool, frozen), check.Equals, false)
+	c.Assert(Check(t, PermAppUpdateEnvSet, team1, app1, prodPool), check.Equals, false)
+	c.Assert(Check(t, PermAppUpdateEnvSet, team1, app1, prodPool, sandbox), check.Equals, true)
+}
+
+func (s *S) TestCheckDenyConditionsFailClosed(c *check.C) {
+	t := &userToken{
+		permissions: []Permission{
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+			{
+				Scheme:     PermAppDeploy,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+				Deny:       true,
+				Conditions: []RoleCondition{{Type: ConditionTag, Value: "frozen"}},
+			},
+			{
+				Scheme:     PermAppUpdateEnvSet,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+				Deny:       true,
+				Conditions: []RoleCondition{{Type: ConditionPool, Value: "prod-*"}},
+			},
+		},
+	}
+	team1 := permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}
+	app1 := permTypes.PermissionContext{CtxType: permTypes.CtxApp, Value: "app1"}
+	devPool := permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "dev-1"}
+	c.Assert(Check(t, PermAppDeploy, team1), check.Equals, false)
+	c.Assert(Check(t, PermAppDeploy, team1, app1), check.Equals, true)
+	c.Assert(Check(t, PermAppUpdateEnvSet, team1, app1), check.Equals, false)
+	c.Assert(Check(t, PermAppUpdateEnvSet, team1, app1, devPool), check.Equals, true)
+	c.Assert(ContextsForPermission(t, PermAppDeploy), check.HasLen, 0)
+	c.Assert(ContextsForPermission(t, PermAppUpdateEnvSet), check.HasLen, 0)
+	c.Assert(ContextsForPermission(t, PermAppRead), check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxGlobal},
+	})
+}
+
+func (s *S) TestCheckTimeCondition(c *check.C) {
+	defer func() { timeNow = time.Now }()
+	t := &userToken{
+		permissions: []Permission{
+			{
+				Scheme:     PermAppDeploy,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+				Conditions: []RoleCondition{{Type: ConditionTime, Value: "09:00-18:00"}},
+			},
+		},
+	}
+	timeNow = func() time.Time { return time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC) }
+	c.Assert(Check(t, PermAppDeploy), check.Equals, true)
+	timeNow = func() time.Time { return time.Date(2020, 1, 1, 19, 0, 0, 0, time.UTC) }
+	c.Assert(Check(t, PermAppDeploy), check.Equals, false)
+}
+
+func (s *S) TestContextsForPermissionWithDeny(c *check.C) {
+	t := &userToken{
+		permissions: []Permission{
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}},
+			{Scheme: PermAppDeploy, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}, Deny: true},
+		},
+	}
+	contexts := ContextsForPermission(t, PermAppDeploy)
+	c.Assert(contexts, check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxTeam, Value: "team1"},
+	})
+	contexts = ContextsForPermission(t, PermAppRead)
+	c.Assert(contexts, check.HasLen, 2)
+	t.permissions = append(t.permissions, Permission{
+		Scheme:  PermAppDeploy,
+		Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+		Deny:    true,
+	})
+	contexts = ContextsForPermission(t, PermAppDeploy)
+	c.Assert(contexts, check.HasLen, 0)
+}
+
+func (s *S) TestContextsForPermissionWithConditions(c *check.C) {
+	t := &userToken{
+		permissions: []Permission{
+			{
+				Scheme:     PermApp,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxPool, Value: "dev-1"},
+				Conditions: []RoleCondition{{Type: ConditionPool, Value: "dev-*"}},
+			},
+			{
+				Scheme:     PermApp,
+				Context:    permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"},
+				Conditions: []RoleCondition{{Type: ConditionTag, Value: "sandbox"}},
+			},
+		},
+	}
+	contexts := ContextsForPermission(t, PermAppRead)
+	c.Assert(contexts, check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxPool, Value: "dev-1"},
+		{CtxType: permTypes.CtxTeam, Value: "team1"},
+	})
+}
+
 func (s *S) TestGetTeamForPermission(c *check.C) {
 	t := &userToken{
 		permissions: []Permission{
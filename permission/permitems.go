@@ -13,6 +13,9 @@ var (
 	PermAppAdmin                         = PermissionRegistry.get("app.admin")                           // [global app team pool]
 	PermAppAdminQuota                    = PermissionRegistry.get("app.admin.quota")                     // [global app team pool]
 	PermAppAdminRoutes                   = PermissionRegistry.get("app.admin.routes")                    // [global app team pool]
+	PermAppApproval                      = PermissionRegistry.get("app.approval")                        // [global app team pool]
+	PermAppApprovalRead                  = PermissionRegistry.get("app.approval.read")                   // [global app team pool]
+	PermAppApprovalReview                = PermissionRegistry.get("app.approval.review")                 // [global app team pool]
 	PermAppBuild                         = PermissionRegistry.get("app.build")                           // [global app team pool]
 	PermAppCreate                        = PermissionRegistry.get("app.create")                          // [global team]
 	PermAppDelete                        = PermissionRegistry.get("app.delete")                          // [global app team pool]
@@ -28,6 +31,7 @@ var (
 	PermAppReadDeploy                    = PermissionRegistry.get("app.read.deploy")                     // [global app team pool]
 	PermAppReadEnv                       = PermissionRegistry.get("app.read.env")                        // [global app team pool]
 	PermAppReadEvents                    = PermissionRegistry.get("app.read.events")                     // [global app team pool]
+	PermAppReadJob                       = PermissionRegistry.get("app.read.job")                        // [global app team pool]
 	PermAppReadLog                       = PermissionRegistry.get("app.read.log")                        // [global app team pool]
 	PermAppReadMetric                    = PermissionRegistry.get("app.read.metric")                     // [global app team pool]
 	PermAppReadRouter                    = PermissionRegistry.get("app.read.router")                     // [global app team pool]
@@ -42,7 +46,11 @@ var (
 	PermAppUpdateCname                   = PermissionRegistry.get("app.update.cname")                    // [global app team pool]
 	PermAppUpdateCnameAdd                = PermissionRegistry.get("app.update.cname.add")                // [global app team pool]
 	PermAppUpdateCnameRemove             = PermissionRegistry.get("app.update.cname.remove")             // [global app team pool]
+	PermAppUpdateDependency              = PermissionRegistry.get("app.update.dependency")               // [global app team pool]
+	PermAppUpdateDependencyAdd           = PermissionRegistry.get("app.update.dependency.add")           // [global app team pool]
+	PermAppUpdateDependencyRemove        = PermissionRegistry.get("app.update.dependency.remove")        // [global app team pool]
 	PermAppUpdateDeploy                  = PermissionRegistry.get("app.update.deploy")                   // [global app team pool]
+	PermAppUpdateDeployHooks             = PermissionRegistry.get("app.update.deploy-hooks")             // [global app team pool]
 	PermAppUpdateDeployRollback          = PermissionRegistry.get("app.update.deploy.rollback")          // [global app team pool]
 	PermAppUpdateDescription             = PermissionRegistry.get("app.update.description")              // [global app team pool]
 	PermAppUpdateEnv                     = PermissionRegistry.get("app.update.env")                      // [global app team pool]
@@ -51,11 +59,15 @@ var (
 	PermAppUpdateEvents                  = PermissionRegistry.get("app.update.events")                   // [global app team pool]
 	PermAppUpdateGrant                   = PermissionRegistry.get("app.update.grant")                    // [global app team pool]
 	PermAppUpdateImageReset              = PermissionRegistry.get("app.update.image-reset")              // [global app team pool]
+	PermAppUpdateJob                     = PermissionRegistry.get("app.update.job")                      // [global app team pool]
+	PermAppUpdateJobRemove               = PermissionRegistry.get("app.update.job.remove")               // [global app team pool]
+	PermAppUpdateJobSet                  = PermissionRegistry.get("app.update.job.set")                  // [global app team pool]
 	PermAppUpdateLog                     = PermissionRegistry.get("app.update.log")                      // [global app team pool]
 	PermAppUpdatePlan                    = PermissionRegistry.get("app.update.plan")                     // [global app team pool]
 	PermAppUpdatePlanoverride            = PermissionRegistry.get("app.update.planoverride")             // [global app team pool]
 	PermAppUpdatePlatform                = PermissionRegistry.get("app.update.platform")                 // [global app team pool]
 	PermAppUpdatePool                    = PermissionRegistry.get("app.update.pool")                     // [global app team pool]
+	PermAppUpdateProtection              = PermissionRegistry.get("app.update.protection")               // [global app team pool]
 	PermAppUpdateRestart                 = PermissionRegistry.get("app.update.restart")                  // [global app team pool]
 	PermAppUpdateRevoke                  = PermissionRegistry.get("app.update.revoke")                   // [global app team pool]
 	PermAppUpdateRoutable                = PermissionRegistry.get("app.update.routable")                 // [global app team pool]
@@ -86,7 +98,14 @@ var (
 	PermClusterRead                      = PermissionRegistry.get("cluster.read")                        // [global]
 	PermClusterReadEvents                = PermissionRegistry.get("cluster.read.events")                 // [global]
 	PermClusterUpdate                    = PermissionRegistry.get("cluster.update")                      // [global]
+	PermClusterUpdateFailover            = PermissionRegistry.get("cluster.update.failover")             // [global]
 	PermDebug                            = PermissionRegistry.get("debug")                               // [global]
+	PermDeployFreeze                     = PermissionRegistry.get("deploy-freeze")                       // [global pool]
+	PermDeployFreezeCreate               = PermissionRegistry.get("deploy-freeze.create")                // [global pool]
+	PermDeployFreezeDelete               = PermissionRegistry.get("deploy-freeze.delete")                // [global pool]
+	PermDeployFreezeOverride             = PermissionRegistry.get("deploy-freeze.override")              // [global pool]
+	PermDeployFreezeRead                 = PermissionRegistry.get("deploy-freeze.read")                  // [global pool]
+	PermDeployFreezeReadEvents           = PermissionRegistry.get("deploy-freeze.read.events")           // [global pool]
 	PermEventBlock                       = PermissionRegistry.get("event-block")                         // [global]
 	PermEventBlockAdd                    = PermissionRegistry.get("event-block.add")                     // [global]
 	PermEventBlockRead                   = PermissionRegistry.get("event-block.read")                    // [global]
@@ -107,6 +126,8 @@ var (
 	PermMachineTemplateDelete            = PermissionRegistry.get("machine.template.delete")             // [global iaas]
 	PermMachineTemplateRead              = PermissionRegistry.get("machine.template.read")               // [global iaas]
 	PermMachineTemplateUpdate            = PermissionRegistry.get("machine.template.update")             // [global iaas]
+	PermMetering                         = PermissionRegistry.get("metering")                            // [global team]
+	PermMeteringRead                     = PermissionRegistry.get("metering.read")                       // [global team]
 	PermNode                             = PermissionRegistry.get("node")                                // [global pool]
 	PermNodeAutoscale                    = PermissionRegistry.get("node.autoscale")                      // [global]
 	PermNodeAutoscaleDelete              = PermissionRegistry.get("node.autoscale.delete")               // [global]
@@ -160,8 +181,13 @@ var (
 	PermRoleDelete                       = PermissionRegistry.get("role.delete")                         // [global]
 	PermRoleRead                         = PermissionRegistry.get("role.read")                           // [global]
 	PermRoleReadEvents                   = PermissionRegistry.get("role.read.events")                    // [global]
+	PermRoleReadExplain                  = PermissionRegistry.get("role.read.explain")                   // [global]
+	PermRoleReadReview                   = PermissionRegistry.get("role.read.review")                    // [global]
 	PermRoleUpdate                       = PermissionRegistry.get("role.update")                         // [global]
 	PermRoleUpdateAssign                 = PermissionRegistry.get("role.update.assign")                  // [global]
+	PermRoleUpdateCondition              = PermissionRegistry.get("role.update.condition")               // [global]
+	PermRoleUpdateConditionAdd           = PermissionRegistry.get("role.update.condition.add")           // [global]
+	PermRoleUpdateConditionRemove        = PermissionRegistry.get("role.update.condition.remove")        // [global]
 	PermRoleUpdateContext                = PermissionRegistry.get("role.update.context")                 // [global]
 	PermRoleUpdateContextType            = PermissionRegistry.get("role.update.context.type")            // [global]
 	PermRoleUpdateDescription            = PermissionRegistry.get("role.update.description")             // [global]
@@ -216,12 +242,19 @@ var (
 	PermTeamDelete                       = PermissionRegistry.get("team.delete")                         // [global team]
 	PermTeamRead                         = PermissionRegistry.get("team.read")                           // [global team]
 	PermTeamReadEvents                   = PermissionRegistry.get("team.read.events")                    // [global team]
+	PermTeamReadQuota                    = PermissionRegistry.get("team.read.quota")                     // [global team]
+	PermTeamRegistryCredential           = PermissionRegistry.get("team.registry-credential")            // [global team]
+	PermTeamRegistryCredentialCreate     = PermissionRegistry.get("team.registry-credential.create")     // [global team]
+	PermTeamRegistryCredentialDelete     = PermissionRegistry.get("team.registry-credential.delete")     // [global team]
+	PermTeamRegistryCredentialRead       = PermissionRegistry.get("team.registry-credential.read")       // [global team]
+	PermTeamRegistryCredentialUpdate     = PermissionRegistry.get("team.registry-credential.update")     // [global team]
 	PermTeamToken                        = PermissionRegistry.get("team.token")                          // [global team]
 	PermTeamTokenCreate                  = PermissionRegistry.get("team.token.create")                   // [global team]
 	PermTeamTokenDelete                  = PermissionRegistry.get("team.token.delete")                   // [global team]
 	PermTeamTokenRead                    = PermissionRegistry.get("team.token.read")                     // [global team]
 	PermTeamTokenUpdate                  = PermissionRegistry.get("team.token.update")                   // [global team]
 	PermTeamUpdate                       = PermissionRegistry.get("team.update")                         // [global team]
+	PermTeamUpdateQuota                  = PermissionRegistry.get("team.update.quota")                   // [global team]
 	PermUser                             = PermissionRegistry.get("user")                                // [global user]
 	PermUserCreate                       = PermissionRegistry.get("user.create")                         // [global]
 	PermUserDelete                       = PermissionRegistry.get("user.delete")                         // [global user]
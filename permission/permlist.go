@@ -25,6 +25,8 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.update.unit.status",
 	"app.update.unit.autoscale.add",
 	"app.update.unit.autoscale.remove",
+	"app.update.job.set",
+	"app.update.job.remove",
 	"app.update.env.set",
 	"app.update.env.unset",
 	"app.update.restart",
@@ -53,6 +55,10 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.update.router.update",
 	"app.update.router.remove",
 	"app.update.routable",
+	"app.update.dependency.add",
+	"app.update.dependency.remove",
+	"app.update.protection",
+	"app.update.deploy-hooks",
 	"app.deploy",
 	"app.deploy.archive-url",
 	"app.deploy.build",
@@ -68,12 +74,16 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.read.metric",
 	"app.read.log",
 	"app.read.certificate",
+	"app.read.job",
 	"app.delete",
 	"app.run",
 	"app.run.shell",
 	"app.admin.routes",
 	"app.admin.quota",
 	"app.build",
+	"app.approval",
+	"app.approval.read",
+	"app.approval.review",
 ).addWithCtx(
 	"node", []permTypes.ContextType{permTypes.CtxPool},
 ).add(
@@ -106,12 +116,18 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"team.create", []permTypes.ContextType{},
 ).add(
 	"team.read.events",
+	"team.read.quota",
 	"team.delete",
 	"team.update",
+	"team.update.quota",
 	"team.token.read",
 	"team.token.create",
 	"team.token.delete",
 	"team.token.update",
+	"team.registry-credential.read",
+	"team.registry-credential.create",
+	"team.registry-credential.update",
+	"team.registry-credential.delete",
 ).addWithCtx(
 	"user", []permTypes.ContextType{permTypes.CtxUser},
 ).addWithCtx(
@@ -166,6 +182,8 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"role.create",
 	"role.delete",
 	"role.read.events",
+	"role.read.explain",
+	"role.read.review",
 	"role.update.name",
 	"role.update.assign",
 	"role.update.dissociate",
@@ -173,6 +191,8 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"role.update.context.type",
 	"role.update.permission.add",
 	"role.update.permission.remove",
+	"role.update.condition.add",
+	"role.update.condition.remove",
 	"role.default.create",
 	"role.default.delete",
 ).add(
@@ -221,11 +241,20 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"event-block.read.events",
 	"event-block.add",
 	"event-block.remove",
+).addWithCtx(
+	"deploy-freeze", []permTypes.ContextType{permTypes.CtxPool},
+).add(
+	"deploy-freeze.read",
+	"deploy-freeze.read.events",
+	"deploy-freeze.create",
+	"deploy-freeze.delete",
+	"deploy-freeze.override",
 ).add(
 	"cluster.admin",
 	"cluster.read.events",
 	"cluster.create",
 	"cluster.update",
+	"cluster.update.failover",
 	"cluster.delete",
 ).addWithCtx(
 	"volume", []permTypes.ContextType{permTypes.CtxVolume, permTypes.CtxTeam, permTypes.CtxPool},
@@ -252,4 +281,8 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"router.read.events",
 	"router.update",
 	"router.delete",
+).addWithCtx(
+	"metering", []permTypes.ContextType{permTypes.CtxTeam},
+).add(
+	"metering.read",
 )
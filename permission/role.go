@@ -17,11 +17,13 @@ import (
 )
 
 type Role struct {
-	Name        string                `bson:"_id" json:"name"`
-	ContextType permTypes.ContextType `json:"context"`
-	Description string
-	SchemeNames []string `json:"scheme_names,omitempty"`
-	Events      []string `json:"events,omitempty"`
+	Name            string                `bson:"_id" json:"name"`
+	ContextType     permTypes.ContextType `json:"context"`
+	Description     string
+	SchemeNames     []string        `json:"scheme_names,omitempty"`
+	DenySchemeNames []string        `json:"deny_scheme_names,omitempty"`
+	Conditions      []RoleCondition `json:"conditions,omitempty"`
+	Events          []string        `json:"events,omitempty"`
 }
 
 func NewRole(name string, ctx string, description string) (Role, error) {
@@ -132,6 +134,16 @@ func DestroyRole(name string) error {
 }
 
 func (r *Role) AddPermissions(permNames ...string) error {
+	return r.addSchemeNames("schemenames", permNames)
+}
+
+// AddDenyPermissions adds explicit deny entries to the role, denied
+// permissions take precedence over any allowed permission.
+func (r *Role) AddDenyPermissions(permNames ...string) error {
+	return r.addSchemeNames("denyschemenames", permNames)
+}
+
+func (r *Role) addSchemeNames(field string, permNames []string) error {
 	for _, permName := range permNames {
 		if permName == "" {
 			return permTypes.ErrInvalidPermissionName
@@ -162,41 +174,93 @@ func (r *Role) AddPermissions(permNames ...string) error {
 		return err
 	}
 	defer coll.Close()
-	err = coll.UpdateId(r.Name, bson.M{"$addToSet": bson.M{"schemenames": bson.M{"$each": permNames}}})
+	err = coll.UpdateId(r.Name, bson.M{"$addToSet": bson.M{field: bson.M{"$each": permNames}}})
 	if err != nil {
 		return err
 	}
-	dbRole, err := FindRole(r.Name)
+	return r.reload()
+}
+
+func (r *Role) RemovePermissions(permNames ...string) error {
+	return r.removeSchemeNames("schemenames", permNames)
+}
+
+func (r *Role) RemoveDenyPermissions(permNames ...string) error {
+	return r.removeSchemeNames("denyschemenames", permNames)
+}
+
+func (r *Role) removeSchemeNames(field string, permNames []string) error {
+	coll, err := rolesCollection()
 	if err != nil {
 		return err
 	}
-	r.SchemeNames = dbRole.SchemeNames
-	return nil
+	defer coll.Close()
+	err = coll.UpdateId(r.Name, bson.M{"$pullAll": bson.M{field: permNames}})
+	if err != nil {
+		return err
+	}
+	return r.reload()
 }
 
-func (r *Role) RemovePermissions(permNames ...string) error {
+// AddCondition adds a condition to the role, replacing any existing
+// condition of the same type.
+func (r *Role) AddCondition(cond RoleCondition) error {
+	err := cond.Validate()
+	if err != nil {
+		return err
+	}
+	coll, err := rolesCollection()
+	if err != nil {
+		return err
+	}
+	defer coll.Close()
+	err = coll.UpdateId(r.Name, bson.M{"$pull": bson.M{"conditions": bson.M{"type": cond.Type}}})
+	if err != nil {
+		return err
+	}
+	err = coll.UpdateId(r.Name, bson.M{"$push": bson.M{"conditions": cond}})
+	if err != nil {
+		return err
+	}
+	return r.reload()
+}
+
+func (r *Role) RemoveCondition(condType ConditionType) error {
 	coll, err := rolesCollection()
 	if err != nil {
 		return err
 	}
 	defer coll.Close()
-	err = coll.UpdateId(r.Name, bson.M{"$pullAll": bson.M{"schemenames": permNames}})
+	err = coll.UpdateId(r.Name, bson.M{"$pull": bson.M{"conditions": bson.M{"type": condType}}})
 	if err != nil {
 		return err
 	}
+	return r.reload()
+}
+
+func (r *Role) reload() error {
 	dbRole, err := FindRole(r.Name)
 	if err != nil {
 		return err
 	}
 	r.SchemeNames = dbRole.SchemeNames
+	r.DenySchemeNames = dbRole.DenySchemeNames
+	r.Conditions = dbRole.Conditions
 	return nil
 }
 
 func (r *Role) filterValidSchemes() PermissionSchemeList {
-	schemes := make(PermissionSchemeList, 0, len(r.SchemeNames))
-	sort.Strings(r.SchemeNames)
-	for i := 0; i < len(r.SchemeNames); i++ {
-		schemeName := r.SchemeNames[i]
+	r.DenySchemeNames, _ = filterValidSchemeNames(r.DenySchemeNames)
+	var schemes PermissionSchemeList
+	r.SchemeNames, schemes = filterValidSchemeNames(r.SchemeNames)
+	return schemes
+}
+
+func filterValidSchemeNames(names []string) ([]string, PermissionSchemeList) {
+	schemes := make(PermissionSchemeList, 0, len(names))
+	sort.Strings(names)
+	for i := 0; i < len(names); i++ {
+		schemeName := names[i]
 		if schemeName == "*" {
 			schemeName = ""
 		}
@@ -204,26 +268,37 @@ func (r *Role) filterValidSchemes() PermissionSchemeList {
 		if scheme == nil {
 			// permission schemes might be removed or renamed, invalid entries
 			// in the database shouldn't be a problem.
-			r.SchemeNames = append(r.SchemeNames[:i], r.SchemeNames[i+1:]...)
+			names = append(names[:i], names[i+1:]...)
 			i--
 			continue
 		}
 		schemes = append(schemes, &scheme.PermissionScheme)
 	}
-	return schemes
+	return names, schemes
 }
 
 func (r *Role) PermissionsFor(contextValue string) []Permission {
 	schemes := r.filterValidSchemes()
-	permissions := make([]Permission, len(schemes))
-	for i, scheme := range schemes {
-		permissions[i] = Permission{
-			Scheme: scheme,
-			Context: permTypes.PermissionContext{
-				CtxType: r.ContextType,
-				Value:   contextValue,
-			},
-		}
+	_, denySchemes := filterValidSchemeNames(r.DenySchemeNames)
+	permissions := make([]Permission, 0, len(schemes)+len(denySchemes))
+	ctx := permTypes.PermissionContext{
+		CtxType: r.ContextType,
+		Value:   contextValue,
+	}
+	for _, scheme := range schemes {
+		permissions = append(permissions, Permission{
+			Scheme:     scheme,
+			Context:    ctx,
+			Conditions: r.Conditions,
+		})
+	}
+	for _, scheme := range denySchemes {
+		permissions = append(permissions, Permission{
+			Scheme:     scheme,
+			Context:    ctx,
+			Deny:       true,
+			Conditions: r.Conditions,
+		})
 	}
 	return permissions
 }
@@ -298,7 +373,15 @@ func (r *Role) Add() error {
 		return err
 	}
 	defer coll.Close()
-	insertRole := Role{Name: name, ContextType: r.ContextType, Description: r.Description, SchemeNames: r.SchemeNames, Events: r.Events}
+	insertRole := Role{
+		Name:            name,
+		ContextType:     r.ContextType,
+		Description:     r.Description,
+		SchemeNames:     r.SchemeNames,
+		DenySchemeNames: r.DenySchemeNames,
+		Conditions:      r.Conditions,
+		Events:          r.Events,
+	}
 	err = coll.Insert(insertRole)
 	if mgo.IsDup(err) {
 		return permTypes.ErrRoleAlreadyExists
@@ -143,6 +143,67 @@ func (s *S) TestPermissionsFor(c *check.C) {
 	c.Assert(perms, check.DeepEquals, expected)
 }
 
+func (s *S) TestRoleAddDenyPermissions(c *check.C) {
+	r, err := NewRole("myrole", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddDenyPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.DenySchemeNames, check.DeepEquals, []string{"app.deploy"})
+	c.Assert(r.SchemeNames, check.IsNil)
+	err = r.AddDenyPermissions("node.create")
+	c.Assert(err, check.ErrorMatches, `permission "node.create" not allowed with context of type "team"`)
+	dbR, err := FindRole("myrole")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbR.DenySchemeNames, check.DeepEquals, []string{"app.deploy"})
+	err = r.RemoveDenyPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	c.Assert(r.DenySchemeNames, check.HasLen, 0)
+}
+
+func (s *S) TestRoleAddCondition(c *check.C) {
+	r, err := NewRole("myrole", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddCondition(RoleCondition{Type: ConditionPool, Value: "prod-*"})
+	c.Assert(err, check.IsNil)
+	err = r.AddCondition(RoleCondition{Type: ConditionTag, Value: "critical"})
+	c.Assert(err, check.IsNil)
+	err = r.AddCondition(RoleCondition{Type: ConditionPool, Value: "staging-*"})
+	c.Assert(err, check.IsNil)
+	expected := []RoleCondition{
+		{Type: ConditionTag, Value: "critical"},
+		{Type: ConditionPool, Value: "staging-*"},
+	}
+	c.Assert(r.Conditions, check.DeepEquals, expected)
+	dbR, err := FindRole("myrole")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbR.Conditions, check.DeepEquals, expected)
+	err = r.AddCondition(RoleCondition{Type: "weekday", Value: "monday"})
+	c.Assert(err, check.ErrorMatches, `unknown condition type "weekday": invalid role condition`)
+	err = r.AddCondition(RoleCondition{Type: ConditionTime, Value: "9h-18h"})
+	c.Assert(err, check.ErrorMatches, `invalid time window "9h-18h".*`)
+	err = r.RemoveCondition(ConditionTag)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.Conditions, check.DeepEquals, []RoleCondition{{Type: ConditionPool, Value: "staging-*"}})
+}
+
+func (s *S) TestPermissionsForWithDenyAndConditions(c *check.C) {
+	r, err := NewRole("myrole", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddPermissions("app.update")
+	c.Assert(err, check.IsNil)
+	err = r.AddDenyPermissions("app.update.env.set")
+	c.Assert(err, check.IsNil)
+	err = r.AddCondition(RoleCondition{Type: ConditionPool, Value: "prod-*"})
+	c.Assert(err, check.IsNil)
+	conds := []RoleCondition{{Type: ConditionPool, Value: "prod-*"}}
+	ctx := permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "something"}
+	perms := r.PermissionsFor("something")
+	c.Assert(perms, check.DeepEquals, []Permission{
+		{Scheme: PermissionRegistry.get("app.update"), Context: ctx, Conditions: conds},
+		{Scheme: PermissionRegistry.get("app.update.env.set"), Context: ctx, Deny: true, Conditions: conds},
+	})
+}
+
 func (s *S) TestRoleAddEvent(c *check.C) {
 	r, err := NewRole("myrole", "team", "")
 	c.Assert(err, check.IsNil)
@@ -88,34 +88,72 @@ func (s *clusterService) FindByProvisioner(ctx context.Context, prov string) ([]
 	return s.storage.FindByProvisioner(ctx, prov)
 }
 
+// FindByPools returns the cluster of each pool, the primary member when the
+// pool is shared by clusters with a placement.
 func (s *clusterService) FindByPools(ctx context.Context, prov string, pools []string) (map[string]provTypes.Cluster, error) {
 	provClusters, err := s.FindByProvisioner(ctx, prov)
 	if err != nil {
 		return nil, err
 	}
 	result := make(map[string]provTypes.Cluster)
-poolLoop:
 	for _, pool := range pools {
-		for _, cluster := range provClusters {
-			if cluster.Default {
-				result[pool] = cluster
-			}
-			for _, clusterPool := range cluster.Pools {
-				if clusterPool == pool {
-					result[pool] = cluster
-					continue poolLoop
-				}
-			}
-		}
-		if _, ok := result[pool]; !ok {
+		clusters := poolClusters(provClusters, pool)
+		if len(clusters) == 0 {
 			return nil, errors.Errorf("unable to find cluster for pool %q", pool)
 		}
+		result[pool] = clusters[0]
 	}
 	return result, nil
 }
 
+// FindByPool returns the cluster of the pool. When the pool is shared by
+// clusters with a placement, the primary member of the pool is returned.
 func (s *clusterService) FindByPool(ctx context.Context, prov, pool string) (*provTypes.Cluster, error) {
-	return s.storage.FindByPool(ctx, prov, pool)
+	c, err := s.storage.FindByPool(ctx, prov, pool)
+	if err != nil || c.Placement == nil {
+		return c, err
+	}
+	clusters, err := s.FindAllByPool(ctx, prov, pool)
+	if err != nil {
+		return nil, err
+	}
+	return &clusters[0], nil
+}
+
+// FindAllByPool returns every cluster backing the pool, ordered by their
+// placement, falling back to the default cluster.
+func (s *clusterService) FindAllByPool(ctx context.Context, prov, pool string) ([]provTypes.Cluster, error) {
+	provClusters, err := s.FindByProvisioner(ctx, prov)
+	if err != nil {
+		return nil, err
+	}
+	result := poolClusters(provClusters, pool)
+	if len(result) == 0 {
+		return nil, provTypes.ErrNoCluster
+	}
+	return result, nil
+}
+
+// poolClusters returns the clusters backing the pool, ordered by their
+// placement, falling back to the default clusters.
+func poolClusters(provClusters []provTypes.Cluster, pool string) []provTypes.Cluster {
+	var result, defaults []provTypes.Cluster
+	for _, cluster := range provClusters {
+		if cluster.Default {
+			defaults = append(defaults, cluster)
+		}
+		for _, clusterPool := range cluster.Pools {
+			if clusterPool == pool {
+				result = append(result, cluster)
+				break
+			}
+		}
+	}
+	if len(result) == 0 {
+		result = defaults
+	}
+	provTypes.SortByPlacement(result)
+	return result
 }
 
 func (s *clusterService) Delete(ctx context.Context, c provTypes.Cluster) error {
@@ -162,6 +200,11 @@ func (s *clusterService) validate(c provTypes.Cluster, isNewCluster bool) error
 			return errors.WithStack(&tsuruErrors.ValidationError{Message: "either default or a list of pools must be set"})
 		}
 	}
+	if c.Placement != nil {
+		if err := c.Placement.Validate(); err != nil {
+			return errors.WithStack(&tsuruErrors.ValidationError{Message: err.Error()})
+		}
+	}
 	prov, err := provision.Get(c.Provisioner)
 	if err != nil {
 		return errors.WithStack(&tsuruErrors.ValidationError{Message: fmt.Sprintf("provisioner error: %v", err)})
@@ -385,6 +385,27 @@ func (s *S) TestFindByPools(c *check.C) {
 	})
 }
 
+func (s *S) TestFindByPoolsPlacement(c *check.C) {
+	clusters := []provTypes.Cluster{
+		{Name: "cluster1", Provisioner: "kubernetes", Pools: []string{"poolA"}, Placement: &provTypes.ClusterPlacement{Drained: true}},
+		{Name: "cluster2", Provisioner: "kubernetes", Pools: []string{"poolA", "poolB"}, Placement: &provTypes.ClusterPlacement{Role: provTypes.ClusterRolePassive}},
+		{Name: "cluster3", Provisioner: "kubernetes", Pools: []string{"poolA"}},
+	}
+	cs := &clusterService{
+		storage: &provTypes.MockClusterStorage{
+			OnFindByProvisioner: func(prov string) ([]provTypes.Cluster, error) {
+				return clusters, nil
+			},
+		},
+	}
+	result, err := cs.FindByPools(context.TODO(), "kubernetes", []string{"poolA", "poolB"})
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, map[string]provTypes.Cluster{
+		"poolA": clusters[2],
+		"poolB": clusters[1],
+	})
+}
+
 func (s *S) TestFindByPoolsNotFound(c *check.C) {
 	prov := "prov1"
 	clusters := []provTypes.Cluster{
@@ -403,6 +424,46 @@ func (s *S) TestFindByPoolsNotFound(c *check.C) {
 	c.Assert(err, check.ErrorMatches, `unable to find cluster for pool "poolD"`)
 }
 
+func (s *S) TestFindAllByPool(c *check.C) {
+	clusters := []provTypes.Cluster{
+		{Name: "cluster1", Provisioner: "kubernetes", Pools: []string{"poolA"}, Placement: &provTypes.ClusterPlacement{Role: provTypes.ClusterRolePassive}},
+		{Name: "cluster2", Provisioner: "kubernetes", Pools: []string{"poolA"}, Placement: &provTypes.ClusterPlacement{Drained: true}},
+		{Name: "cluster3", Provisioner: "kubernetes", Pools: []string{"poolA", "poolB"}, Placement: &provTypes.ClusterPlacement{Weight: 2}},
+		{Name: "cluster4", Provisioner: "kubernetes", Default: true},
+	}
+	cs := &clusterService{
+		storage: &provTypes.MockClusterStorage{
+			OnFindByProvisioner: func(prov string) ([]provTypes.Cluster, error) {
+				c.Assert(prov, check.Equals, "kubernetes")
+				return clusters, nil
+			},
+			OnFindByPool: func(prov, pool string) (*provTypes.Cluster, error) {
+				return &clusters[0], nil
+			},
+		},
+	}
+	result, err := cs.FindAllByPool(context.TODO(), "kubernetes", "poolA")
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []provTypes.Cluster{clusters[2], clusters[0], clusters[1]})
+	result, err = cs.FindAllByPool(context.TODO(), "kubernetes", "poolC")
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []provTypes.Cluster{clusters[3]})
+	primary, err := cs.FindByPool(context.TODO(), "kubernetes", "poolA")
+	c.Assert(err, check.IsNil)
+	c.Assert(*primary, check.DeepEquals, clusters[2])
+}
+
+func (s *S) TestClusterServiceCreateInvalidPlacement(c *check.C) {
+	cs := &clusterService{storage: &provTypes.MockClusterStorage{}}
+	err := cs.Create(context.TODO(), provTypes.Cluster{
+		Name:        "c1",
+		Provisioner: "fake",
+		Pools:       []string{"poolA"},
+		Placement:   &provTypes.ClusterPlacement{Role: "standby"},
+	})
+	c.Assert(err, check.ErrorMatches, "placement role must be either active or passive")
+}
+
 var _ ClusteredProvisioner = &provisionClusterProv{}
 
 type provisionClusterProv struct {
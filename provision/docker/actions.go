@@ -58,6 +58,7 @@ type changeUnitsPipelineArgs struct {
 	provisioner *dockerProvisioner
 	appDestroy  bool
 	event       *event.Event
+	verify      func(context.Context, appTypes.AppVersion) error
 }
 
 type callbackFunc func(*container.Container, chan *container.Container) error
@@ -555,7 +556,11 @@ var setRouterHealthcheck = action.Action{
 		if writer == nil {
 			writer = ioutil.Discard
 		}
-		newHCData := yamlData.ToRouterHC()
+		webProcess, err := args.version.WebProcess()
+		if err != nil {
+			return nil, err
+		}
+		newHCData := yamlData.ToRouterHC(webProcess)
 		msg := fmt.Sprintf("Path: %s", newHCData.Path)
 		if newHCData.Status != 0 {
 			msg = fmt.Sprintf("%s, Status: %d", msg, newHCData.Status)
@@ -587,13 +592,19 @@ var setRouterHealthcheck = action.Action{
 			if err != nil {
 				return err
 			}
-			return hcRouter.SetHealthcheck(ctx.Context, args.app, yamlData.ToRouterHC())
+			var oldWebProcess string
+			oldWebProcess, err = oldVersion.WebProcess()
+			if err != nil {
+				return err
+			}
+			return hcRouter.SetHealthcheck(ctx.Context, args.app, yamlData.ToRouterHC(oldWebProcess))
 		})
 		return newContainers, err
 	},
 	Backward: func(ctx action.BWContext) {
 		args := ctx.Params[0].(changeUnitsPipelineArgs)
 		var yamlData provTypes.TsuruYamlData
+		var webProcess string
 		oldVersion, err := servicemanager.AppVersion.LatestSuccessfulVersion(ctx.Context, args.app)
 		if err != nil && err != appTypes.ErrNoVersionsAvailable {
 			log.Errorf("[set-router-healthcheck:Backward] Error getting old version: %s", err)
@@ -605,8 +616,13 @@ var setRouterHealthcheck = action.Action{
 				log.Errorf("[set-router-healthcheck:Backward] Error getting yaml data: %s", err)
 				return
 			}
+			webProcess, err = oldVersion.WebProcess()
+			if err != nil {
+				log.Errorf("[set-router-healthcheck:Backward] Error getting web process: %s", err)
+				return
+			}
 		}
-		hcData := yamlData.ToRouterHC()
+		hcData := yamlData.ToRouterHC(webProcess)
 		err = runInRouters(ctx.Context, args.app, func(r router.Router) error {
 			hcRouter, ok := r.(router.CustomHealthcheckRouter)
 			if !ok {
@@ -848,6 +864,22 @@ var followLogsAndCommit = action.Action{
 	MinParams: 1,
 }
 
+var verifyNewUnits = action.Action{
+	Name: "verify-new-units",
+	Forward: func(ctx action.FWContext) (action.Result, error) {
+		args := ctx.Params[0].(changeUnitsPipelineArgs)
+		if args.verify == nil {
+			return ctx.Previous, nil
+		}
+		err := args.verify(ctx.Context, args.version)
+		if err != nil {
+			return nil, err
+		}
+		return ctx.Previous, nil
+	},
+	OnError: rollbackNotice,
+}
+
 var updateAppImage = action.Action{
 	Name: "update-app-image",
 	Forward: func(ctx action.FWContext) (action.Result, error) {
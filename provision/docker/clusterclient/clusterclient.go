@@ -61,7 +61,7 @@ func (c *ClusterClient) PullAndCreateContainer(opts docker.CreateContainerOption
 		hostAddr, cont, err = c.Cluster.CreateContainerPullOptsSchedulerOpts(
 			opts,
 			pullOpts,
-			dockercommon.RegistryAuthConfig(opts.Config.Image),
+			dockercommon.PullAuthConfig(opts),
 			schedulerOpts,
 		)
 		hostAddr = net.URLToHost(hostAddr)
@@ -117,7 +117,7 @@ func (c *ClusterClient) PullAndCreateContainer(opts docker.CreateContainerOption
 	addr, cont, err = c.Cluster.CreateContainerPullOptsSchedulerOpts(
 		opts,
 		pullOpts,
-		dockercommon.RegistryAuthConfig(opts.Config.Image),
+		dockercommon.PullAuthConfig(opts),
 		schedulerOpts,
 		nodes...,
 	)
@@ -456,8 +456,8 @@ func (c *Container) hostConfig(app provision.App, isDeploy bool) (*docker.HostCo
 	}
 
 	if !isDeploy {
-		hostConfig.Memory = app.GetMemory()
-		hostConfig.MemorySwap = app.GetMemory() + app.GetSwap()
+		hostConfig.Memory = app.GetProcessMemory(c.ProcessName)
+		hostConfig.MemorySwap = hostConfig.Memory + app.GetSwap()
 		hostConfig.RestartPolicy = docker.AlwaysRestart()
 		hostConfig.PortBindings = map[docker.Port][]docker.PortBinding{
 			docker.Port(c.ExposedPort): {{HostIP: "", HostPort: ""}},
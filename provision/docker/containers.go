@@ -100,7 +100,7 @@ func (p *dockerProvisioner) HandleMoveErrors(moveErrors chan error, writer io.Wr
 	return nil
 }
 
-func (p *dockerProvisioner) runReplaceUnitsPipeline(ctx context.Context, w io.Writer, a provision.App, toAdd map[string]*containersToAdd, toRemoveContainers []container.Container, version appTypes.AppVersion, toHosts ...string) ([]container.Container, error) {
+func (p *dockerProvisioner) runReplaceUnitsPipeline(ctx context.Context, w io.Writer, a provision.App, toAdd map[string]*containersToAdd, toRemoveContainers []container.Container, version appTypes.AppVersion, verify func(context.Context, appTypes.AppVersion) error, toHosts ...string) ([]container.Container, error) {
 	var toHost string
 	if len(toHosts) > 0 {
 		toHost = toHosts[0]
@@ -118,6 +118,7 @@ func (p *dockerProvisioner) runReplaceUnitsPipeline(ctx context.Context, w io.Wr
 		version:     version,
 		provisioner: p,
 		event:       evt,
+		verify:      verify,
 	}
 	var pipeline *action.Pipeline
 	if p.isDryMode {
@@ -132,6 +133,7 @@ func (p *dockerProvisioner) runReplaceUnitsPipeline(ctx context.Context, w io.Wr
 			&addNewRoutes,
 			&setRouterHealthcheck,
 			&removeOldRoutes,
+			&verifyNewUnits,
 			&updateAppImage,
 			&provisionRemoveOldUnits,
 			&provisionUnbindOldUnits,
@@ -144,7 +146,7 @@ func (p *dockerProvisioner) runReplaceUnitsPipeline(ctx context.Context, w io.Wr
 	return pipeline.Result().([]container.Container), nil
 }
 
-func (p *dockerProvisioner) runCreateUnitsPipeline(ctx context.Context, w io.Writer, a provision.App, toAdd map[string]*containersToAdd, version appTypes.AppVersion) ([]container.Container, error) {
+func (p *dockerProvisioner) runCreateUnitsPipeline(ctx context.Context, w io.Writer, a provision.App, toAdd map[string]*containersToAdd, version appTypes.AppVersion, verify func(context.Context, appTypes.AppVersion) error) ([]container.Container, error) {
 	if w == nil {
 		w = ioutil.Discard
 	}
@@ -156,12 +158,14 @@ func (p *dockerProvisioner) runCreateUnitsPipeline(ctx context.Context, w io.Wri
 		version:     version,
 		provisioner: p,
 		event:       evt,
+		verify:      verify,
 	}
 	pipeline := action.NewPipeline(
 		&provisionAddUnitsToHost,
 		&bindAndHealthcheck,
 		&addNewRoutes,
 		&setRouterHealthcheck,
+		&verifyNewUnits,
 		&updateAppImage,
 	)
 	err := pipeline.Execute(ctx, args)
@@ -215,7 +219,7 @@ func (p *dockerProvisioner) MoveOneContainer(ctx context.Context, c container.Co
 		evtClone.SetLogWriter(ioutil.Discard)
 		pipelineWriter = evtClone
 	}
-	addedContainers, err := p.runReplaceUnitsPipeline(ctx, pipelineWriter, a, toAdd, []container.Container{c}, version, destHosts...)
+	addedContainers, err := p.runReplaceUnitsPipeline(ctx, pipelineWriter, a, toAdd, []container.Container{c}, version, nil, destHosts...)
 	if evt != nil {
 		evt.LogsFrom(evtClone)
 	}
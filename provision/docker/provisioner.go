@@ -297,7 +297,7 @@ func (p *dockerProvisioner) Restart(ctx context.Context, a provision.App, proces
 		toAdd[c.ProcessName].Quantity++
 		toAdd[c.ProcessName].Status = provision.StatusStarted
 	}
-	_, err = p.runReplaceUnitsPipeline(ctx, w, a, toAdd, containers, version)
+	_, err = p.runReplaceUnitsPipeline(ctx, w, a, toAdd, containers, version, nil)
 	return err
 }
 
@@ -359,7 +359,7 @@ func (p *dockerProvisioner) Deploy(ctx context.Context, args provision.DeployArg
 		return "", errors.New("docker provisioner does not support multiple versions")
 	}
 	if args.Version.VersionInfo().DeployImage != "" {
-		err := p.deploy(ctx, args.App, args.Version, args.Event)
+		err := p.deploy(ctx, args.App, args.Version, args.Event, args.Verify)
 		if err != nil {
 			return "", err
 		}
@@ -370,14 +370,20 @@ func (p *dockerProvisioner) Deploy(ctx context.Context, args provision.DeployArg
 	if err != nil {
 		return "", err
 	}
-	err = p.deploy(ctx, args.App, args.Version, args.Event)
+	if args.ImageReady != nil {
+		err = args.ImageReady(ctx, args.Version)
+		if err != nil {
+			return "", err
+		}
+	}
+	err = p.deploy(ctx, args.App, args.Version, args.Event, args.Verify)
 	if err != nil {
 		return "", err
 	}
 	return imageID, nil
 }
 
-func (p *dockerProvisioner) deploy(ctx context.Context, a provision.App, version appTypes.AppVersion, evt *event.Event) error {
+func (p *dockerProvisioner) deploy(ctx context.Context, a provision.App, version appTypes.AppVersion, evt *event.Event, verify func(context.Context, appTypes.AppVersion) error) error {
 	if err := checkCanceled(evt); err != nil {
 		return err
 	}
@@ -399,10 +405,10 @@ func (p *dockerProvisioner) deploy(ctx context.Context, a provision.App, version
 			}
 			toAdd[processName].Quantity++
 		}
-		_, err = p.runCreateUnitsPipeline(ctx, evt, a, toAdd, version)
+		_, err = p.runCreateUnitsPipeline(ctx, evt, a, toAdd, version, verify)
 	} else {
 		toAdd := getContainersToAdd(processes, containers)
-		_, err = p.runReplaceUnitsPipeline(ctx, evt, a, toAdd, containers, version)
+		_, err = p.runReplaceUnitsPipeline(ctx, evt, a, toAdd, containers, version, verify)
 	}
 	if err != nil {
 		err = provision.ErrUnitStartup{Err: err}
@@ -550,7 +556,7 @@ func (p *dockerProvisioner) AddUnits(ctx context.Context, a provision.App, units
 	if w == nil {
 		w = ioutil.Discard
 	}
-	_, err := p.runCreateUnitsPipeline(ctx, w, a, map[string]*containersToAdd{process: {Quantity: int(units)}}, version)
+	_, err := p.runCreateUnitsPipeline(ctx, w, a, map[string]*containersToAdd{process: {Quantity: int(units)}}, version, nil)
 	return err
 }
 
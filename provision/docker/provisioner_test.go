@@ -303,6 +303,84 @@ func (s *S) TestDeploy(c *check.C) {
 	})
 }
 
+func (s *S) TestDeployCallsImageReadyAndVerify(c *check.C) {
+	stopCh := s.stopContainers(s.server.URL(), 1)
+	defer func() { <-stopCh }()
+	a := s.newApp("myapp")
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	customData := map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	}
+	version, err := newVersionForApp(s.p, &a, customData)
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: "app", Value: a.Name},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	var calls []string
+	_, err = s.p.Deploy(context.TODO(), provision.DeployArgs{
+		App:     &a,
+		Version: version,
+		Event:   evt,
+		ImageReady: func(ctx context.Context, v appTypes.AppVersion) error {
+			c.Assert(v.VersionInfo().DeployImage, check.Not(check.Equals), "")
+			calls = append(calls, "ready")
+			return nil
+		},
+		Verify: func(ctx context.Context, v appTypes.AppVersion) error {
+			units, err := a.Units()
+			c.Assert(err, check.IsNil)
+			c.Assert(units, check.HasLen, 1)
+			calls = append(calls, "verify")
+			return nil
+		},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(calls, check.DeepEquals, []string{"ready", "verify"})
+}
+
+func (s *S) TestDeployVerifyFailureRollsBack(c *check.C) {
+	stopCh := s.stopContainers(s.server.URL(), 1)
+	defer func() { <-stopCh }()
+	a := s.newApp("myapp")
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	customData := map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	}
+	version, err := newVersionForApp(s.p, &a, customData)
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: "app", Value: a.Name},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	_, err = s.p.Deploy(context.TODO(), provision.DeployArgs{
+		App:     &a,
+		Version: version,
+		Event:   evt,
+		Verify: func(ctx context.Context, v appTypes.AppVersion) error {
+			return fmt.Errorf("post-deploy hook failed")
+		},
+	})
+	c.Assert(err, check.ErrorMatches, ".*post-deploy hook failed.*")
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 0)
+	_, err = servicemanager.AppVersion.LatestSuccessfulVersion(context.TODO(), &a)
+	c.Assert(err, check.Equals, appTypes.ErrNoVersionsAvailable)
+}
+
 func (s *S) TestDeployWithLimiterActive(c *check.C) {
 	config.Set("docker:limit:actions-per-host", 1)
 	defer config.Unset("docker:limit:actions-per-host")
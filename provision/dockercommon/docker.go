@@ -47,7 +47,7 @@ func (c *PullAndCreateClient) PullAndCreateContainer(opts docker.CreateContainer
 		InactivityTimeout: tsuruNet.StreamInactivityTimeout,
 		RawJSONStream:     true,
 	}
-	err := c.Client.PullImage(pullOpts, RegistryAuthConfig(opts.Config.Image))
+	err := c.Client.PullImage(pullOpts, PullAuthConfig(opts))
 	if err != nil {
 		return nil, "", err
 	}
@@ -138,6 +138,24 @@ func PushImage(client Client, name, tag string, authconfig docker.AuthConfigurat
 	return nil
 }
 
+type pullAuthCtxKey struct{}
+
+// WithPullAuth returns a context to be set in the create container options
+// so the image is pulled with the given credentials instead of the ones from
+// the tsuru registry.
+func WithPullAuth(ctx context.Context, auth docker.AuthConfiguration) context.Context {
+	return context.WithValue(ctx, pullAuthCtxKey{}, auth)
+}
+
+func PullAuthConfig(opts docker.CreateContainerOptions) docker.AuthConfiguration {
+	if opts.Context != nil {
+		if auth, ok := opts.Context.Value(pullAuthCtxKey{}).(docker.AuthConfiguration); ok {
+			return auth
+		}
+	}
+	return RegistryAuthConfig(opts.Config.Image)
+}
+
 func RegistryAuthConfig(image string) docker.AuthConfiguration {
 	var authConfig docker.AuthConfiguration
 	addr, _ := config.GetString("docker:registry")
@@ -5,6 +5,7 @@
 package dockercommon
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"net/http"
@@ -139,6 +140,26 @@ func (s *S) TestPushImageNoRegistry(c *check.C) {
 	c.Assert(request, check.IsNil)
 }
 
+func (s *S) TestPullAuthConfig(c *check.C) {
+	config.Set("docker:registry", "localhost:3030")
+	config.Set("docker:registry-auth:username", "myuser")
+	config.Set("docker:registry-auth:password", "mypassword")
+	defer config.Unset("docker:registry")
+	defer config.Unset("docker:registry-auth")
+	opts := docker.CreateContainerOptions{Config: &docker.Config{Image: "localhost:3030/base/img"}}
+	c.Assert(PullAuthConfig(opts), check.DeepEquals, docker.AuthConfiguration{
+		ServerAddress: "localhost:3030",
+		Username:      "myuser",
+		Password:      "mypassword",
+	})
+	teamAuth := docker.AuthConfiguration{ServerAddress: "private.io", Username: "bot", Password: "secret"}
+	opts = docker.CreateContainerOptions{
+		Config:  &docker.Config{Image: "private.io/base/img"},
+		Context: WithPullAuth(context.TODO(), teamAuth),
+	}
+	c.Assert(PullAuthConfig(opts), check.DeepEquals, teamAuth)
+}
+
 func (s *S) TestGetNodeByHost(c *check.C) {
 	nodes := []cluster.Node{{
 		Address: "http://h1:80",
@@ -0,0 +1,114 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package provision
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	JobConcurrencyAllow   = "allow"
+	JobConcurrencyForbid  = "forbid"
+	JobConcurrencyReplace = "replace"
+
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+
+	jobNameMaxLength = 30
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+
+	jobNameRegexp       = regexp.MustCompile(`^[a-z][a-z0-9-]*[a-z0-9]$`)
+	jobScheduleMacros   = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+	jobScheduleFieldExp = regexp.MustCompile(`^[0-9A-Za-z*?/,-]+$`)
+)
+
+// JobSpec describes a command run periodically by the provisioner using the
+// image of an app version. A zero Version means the job always runs the
+// latest successful version of the app, being updated on each deploy.
+type JobSpec struct {
+	Name              string `json:"name"`
+	Schedule          string `json:"schedule"`
+	Command           string `json:"command"`
+	Process           string `json:"process,omitempty"`
+	Version           int    `json:"version,omitempty"`
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+	// Timeout is the maximum duration of each run, in seconds. Zero means
+	// no timeout.
+	Timeout int64 `json:"timeout,omitempty"`
+}
+
+func (s *JobSpec) Validate() error {
+	if len(s.Name) > jobNameMaxLength || !jobNameRegexp.MatchString(s.Name) {
+		return errors.Errorf("invalid job name %q, it must start with a letter, contain only lowercase letters, numbers and dashes and have at most %d characters", s.Name, jobNameMaxLength)
+	}
+	if err := validateJobSchedule(s.Schedule); err != nil {
+		return err
+	}
+	if strings.TrimSpace(s.Command) == "" {
+		return errors.New("job command is required")
+	}
+	switch s.ConcurrencyPolicy {
+	case "":
+		s.ConcurrencyPolicy = JobConcurrencyAllow
+	case JobConcurrencyAllow, JobConcurrencyForbid, JobConcurrencyReplace:
+	default:
+		return errors.Errorf("invalid concurrency policy %q, must be one of: %s, %s, %s", s.ConcurrencyPolicy, JobConcurrencyAllow, JobConcurrencyForbid, JobConcurrencyReplace)
+	}
+	if s.Timeout < 0 {
+		return errors.New("job timeout must not be negative")
+	}
+	if s.Version < 0 {
+		return errors.New("job version must not be negative")
+	}
+	return nil
+}
+
+func validateJobSchedule(schedule string) error {
+	schedule = strings.TrimSpace(schedule)
+	for _, macro := range jobScheduleMacros {
+		if schedule == macro {
+			return nil
+		}
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return errors.Errorf("invalid job schedule %q, must be a cron expression with 5 fields", schedule)
+	}
+	for _, f := range fields {
+		if !jobScheduleFieldExp.MatchString(f) {
+			return errors.Errorf("invalid job schedule %q, invalid field %q", schedule, f)
+		}
+	}
+	return nil
+}
+
+// JobRun is a single execution of a job.
+type JobRun struct {
+	Name      string     `json:"name"`
+	Job       string     `json:"job"`
+	Version   int        `json:"version"`
+	Status    string     `json:"status"`
+	ExitCode  *int32     `json:"exitCode,omitempty"`
+	StartTime time.Time  `json:"startTime"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+}
+
+// JobProvisioner is a provisioner able to run commands periodically, on a
+// cron schedule.
+type JobProvisioner interface {
+	ListJobs(ctx context.Context, a App) ([]JobSpec, error)
+	SetJob(ctx context.Context, a App, spec JobSpec) error
+	RemoveJob(ctx context.Context, a App, name string) error
+	ListJobRuns(ctx context.Context, a App, name string) ([]JobRun, error)
+}
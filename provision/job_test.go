@@ -0,0 +1,38 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package provision
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+func (ProvisionSuite) TestJobSpecValidate(c *check.C) {
+	tests := []struct {
+		spec        JobSpec
+		expectedErr string
+	}{
+		{spec: JobSpec{Name: "cleanup", Schedule: "*/5 * * * *", Command: "./cleanup"}},
+		{spec: JobSpec{Name: "daily-report", Schedule: "@daily", Command: "./report", ConcurrencyPolicy: JobConcurrencyForbid}},
+		{spec: JobSpec{Name: "Cleanup", Schedule: "@daily", Command: "./cleanup"}, expectedErr: `invalid job name "Cleanup".*`},
+		{spec: JobSpec{Name: "cleanup-", Schedule: "@daily", Command: "./cleanup"}, expectedErr: `invalid job name "cleanup-".*`},
+		{spec: JobSpec{Name: "a-very-long-job-name-for-the-limit", Schedule: "@daily", Command: "./cleanup"}, expectedErr: `invalid job name .*`},
+		{spec: JobSpec{Name: "cleanup", Schedule: "* * * *", Command: "./cleanup"}, expectedErr: `invalid job schedule "\* \* \* \*", must be a cron expression with 5 fields`},
+		{spec: JobSpec{Name: "cleanup", Schedule: "@sometimes", Command: "./cleanup"}, expectedErr: `invalid job schedule .*`},
+		{spec: JobSpec{Name: "cleanup", Schedule: "* * * * $", Command: "./cleanup"}, expectedErr: `invalid job schedule .*, invalid field "\$"`},
+		{spec: JobSpec{Name: "cleanup", Schedule: "@daily", Command: " "}, expectedErr: `job command is required`},
+		{spec: JobSpec{Name: "cleanup", Schedule: "@daily", Command: "./cleanup", ConcurrencyPolicy: "never"}, expectedErr: `invalid concurrency policy "never".*`},
+		{spec: JobSpec{Name: "cleanup", Schedule: "@daily", Command: "./cleanup", Timeout: -1}, expectedErr: `job timeout must not be negative`},
+		{spec: JobSpec{Name: "cleanup", Schedule: "@daily", Command: "./cleanup", Version: -1}, expectedErr: `job version must not be negative`},
+	}
+	for i, tt := range tests {
+		err := tt.spec.Validate()
+		if tt.expectedErr == "" {
+			c.Assert(err, check.IsNil, check.Commentf("test %d", i))
+			c.Assert(tt.spec.ConcurrencyPolicy, check.Not(check.Equals), "", check.Commentf("test %d", i))
+		} else {
+			c.Assert(err, check.ErrorMatches, tt.expectedErr, check.Commentf("test %d", i))
+		}
+	}
+}
@@ -6,6 +6,7 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
@@ -21,10 +22,33 @@ import (
 var errNoDeploy = errors.New("no routable version found for app, at least one deploy is required before configuring autoscale")
 
 func (p *kubernetesProvisioner) GetAutoScale(ctx context.Context, a provision.App) ([]provision.AutoScaleSpec, error) {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return nil, err
 	}
+	var specs []provision.AutoScaleSpec
+	specIdx := map[string]int{}
+	for _, client := range members.serving {
+		clusterSpecs, err := p.getAutoScaleForCluster(ctx, client, a)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range clusterSpecs {
+			key := fmt.Sprintf("%s-%d", spec.Process, spec.Version)
+			idx, ok := specIdx[key]
+			if !ok {
+				specIdx[key] = len(specs)
+				specs = append(specs, spec)
+				continue
+			}
+			specs[idx].MinUnits += spec.MinUnits
+			specs[idx].MaxUnits += spec.MaxUnits
+		}
+	}
+	return specs, nil
+}
+
+func (p *kubernetesProvisioner) getAutoScaleForCluster(ctx context.Context, client *ClusterClient, a provision.App) ([]provision.AutoScaleSpec, error) {
 	controller, err := getClusterController(p, client)
 	if err != nil {
 		return nil, err
@@ -80,10 +104,20 @@ func hpaToSpec(hpa autoscalingv2.HorizontalPodAutoscaler) provision.AutoScaleSpe
 }
 
 func (p *kubernetesProvisioner) RemoveAutoScale(ctx context.Context, a provision.App, process string) error {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return err
 	}
+	for _, client := range members.all() {
+		err = removeAutoScale(ctx, client, a, process)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeAutoScale(ctx context.Context, client *ClusterClient, a provision.App, process string) error {
 	ns, err := client.AppNamespace(ctx, a)
 	if err != nil {
 		return err
@@ -95,12 +129,31 @@ func (p *kubernetesProvisioner) RemoveAutoScale(ctx context.Context, a provision
 	return nil
 }
 
+// SetAutoScale splits the units of the spec among the clusters serving the
+// pool of the app.
 func (p *kubernetesProvisioner) SetAutoScale(ctx context.Context, a provision.App, spec provision.AutoScaleSpec) error {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return err
 	}
-	return setAutoScale(ctx, client, a, spec)
+	clusterSpecs := splitAutoScale(members.serving, spec)
+	for i, client := range members.serving {
+		if clusterSpecs[i] == nil {
+			err = removeAutoScale(ctx, client, a, spec.Process)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err = setAutoScale(ctx, client, a, *clusterSpecs[i])
+		if err == errNoDeploy && i > 0 {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func setAutoScale(ctx context.Context, client *ClusterClient, a provision.App, spec provision.AutoScaleSpec) error {
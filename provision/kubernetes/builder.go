@@ -50,6 +50,7 @@ func (c *KubeClient) BuildPod(ctx context.Context, a provision.App, evt *event.E
 		attachInput:       archiveFile,
 		attachOutput:      evt,
 		inputFile:         "/home/application/archive.tar.gz",
+		architectures:     client.buildArchitectures(a.GetPool()),
 	}
 	return createBuildPod(ctx, params)
 }
@@ -151,6 +152,7 @@ func (c *KubeClient) BuildImage(ctx context.Context, name string, images []strin
 		inputFile:         "/data/context.tar.gz",
 		attachInput:       inputStream,
 		attachOutput:      output,
+		architectures:     client.buildArchitectures(""),
 	}
 	return createImageBuildPod(ctx, params)
 }
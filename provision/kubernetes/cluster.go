@@ -6,6 +6,7 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"net"
@@ -47,6 +48,20 @@ const (
 	singlePoolKey          = "single-pool"
 	ephemeralStorageKey    = "ephemeral-storage"
 	preStopSleepKey        = "pre-stop-sleep"
+	sidecarsKey            = "sidecars"
+	initContainersKey      = "init-containers"
+	pdbMaxUnavailableKey   = "pdb-max-unavailable"
+	pdbMinAvailableKey     = "pdb-min-available"
+	topologySpreadZoneKey  = "topology-spread-zone"
+	topologySpreadNodeKey  = "topology-spread-node"
+	schedulingPolicyKey    = "scheduling-policy"
+	cnbBuilderImageKey     = "cnb-builder-image"
+	buildArchitecturesKey  = "build-architectures"
+
+	networkPoliciesKey               = "enable-network-policies"
+	networkPolicyRouterNamespacesKey = "network-policy-router-namespaces"
+	networkPolicyRouterCIDRsKey      = "network-policy-router-cidrs"
+	networkPolicyEgressCIDRsKey      = "network-policy-egress-cidrs"
 
 	enableLogsFromAPIServerKey = "enable-logs-from-apiserver"
 	defaultLogsFromAPIServer   = false
@@ -71,6 +86,20 @@ var (
 		singlePoolKey:          "Set to use entire cluster to a pool instead only designated nodes. Defaults do false.",
 		ephemeralStorageKey:    fmt.Sprintf("Sets limit for ephemeral storage for created pods. This config may be prefixed with `<pool-name>:`. Defaults to %s.", defaultEphemeralStorageLimit.String()),
 		preStopSleepKey:        fmt.Sprintf("Number of seconds to sleep in the preStop lifecycle hook. This config may be prefixed with `<pool-name>:`. Defaults to %d.", defaultPreStopSleepSeconds),
+		sidecarsKey:            "JSON list of sidecar containers added to every app process, in the same format used by tsuru.yaml. This config may be prefixed with `<pool-name>:`.",
+		initContainersKey:      "JSON list of init containers added to every app process, in the same format used by tsuru.yaml. This config may be prefixed with `<pool-name>:`.",
+		pdbMaxUnavailableKey:   "Max unavailable units, as a number or percentage, in the pod disruption budget created for every app process. Conflicts with pdb-min-available. This config may be prefixed with `<pool-name>:`.",
+		pdbMinAvailableKey:     "Min available units, as a number or percentage, in the pod disruption budget created for every app process. Conflicts with pdb-max-unavailable. This config may be prefixed with `<pool-name>:`.",
+		topologySpreadZoneKey:  "Spread units of every app process across zones, either hard or soft. This config may be prefixed with `<pool-name>:`.",
+		topologySpreadNodeKey:  "Spread units of every app process across nodes, either hard or soft. This config may be prefixed with `<pool-name>:`.",
+		schedulingPolicyKey:    "JSON object with requiredAffinity, preferredAffinity, tolerations, taints and dedicated fields, restricting where pods of a pool run and tainting nodes added to the pool. This config may be prefixed with `<pool-name>:`.",
+		buildArchitecturesKey:  "Comma separated list of architectures, like amd64,arm64, to build platforms and apps deployed from source for. Each architecture is built on its own nodes and the images are joined in manifest lists in the tsuru registry. This config may be prefixed with `<pool-name>:`, platforms use the value without prefix.",
+		cnbBuilderImageKey:     "Cloud Native Buildpacks builder image used to build apps from source, instead of tsuru platforms. Apps may override it with the TSURU_CNB_BUILDER_IMAGE env when the image is allowed by the cnb-builder-image constraint of the pool. This config may be prefixed with `<pool-name>:`.",
+
+		networkPoliciesKey:               "Enable network policies restricting the traffic of app units to the router, the apps declared as dependencies and the bound service instances. Defaults to false.",
+		networkPolicyRouterNamespacesKey: "Comma separated list of namespaces running routers, allowed to reach every app when network policies are enabled. This config may be prefixed with `<pool-name>:`.",
+		networkPolicyRouterCIDRsKey:      "Comma separated list of CIDRs allowed to reach every app when network policies are enabled, usually the addresses of routers running outside the cluster. This config may be prefixed with `<pool-name>:`.",
+		networkPolicyEgressCIDRsKey:      "Comma separated list of CIDRs every app may reach when network policies are enabled. This config may be prefixed with `<pool-name>:`.",
 
 		enableLogsFromAPIServerKey: "Enable tsuru to request application logs from kubernetes api-server, will be enabled by default in next tsuru major version",
 	}
@@ -265,6 +294,71 @@ func (c *ClusterClient) ephemeralStorage(pool string) (resource.Quantity, error)
 	return quantity, nil
 }
 
+func (c *ClusterClient) poolContainers(pool, key string) ([]provTypes.TsuruYamlKubernetesContainer, error) {
+	if c.CustomData == nil {
+		return nil, nil
+	}
+	containersRaw := c.configForContext(pool, key)
+	if containersRaw == "" {
+		return nil, nil
+	}
+	var containers []provTypes.TsuruYamlKubernetesContainer
+	err := json.Unmarshal([]byte(containersRaw), &containers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "misconfigured cluster %s", key)
+	}
+	return containers, nil
+}
+
+func (c *ClusterClient) disruptionBudget(pool string) (*provTypes.TsuruYamlKubernetesDisruptionBudget, error) {
+	if c.CustomData == nil {
+		return nil, nil
+	}
+	budget := provTypes.TsuruYamlKubernetesDisruptionBudget{
+		MaxUnavailable: c.configForContext(pool, pdbMaxUnavailableKey),
+		MinAvailable:   c.configForContext(pool, pdbMinAvailableKey),
+	}
+	if budget.MaxUnavailable == "" && budget.MinAvailable == "" {
+		return nil, nil
+	}
+	err := budget.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "misconfigured cluster pod disruption budget")
+	}
+	return &budget, nil
+}
+
+func (c *ClusterClient) topologySpread(pool string) (provTypes.TsuruYamlKubernetesTopologySpread, error) {
+	if c.CustomData == nil {
+		return provTypes.TsuruYamlKubernetesTopologySpread{}, nil
+	}
+	spread := provTypes.TsuruYamlKubernetesTopologySpread{
+		Zone: c.configForContext(pool, topologySpreadZoneKey),
+		Node: c.configForContext(pool, topologySpreadNodeKey),
+	}
+	err := spread.Validate()
+	if err != nil {
+		return provTypes.TsuruYamlKubernetesTopologySpread{}, errors.Wrap(err, "misconfigured cluster topology spread")
+	}
+	return spread, nil
+}
+
+func (c *ClusterClient) schedulingPolicy(pool string) (poolSchedulingPolicy, error) {
+	if c.CustomData == nil {
+		return poolSchedulingPolicy{}, nil
+	}
+	policyRaw := c.configForContext(pool, schedulingPolicyKey)
+	if policyRaw == "" {
+		return poolSchedulingPolicy{}, nil
+	}
+	var policy poolSchedulingPolicy
+	err := json.Unmarshal([]byte(policyRaw), &policy)
+	if err != nil {
+		return poolSchedulingPolicy{}, errors.Wrap(err, "misconfigured cluster scheduling policy")
+	}
+	return policy, nil
+}
+
 func (c *ClusterClient) ExternalPolicyLocal(pool string) (bool, error) {
 	if c.CustomData == nil {
 		return false, nil
@@ -313,6 +407,14 @@ func (c *ClusterClient) LogsFromAPIServerEnabled() bool {
 	return enabled
 }
 
+func (c *ClusterClient) networkPoliciesEnabled() bool {
+	if c.CustomData == nil {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(c.CustomData[networkPoliciesKey])
+	return enabled
+}
+
 func (c *ClusterClient) namespaceLabels(ns string) (map[string]string, error) {
 	labels := map[string]string{
 		"name": ns,
@@ -382,6 +484,17 @@ func (c *ClusterClient) SinglePool() (bool, error) {
 	return strconv.ParseBool(singlePool)
 }
 
+func (c *ClusterClient) cnbBuilderImage(pool string) string {
+	if c.CustomData == nil {
+		return ""
+	}
+	return c.configForContext(pool, cnbBuilderImageKey)
+}
+
+func (c *ClusterClient) buildArchitectures(pool string) []string {
+	return c.configList(pool, buildArchitecturesKey)
+}
+
 func (c *ClusterClient) configForContext(context, key string) string {
 	if v, ok := c.CustomData[context+":"+key]; ok {
 		return v
@@ -389,6 +502,20 @@ func (c *ClusterClient) configForContext(context, key string) string {
 	return c.CustomData[key]
 }
 
+// configList returns the comma separated values of the config for the pool.
+func (c *ClusterClient) configList(pool, key string) []string {
+	if c.CustomData == nil {
+		return nil
+	}
+	var values []string
+	for _, v := range strings.Split(c.configForContext(pool, key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func (c *ClusterClient) RestConfig() *rest.Config {
 	return c.restConfig
 }
@@ -427,6 +554,21 @@ func clustersForApps(ctx context.Context, apps []provision.App) ([]clusterApp, e
 		}
 		mapItem.apps = append(mapItem.apps, a)
 		clusterClientMap[cluster.Name] = mapItem
+		if cluster.Placement == nil {
+			continue
+		}
+		members, err := membersWithPrimary(ctx, mapItem.client, poolName)
+		if err != nil {
+			return nil, err
+		}
+		for _, cli := range members.all()[1:] {
+			memberItem, inMap := clusterClientMap[cli.Name]
+			if !inMap {
+				memberItem = clusterApp{client: cli}
+			}
+			memberItem.apps = append(memberItem.apps, a)
+			clusterClientMap[cli.Name] = memberItem
+		}
 	}
 	result := make([]clusterApp, 0, len(clusterClientMap))
 	for _, v := range clusterClientMap {
@@ -0,0 +1,268 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app/image"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	cnbBuilderImageEnv = "TSURU_CNB_BUILDER_IMAGE"
+	cnbCacheTag        = "cnb-cache"
+	cnbContainerName   = "cnb-build-cont"
+	cnbAppDir          = "/workspace"
+	cnbPlatformDir     = "/tmp/platform"
+	cnbArchiveFile     = "/tmp/archive.tar.gz"
+)
+
+var _ provision.BuilderKubeClientCNB = &KubeClient{}
+
+// CNBBuilderImage returns the builder image set in the cluster for the app
+// pool. Apps may choose their own builder image with the
+// TSURU_CNB_BUILDER_IMAGE env, as long as it's allowed by the
+// cnb-builder-image constraint of the pool, since the builder runs with the
+// credentials to push to the tsuru registry.
+func (c *KubeClient) CNBBuilderImage(ctx context.Context, a provision.App) (string, error) {
+	if env, ok := a.Envs()[cnbBuilderImageEnv]; ok && env.Value != "" {
+		p, err := pool.GetPoolByName(ctx, a.GetPool())
+		if err != nil {
+			return "", err
+		}
+		err = p.ValidateCNBBuilderImage(env.Value)
+		if err != nil {
+			return "", err
+		}
+		return env.Value, nil
+	}
+	client, err := clusterForPool(ctx, a.GetPool())
+	if err != nil {
+		return "", err
+	}
+	return client.cnbBuilderImage(a.GetPool()), nil
+}
+
+func (c *KubeClient) BuildCNBPod(ctx context.Context, a provision.App, evt *event.Event, archiveFile io.Reader, version appTypes.AppVersion, builderImage string) error {
+	client, err := clusterForPool(ctx, a.GetPool())
+	if err != nil {
+		return err
+	}
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	buildPodName := buildPodNameForApp(a, version)
+	pod, err := newCNBBuildPod(ctx, client, a, buildPodName, builderImage, version.BaseImageName(), "")
+	if err != nil {
+		return err
+	}
+	defer cleanupPod(ctx, client, buildPodName, ns)
+	return createPod(ctx, createPodParams{
+		app:           a,
+		client:        client,
+		podName:       buildPodName,
+		attachInput:   archiveFile,
+		attachOutput:  evt,
+		pod:           &pod,
+		mainContainer: cnbContainerName,
+	})
+}
+
+func (c *KubeClient) RebaseCNBPod(ctx context.Context, a provision.App, evt *event.Event, previousImage string, version appTypes.AppVersion, builderImage string) error {
+	client, err := clusterForPool(ctx, a.GetPool())
+	if err != nil {
+		return err
+	}
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	buildPodName := buildPodNameForApp(a, version)
+	pod, err := newCNBBuildPod(ctx, client, a, buildPodName, builderImage, version.BaseImageName(), previousImage)
+	if err != nil {
+		return err
+	}
+	defer cleanupPod(ctx, client, buildPodName, ns)
+	return createPod(ctx, createPodParams{
+		app:           a,
+		client:        client,
+		podName:       buildPodName,
+		attachOutput:  evt,
+		pod:           &pod,
+		mainContainer: cnbContainerName,
+	})
+}
+
+// cnbCreatorCmd returns the script extracting the app archive received in
+// stdin and running the lifecycle creator, which detects the buildpacks,
+// builds the app and exports the image, reusing layers cached in the
+// registry by the previous builds of the app.
+func cnbCreatorCmd(destinationImage string, platformEnvs []string) string {
+	repository, tag := image.SplitImageName(destinationImage)
+	args := []string{
+		"/cnb/lifecycle/creator",
+		"-app=" + cnbAppDir,
+		"-platform=" + cnbPlatformDir,
+		fmt.Sprintf("-cache-image=%s:%s", repository, cnbCacheTag),
+		fmt.Sprintf("-previous-image=%s:latest", repository),
+	}
+	if tag != "latest" {
+		args = append(args, fmt.Sprintf("-tag=%s:latest", repository))
+	}
+	args = append(args, destinationImage)
+	cmds := []string{
+		fmt.Sprintf("mkdir -p %s %s/env", cnbAppDir, cnbPlatformDir),
+		fmt.Sprintf("cat >%s", cnbArchiveFile),
+		fmt.Sprintf("tar -xzf %[1]s -C %[2]s && rm %[1]s", cnbArchiveFile, cnbAppDir),
+	}
+	for _, name := range platformEnvs {
+		cmds = append(cmds, fmt.Sprintf(`printenv %[1]s >%[2]s/env/%[1]s`, name, cnbPlatformDir))
+	}
+	cmds = append(cmds, "exec "+strings.Join(args, " "))
+	return strings.Join(cmds, "\n")
+}
+
+// cnbRebaserCmd returns the command running the lifecycle rebaser, which
+// replaces the run image layers of the previous image with the current ones
+// of its run image, saving the result as the destination image. The app is
+// not built again, as its source isn't kept in the image.
+func cnbRebaserCmd(previousImage, destinationImage string) string {
+	repository, tag := image.SplitImageName(destinationImage)
+	args := []string{
+		"/cnb/lifecycle/rebaser",
+		"-previous-image=" + previousImage,
+		destinationImage,
+	}
+	if tag != "latest" {
+		args = append(args, repository+":latest")
+	}
+	return "exec " + strings.Join(args, " ")
+}
+
+// cnbRegistryAuth returns the value of CNB_REGISTRY_AUTH, used by the
+// lifecycle to push the image and its cache to the tsuru registry.
+func cnbRegistryAuth(img string) (string, error) {
+	username, password, registry := registryAuth(img)
+	if registry == "" {
+		return "", nil
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	data, err := json.Marshal(map[string]string{registry: "Basic " + auth})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// newCNBBuildPod returns the pod building the app image with buildpacks, or
+// rebasing previousImage when it's set.
+func newCNBBuildPod(ctx context.Context, client *ClusterClient, a provision.App, podName, builderImage, destinationImage, previousImage string) (apiv1.Pod, error) {
+	err := ensureNamespaceForApp(ctx, client, a)
+	if err != nil {
+		return apiv1.Pod{}, err
+	}
+	err = ensureServiceAccountForApp(ctx, client, a)
+	if err != nil {
+		return apiv1.Pod{}, err
+	}
+	labels, err := provision.ServiceLabels(ctx, provision.ServiceLabelsOpts{
+		App: a,
+		ServiceLabelExtendedOpts: provision.ServiceLabelExtendedOpts{
+			IsBuild:     true,
+			Prefix:      tsuruLabelPrefix,
+			Provisioner: provisionerName,
+		},
+	})
+	if err != nil {
+		return apiv1.Pod{}, err
+	}
+	annotations := provision.LabelSet{Prefix: tsuruLabelPrefix}
+	annotations.SetBuildImage(destinationImage)
+	nodeSelector := provision.NodeLabels(provision.NodeLabelsOpts{
+		Pool:   a.GetPool(),
+		Prefix: tsuruLabelPrefix,
+	}).ToNodeByPoolSelector()
+	singlePool, err := client.SinglePool()
+	if err != nil {
+		return apiv1.Pod{}, errors.WithMessage(err, "misconfigured cluster single pool value")
+	}
+	if singlePool {
+		nodeSelector = map[string]string{}
+	}
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return apiv1.Pod{}, err
+	}
+	pullSecrets, err := getAppImagePullSecrets(ctx, client, ns, a, builderImage)
+	if err != nil {
+		return apiv1.Pod{}, err
+	}
+	var envs []apiv1.EnvVar
+	var platformEnvs []string
+	for name, env := range a.Envs() {
+		if strings.HasPrefix(name, "TSURU_") || previousImage != "" {
+			continue
+		}
+		envs = append(envs, apiv1.EnvVar{Name: name, Value: env.Value})
+		platformEnvs = append(platformEnvs, name)
+	}
+	sort.Strings(platformEnvs)
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+	registryAuth, err := cnbRegistryAuth(destinationImage)
+	if err != nil {
+		return apiv1.Pod{}, err
+	}
+	if registryAuth != "" {
+		envs = append(envs, apiv1.EnvVar{Name: "CNB_REGISTRY_AUTH", Value: registryAuth})
+	}
+	cmd := cnbCreatorCmd(destinationImage, platformEnvs)
+	if previousImage != "" {
+		cmd = cnbRebaserCmd(previousImage, destinationImage)
+	}
+	serviceLinks := false
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        podName,
+			Namespace:   ns,
+			Labels:      labels.ToLabels(),
+			Annotations: annotations.ToLabels(),
+		},
+		Spec: apiv1.PodSpec{
+			EnableServiceLinks: &serviceLinks,
+			ImagePullSecrets:   pullSecrets,
+			ServiceAccountName: serviceAccountNameForApp(a),
+			NodeSelector:       nodeSelector,
+			RestartPolicy:      apiv1.RestartPolicyNever,
+			Containers: []apiv1.Container{
+				{
+					Name:      cnbContainerName,
+					Image:     builderImage,
+					Stdin:     previousImage == "",
+					StdinOnce: previousImage == "",
+					Env:       envs,
+					Command:   []string{"sh", "-ec", cmd},
+				},
+			},
+		},
+	}
+	err = applySchedulingPolicy(client, a.GetPool(), &pod.Spec)
+	return pod, err
+}
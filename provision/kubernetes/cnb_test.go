@@ -0,0 +1,50 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestCNBCreatorCmd(c *check.C) {
+	cmd := cnbCreatorCmd("registry.example.com/tsuru/app-myapp:v2", []string{"BP_NODE_VERSION"})
+	c.Assert(cmd, check.Equals, `mkdir -p /workspace /tmp/platform/env
+cat >/tmp/archive.tar.gz
+tar -xzf /tmp/archive.tar.gz -C /workspace && rm /tmp/archive.tar.gz
+printenv BP_NODE_VERSION >/tmp/platform/env/BP_NODE_VERSION
+exec /cnb/lifecycle/creator -app=/workspace -platform=/tmp/platform -cache-image=registry.example.com/tsuru/app-myapp:cnb-cache -previous-image=registry.example.com/tsuru/app-myapp:latest -tag=registry.example.com/tsuru/app-myapp:latest registry.example.com/tsuru/app-myapp:v2`)
+}
+
+func (s *S) TestCNBRebaserCmd(c *check.C) {
+	cmd := cnbRebaserCmd("registry.example.com/tsuru/app-myapp@sha256:abc", "registry.example.com/tsuru/app-myapp:v3")
+	c.Assert(cmd, check.Equals, "exec /cnb/lifecycle/rebaser -previous-image=registry.example.com/tsuru/app-myapp@sha256:abc registry.example.com/tsuru/app-myapp:v3 registry.example.com/tsuru/app-myapp:latest")
+}
+
+func (s *S) TestCNBBuilderImage(c *check.C) {
+	s.clusterClient.CustomData["test-default:"+cnbBuilderImageKey] = "paketobuildpacks/builder:base"
+	defer delete(s.clusterClient.CustomData, "test-default:"+cnbBuilderImageKey)
+	client := &KubeClient{}
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	img, err := client.CNBBuilderImage(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(img, check.Equals, "paketobuildpacks/builder:base")
+	a.SetEnv(bind.EnvVar{Name: cnbBuilderImageEnv, Value: "heroku/builder:22"})
+	_, err = client.CNBBuilderImage(context.TODO(), a)
+	c.Assert(err, check.ErrorMatches, `buildpacks builder image "heroku/builder:22" is not allowed on pool "test-default"`)
+	err = pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: "test-default", Field: pool.ConstraintTypeCNBBuilderImage, Values: []string{"heroku/builder:*"}})
+	c.Assert(err, check.IsNil)
+	img, err = client.CNBBuilderImage(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(img, check.Equals, "heroku/builder:22")
+	other := provisiontest.NewFakeAppWithPool("other", "python", "other-pool", 0)
+	img, err = client.CNBBuilderImage(context.TODO(), other)
+	c.Assert(err, check.IsNil)
+	c.Assert(img, check.Equals, "")
+}
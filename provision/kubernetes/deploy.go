@@ -30,14 +30,17 @@ import (
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/dockercommon"
 	"github.com/tsuru/tsuru/provision/servicecommon"
+	"github.com/tsuru/tsuru/registry"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	provTypes "github.com/tsuru/tsuru/types/provision"
+	registryTypes "github.com/tsuru/tsuru/types/registry"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -173,12 +176,69 @@ type createPodParams struct {
 	attachOutput      io.Writer
 	pod               *apiv1.Pod
 	mainContainer     string
+	// architectures, when set, builds the destination images once for each
+	// architecture, joining them in manifest lists.
+	architectures []string
+	// architecture restricts the pod to nodes of the architecture.
+	architecture string
 }
 
 func createBuildPod(ctx context.Context, params createPodParams) error {
 	cmds := dockercommon.ArchiveBuildCmds(params.app, "file:///home/application/archive.tar.gz")
 	params.cmds = cmds
-	return createPod(ctx, params)
+	return createPodForArchitectures(ctx, params, createPod)
+}
+
+// createPodForArchitectures runs create once for each architecture in params,
+// with the pod running on nodes of the architecture and pushing the image
+// tagged with the architecture suffix. The destination images are then
+// pushed as manifest lists of the images built for each architecture.
+func createPodForArchitectures(ctx context.Context, params createPodParams, create func(context.Context, createPodParams) error) error {
+	if len(params.architectures) == 0 {
+		return create(ctx, params)
+	}
+	var input []byte
+	if params.attachInput != nil {
+		var err error
+		input, err = ioutil.ReadAll(params.attachInput)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	ns := params.client.Namespace()
+	if params.app != nil {
+		var err error
+		ns, err = params.client.AppNamespace(ctx, params.app)
+		if err != nil {
+			return err
+		}
+	}
+	archImages := map[string]string{}
+	for _, arch := range params.architectures {
+		archParams := params
+		archParams.architectures = nil
+		archParams.architecture = arch
+		archParams.podName = fmt.Sprintf("%s-%s", params.podName, arch)
+		archParams.destinationImages = []string{image.ArchitectureImage(params.destinationImages[0], arch)}
+		if input != nil {
+			archParams.attachInput = bytes.NewReader(input)
+		}
+		fmt.Fprintf(params.attachOutput, " ---> Building for architecture %s\n", arch)
+		err := create(ctx, archParams)
+		cleanupPod(ctx, params.client, archParams.podName, ns)
+		if err != nil {
+			return err
+		}
+		archImages[arch] = archParams.destinationImages[0]
+	}
+	for _, img := range params.destinationImages {
+		fmt.Fprintf(params.attachOutput, " ---> Pushing manifest list %s\n", img)
+		err := registry.CreateManifestList(ctx, img, archImages)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func createDeployPod(ctx context.Context, params createPodParams) error {
@@ -191,25 +251,27 @@ func createDeployPod(ctx context.Context, params createPodParams) error {
 	if tag != "latest" {
 		params.destinationImages = append(params.destinationImages, fmt.Sprintf("%s:latest", repository))
 	}
-	return createPod(ctx, params)
+	return createPodForArchitectures(ctx, params, createPod)
 }
 
 func createImageBuildPod(ctx context.Context, params createPodParams) error {
 	params.mainContainer = "build-cont"
-	kubeConf := getKubeConfig()
-	pod, err := newDeployAgentImageBuildPod(ctx, params.client, params.sourceImage, params.podName, deployAgentConfig{
-		name:              params.mainContainer,
-		image:             kubeConf.DeploySidecarImage,
-		cmd:               fmt.Sprintf("mkdir -p $(dirname %[1]s) && cat >%[1]s && tsuru_unit_agent", params.inputFile),
-		destinationImages: params.destinationImages,
-		inputFile:         params.inputFile,
-		dockerfileBuild:   true,
+	return createPodForArchitectures(ctx, params, func(ctx context.Context, params createPodParams) error {
+		kubeConf := getKubeConfig()
+		pod, err := newDeployAgentImageBuildPod(ctx, params.client, params.sourceImage, params.podName, deployAgentConfig{
+			name:              params.mainContainer,
+			image:             kubeConf.DeploySidecarImage,
+			cmd:               fmt.Sprintf("mkdir -p $(dirname %[1]s) && cat >%[1]s && tsuru_unit_agent", params.inputFile),
+			destinationImages: params.destinationImages,
+			inputFile:         params.inputFile,
+			dockerfileBuild:   true,
+		})
+		if err != nil {
+			return err
+		}
+		params.pod = &pod
+		return createPod(ctx, params)
 	})
-	if err != nil {
-		return err
-	}
-	params.pod = &pod
-	return createPod(ctx, params)
 }
 
 func getImagePullSecrets(ctx context.Context, client *ClusterClient, namespace string, images ...string) ([]apiv1.LocalObjectReference, error) {
@@ -235,6 +297,76 @@ func getImagePullSecrets(ctx context.Context, client *ClusterClient, namespace s
 	}, nil
 }
 
+// getAppImagePullSecrets returns the pull secrets for images used by the app,
+// including the ones for registry credentials owned by the app team.
+func getAppImagePullSecrets(ctx context.Context, client *ClusterClient, namespace string, a provision.App, images ...string) ([]apiv1.LocalObjectReference, error) {
+	pullSecrets, err := getImagePullSecrets(ctx, client, namespace, images...)
+	if err != nil || a == nil {
+		return pullSecrets, err
+	}
+	tsuruRegistry, _ := config.GetString("docker:registry")
+	teams := []string{a.GetTeamOwner()}
+	for _, image := range images {
+		if image == "" || (tsuruRegistry != "" && strings.Split(image, "/")[0] == tsuruRegistry) {
+			continue
+		}
+		cred, err := servicemanager.RegistryCredential.FindForImage(ctx, teams, image)
+		if err == registryTypes.ErrCredentialNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		secretName := teamRegistrySecretName(cred.Team, cred.Registry)
+		if containsPullSecret(pullSecrets, secretName) {
+			continue
+		}
+		err = ensureDockerConfigSecret(ctx, client, namespace, secretName, registryTypes.ServerAddress(cred.Registry), cred.Username, cred.Token)
+		if err != nil {
+			return nil, err
+		}
+		pullSecrets = append(pullSecrets, apiv1.LocalObjectReference{Name: secretName})
+	}
+	return pullSecrets, nil
+}
+
+// RemoveRegistryCredential removes the pull secrets created for the registry
+// credential of the team from every namespace in every cluster.
+func (p *kubernetesProvisioner) RemoveRegistryCredential(ctx context.Context, team, registry string) error {
+	secretName := teamRegistrySecretName(team, registry)
+	err := forEachCluster(ctx, func(client *ClusterClient) error {
+		secrets, err := client.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", secretName).String(),
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, secret := range secrets.Items {
+			if secret.Name != secretName {
+				continue
+			}
+			err = client.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+			if err != nil && !k8sErrors.IsNotFound(err) {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	})
+	if err == provTypes.ErrNoCluster {
+		return nil
+	}
+	return err
+}
+
+func containsPullSecret(pullSecrets []apiv1.LocalObjectReference, name string) bool {
+	for _, s := range pullSecrets {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func ensureAuthSecret(ctx context.Context, client *ClusterClient, namespace string) error {
 	registry, _ := config.GetString("docker:registry")
 	username, _ := config.GetString("docker:registry-auth:username")
@@ -242,6 +374,10 @@ func ensureAuthSecret(ctx context.Context, client *ClusterClient, namespace stri
 	if len(username) == 0 && len(password) == 0 {
 		return nil
 	}
+	return ensureDockerConfigSecret(ctx, client, namespace, registrySecretName(registry), registry, username, password)
+}
+
+func ensureDockerConfigSecret(ctx context.Context, client *ClusterClient, namespace, name, registry, username, password string) error {
 	authEncoded := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
 	conf := map[string]map[string]dockerTypes.AuthConfig{
 		"auths": {
@@ -258,7 +394,7 @@ func ensureAuthSecret(ctx context.Context, client *ClusterClient, namespace stri
 	}
 	secret := &apiv1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: registrySecretName(registry),
+			Name: name,
 		},
 		Data: map[string][]byte{
 			apiv1.DockerConfigJsonKey: serializedConf,
@@ -296,6 +432,12 @@ func createPod(ctx context.Context, params createPodParams) error {
 		}
 		params.pod = &pod
 	}
+	if params.architecture != "" {
+		if params.pod.Spec.NodeSelector == nil {
+			params.pod.Spec.NodeSelector = map[string]string{}
+		}
+		params.pod.Spec.NodeSelector[apiv1.LabelArchStable] = params.architecture
+	}
 	ns, err := params.client.AppNamespace(ctx, params.app)
 	if err != nil {
 		return err
@@ -388,6 +530,7 @@ func logPodEvents(ctx context.Context, client *ClusterClient, initialResourceVer
 type hcResult struct {
 	liveness  *apiv1.Probe
 	readiness *apiv1.Probe
+	startup   *apiv1.Probe
 }
 
 func probesFromHC(hc *provTypes.TsuruYamlHealthcheck, port int) (hcResult, error) {
@@ -445,6 +588,106 @@ func probesFromHC(hc *provTypes.TsuruYamlHealthcheck, port int) (hcResult, error
 	return result, nil
 }
 
+// probesForProcess returns the probes declared for the process in the probes
+// section of tsuru.yaml. The legacy healthcheck is still used for the web
+// process when it declares no readiness or liveness probe.
+func probesForProcess(yamlData provTypes.TsuruYamlData, process, webProcess string, ports []provTypes.TsuruYamlKubernetesProcessPortConfig) (hcResult, error) {
+	var result hcResult
+	var err error
+	var defaultPort int
+	if len(ports) > 0 {
+		if process == webProcess {
+			//TODO: add support to multiple HCs
+			result, err = probesFromHC(yamlData.Healthcheck, ports[0].TargetPort)
+			if err != nil {
+				return result, err
+			}
+		}
+		defaultPort = ports[0].TargetPort
+		if defaultPort == 0 {
+			defaultPort, _ = strconv.Atoi(provision.WebProcessDefaultPort())
+		}
+	}
+	probes, ok := yamlData.Probes[process]
+	if !ok {
+		return result, nil
+	}
+	err = probes.Validate()
+	if err != nil {
+		return result, errors.Wrapf(err, "invalid probes for process %q", process)
+	}
+	if probes.Readiness != nil {
+		result.readiness, err = probeFromYaml(probes.Readiness, defaultPort)
+		if err != nil {
+			return result, errors.Wrapf(err, "readiness probe for process %q", process)
+		}
+	}
+	if probes.Liveness != nil {
+		result.liveness, err = probeFromYaml(probes.Liveness, defaultPort)
+		if err != nil {
+			return result, errors.Wrapf(err, "liveness probe for process %q", process)
+		}
+	}
+	if probes.Startup != nil {
+		result.startup, err = probeFromYaml(probes.Startup, defaultPort)
+		if err != nil {
+			return result, errors.Wrapf(err, "startup probe for process %q", process)
+		}
+	}
+	return result, nil
+}
+
+func probeFromYaml(p *provTypes.TsuruYamlProbe, defaultPort int) (*apiv1.Probe, error) {
+	probe := &apiv1.Probe{
+		InitialDelaySeconds: int32(p.InitialDelaySeconds),
+		PeriodSeconds:       int32(p.PeriodSeconds),
+		TimeoutSeconds:      int32(p.TimeoutSeconds),
+		FailureThreshold:    int32(p.FailureThreshold),
+		SuccessThreshold:    int32(p.SuccessThreshold),
+	}
+	switch {
+	case p.HTTP != nil:
+		port := p.HTTP.Port
+		if port == 0 {
+			port = defaultPort
+		}
+		if port == 0 {
+			return nil, errors.New("http port is required for processes without ports")
+		}
+		scheme := p.HTTP.Scheme
+		if scheme == "" {
+			scheme = provision.DefaultHealthcheckScheme
+		}
+		headers := []apiv1.HTTPHeader{}
+		for header, value := range p.HTTP.Headers {
+			headers = append(headers, apiv1.HTTPHeader{Name: header, Value: value})
+		}
+		sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+		probe.Handler.HTTPGet = &apiv1.HTTPGetAction{
+			Path:        p.HTTP.Path,
+			Port:        intstr.FromInt(port),
+			Scheme:      apiv1.URIScheme(strings.ToUpper(scheme)),
+			HTTPHeaders: headers,
+		}
+	case p.TCP != nil:
+		port := p.TCP.Port
+		if port == 0 {
+			port = defaultPort
+		}
+		if port == 0 {
+			return nil, errors.New("tcp port is required for processes without ports")
+		}
+		probe.Handler.TCPSocket = &apiv1.TCPSocketAction{
+			Port: intstr.FromInt(port),
+		}
+	default:
+		probe.Handler.Exec = &apiv1.ExecAction{
+			Command: []string{"/bin/sh", "-c", strings.Join(p.Command, " ")},
+		}
+	}
+	return probe, nil
+}
+
 func ensureNamespaceForApp(ctx context.Context, client *ClusterClient, app provision.App) error {
 	ns, err := client.AppNamespace(ctx, app)
 	if err != nil {
@@ -526,13 +769,9 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 	if err != nil {
 		return nil, nil, errors.WithStack(err)
 	}
-	var hcData hcResult
-	if process == webProcessName && len(processPorts) > 0 {
-		//TODO: add support to multiple HCs
-		hcData, err = probesFromHC(yamlData.Healthcheck, processPorts[0].TargetPort)
-		if err != nil {
-			return nil, nil, err
-		}
+	hcData, err := probesForProcess(yamlData, process, webProcessName, processPorts)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	sleepSec := client.preStopSleepSeconds(a.GetPool())
@@ -576,40 +815,26 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 		nodeSelector = map[string]string{}
 	}
 	_, uid := dockercommon.UserForContainer()
-	resourceLimits := apiv1.ResourceList{}
-	overcommit, err := client.OvercommitFactor(a.GetPool())
+	resources, err := resourceRequirementsForApp(client, a, process)
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "misconfigured cluster overcommit factor")
-	}
-	resourceRequests := apiv1.ResourceList{}
-	memory := a.GetMemory()
-	if memory != 0 {
-		resourceLimits[apiv1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
-		resourceRequests[apiv1.ResourceMemory] = *resource.NewQuantity(memory/overcommit, resource.BinarySI)
-	}
-	cpu := a.GetMilliCPU()
-	if cpu != 0 {
-		resourceLimits[apiv1.ResourceCPU] = *resource.NewMilliQuantity(int64(cpu), resource.DecimalSI)
-		resourceRequests[apiv1.ResourceCPU] = *resource.NewMilliQuantity(int64(cpu)/overcommit, resource.DecimalSI)
+		return nil, nil, err
 	}
-	ephemeral, err := client.ephemeralStorage(a.GetPool())
+	volumes, mounts, err := createVolumesForApp(ctx, client, a)
 	if err != nil {
 		return nil, nil, err
 	}
-	if ephemeral.Value() > 0 {
-		resourceRequests[apiv1.ResourceEphemeralStorage] = *resource.NewQuantity(0, resource.DecimalSI)
-		resourceLimits[apiv1.ResourceEphemeralStorage] = ephemeral
-	}
-	volumes, mounts, err := createVolumesForApp(ctx, client, a)
+	extraContainers, err := containersForProcess(ctx, client, a, yamlData, process, resources)
 	if err != nil {
 		return nil, nil, err
 	}
+	volumes = append(volumes, extraContainers.volumes...)
+	mounts = append(mounts, extraContainers.mounts...)
 	ns, err := client.AppNamespace(ctx, a)
 	if err != nil {
 		return nil, nil, err
 	}
-	deployImage := version.VersionInfo().DeployImage
-	pullSecrets, err := getImagePullSecrets(ctx, client, ns, deployImage)
+	deployImage := version.VersionInfo().DeployImageReference()
+	pullSecrets, err := getAppImagePullSecrets(ctx, client, ns, a, deployImage)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -618,6 +843,14 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 	baseName := deploymentNameForAppBase(a, process)
 	depLabels["app"] = baseName
 	podLabels["app"] = baseName
+	processConfig, err := processConfigForVersion(version, process)
+	if err != nil {
+		return nil, nil, err
+	}
+	topologySpread, err := topologySpreadForProcess(client, a, processConfig, map[string]string{"app": baseName})
+	if err != nil {
+		return nil, nil, err
+	}
 	containerPorts := make([]apiv1.ContainerPort, len(processPorts))
 	for i, port := range processPorts {
 		portInt := port.TargetPort
@@ -659,11 +892,13 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 					SecurityContext: &apiv1.PodSecurityContext{
 						RunAsUser: uid,
 					},
-					RestartPolicy: apiv1.RestartPolicyAlways,
-					NodeSelector:  nodeSelector,
-					Volumes:       volumes,
-					Subdomain:     headlessServiceName(a, process),
-					Containers: []apiv1.Container{
+					RestartPolicy:             apiv1.RestartPolicyAlways,
+					NodeSelector:              nodeSelector,
+					Volumes:                   volumes,
+					Subdomain:                 headlessServiceName(a, process),
+					TopologySpreadConstraints: topologySpread,
+					InitContainers:            extraContainers.initContainers,
+					Containers: append([]apiv1.Container{
 						{
 							Name:           depName,
 							Image:          deployImage,
@@ -671,19 +906,22 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 							Env:            appEnvs(a, process, version, false),
 							ReadinessProbe: hcData.readiness,
 							LivenessProbe:  hcData.liveness,
-							Resources: apiv1.ResourceRequirements{
-								Limits:   resourceLimits,
-								Requests: resourceRequests,
-							},
-							VolumeMounts: mounts,
-							Ports:        containerPorts,
-							Lifecycle:    &lifecycle,
+							StartupProbe:   hcData.startup,
+							Resources:      resources,
+							VolumeMounts:   mounts,
+							Ports:          containerPorts,
+							Lifecycle:      &lifecycle,
 						},
-					},
+					}, extraContainers.sidecars...),
 				},
 			},
 		},
 	}
+	err = applySchedulingPolicy(client, a.GetPool(), &deployment.Spec.Template.Spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	applyArchitectures(&deployment.Spec.Template.Spec, version.VersionInfo().Architectures)
 	var newDep *appsv1.Deployment
 	if oldDeployment == nil {
 		newDep, err = client.AppsV1().Deployments(ns).Create(ctx, &deployment, metav1.CreateOptions{})
@@ -693,6 +931,39 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 	return newDep, labels, errors.WithStack(err)
 }
 
+// resourceRequirementsForApp returns the limits of the plan of the process,
+// requests are reduced by the overcommit factor of the pool.
+func resourceRequirementsForApp(client *ClusterClient, a provision.App, process string) (apiv1.ResourceRequirements, error) {
+	resourceLimits := apiv1.ResourceList{}
+	overcommit, err := client.OvercommitFactor(a.GetPool())
+	if err != nil {
+		return apiv1.ResourceRequirements{}, errors.WithMessage(err, "misconfigured cluster overcommit factor")
+	}
+	resourceRequests := apiv1.ResourceList{}
+	memory := a.GetProcessMemory(process)
+	if memory != 0 {
+		resourceLimits[apiv1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
+		resourceRequests[apiv1.ResourceMemory] = *resource.NewQuantity(memory/overcommit, resource.BinarySI)
+	}
+	cpu := a.GetProcessMilliCPU(process)
+	if cpu != 0 {
+		resourceLimits[apiv1.ResourceCPU] = *resource.NewMilliQuantity(int64(cpu), resource.DecimalSI)
+		resourceRequests[apiv1.ResourceCPU] = *resource.NewMilliQuantity(int64(cpu)/overcommit, resource.DecimalSI)
+	}
+	ephemeral, err := client.ephemeralStorage(a.GetPool())
+	if err != nil {
+		return apiv1.ResourceRequirements{}, err
+	}
+	if ephemeral.Value() > 0 {
+		resourceRequests[apiv1.ResourceEphemeralStorage] = *resource.NewQuantity(0, resource.DecimalSI)
+		resourceLimits[apiv1.ResourceEphemeralStorage] = ephemeral
+	}
+	return apiv1.ResourceRequirements{
+		Limits:   resourceLimits,
+		Requests: resourceRequests,
+	}, nil
+}
+
 func appEnvs(a provision.App, process string, version appTypes.AppVersion, isDeploy bool) []apiv1.EnvVar {
 	appEnvs := EnvsForApp(a, process, version, isDeploy)
 	envs := make([]apiv1.EnvVar, len(appEnvs))
@@ -768,6 +1039,16 @@ func (m *serviceManager) CleanupServices(ctx context.Context, a provision.App, d
 		}
 	}
 
+	ns, err := m.client.AppNamespace(ctx, a)
+	if err != nil {
+		multiErrors.Add(err)
+	} else {
+		err = cleanupDisruptionBudgets(ctx, m.client, ns, a, processInUse)
+		if err != nil {
+			multiErrors.Add(err)
+		}
+	}
+
 	return multiErrors.ToError()
 }
 
@@ -1120,6 +1401,11 @@ func (m *serviceManager) DeployService(ctx context.Context, a provision.App, pro
 		return errors.Wrap(err, "unable to ensure auto scale is configured")
 	}
 
+	err = ensureDisruptionBudget(ctx, m.client, a, process, version)
+	if err != nil {
+		return errors.Wrap(err, "unable to ensure pod disruption budget is configured")
+	}
+
 	return nil
 }
 
@@ -1663,7 +1949,7 @@ func newDeployAgentPod(ctx context.Context, client *ClusterClient, sourceImage s
 	if err != nil {
 		return apiv1.Pod{}, err
 	}
-	pullSecrets, err := getImagePullSecrets(ctx, client, ns, sourceImage, conf.image)
+	pullSecrets, err := getAppImagePullSecrets(ctx, client, ns, app, sourceImage, conf.image)
 	if err != nil {
 		return apiv1.Pod{}, err
 	}
@@ -1671,7 +1957,7 @@ func newDeployAgentPod(ctx context.Context, client *ClusterClient, sourceImage s
 		conf.runAsUser = strconv.FormatInt(*uid, 10)
 	}
 	serviceLinks := false
-	return apiv1.Pod{
+	pod := apiv1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        podName,
 			Namespace:   ns,
@@ -1705,7 +1991,9 @@ func newDeployAgentPod(ctx context.Context, client *ClusterClient, sourceImage s
 				newDeployAgentContainer(conf),
 			},
 		},
-	}, nil
+	}
+	err = applySchedulingPolicy(client, app.GetPool(), &pod.Spec)
+	return pod, err
 }
 
 func newDeployAgentImageBuildPod(ctx context.Context, client *ClusterClient, sourceImage string, podName string, conf deployAgentConfig) (apiv1.Pod, error) {
@@ -26,15 +26,19 @@ import (
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/nodecontainer"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
 	"github.com/tsuru/tsuru/provision/servicecommon"
 	"github.com/tsuru/tsuru/safe"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	provTypes "github.com/tsuru/tsuru/types/provision"
+	registryTypes "github.com/tsuru/tsuru/types/registry"
 	"github.com/tsuru/tsuru/volume"
 	check "gopkg.in/check.v1"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -933,6 +937,372 @@ func (s *S) TestServiceManagerDeployServiceWithHC(c *check.C) {
 	}
 }
 
+func (s *S) TestServiceManagerDeployServiceWithProbes(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web":    "cm1",
+			"worker": "cmd2",
+		},
+		"healthcheck": provTypes.TsuruYamlHealthcheck{
+			Path: "/legacy",
+		},
+		"probes": map[string]provTypes.TsuruYamlProbes{
+			"web": {
+				Liveness: &provTypes.TsuruYamlProbe{
+					TCP:           &provTypes.TsuruYamlProbeTCP{},
+					PeriodSeconds: 20,
+				},
+				Startup: &provTypes.TsuruYamlProbe{
+					HTTP: &provTypes.TsuruYamlProbeHTTP{
+						Path:    "/started",
+						Scheme:  "https",
+						Headers: map[string]string{"Host": "myapp.com"},
+					},
+					FailureThreshold: 30,
+					PeriodSeconds:    10,
+				},
+			},
+			"worker": {
+				Readiness: &provTypes.TsuruYamlProbe{
+					Command: []string{"cat", "/tmp/ready"},
+				},
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web":    servicecommon.ProcessState{Start: true},
+		"worker": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	nsName, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(nsName).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	container := dep.Spec.Template.Spec.Containers[0]
+	c.Assert(container.ReadinessProbe, check.DeepEquals, &apiv1.Probe{
+		PeriodSeconds:    10,
+		FailureThreshold: 3,
+		TimeoutSeconds:   60,
+		Handler: apiv1.Handler{
+			HTTPGet: &apiv1.HTTPGetAction{
+				Path:        "/legacy",
+				Port:        intstr.FromInt(8888),
+				Scheme:      apiv1.URISchemeHTTP,
+				HTTPHeaders: []apiv1.HTTPHeader{},
+			},
+		},
+	})
+	c.Assert(container.LivenessProbe, check.DeepEquals, &apiv1.Probe{
+		PeriodSeconds: 20,
+		Handler: apiv1.Handler{
+			TCPSocket: &apiv1.TCPSocketAction{
+				Port: intstr.FromInt(8888),
+			},
+		},
+	})
+	c.Assert(container.StartupProbe, check.DeepEquals, &apiv1.Probe{
+		PeriodSeconds:    10,
+		FailureThreshold: 30,
+		Handler: apiv1.Handler{
+			HTTPGet: &apiv1.HTTPGetAction{
+				Path:        "/started",
+				Port:        intstr.FromInt(8888),
+				Scheme:      apiv1.URISchemeHTTPS,
+				HTTPHeaders: []apiv1.HTTPHeader{{Name: "Host", Value: "myapp.com"}},
+			},
+		},
+	})
+	dep, err = s.client.Clientset.AppsV1().Deployments(nsName).Get(context.TODO(), "myapp-worker", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	container = dep.Spec.Template.Spec.Containers[0]
+	c.Assert(container.ReadinessProbe, check.DeepEquals, &apiv1.Probe{
+		Handler: apiv1.Handler{
+			Exec: &apiv1.ExecAction{
+				Command: []string{"/bin/sh", "-c", "cat /tmp/ready"},
+			},
+		},
+	})
+	c.Assert(container.LivenessProbe, check.IsNil)
+	c.Assert(container.StartupProbe, check.IsNil)
+}
+
+func (s *S) TestServiceManagerDeployServiceWithSidecars(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	err = pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: a.Pool, Field: pool.ConstraintTypeSidecarImage, Values: []string{"envoyproxy/*", "busybox"}})
+	c.Assert(err, check.IsNil)
+	s.clusterClient.CustomData[a.Pool+":"+sidecarsKey] = `[{"name": "logger", "image": "tsuru/logger"}]`
+	defer delete(s.clusterClient.CustomData, a.Pool+":"+sidecarsKey)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "cm1",
+		},
+		"kubernetes": provTypes.TsuruYamlKubernetesConfig{
+			Groups: map[string]provTypes.TsuruYamlKubernetesGroup{
+				"pod1": {
+					"web": provTypes.TsuruYamlKubernetesProcessConfig{
+						SharedVolumes: []provTypes.TsuruYamlKubernetesSharedVolume{
+							{Name: "config", MountPath: "/etc/app"},
+						},
+						InitContainers: []provTypes.TsuruYamlKubernetesContainer{
+							{
+								Name:         "setup",
+								Image:        "busybox",
+								Command:      []string{"sh", "-c", "echo ok > /config/ready"},
+								VolumeMounts: []provTypes.TsuruYamlKubernetesVolumeMount{{Name: "config", MountPath: "/config"}},
+							},
+						},
+						Sidecars: []provTypes.TsuruYamlKubernetesContainer{
+							{
+								Name:  "proxy",
+								Image: "envoyproxy/envoy:v1.16",
+								Args:  []string{"-c", "/etc/envoy.yaml"},
+								Env:   map[string]string{"B": "2", "A": "1"},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	nsName, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(nsName).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	podSpec := dep.Spec.Template.Spec
+	c.Assert(podSpec.Volumes, check.DeepEquals, []apiv1.Volume{
+		{Name: "config", VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}}},
+	})
+	resources := apiv1.ResourceRequirements{
+		Limits: apiv1.ResourceList{
+			apiv1.ResourceEphemeralStorage: defaultEphemeralStorageLimit,
+		},
+		Requests: apiv1.ResourceList{
+			apiv1.ResourceEphemeralStorage: *resource.NewQuantity(0, resource.DecimalSI),
+		},
+	}
+	c.Assert(podSpec.InitContainers, check.DeepEquals, []apiv1.Container{
+		{
+			Name:         "setup",
+			Image:        "busybox",
+			Command:      []string{"sh", "-c", "echo ok > /config/ready"},
+			VolumeMounts: []apiv1.VolumeMount{{Name: "config", MountPath: "/config"}},
+			Resources:    resources,
+		},
+	})
+	c.Assert(podSpec.Containers, check.HasLen, 3)
+	c.Assert(podSpec.Containers[0].Name, check.Equals, "myapp-web")
+	c.Assert(podSpec.Containers[0].VolumeMounts, check.DeepEquals, []apiv1.VolumeMount{{Name: "config", MountPath: "/etc/app"}})
+	c.Assert(podSpec.Containers[1:], check.DeepEquals, []apiv1.Container{
+		{Name: "logger", Image: "tsuru/logger", Resources: resources},
+		{
+			Name:      "proxy",
+			Image:     "envoyproxy/envoy:v1.16",
+			Args:      []string{"-c", "/etc/envoy.yaml"},
+			Env:       []apiv1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+			Resources: resources,
+		},
+	})
+}
+
+func (s *S) TestServiceManagerDeployServiceWithSidecarsPlanResources(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	a.Plan = appTypes.Plan{Memory: 1024, CPUMilli: 500}
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "cm1",
+		},
+		"kubernetes": provTypes.TsuruYamlKubernetesConfig{
+			Groups: map[string]provTypes.TsuruYamlKubernetesGroup{
+				"pod1": {
+					"web": provTypes.TsuruYamlKubernetesProcessConfig{
+						Sidecars: []provTypes.TsuruYamlKubernetesContainer{
+							{Name: "proxy", Image: "envoyproxy/envoy:v1.16"},
+						},
+					},
+				},
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	nsName, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(nsName).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	containers := dep.Spec.Template.Spec.Containers
+	c.Assert(containers, check.HasLen, 2)
+	c.Assert(containers[1].Name, check.Equals, "proxy")
+	c.Assert(containers[1].Resources, check.DeepEquals, containers[0].Resources)
+	c.Assert(containers[1].Resources.Limits.Memory().Value(), check.Equals, int64(1024))
+	c.Assert(containers[1].Resources.Limits.Cpu().MilliValue(), check.Equals, int64(500))
+}
+
+func (s *S) TestServiceManagerDeployServiceWithSidecarImageNotAllowed(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	err = pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: a.Pool, Field: pool.ConstraintTypeSidecarImage, Values: []string{"busybox"}})
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "cm1",
+		},
+		"kubernetes": provTypes.TsuruYamlKubernetesConfig{
+			Groups: map[string]provTypes.TsuruYamlKubernetesGroup{
+				"pod1": {
+					"web": provTypes.TsuruYamlKubernetesProcessConfig{
+						Sidecars: []provTypes.TsuruYamlKubernetesContainer{
+							{Name: "proxy", Image: "envoyproxy/envoy:v1.16"},
+						},
+					},
+				},
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.ErrorMatches, `(?s).*image "envoyproxy/envoy:v1.16" is not allowed for sidecars on pool "test-default".*`)
+}
+
+func (s *S) TestServiceManagerDeployServiceWithDisruptionBudget(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	s.clusterClient.CustomData[pdbMaxUnavailableKey] = "25%"
+	s.clusterClient.CustomData[a.Pool+":"+topologySpreadZoneKey] = "hard"
+	s.clusterClient.CustomData[topologySpreadNodeKey] = "soft"
+	defer func() {
+		delete(s.clusterClient.CustomData, pdbMaxUnavailableKey)
+		delete(s.clusterClient.CustomData, a.Pool+":"+topologySpreadZoneKey)
+		delete(s.clusterClient.CustomData, topologySpreadNodeKey)
+	}()
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web":    "cm1",
+			"worker": "cm2",
+		},
+		"kubernetes": provTypes.TsuruYamlKubernetesConfig{
+			Groups: map[string]provTypes.TsuruYamlKubernetesGroup{
+				"pod1": {
+					"worker": provTypes.TsuruYamlKubernetesProcessConfig{
+						DisruptionBudget: &provTypes.TsuruYamlKubernetesDisruptionBudget{MinAvailable: "1"},
+						TopologySpread:   &provTypes.TsuruYamlKubernetesTopologySpread{Zone: "none"},
+					},
+				},
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web":    servicecommon.ProcessState{Start: true},
+		"worker": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	nsName, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	pdb, err := s.client.PolicyV1beta1().PodDisruptionBudgets(nsName).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	maxUnavailable := intstr.FromString("25%")
+	c.Assert(pdb.Spec, check.DeepEquals, policyv1beta1.PodDisruptionBudgetSpec{
+		MaxUnavailable: &maxUnavailable,
+		Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "myapp-web"}},
+	})
+	c.Assert(pdb.Labels["tsuru.io/app-process"], check.Equals, "web")
+	pdb, err = s.client.PolicyV1beta1().PodDisruptionBudgets(nsName).Get(context.TODO(), "myapp-worker", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	minAvailable := intstr.FromInt(1)
+	c.Assert(pdb.Spec.MinAvailable, check.DeepEquals, &minAvailable)
+	c.Assert(pdb.Spec.MaxUnavailable, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(nsName).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "myapp-web"}}
+	c.Assert(dep.Spec.Template.Spec.TopologySpreadConstraints, check.DeepEquals, []apiv1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: apiv1.DoNotSchedule, LabelSelector: selector},
+		{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: apiv1.ScheduleAnyway, LabelSelector: selector},
+	})
+	dep, err = s.client.Clientset.AppsV1().Deployments(nsName).Get(context.TODO(), "myapp-worker", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.TopologySpreadConstraints, check.DeepEquals, []apiv1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: apiv1.ScheduleAnyway, LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "myapp-worker"}}},
+	})
+	err = cleanupDisruptionBudgets(context.TODO(), s.clusterClient, nsName, a, map[string]struct{}{"web": {}})
+	c.Assert(err, check.IsNil)
+	_, err = s.client.PolicyV1beta1().PodDisruptionBudgets(nsName).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	_, err = s.client.PolicyV1beta1().PodDisruptionBudgets(nsName).Get(context.TODO(), "myapp-worker", metav1.GetOptions{})
+	c.Assert(k8sErrors.IsNotFound(err), check.Equals, true)
+}
+
+func (s *S) TestDisruptionBudgetInvalidClusterConfig(c *check.C) {
+	s.clusterClient.CustomData[pdbMaxUnavailableKey] = "1"
+	s.clusterClient.CustomData[pdbMinAvailableKey] = "1"
+	defer func() {
+		delete(s.clusterClient.CustomData, pdbMaxUnavailableKey)
+		delete(s.clusterClient.CustomData, pdbMinAvailableKey)
+	}()
+	_, err := s.clusterClient.disruptionBudget("mypool")
+	c.Assert(err, check.ErrorMatches, "misconfigured cluster pod disruption budget: exactly one of max_unavailable or min_available must be set")
+}
+
+func (s *S) TestProbesForProcessTCPWithoutPort(c *check.C) {
+	yamlData := provTypes.TsuruYamlData{
+		Probes: map[string]provTypes.TsuruYamlProbes{
+			"worker": {
+				Liveness: &provTypes.TsuruYamlProbe{TCP: &provTypes.TsuruYamlProbeTCP{}},
+			},
+		},
+	}
+	_, err := probesForProcess(yamlData, "worker", "web", nil)
+	c.Assert(err, check.ErrorMatches, `liveness probe for process "worker": tcp port is required for processes without ports`)
+}
+
 func (s *S) TestServiceManagerDeployServiceWithRestartHooks(c *check.C) {
 	waitDep := s.mock.DeploymentReactions(c)
 	defer waitDep()
@@ -1328,6 +1698,60 @@ func (s *S) TestServiceManagerDeployServiceWithRegistryAuth(c *check.C) {
 	})
 }
 
+func (s *S) TestGetAppImagePullSecretsWithTeamCredential(c *check.C) {
+	config.Set("docker:registry", "myreg.com")
+	defer config.Unset("docker:registry")
+	s.mockService.RegistryCredential.OnFindForImage = func(teams []string, image string) (*registryTypes.Credential, error) {
+		c.Assert(teams, check.DeepEquals, []string{"myteam"})
+		if registryTypes.ImageRegistry(image) != "private.io:5000" {
+			return nil, registryTypes.ErrCredentialNotFound
+		}
+		return &registryTypes.Credential{Team: "myteam", Registry: "private.io:5000", Username: "bot", Token: "secret"}, nil
+	}
+	defer s.mockService.ResetRegistryCredential()
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	a.TeamOwner = "myteam"
+	pullSecrets, err := getAppImagePullSecrets(context.TODO(), s.clusterClient, "default", a, "myreg.com/tsuru/app-myapp:v1", "private.io:5000/myimage:v1", "private.io:5000/other:v1", "docker.io/library/busybox")
+	c.Assert(err, check.IsNil)
+	c.Assert(pullSecrets, check.DeepEquals, []apiv1.LocalObjectReference{
+		{Name: "registry-myreg.com"},
+		{Name: "registry-team-myteam-private.io-5000"},
+	})
+	secret, err := s.client.CoreV1().Secrets("default").Get(context.TODO(), "registry-team-myteam-private.io-5000", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(secret.Type, check.Equals, apiv1.SecretTypeDockerConfigJson)
+	c.Assert(string(secret.Data[apiv1.DockerConfigJsonKey]), check.Equals, `{"auths":{"private.io:5000":{"username":"bot","password":"secret","auth":"Ym90OnNlY3JldA=="}}}`)
+}
+
+func (s *S) TestRemoveRegistryCredential(c *check.C) {
+	for _, secret := range []struct{ ns, name string }{
+		{"default", "registry-team-myteam-private.io-5000"},
+		{"tsuru-pool1", "registry-team-myteam-private.io-5000"},
+		{"default", "registry-team-otherteam-private.io-5000"},
+		{"default", "registry-team-myteam-other.io"},
+	} {
+		_, err := s.client.CoreV1().Secrets(secret.ns).Create(context.TODO(), &apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secret.name, Namespace: secret.ns},
+		}, metav1.CreateOptions{})
+		c.Assert(err, check.IsNil)
+	}
+	err := s.p.RemoveRegistryCredential(context.TODO(), "myteam", "private.io:5000")
+	c.Assert(err, check.IsNil)
+	secrets, err := s.client.CoreV1().Secrets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	var names []string
+	for _, secret := range secrets.Items {
+		if strings.HasPrefix(secret.Name, "registry-team-") {
+			names = append(names, secret.Namespace+"/"+secret.Name)
+		}
+	}
+	sort.Strings(names)
+	c.Assert(names, check.DeepEquals, []string{
+		"default/registry-team-myteam-other.io",
+		"default/registry-team-otherteam-private.io-5000",
+	})
+}
+
 func (s *S) TestServiceManagerDeployServiceProgressMessages(c *check.C) {
 	waitDep := s.mock.DeploymentReactions(c)
 	defer waitDep()
@@ -1685,6 +2109,45 @@ func (s *S) TestServiceManagerDeployServiceWithResourceRequirements(c *check.C)
 	})
 }
 
+func (s *S) TestServiceManagerDeployServiceWithProcessPlan(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	a.Plan = appTypes.Plan{Memory: 1024}
+	a.ProcessPlans = map[string]appTypes.Plan{
+		"p2": {Name: "small", Memory: 512, CPUMilli: 100},
+	}
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"p1": "cm1",
+			"p2": "cm2",
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"p1": servicecommon.ProcessState{Start: true},
+		"p2": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-p1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.Containers[0].Resources.Limits[apiv1.ResourceMemory], check.DeepEquals, *resource.NewQuantity(1024, resource.BinarySI))
+	_, hasCPU := dep.Spec.Template.Spec.Containers[0].Resources.Limits[apiv1.ResourceCPU]
+	c.Assert(hasCPU, check.Equals, false)
+	dep, err = s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-p2", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.Containers[0].Resources.Limits[apiv1.ResourceMemory], check.DeepEquals, *resource.NewQuantity(512, resource.BinarySI))
+	c.Assert(dep.Spec.Template.Spec.Containers[0].Resources.Limits[apiv1.ResourceCPU], check.DeepEquals, *resource.NewMilliQuantity(100, resource.DecimalSI))
+}
+
 func (s *S) TestServiceManagerDeployServiceWithClusterWideOvercommitFactor(c *check.C) {
 	waitDep := s.mock.DeploymentReactions(c)
 	defer waitDep()
@@ -153,6 +153,10 @@ func registrySecretName(registry string) string {
 	return fmt.Sprintf("registry-%s", registry)
 }
 
+func teamRegistrySecretName(team, registry string) string {
+	return fmt.Sprintf("registry-team-%s-%s", validKubeName(team), validKubeName(registry))
+}
+
 func waitFor(ctx context.Context, fn func() (bool, error), onCancel func() error) error {
 	start := time.Now()
 	for {
@@ -435,6 +439,15 @@ func podContainerNames(pod *apiv1.Pod) []string {
 	return names
 }
 
+// appContainerName returns the name of the container running the app in a
+// unit, sidecars are always added after it.
+func appContainerName(pod *apiv1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	return pod.Spec.Containers[0].Name
+}
+
 func waitForPod(ctx context.Context, client *ClusterClient, origPod *apiv1.Pod, namespace string, returnOnRunning bool) error {
 	validContSet := set.FromSlice(podContainerNames(origPod))
 	return waitFor(ctx, func() (bool, error) {
@@ -1069,6 +1082,8 @@ type runSinglePodArgs struct {
 	name         string
 	image        string
 	app          provision.App
+	// architectures are the architectures the image is available for.
+	architectures []string
 }
 
 func runPod(ctx context.Context, args runSinglePodArgs) error {
@@ -1122,6 +1137,11 @@ func runPod(ctx context.Context, args runSinglePodArgs) error {
 			},
 		},
 	}
+	err = applySchedulingPolicy(args.client, args.app.GetPool(), &pod.Spec)
+	if err != nil {
+		return err
+	}
+	applyArchitectures(&pod.Spec, args.architectures)
 
 	var initialResource string
 	if args.eventsOutput != nil {
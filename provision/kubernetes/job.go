@@ -0,0 +1,568 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/dockercommon"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	jobCommandAnnotation       = tsuruLabelPrefix + "job-command"
+	jobProcessAnnotation       = tsuruLabelPrefix + "job-process"
+	jobVersionAnnotation       = tsuruLabelPrefix + "job-version"
+	jobPinnedVersionAnnotation = tsuruLabelPrefix + "job-pinned-version"
+	jobRunRecordedAnnotation   = tsuruLabelPrefix + "job-run-recorded"
+
+	jobRunEventKind = "job run"
+	jobHistoryLimit = int32(10)
+	jobAppDir       = "/home/application/current"
+
+	// Kubernetes appends 11 characters to the name of the cron job when
+	// naming its jobs, which must fit in a label value.
+	cronJobNameMaxLen = 52
+)
+
+var errNoJobVersion = errors.New("no successful version found for app, at least one deploy is required before adding jobs")
+
+func cronJobNameForApp(a provision.App, job string) string {
+	name := fmt.Sprintf("%s-job-%s", validKubeName(a.GetName()), job)
+	if len(name) <= cronJobNameMaxLen {
+		return name
+	}
+	h := sha256.New()
+	h.Write([]byte(name))
+	hash := fmt.Sprintf("%x", h.Sum(nil))[:10]
+	prefix := strings.TrimRight(name[:cronJobNameMaxLen-len(hash)-1], "-.")
+	return fmt.Sprintf("%s-%s", prefix, hash)
+}
+
+func jobCmds(cmd string) []string {
+	source := "[ -f /home/application/apprc ] && source /home/application/apprc"
+	cd := fmt.Sprintf("[ -d %s ] && cd %s", jobAppDir, jobAppDir)
+	return []string{"/bin/sh", "-c", fmt.Sprintf("%s; %s; %s", source, cd, cmd)}
+}
+
+// jobLabels returns the labels of the cron jobs of the app and their pods. The
+// process and version of the job are left out, so that job pods are never
+// selected by the services of the app, and kept in annotations instead.
+func jobLabels(ctx context.Context, a provision.App, job string) (*provision.LabelSet, error) {
+	ls, err := provision.ServiceLabels(ctx, provision.ServiceLabelsOpts{
+		App: a,
+		ServiceLabelExtendedOpts: provision.ServiceLabelExtendedOpts{
+			Prefix:      tsuruLabelPrefix,
+			Provisioner: provisionerName,
+		},
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ls.SetJob(job)
+	return ls, nil
+}
+
+func jobSelector(ctx context.Context, a provision.App, job string) (string, error) {
+	ls, err := jobLabels(ctx, a, job)
+	if err != nil {
+		return "", err
+	}
+	return labels.SelectorFromSet(labels.Set(ls.ToJobSelector())).String(), nil
+}
+
+func (p *kubernetesProvisioner) ListJobs(ctx context.Context, a provision.App) ([]provision.JobSpec, error) {
+	client, err := clusterForPool(ctx, a.GetPool())
+	if err != nil {
+		return nil, err
+	}
+	cronJobs, err := cronJobsForApp(ctx, client, a, "")
+	if err != nil {
+		return nil, err
+	}
+	specs := make([]provision.JobSpec, len(cronJobs))
+	for i := range cronJobs {
+		specs[i] = cronJobToSpec(&cronJobs[i])
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+	return specs, nil
+}
+
+func cronJobsForApp(ctx context.Context, client *ClusterClient, a provision.App, job string) ([]batchv1beta1.CronJob, error) {
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := jobSelector(ctx, a, job)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.BatchV1beta1().CronJobs(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return list.Items, nil
+}
+
+func cronJobToSpec(cronJob *batchv1beta1.CronJob) provision.JobSpec {
+	ls := labelSetFromMeta(&cronJob.ObjectMeta)
+	spec := provision.JobSpec{
+		Name:              ls.JobName(),
+		Schedule:          cronJob.Spec.Schedule,
+		Command:           cronJob.Annotations[jobCommandAnnotation],
+		Process:           cronJob.Annotations[jobProcessAnnotation],
+		ConcurrencyPolicy: concurrencyPolicyToSpec(cronJob.Spec.ConcurrencyPolicy),
+	}
+	spec.Version, _ = strconv.Atoi(cronJob.Annotations[jobPinnedVersionAnnotation])
+	if deadline := cronJob.Spec.JobTemplate.Spec.ActiveDeadlineSeconds; deadline != nil {
+		spec.Timeout = *deadline
+	}
+	return spec
+}
+
+func concurrencyPolicyToSpec(policy batchv1beta1.ConcurrencyPolicy) string {
+	switch policy {
+	case batchv1beta1.ForbidConcurrent:
+		return provision.JobConcurrencyForbid
+	case batchv1beta1.ReplaceConcurrent:
+		return provision.JobConcurrencyReplace
+	}
+	return provision.JobConcurrencyAllow
+}
+
+func concurrencyPolicyFromSpec(policy string) batchv1beta1.ConcurrencyPolicy {
+	switch policy {
+	case provision.JobConcurrencyForbid:
+		return batchv1beta1.ForbidConcurrent
+	case provision.JobConcurrencyReplace:
+		return batchv1beta1.ReplaceConcurrent
+	}
+	return batchv1beta1.AllowConcurrent
+}
+
+func (p *kubernetesProvisioner) SetJob(ctx context.Context, a provision.App, spec provision.JobSpec) error {
+	members, err := membersForPool(ctx, a.GetPool())
+	if err != nil {
+		return err
+	}
+	var version appTypes.AppVersion
+	if spec.Version == 0 {
+		version, err = servicemanager.AppVersion.LatestSuccessfulVersion(ctx, a)
+		if err == appTypes.ErrNoVersionsAvailable {
+			return errNoJobVersion
+		}
+	} else {
+		version, err = servicemanager.AppVersion.VersionByImageOrVersion(ctx, a, strconv.Itoa(spec.Version))
+	}
+	if err != nil {
+		return err
+	}
+	scheduled := jobMember(members)
+	for _, client := range members.active() {
+		err = setJob(ctx, client, a, spec, version, client != scheduled)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to set job in cluster %q", client.Name)
+		}
+	}
+	return nil
+}
+
+func setJob(ctx context.Context, client *ClusterClient, a provision.App, spec provision.JobSpec, version appTypes.AppVersion, suspend bool) error {
+	err := ensureNamespaceForApp(ctx, client, a)
+	if err != nil {
+		return err
+	}
+	err = ensureServiceAccountForApp(ctx, client, a)
+	if err != nil {
+		return err
+	}
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	ls, err := jobLabels(ctx, a, spec.Name)
+	if err != nil {
+		return err
+	}
+	image := version.VersionInfo().DeployImageReference()
+	pullSecrets, err := getAppImagePullSecrets(ctx, client, ns, a, image)
+	if err != nil {
+		return err
+	}
+	singlePool, err := client.SinglePool()
+	if err != nil {
+		return errors.WithMessage(err, "misconfigured cluster single pool value")
+	}
+	nodeSelector := provision.NodeLabels(provision.NodeLabelsOpts{
+		Pool:   a.GetPool(),
+		Prefix: tsuruLabelPrefix,
+	}).ToNodeByPoolSelector()
+	if singlePool {
+		nodeSelector = map[string]string{}
+	}
+	resources, err := resourceRequirementsForApp(client, a, spec.Process)
+	if err != nil {
+		return err
+	}
+	volumes, mounts, err := createVolumesForApp(ctx, client, a)
+	if err != nil {
+		return err
+	}
+	_, uid := dockercommon.UserForContainer()
+	jobAnnotations := map[string]string{
+		jobProcessAnnotation: spec.Process,
+		jobVersionAnnotation: strconv.Itoa(version.Version()),
+	}
+	annotations := map[string]string{
+		jobCommandAnnotation: spec.Command,
+	}
+	for k, v := range jobAnnotations {
+		annotations[k] = v
+	}
+	if spec.Version != 0 {
+		annotations[jobPinnedVersionAnnotation] = strconv.Itoa(spec.Version)
+	}
+	var deadline *int64
+	if spec.Timeout > 0 {
+		deadline = &spec.Timeout
+	}
+	historyLimit := jobHistoryLimit
+	serviceLinks := false
+	backoffLimit := int32(0)
+	name := cronJobNameForApp(a, spec.Name)
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   ns,
+			Labels:      ls.ToLabels(),
+			Annotations: annotations,
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   spec.Schedule,
+			Suspend:                    &suspend,
+			ConcurrencyPolicy:          concurrencyPolicyFromSpec(spec.ConcurrencyPolicy),
+			SuccessfulJobsHistoryLimit: &historyLimit,
+			FailedJobsHistoryLimit:     &historyLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      ls.ToLabels(),
+					Annotations: jobAnnotations,
+				},
+				Spec: batchv1.JobSpec{
+					ActiveDeadlineSeconds: deadline,
+					BackoffLimit:          &backoffLimit,
+					Template: apiv1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels:      ls.ToLabels(),
+							Annotations: jobAnnotations,
+						},
+						Spec: apiv1.PodSpec{
+							EnableServiceLinks: &serviceLinks,
+							ImagePullSecrets:   pullSecrets,
+							ServiceAccountName: serviceAccountNameForApp(a),
+							SecurityContext: &apiv1.PodSecurityContext{
+								RunAsUser: uid,
+							},
+							RestartPolicy: apiv1.RestartPolicyNever,
+							NodeSelector:  nodeSelector,
+							Volumes:       volumes,
+							Containers: []apiv1.Container{
+								{
+									Name:         name,
+									Image:        image,
+									Command:      jobCmds(spec.Command),
+									Env:          appEnvs(a, spec.Process, version, false),
+									Resources:    resources,
+									VolumeMounts: mounts,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	err = applySchedulingPolicy(client, a.GetPool(), &cronJob.Spec.JobTemplate.Spec.Template.Spec)
+	if err != nil {
+		return err
+	}
+	applyArchitectures(&cronJob.Spec.JobTemplate.Spec.Template.Spec, version.VersionInfo().Architectures)
+	existing, err := client.BatchV1beta1().CronJobs(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+		_, err = client.BatchV1beta1().CronJobs(ns).Create(ctx, cronJob, metav1.CreateOptions{})
+		return errors.WithStack(err)
+	}
+	cronJob.ResourceVersion = existing.ResourceVersion
+	_, err = client.BatchV1beta1().CronJobs(ns).Update(ctx, cronJob, metav1.UpdateOptions{})
+	return errors.WithStack(err)
+}
+
+func (p *kubernetesProvisioner) RemoveJob(ctx context.Context, a provision.App, name string) error {
+	members, err := membersForPool(ctx, a.GetPool())
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, client := range members.all() {
+		ns, err := client.AppNamespace(ctx, a)
+		if err != nil {
+			return err
+		}
+		err = client.BatchV1beta1().CronJobs(ns).Delete(ctx, cronJobNameForApp(a, name), metav1.DeleteOptions{
+			PropagationPolicy: propagationPtr(metav1.DeletePropagationBackground),
+		})
+		if k8sErrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		found = true
+	}
+	if !found {
+		return provision.ErrJobNotFound
+	}
+	return nil
+}
+
+func (p *kubernetesProvisioner) ListJobRuns(ctx context.Context, a provision.App, name string) ([]provision.JobRun, error) {
+	members, err := membersForPool(ctx, a.GetPool())
+	if err != nil {
+		return nil, err
+	}
+	var runs []provision.JobRun
+	found := false
+	for _, client := range members.all() {
+		clusterRuns, err := jobRunsForCluster(ctx, client, a, name)
+		if err == provision.ErrJobNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		runs = append(runs, clusterRuns...)
+	}
+	if !found {
+		return nil, provision.ErrJobNotFound
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartTime.After(runs[j].StartTime)
+	})
+	return runs, nil
+}
+
+func jobRunsForCluster(ctx context.Context, client *ClusterClient, a provision.App, name string) ([]provision.JobRun, error) {
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	_, err = client.BatchV1beta1().CronJobs(ns).Get(ctx, cronJobNameForApp(a, name), metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, provision.ErrJobNotFound
+		}
+		return nil, errors.WithStack(err)
+	}
+	selector, err := jobSelector(ctx, a, name)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := client.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pods, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	runs := make([]provision.JobRun, len(jobs.Items))
+	for i := range jobs.Items {
+		runs[i] = jobToRun(&jobs.Items[i], pods.Items)
+	}
+	return runs, nil
+}
+
+// jobToRun converts a kubernetes job created by a cron job into a job run.
+// The exit code is taken from the last pod created by the job.
+func jobToRun(job *batchv1.Job, pods []apiv1.Pod) provision.JobRun {
+	ls := labelSetFromMeta(&job.ObjectMeta)
+	version, _ := strconv.Atoi(job.Annotations[jobVersionAnnotation])
+	run := provision.JobRun{
+		Name:      job.Name,
+		Job:       ls.JobName(),
+		Version:   version,
+		Status:    provision.JobRunRunning,
+		StartTime: job.CreationTimestamp.Time,
+	}
+	if job.Status.StartTime != nil {
+		run.StartTime = job.Status.StartTime.Time
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != apiv1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			run.Status = provision.JobRunSucceeded
+		case batchv1.JobFailed:
+			run.Status = provision.JobRunFailed
+		default:
+			continue
+		}
+		endTime := cond.LastTransitionTime.Time
+		run.EndTime = &endTime
+	}
+	if job.Status.CompletionTime != nil {
+		endTime := job.Status.CompletionTime.Time
+		run.EndTime = &endTime
+	}
+	var lastPod *apiv1.Pod
+	for i := range pods {
+		if pods[i].Labels["job-name"] != job.Name {
+			continue
+		}
+		if lastPod == nil || lastPod.CreationTimestamp.Before(&pods[i].CreationTimestamp) {
+			lastPod = &pods[i]
+		}
+	}
+	if lastPod != nil {
+		for _, status := range lastPod.Status.ContainerStatuses {
+			if status.State.Terminated != nil {
+				exitCode := status.State.Terminated.ExitCode
+				run.ExitCode = &exitCode
+			}
+		}
+	}
+	return run
+}
+
+// updateJobsVersion points the jobs following the latest version of the app
+// to the version just deployed.
+func updateJobsVersion(ctx context.Context, client *ClusterClient, a provision.App, version appTypes.AppVersion) error {
+	cronJobs, err := cronJobsForApp(ctx, client, a, "")
+	if err != nil {
+		return err
+	}
+	for i := range cronJobs {
+		spec := cronJobToSpec(&cronJobs[i])
+		if spec.Version != 0 {
+			continue
+		}
+		suspended := cronJobs[i].Spec.Suspend != nil && *cronJobs[i].Spec.Suspend
+		err = setJob(ctx, client, a, spec, version, suspended)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeJobsForAppNS(ctx context.Context, client *ClusterClient, ns string, a provision.App) error {
+	selector, err := jobSelector(ctx, a, "")
+	if err != nil {
+		return err
+	}
+	err = client.BatchV1beta1().CronJobs(ns).DeleteCollection(ctx, metav1.DeleteOptions{
+		PropagationPolicy: propagationPtr(metav1.DeletePropagationBackground),
+	}, metav1.ListOptions{LabelSelector: selector})
+	return errors.WithStack(err)
+}
+
+// recordJobRun creates an event and adds a line to the app log for each
+// finished job run. Jobs are annotated once recorded, so each run is only
+// recorded once, even after the controller restarts.
+func recordJobRun(ctx context.Context, client *ClusterClient, job *batchv1.Job) error {
+	ls := labelSetFromMeta(&job.ObjectMeta)
+	if !ls.IsJob() || job.Annotations[jobRunRecordedAnnotation] == "true" {
+		return nil
+	}
+	pods, err := client.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"job-name": job.Name}).String(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	run := jobToRun(job, pods.Items)
+	if run.Status == provision.JobRunRunning {
+		return nil
+	}
+	recorded := job.DeepCopy()
+	if recorded.Annotations == nil {
+		recorded.Annotations = map[string]string{}
+	}
+	recorded.Annotations[jobRunRecordedAnnotation] = "true"
+	_, err = client.BatchV1().Jobs(job.Namespace).Update(ctx, recorded, metav1.UpdateOptions{})
+	if err != nil {
+		if k8sErrors.IsConflict(err) || k8sErrors.IsNotFound(err) {
+			// the run will be recorded when the newer version of the job is
+			// received by the informer.
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	appName := ls.AppName()
+	msg := fmt.Sprintf("job %q run %q succeeded", run.Job, run.Name)
+	var runErr error
+	if run.Status == provision.JobRunFailed {
+		msg = fmt.Sprintf("job %q run %q failed", run.Job, run.Name)
+		if run.ExitCode != nil {
+			msg = fmt.Sprintf("%s with exit code %d", msg, *run.ExitCode)
+		} else if reason := jobFailureReason(job); reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, reason)
+		}
+		runErr = errors.New(msg)
+	}
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: appName},
+		InternalKind: jobRunEventKind,
+		CustomData:   run,
+		DisableLock:  true,
+		Allowed:      event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, appName)),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	evt.Logf("%s", msg)
+	err = evt.DoneCustomData(runErr, run)
+	if err != nil {
+		return err
+	}
+	return servicemanager.AppLog.Add(appName, msg, "tsuru", run.Name)
+}
+
+func jobFailureReason(job *batchv1.Job) string {
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == apiv1.ConditionTrue {
+			if cond.Message != "" {
+				return cond.Message
+			}
+			return cond.Reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	check "gopkg.in/check.v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) TestProvisionerSetJob(c *check.C) {
+	a, _, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	version := newSuccessfulVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	})
+	err := s.p.SetJob(context.TODO(), a, provision.JobSpec{
+		Name:              "cleanup",
+		Schedule:          "*/5 * * * *",
+		Command:           "python cleanup.py",
+		Process:           "web",
+		ConcurrencyPolicy: provision.JobConcurrencyForbid,
+		Timeout:           60,
+	})
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	cronJob, err := s.client.BatchV1beta1().CronJobs(ns).Get(context.TODO(), "myapp-job-cleanup", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJob.Spec.Schedule, check.Equals, "*/5 * * * *")
+	c.Assert(cronJob.Spec.ConcurrencyPolicy, check.Equals, batchv1beta1.ForbidConcurrent)
+	c.Assert(cronJob.Labels["tsuru.io/is-job"], check.Equals, "true")
+	c.Assert(cronJob.Labels["tsuru.io/job-name"], check.Equals, "cleanup")
+	jobSpec := cronJob.Spec.JobTemplate.Spec
+	c.Assert(*jobSpec.ActiveDeadlineSeconds, check.Equals, int64(60))
+	podLabels := jobSpec.Template.Labels
+	c.Assert(podLabels["tsuru.io/app-process"], check.Equals, "")
+	_, ok := podLabels["tsuru.io/app-version"]
+	c.Assert(ok, check.Equals, false)
+	c.Assert(jobSpec.Template.Annotations, check.DeepEquals, map[string]string{
+		"tsuru.io/job-process": "web",
+		"tsuru.io/job-version": strconv.Itoa(version.Version()),
+	})
+	c.Assert(cronJob.Spec.JobTemplate.Annotations, check.DeepEquals, jobSpec.Template.Annotations)
+	podSpec := jobSpec.Template.Spec
+	c.Assert(podSpec.RestartPolicy, check.Equals, apiv1.RestartPolicyNever)
+	c.Assert(podSpec.Containers, check.HasLen, 1)
+	c.Assert(podSpec.Containers[0].Image, check.Equals, version.VersionInfo().DeployImage)
+	c.Assert(podSpec.Containers[0].Command, check.DeepEquals, jobCmds("python cleanup.py"))
+	jobs, err := s.p.ListJobs(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.DeepEquals, []provision.JobSpec{
+		{
+			Name:              "cleanup",
+			Schedule:          "*/5 * * * *",
+			Command:           "python cleanup.py",
+			Process:           "web",
+			ConcurrencyPolicy: provision.JobConcurrencyForbid,
+			Timeout:           60,
+		},
+	})
+}
+
+func (s *S) TestCronJobNameForApp(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	c.Assert(cronJobNameForApp(a, "cleanup"), check.Equals, "myapp-job-cleanup")
+	a = provisiontest.NewFakeApp("my-app-with-a-very-long-name-reaching-forty", "python", 0)
+	name := cronJobNameForApp(a, "cleanup-of-old-records-daily")
+	c.Assert(len(name) <= 52, check.Equals, true)
+	c.Assert(name, check.Matches, `my-app-with-a-very-long-name-reaching-for-[0-9a-f]{10}`)
+	c.Assert(cronJobNameForApp(a, "cleanup-of-old-records-weekly"), check.Not(check.Equals), name)
+}
+
+func (s *S) TestProvisionerSetJobNoVersion(c *check.C) {
+	a, _, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	err := s.p.SetJob(context.TODO(), a, provision.JobSpec{
+		Name:     "cleanup",
+		Schedule: "@daily",
+		Command:  "python cleanup.py",
+	})
+	c.Assert(err, check.Equals, errNoJobVersion)
+}
+
+func (s *S) TestProvisionerRemoveJob(c *check.C) {
+	a, _, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	newSuccessfulVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	})
+	err := s.p.SetJob(context.TODO(), a, provision.JobSpec{
+		Name:     "cleanup",
+		Schedule: "@daily",
+		Command:  "python cleanup.py",
+	})
+	c.Assert(err, check.IsNil)
+	err = s.p.RemoveJob(context.TODO(), a, "cleanup")
+	c.Assert(err, check.IsNil)
+	jobs, err := s.p.ListJobs(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 0)
+	err = s.p.RemoveJob(context.TODO(), a, "cleanup")
+	c.Assert(err, check.Equals, provision.ErrJobNotFound)
+}
+
+func (s *S) TestJobToRun(c *check.C) {
+	start := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myapp-job-cleanup-123",
+			Labels: map[string]string{
+				"tsuru.io/app-name": "myapp",
+				"tsuru.io/is-job":   "true",
+				"tsuru.io/job-name": "cleanup",
+			},
+			Annotations: map[string]string{
+				"tsuru.io/job-version": "2",
+			},
+		},
+		Status: batchv1.JobStatus{
+			StartTime: &metav1.Time{Time: start},
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: apiv1.ConditionTrue, LastTransitionTime: metav1.Time{Time: end}},
+			},
+		},
+	}
+	pods := []apiv1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:            map[string]string{"job-name": "myapp-job-cleanup-123"},
+				CreationTimestamp: metav1.Time{Time: start},
+			},
+			Status: apiv1.PodStatus{
+				ContainerStatuses: []apiv1.ContainerStatus{
+					{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 3}}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"job-name": "other"},
+			},
+		},
+	}
+	run := jobToRun(job, pods)
+	exitCode := int32(3)
+	c.Assert(run, check.DeepEquals, provision.JobRun{
+		Name:      "myapp-job-cleanup-123",
+		Job:       "cleanup",
+		Version:   2,
+		Status:    provision.JobRunFailed,
+		ExitCode:  &exitCode,
+		StartTime: start,
+		EndTime:   &end,
+	})
+}
+
+func (s *S) TestRecordJobRun(c *check.C) {
+	now := time.Now().UTC()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp-job-cleanup-123",
+			Namespace: "default",
+			Labels: map[string]string{
+				"tsuru.io/app-name": "myapp",
+				"tsuru.io/is-job":   "true",
+				"tsuru.io/job-name": "cleanup",
+			},
+		},
+		Status: batchv1.JobStatus{
+			StartTime:      &metav1.Time{Time: now},
+			CompletionTime: &metav1.Time{Time: now},
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: apiv1.ConditionTrue},
+			},
+		},
+	}
+	_, err := s.client.BatchV1().Jobs("default").Create(context.TODO(), job, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	err = recordJobRun(context.TODO(), s.clusterClient, job)
+	c.Assert(err, check.IsNil)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeApp, Value: "myapp"},
+		Kind:   jobRunEventKind,
+	}, eventtest.HasEvent)
+	recorded, err := s.client.BatchV1().Jobs("default").Get(context.TODO(), job.Name, metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(recorded.Annotations[jobRunRecordedAnnotation], check.Equals, "true")
+	err = recordJobRun(context.TODO(), s.clusterClient, recorded)
+	c.Assert(err, check.IsNil)
+}
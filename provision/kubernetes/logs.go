@@ -33,13 +33,31 @@ var (
 )
 
 func (p *kubernetesProvisioner) ListLogs(ctx context.Context, app appTypes.App, args appTypes.ListLogArgs) ([]appTypes.Applog, error) {
-	clusterClient, err := clusterForPool(ctx, app.GetPool())
+	members, err := membersForPool(ctx, app.GetPool())
 	if err != nil {
 		return nil, err
 	}
-	if !clusterClient.LogsFromAPIServerEnabled() {
+	if !members.serving[0].LogsFromAPIServerEnabled() {
 		return nil, provision.ErrLogsUnavailable
 	}
+	var logs []appTypes.Applog
+	for _, clusterClient := range members.all() {
+		if !clusterClient.LogsFromAPIServerEnabled() {
+			continue
+		}
+		clusterLogs, err := p.listLogsForCluster(ctx, clusterClient, app, args)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, clusterLogs...)
+	}
+	if len(members.all()) > 1 {
+		sort.SliceStable(logs, func(i, j int) bool { return logs[i].Date.Before(logs[j].Date) })
+	}
+	return logs, nil
+}
+
+func (p *kubernetesProvisioner) listLogsForCluster(ctx context.Context, clusterClient *ClusterClient, app appTypes.App, args appTypes.ListLogArgs) ([]appTypes.Applog, error) {
 	clusterController, err := getClusterController(p, clusterClient)
 	if err != nil {
 		return nil, err
@@ -66,13 +84,34 @@ func (p *kubernetesProvisioner) ListLogs(ctx context.Context, app appTypes.App,
 }
 
 func (p *kubernetesProvisioner) WatchLogs(ctx context.Context, app appTypes.App, args appTypes.ListLogArgs) (appTypes.LogWatcher, error) {
-	clusterClient, err := clusterForPool(ctx, app.GetPool())
+	members, err := membersForPool(ctx, app.GetPool())
 	if err != nil {
 		return nil, err
 	}
-	if !clusterClient.LogsFromAPIServerEnabled() {
+	if !members.serving[0].LogsFromAPIServerEnabled() {
 		return nil, provision.ErrLogsUnavailable
 	}
+	var watchers []*k8sLogsWatcher
+	for _, clusterClient := range members.all() {
+		if !clusterClient.LogsFromAPIServerEnabled() {
+			continue
+		}
+		watcher, err := p.watchLogsForCluster(ctx, clusterClient, app, args)
+		if err != nil {
+			for _, w := range watchers {
+				w.Close()
+			}
+			return nil, err
+		}
+		watchers = append(watchers, watcher)
+	}
+	if len(watchers) == 1 {
+		return watchers[0], nil
+	}
+	return newMultiClusterLogsWatcher(watchers), nil
+}
+
+func (p *kubernetesProvisioner) watchLogsForCluster(ctx context.Context, clusterClient *ClusterClient, app appTypes.App, args appTypes.ListLogArgs) (*k8sLogsWatcher, error) {
 	clusterClient.SetTimeout(watchTimeout)
 
 	clusterController, err := getClusterController(p, clusterClient)
@@ -148,6 +187,7 @@ func listLogsFromPods(ctx context.Context, clusterClient *ClusterClient, ns stri
 			defer wg.Done()
 
 			request := clusterClient.CoreV1().Pods(ns).GetLogs(pod.ObjectMeta.Name, &apiv1.PodLogOptions{
+				Container:  appContainerName(pod),
 				TailLines:  tailLimit,
 				Timestamps: true,
 			})
@@ -291,6 +331,7 @@ func (k *k8sLogsWatcher) watchPod(pod *apiv1.Pod, addedLater bool) {
 	}
 
 	request := k.clusterClient.CoreV1().Pods(k.ns).GetLogs(pod.ObjectMeta.Name, &apiv1.PodLogOptions{
+		Container:  appContainerName(pod),
 		Follow:     true,
 		TailLines:  &tailLines,
 		Timestamps: true,
@@ -334,6 +375,55 @@ func (k *k8sLogsWatcher) Close() {
 	})
 }
 
+// multiClusterLogsWatcher merges the logs watched in each member cluster of
+// the pool of the app.
+type multiClusterLogsWatcher struct {
+	watchers []*k8sLogsWatcher
+	ch       chan appTypes.Applog
+	ctx      context.Context
+	done     context.CancelFunc
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+func newMultiClusterLogsWatcher(watchers []*k8sLogsWatcher) *multiClusterLogsWatcher {
+	ctx, done := context.WithCancel(context.Background())
+	m := &multiClusterLogsWatcher{
+		watchers: watchers,
+		ch:       make(chan appTypes.Applog, logWatchBufferSize),
+		ctx:      ctx,
+		done:     done,
+	}
+	for _, w := range watchers {
+		m.wg.Add(1)
+		go func(w *k8sLogsWatcher) {
+			defer m.wg.Done()
+			for l := range w.Chan() {
+				select {
+				case m.ch <- l:
+				case <-m.ctx.Done():
+				}
+			}
+		}(w)
+	}
+	return m
+}
+
+func (m *multiClusterLogsWatcher) Chan() <-chan appTypes.Applog {
+	return m.ch
+}
+
+func (m *multiClusterLogsWatcher) Close() {
+	m.once.Do(func() {
+		m.done()
+		for _, w := range m.watchers {
+			w.Close()
+		}
+		m.wg.Wait()
+		close(m.ch)
+	})
+}
+
 func (k *k8sLogsWatcher) OnPodEvent(pod *apiv1.Pod) {
 	_, alreadyWatching := k.watchingPods[pod.ObjectMeta.Name]
 	podMatches := matchPod(pod, k.logArgs)
@@ -17,12 +17,14 @@ import (
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/router/rebuild"
+	batchv1 "k8s.io/api/batch/v1"
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
 	autoscalingInformers "k8s.io/client-go/informers/autoscaling/v2beta2"
+	batchInformers "k8s.io/client-go/informers/batch/v1"
 	v1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/informers/internalinterfaces"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -62,6 +64,7 @@ type clusterController struct {
 	serviceInformer         v1informers.ServiceInformer
 	nodeInformer            v1informers.NodeInformer
 	hpaInformer             autoscalingInformers.HorizontalPodAutoscalerInformer
+	jobInformer             batchInformers.JobInformer
 	stopCh                  chan struct{}
 	cancel                  context.CancelFunc
 	resourceReadyCache      map[types.NamespacedName]bool
@@ -191,9 +194,36 @@ func (c *clusterController) start() error {
 		},
 	})
 
+	jobInformer, err := c.getJobInformerWait(false)
+	if err != nil {
+		return err
+	}
+	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.onJobEvent(obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.onJobEvent(newObj)
+		},
+	})
+
 	return nil
 }
 
+func (c *clusterController) onJobEvent(obj interface{}) {
+	if !c.isLeader() {
+		return
+	}
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	err := recordJobRun(context.Background(), c.cluster, job)
+	if err != nil {
+		log.Errorf("[job-run-controller] error recording run of job %q: %v", job.Name, err)
+	}
+}
+
 func (c *clusterController) onAdd(obj interface{}) error {
 	// Pods are never ready on add, ignore and do nothing
 	return nil
@@ -279,7 +309,7 @@ func (c *clusterController) enqueuePod(pod *apiv1.Pod) {
 	if appName == "" {
 		return
 	}
-	if labelSet.IsDeploy() || labelSet.IsIsolatedRun() {
+	if labelSet.IsDeploy() || labelSet.IsIsolatedRun() || labelSet.IsJob() {
 		return
 	}
 	routerLocal, _ := c.cluster.RouterAddressLocal(labelSet.AppPool())
@@ -359,6 +389,25 @@ func (c *clusterController) getHPAInformer() (autoscalingInformers.HorizontalPod
 	return c.hpaInformer, err
 }
 
+func (c *clusterController) getJobInformerWait(wait bool) (batchInformers.JobInformer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.jobInformer == nil {
+		err := c.withFilteredInformerFactory(func(factory informers.SharedInformerFactory) {
+			c.jobInformer = factory.Batch().V1().Jobs()
+			c.jobInformer.Informer()
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if wait {
+		err = c.waitForSync(c.jobInformer.Informer())
+	}
+	return c.jobInformer, err
+}
+
 func (c *clusterController) getPodInformerWait(wait bool) (v1informers.PodInformer, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
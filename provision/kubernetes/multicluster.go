@@ -0,0 +1,351 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/dockercommon"
+	"github.com/tsuru/tsuru/provision/servicecommon"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// poolMembers are the clusters backing a pool. Serving clusters run the
+// units of the apps in the pool, the primary cluster first. Standby clusters
+// receive every deployed version with no units and drained clusters are only
+// cleaned up.
+type poolMembers struct {
+	serving []*ClusterClient
+	standby []*ClusterClient
+	drained []*ClusterClient
+}
+
+func (m poolMembers) active() []*ClusterClient {
+	return append(append([]*ClusterClient{}, m.serving...), m.standby...)
+}
+
+func (m poolMembers) all() []*ClusterClient {
+	return append(m.active(), m.drained...)
+}
+
+// membersForPool returns the clusters backing the pool. Clusters serve the
+// pool when they have the same role as the primary cluster: active clusters
+// serve while at least one of them isn't drained, passive clusters take over
+// afterwards.
+func membersForPool(ctx context.Context, pool string) (poolMembers, error) {
+	primary, err := clusterForPool(ctx, pool)
+	if err != nil {
+		return poolMembers{}, err
+	}
+	return membersWithPrimary(ctx, primary, pool)
+}
+
+func membersWithPrimary(ctx context.Context, primary *ClusterClient, pool string) (poolMembers, error) {
+	members := poolMembers{serving: []*ClusterClient{primary}}
+	if primary.Placement == nil {
+		return members, nil
+	}
+	clusters, err := servicemanager.Cluster.FindAllByPool(ctx, provisionerName, pool)
+	if err != nil {
+		return poolMembers{}, err
+	}
+	for i := range clusters {
+		if clusters[i].Name == primary.Name {
+			continue
+		}
+		client, err := NewClusterClient(&clusters[i])
+		if err != nil {
+			return poolMembers{}, err
+		}
+		placement := clusters[i].Placement
+		switch {
+		case placement.IsDrained():
+			members.drained = append(members.drained, client)
+		case placement.IsActive() == primary.Placement.IsActive() && !primary.Placement.IsDrained():
+			members.serving = append(members.serving, client)
+		default:
+			members.standby = append(members.standby, client)
+		}
+	}
+	return members, nil
+}
+
+// deployToMembers deploys the version to every member cluster of the app
+// pool besides the primary cluster. Standby clusters keep their units.
+func deployToMembers(ctx context.Context, members poolMembers, args provision.DeployArgs) error {
+	// The version is checked and verified once, while deploying to the
+	// primary cluster.
+	args.ImageReady = nil
+	args.Verify = nil
+	for i, client := range members.active() {
+		if i == 0 {
+			continue
+		}
+		if args.Event != nil {
+			fmt.Fprintf(args.Event, "\n---- Deploying to cluster %q ----\n", client.Name)
+		}
+		err := deployToCluster(ctx, client, args, i >= len(members.serving))
+		if err != nil {
+			return errors.Wrapf(err, "unable to deploy to cluster %q", client.Name)
+		}
+	}
+	return nil
+}
+
+func deployToCluster(ctx context.Context, client *ClusterClient, args provision.DeployArgs, standby bool) error {
+	err := ensureAppCustomResourceSynced(ctx, client, args.App)
+	if err != nil {
+		return err
+	}
+	var oldVersionNumber int
+	if !args.PreserveVersions {
+		oldVersionNumber, err = baseVersionForApp(ctx, client, args.App)
+		if err != nil {
+			return err
+		}
+	}
+	var spec servicecommon.ProcessSpec
+	if standby {
+		processes, err := args.Version.Processes()
+		if err != nil {
+			return err
+		}
+		spec = servicecommon.ProcessSpec{}
+		for process := range processes {
+			spec[process] = servicecommon.ProcessState{}
+		}
+	}
+	err = servicecommon.RunServicePipeline(ctx, &serviceManager{
+		client: client,
+		writer: args.Event,
+	}, oldVersionNumber, args, spec)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = updateJobsVersion(ctx, client, args.App, args.Version)
+	if err != nil {
+		return err
+	}
+	return ensureAppCustomResourceSynced(ctx, client, args.App)
+}
+
+// changeUnitsInClusters adds or removes units of the process across the
+// clusters, keeping the units of each cluster proportional to its weight.
+func changeUnitsInClusters(ctx context.Context, clients []*ClusterClient, a provision.App, units int, processName string, version appTypes.AppVersion, w io.Writer) error {
+	if processName == "" {
+		cmdData, err := dockercommon.ContainerCmdsDataFromVersion(version)
+		if err != nil {
+			return err
+		}
+		_, processName, err = dockercommon.ProcessCmdForVersion(processName, cmdData)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	current := make([]int, len(clients))
+	weights := make([]int, len(clients))
+	for i, client := range clients {
+		err := ensureAppCustomResourceSynced(ctx, client, a)
+		if err != nil {
+			return err
+		}
+		m := &serviceManager{client: client}
+		_, replicas, err := m.CurrentLabels(ctx, a, processName, version.Version())
+		if err != nil {
+			return err
+		}
+		if replicas != nil {
+			current[i] = int(*replicas)
+		}
+		weights[i] = client.Placement.GetWeight()
+	}
+	available := 0
+	for _, c := range current {
+		available += c
+	}
+	if available+units < 0 {
+		return errors.Errorf("cannot remove %d units from process %q, only %d available", -units, processName, available)
+	}
+	deltas := distributeUnits(current, weights, units)
+	for i, d := range deltas {
+		if d == 0 {
+			continue
+		}
+		err := servicecommon.ChangeUnits(ctx, &serviceManager{
+			client: clients[i],
+			writer: w,
+		}, a, d, processName, version)
+		if err != nil {
+			return errors.Wrapf(err, "unable to change units in cluster %q", clients[i].Name)
+		}
+	}
+	return nil
+}
+
+// distributeUnits splits delta units among clusters running the current
+// units, one unit at a time, always picking the cluster whose units are
+// furthest from its share of the weights. Ties favor adding units to the
+// first clusters and removing units from the last ones. Removed units are limited to the current units of each cluster.
+func distributeUnits(current, weights []int, delta int) []int {
+	units := append([]int{}, current...)
+	result := make([]int, len(current))
+	for ; delta > 0; delta-- {
+		best := 0
+		for i := range units {
+			if units[i]*weights[best] < units[best]*weights[i] {
+				best = i
+			}
+		}
+		units[best]++
+		result[best]++
+	}
+	for ; delta < 0; delta++ {
+		best := -1
+		for i := range units {
+			if units[i] == 0 {
+				continue
+			}
+			if best == -1 || units[i]*weights[best] >= units[best]*weights[i] {
+				best = i
+			}
+		}
+		if best == -1 {
+			break
+		}
+		units[best]--
+		result[best]--
+	}
+	return result
+}
+
+func (p *kubernetesProvisioner) Failover(ctx context.Context, a provision.App, cluster string, w io.Writer) error {
+	if w == nil {
+		w = ioutil.Discard
+	}
+	clust, err := servicemanager.Cluster.FindByName(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	if !clust.Placement.IsDrained() {
+		return errors.Errorf("cluster %q must be drained before failing over", cluster)
+	}
+	members, err := membersForPool(ctx, a.GetPool())
+	if err != nil {
+		return err
+	}
+	if members.serving[0].Name == cluster {
+		return errors.Errorf("no cluster available to receive units from cluster %q", cluster)
+	}
+	drainedClient, err := NewClusterClient(clust)
+	if err != nil {
+		return err
+	}
+	grouped, err := deploymentsDataForApp(ctx, drainedClient, a)
+	if err != nil {
+		fmt.Fprintf(w, "Unable to list units of app %q in cluster %q, units not moved: %v\n", a.GetName(), cluster, err)
+		return nil
+	}
+	var versionNumbers []int
+	for v := range grouped.versioned {
+		versionNumbers = append(versionNumbers, v)
+	}
+	sort.Ints(versionNumbers)
+	for _, v := range versionNumbers {
+		version, err := servicemanager.AppVersion.VersionByImageOrVersion(ctx, a, strconv.Itoa(v))
+		if err != nil {
+			return err
+		}
+		for _, dep := range grouped.versioned[v] {
+			if dep.replicas == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "Moving %d units of process %q version %d of app %q from cluster %q\n", dep.replicas, dep.process, v, a.GetName(), cluster)
+			err = changeUnitsInClusters(ctx, members.serving, a, dep.replicas, dep.process, version, w)
+			if err != nil {
+				return err
+			}
+			err = servicecommon.ChangeUnits(ctx, &serviceManager{
+				client: drainedClient,
+				writer: w,
+			}, a, -dep.replicas, dep.process, version)
+			if err != nil {
+				fmt.Fprintf(w, "Unable to remove units from cluster %q: %v\n", cluster, err)
+			}
+		}
+	}
+	return nil
+}
+
+// memberForUnit returns the member cluster of the pool running the unit,
+// along with the pod of the unit.
+func memberForUnit(ctx context.Context, members poolMembers, a provision.App, unitID string) (*ClusterClient, *apiv1.Pod, error) {
+	for _, client := range members.all() {
+		ns, err := client.AppNamespace(ctx, a)
+		if err != nil {
+			return nil, nil, err
+		}
+		pod, err := client.CoreV1().Pods(ns).Get(ctx, unitID, metav1.GetOptions{})
+		if err == nil {
+			return client, pod, nil
+		}
+		if !k8sErrors.IsNotFound(err) {
+			return nil, nil, errors.WithStack(err)
+		}
+	}
+	return nil, nil, &provision.UnitNotFoundError{ID: unitID}
+}
+
+// jobMember returns the member cluster running the cron jobs of the pool.
+// Jobs are created in every active member, but only scheduled in the first
+// one that isn't drained, so that each run happens once.
+func jobMember(members poolMembers) *ClusterClient {
+	for _, client := range members.active() {
+		if !client.Placement.IsDrained() {
+			return client
+		}
+	}
+	return members.serving[0]
+}
+
+// splitAutoScale splits the units of the autoscale spec among the clusters
+// serving the pool, proportionally to their weights. Clusters with no units
+// to scale get a nil spec, the others keep at least one unit.
+func splitAutoScale(clients []*ClusterClient, spec provision.AutoScaleSpec) []*provision.AutoScaleSpec {
+	if len(clients) == 1 {
+		return []*provision.AutoScaleSpec{&spec}
+	}
+	weights := make([]int, len(clients))
+	for i, client := range clients {
+		weights[i] = client.Placement.GetWeight()
+	}
+	zero := make([]int, len(clients))
+	minUnits := distributeUnits(zero, weights, int(spec.MinUnits))
+	maxUnits := distributeUnits(zero, weights, int(spec.MaxUnits))
+	specs := make([]*provision.AutoScaleSpec, len(clients))
+	for i := range clients {
+		if maxUnits[i] == 0 {
+			continue
+		}
+		clusterSpec := spec
+		clusterSpec.MinUnits = uint(minUnits[i])
+		if clusterSpec.MinUnits == 0 {
+			clusterSpec.MinUnits = 1
+		}
+		clusterSpec.MaxUnits = uint(maxUnits[i])
+		specs[i] = &clusterSpec
+	}
+	return specs
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/provision"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestDistributeUnits(c *check.C) {
+	tests := []struct {
+		current []int
+		weights []int
+		delta   int
+		result  []int
+	}{
+		{current: []int{0, 0}, weights: []int{1, 1}, delta: 3, result: []int{2, 1}},
+		{current: []int{0, 0}, weights: []int{2, 1}, delta: 6, result: []int{4, 2}},
+		{current: []int{3, 0}, weights: []int{1, 1}, delta: 3, result: []int{0, 3}},
+		{current: []int{4, 2}, weights: []int{1, 1}, delta: -3, result: []int{-2, -1}},
+		{current: []int{1, 0}, weights: []int{1, 1}, delta: -2, result: []int{-1, 0}},
+		{current: []int{2}, weights: []int{1}, delta: 1, result: []int{1}},
+	}
+	for i, tt := range tests {
+		c.Check(distributeUnits(tt.current, tt.weights, tt.delta), check.DeepEquals, tt.result, check.Commentf("test %d", i))
+	}
+}
+
+func (s *S) TestMembersForPool(c *check.C) {
+	base := s.client.GetCluster()
+	newCluster := func(name string, placement provTypes.ClusterPlacement) provTypes.Cluster {
+		clust := *base
+		clust.Name = name
+		clust.Placement = &placement
+		return clust
+	}
+	clusters := []provTypes.Cluster{
+		newCluster("c1", provTypes.ClusterPlacement{Role: provTypes.ClusterRoleActive}),
+		newCluster("c2", provTypes.ClusterPlacement{Role: provTypes.ClusterRoleActive, Weight: 2}),
+		newCluster("c3", provTypes.ClusterPlacement{Role: provTypes.ClusterRolePassive}),
+		newCluster("c4", provTypes.ClusterPlacement{Role: provTypes.ClusterRoleActive, Drained: true}),
+	}
+	s.mockService.Cluster.OnFindByPool = func(provName, poolName string) (*provTypes.Cluster, error) {
+		return &clusters[0], nil
+	}
+	s.mockService.Cluster.OnFindAllByPool = func(provName, poolName string) ([]provTypes.Cluster, error) {
+		c.Assert(poolName, check.Equals, "mypool")
+		return clusters, nil
+	}
+	names := func(clients []*ClusterClient) []string {
+		var result []string
+		for _, cli := range clients {
+			result = append(result, cli.Name)
+		}
+		return result
+	}
+	members, err := membersForPool(context.TODO(), "mypool")
+	c.Assert(err, check.IsNil)
+	c.Assert(names(members.serving), check.DeepEquals, []string{"c1", "c2"})
+	c.Assert(names(members.standby), check.DeepEquals, []string{"c3"})
+	c.Assert(names(members.drained), check.DeepEquals, []string{"c4"})
+	c.Assert(names(members.all()), check.DeepEquals, []string{"c1", "c2", "c3", "c4"})
+}
+
+func (s *S) TestMembersForPoolWithoutPlacement(c *check.C) {
+	members, err := membersForPool(context.TODO(), "mypool")
+	c.Assert(err, check.IsNil)
+	c.Assert(members.serving, check.HasLen, 1)
+	c.Assert(members.serving[0].Name, check.Equals, s.client.GetCluster().Name)
+	c.Assert(members.standby, check.HasLen, 0)
+	c.Assert(members.drained, check.HasLen, 0)
+}
+
+func (s *S) TestSplitAutoScale(c *check.C) {
+	clients := []*ClusterClient{
+		{Cluster: &provTypes.Cluster{Name: "c1", Placement: &provTypes.ClusterPlacement{Weight: 2}}},
+		{Cluster: &provTypes.Cluster{Name: "c2"}},
+		{Cluster: &provTypes.Cluster{Name: "c3"}},
+	}
+	spec := provision.AutoScaleSpec{Process: "web", MinUnits: 2, MaxUnits: 4, AverageCPU: "500m"}
+	specs := splitAutoScale(clients, spec)
+	c.Assert(specs, check.DeepEquals, []*provision.AutoScaleSpec{
+		{Process: "web", MinUnits: 1, MaxUnits: 2, AverageCPU: "500m"},
+		{Process: "web", MinUnits: 1, MaxUnits: 1, AverageCPU: "500m"},
+		{Process: "web", MinUnits: 1, MaxUnits: 1, AverageCPU: "500m"},
+	})
+	specs = splitAutoScale(clients, provision.AutoScaleSpec{Process: "web", MinUnits: 1, MaxUnits: 2})
+	c.Assert(specs, check.DeepEquals, []*provision.AutoScaleSpec{
+		{Process: "web", MinUnits: 1, MaxUnits: 1},
+		{Process: "web", MinUnits: 1, MaxUnits: 1},
+		nil,
+	})
+	specs = splitAutoScale(clients[:1], spec)
+	c.Assert(specs, check.DeepEquals, []*provision.AutoScaleSpec{&spec})
+}
+
+func (s *S) TestJobMember(c *check.C) {
+	c1 := &ClusterClient{Cluster: &provTypes.Cluster{Name: "c1", Placement: &provTypes.ClusterPlacement{Drained: true}}}
+	c2 := &ClusterClient{Cluster: &provTypes.Cluster{Name: "c2", Placement: &provTypes.ClusterPlacement{}}}
+	c.Assert(jobMember(poolMembers{serving: []*ClusterClient{c1}, standby: []*ClusterClient{c2}}), check.Equals, c2)
+	c.Assert(jobMember(poolMembers{serving: []*ClusterClient{c2, c1}}), check.Equals, c2)
+	c.Assert(jobMember(poolMembers{serving: []*ClusterClient{c1}}), check.Equals, c1)
+}
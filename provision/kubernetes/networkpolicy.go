@@ -0,0 +1,153 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func networkPolicyNameForApp(a provision.App) string {
+	return fmt.Sprintf("%s-network-policy", validKubeName(a.GetName()))
+}
+
+func (p *kubernetesProvisioner) SetNetworkPolicy(ctx context.Context, a provision.App, policy provision.NetworkPolicy) error {
+	members, err := membersForPool(ctx, a.GetPool())
+	if err != nil {
+		return err
+	}
+	for _, client := range members.active() {
+		err = setNetworkPolicy(ctx, client, a, policy)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to set network policy in cluster %q", client.Name)
+		}
+	}
+	return nil
+}
+
+func setNetworkPolicy(ctx context.Context, client *ClusterClient, a provision.App, policy provision.NetworkPolicy) error {
+	if !client.networkPoliciesEnabled() {
+		return nil
+	}
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	np, err := networkPolicyForApp(ctx, client, ns, a, policy)
+	if err != nil {
+		return err
+	}
+	existing, err := client.NetworkingV1().NetworkPolicies(ns).Get(ctx, np.Name, metav1.GetOptions{})
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+		_, err = client.NetworkingV1().NetworkPolicies(ns).Create(ctx, np, metav1.CreateOptions{})
+		return errors.WithStack(err)
+	}
+	np.ResourceVersion = existing.ResourceVersion
+	_, err = client.NetworkingV1().NetworkPolicies(ns).Update(ctx, np, metav1.UpdateOptions{})
+	return errors.WithStack(err)
+}
+
+func deleteNetworkPolicy(ctx context.Context, client *ClusterClient, ns string, a provision.App) error {
+	err := client.NetworkingV1().NetworkPolicies(ns).Delete(ctx, networkPolicyNameForApp(a), metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// networkPolicyForApp builds the policy applied to the units of the app.
+// Units may always reach each other and the cluster DNS, ingress is also
+// allowed from the routers configured in the cluster.
+func networkPolicyForApp(ctx context.Context, client *ClusterClient, ns string, a provision.App, policy provision.NetworkPolicy) (*networkingv1.NetworkPolicy, error) {
+	ls, err := provision.ServiceLabels(ctx, provision.ServiceLabelsOpts{
+		App: a,
+		ServiceLabelExtendedOpts: provision.ServiceLabelExtendedOpts{
+			Prefix:      tsuruLabelPrefix,
+			Provisioner: provisionerName,
+		},
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pool := a.GetPool()
+	var routerPeers []networkingv1.NetworkPolicyPeer
+	for _, routerNs := range client.configList(pool, networkPolicyRouterNamespacesKey) {
+		routerPeers = append(routerPeers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": routerNs},
+			},
+		})
+	}
+	routerPeers = append(routerPeers, cidrPeers(client.configList(pool, networkPolicyRouterCIDRsKey))...)
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{From: appPeers(ls, append([]string{a.GetName()}, policy.IngressApps...))},
+	}
+	if len(routerPeers) > 0 {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{From: routerPeers})
+	}
+	udp, tcp := apiv1.ProtocolUDP, apiv1.ProtocolTCP
+	dnsPort := intstr.FromInt(53)
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &udp, Port: &dnsPort},
+			{Protocol: &tcp, Port: &dnsPort},
+		}},
+		{To: appPeers(ls, append([]string{a.GetName()}, policy.EgressApps...))},
+	}
+	egressCIDRs := append(client.configList(pool, networkPolicyEgressCIDRsKey), policy.EgressCIDRs...)
+	if len(egressCIDRs) > 0 {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{To: cidrPeers(egressCIDRs)})
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      networkPolicyNameForApp(a),
+			Namespace: ns,
+			Labels:    ls.ToLabels(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: ls.ToAllProcessesSelector(),
+			},
+			Ingress:     ingress,
+			Egress:      egress,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}, nil
+}
+
+// appPeers selects the units of the apps in every namespace.
+func appPeers(ls *provision.LabelSet, apps []string) []networkingv1.NetworkPolicyPeer {
+	return []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{},
+		PodSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      ls.AppNameKey(),
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   apps,
+			}},
+		},
+	}}
+}
+
+func cidrPeers(cidrs []string) []networkingv1.NetworkPolicyPeer {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, cidr := range cidrs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+		})
+	}
+	return peers
+}
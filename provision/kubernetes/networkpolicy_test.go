@@ -0,0 +1,105 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func (s *S) TestSetNetworkPolicy(c *check.C) {
+	s.clusterClient.CustomData[networkPoliciesKey] = "true"
+	s.clusterClient.CustomData[networkPolicyRouterNamespacesKey] = "tsuru-router"
+	s.clusterClient.CustomData["test-default:"+networkPolicyRouterCIDRsKey] = "10.0.0.0/24"
+	s.clusterClient.CustomData[networkPolicyEgressCIDRsKey] = "10.1.0.0/16"
+	defer func() {
+		delete(s.clusterClient.CustomData, networkPoliciesKey)
+		delete(s.clusterClient.CustomData, networkPolicyRouterNamespacesKey)
+		delete(s.clusterClient.CustomData, "test-default:"+networkPolicyRouterCIDRsKey)
+		delete(s.clusterClient.CustomData, networkPolicyEgressCIDRsKey)
+	}()
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	err := s.p.Provision(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	err = s.p.SetNetworkPolicy(context.TODO(), a, provision.NetworkPolicy{
+		IngressApps: []string{"frontend"},
+		EgressApps:  []string{"backend"},
+		EgressCIDRs: []string{"192.168.1.10/32"},
+	})
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	np, err := s.client.NetworkingV1().NetworkPolicies(ns).Get(context.TODO(), "myapp-network-policy", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	appPeer := func(apps ...string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{},
+			PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tsuru.io/app-name", Operator: metav1.LabelSelectorOpIn, Values: apps},
+				},
+			},
+		}
+	}
+	udp, tcp := apiv1.ProtocolUDP, apiv1.ProtocolTCP
+	dnsPort := intstr.FromInt(53)
+	c.Assert(np.Spec, check.DeepEquals, networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"tsuru.io/app-name": "myapp",
+				"tsuru.io/is-build": "false",
+			},
+		},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{
+			{From: []networkingv1.NetworkPolicyPeer{appPeer("myapp", "frontend")}},
+			{From: []networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "tsuru-router"}}},
+				{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/24"}},
+			}},
+		},
+		Egress: []networkingv1.NetworkPolicyEgressRule{
+			{Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &dnsPort},
+				{Protocol: &tcp, Port: &dnsPort},
+			}},
+			{To: []networkingv1.NetworkPolicyPeer{appPeer("myapp", "backend")}},
+			{To: []networkingv1.NetworkPolicyPeer{
+				{IPBlock: &networkingv1.IPBlock{CIDR: "10.1.0.0/16"}},
+				{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.1.10/32"}},
+			}},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+	})
+	err = s.p.SetNetworkPolicy(context.TODO(), a, provision.NetworkPolicy{})
+	c.Assert(err, check.IsNil)
+	np, err = s.client.NetworkingV1().NetworkPolicies(ns).Get(context.TODO(), "myapp-network-policy", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(np.Spec.Ingress[0].From, check.DeepEquals, []networkingv1.NetworkPolicyPeer{appPeer("myapp")})
+	c.Assert(np.Spec.Egress, check.HasLen, 3)
+}
+
+func (s *S) TestSetNetworkPolicyDisabled(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	err := s.p.Provision(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	_, err = s.client.NetworkingV1().NetworkPolicies(ns).Create(context.TODO(), &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-network-policy", Namespace: ns},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	err = s.p.SetNetworkPolicy(context.TODO(), a, provision.NetworkPolicy{IngressApps: []string{"frontend"}})
+	c.Assert(err, check.IsNil)
+	np, err := s.client.NetworkingV1().NetworkPolicies(ns).Get(context.TODO(), "myapp-network-policy", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(np.Spec, check.DeepEquals, networkingv1.NetworkPolicySpec{})
+}
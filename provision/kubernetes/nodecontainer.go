@@ -105,11 +105,17 @@ func (m *nodeContainerManager) deployNodeContainerForCluster(ctx context.Context
 		}
 		affinity = &apiv1.Affinity{}
 	}
+	tolerations, err := nodeContainerTolerations(client, pool, filter)
+	if err != nil {
+		return err
+	}
 	if oldDs != nil && placementOnly {
-		if reflect.DeepEqual(oldDs.Spec.Template.Spec.Affinity, affinity) {
+		if reflect.DeepEqual(oldDs.Spec.Template.Spec.Affinity, affinity) &&
+			reflect.DeepEqual(oldDs.Spec.Template.Spec.Tolerations, tolerations) {
 			return nil
 		}
 		oldDs.Spec.Template.Spec.Affinity = affinity
+		oldDs.Spec.Template.Spec.Tolerations = tolerations
 		_, err = client.AppsV1().DaemonSets(ns).Update(ctx, oldDs, metav1.UpdateOptions{})
 		return errors.WithStack(err)
 	}
@@ -231,12 +237,7 @@ func (m *nodeContainerManager) deployNodeContainerForCluster(ctx context.Context
 							SecurityContext: secCtx,
 						},
 					},
-					Tolerations: []apiv1.Toleration{
-						{
-							Key:      tsuruNodeDisabledTaint,
-							Operator: apiv1.TolerationOpExists,
-						},
-					},
+					Tolerations: tolerations,
 				},
 			},
 		},
@@ -249,6 +250,60 @@ func (m *nodeContainerManager) deployNodeContainerForCluster(ctx context.Context
 	return errors.WithStack(err)
 }
 
+// nodeContainerTolerations returns the tolerations of the daemonset of a node
+// container, allowing it to run on the nodes tainted by the scheduling
+// policies of the pools it serves.
+func nodeContainerTolerations(client *ClusterClient, pool string, filter servicecommon.PoolFilter) ([]apiv1.Toleration, error) {
+	tolerations := []apiv1.Toleration{
+		{
+			Key:      tsuruNodeDisabledTaint,
+			Operator: apiv1.TolerationOpExists,
+		},
+		{
+			Key:      tsuruDedicatedPoolTaint,
+			Operator: apiv1.TolerationOpExists,
+		},
+	}
+	pools := []string{pool}
+	if pool == "" {
+		excluded := make(map[string]bool, len(filter.Exclude))
+		for _, p := range filter.Exclude {
+			excluded[p] = true
+		}
+		candidates := filter.Include
+		if len(candidates) == 0 {
+			candidates = client.Pools
+		}
+		for _, p := range candidates {
+			if !excluded[p] {
+				pools = append(pools, p)
+			}
+		}
+	}
+	for _, p := range pools {
+		policy, err := client.schedulingPolicy(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, toleration := range policy.podTolerations(p) {
+			if toleration.Key == tsuruDedicatedPoolTaint || containsToleration(tolerations, toleration) {
+				continue
+			}
+			tolerations = append(tolerations, toleration)
+		}
+	}
+	return tolerations, nil
+}
+
+func containsToleration(tolerations []apiv1.Toleration, toleration apiv1.Toleration) bool {
+	for _, t := range tolerations {
+		if reflect.DeepEqual(t, toleration) {
+			return true
+		}
+	}
+	return false
+}
+
 func ensureNodeContainers(a provision.App) error {
 	m := nodeContainerManager{
 		app: a,
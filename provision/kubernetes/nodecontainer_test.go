@@ -271,6 +271,10 @@ func (s *S) TestManagerDeployNodeContainer(c *check.C) {
 							Key:      "tsuru.io/disabled",
 							Operator: apiv1.TolerationOpExists,
 						},
+						{
+							Key:      "tsuru.io/dedicated-pool",
+							Operator: apiv1.TolerationOpExists,
+						},
 					},
 				},
 			},
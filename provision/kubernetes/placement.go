@@ -0,0 +1,215 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/provision"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	apiv1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	zoneTopologyKey = "topology.kubernetes.io/zone"
+	nodeTopologyKey = "kubernetes.io/hostname"
+)
+
+func pdbNameForApp(a provision.App, process string) string {
+	return appProcessName(a, process, 0, "")
+}
+
+func processConfigForVersion(version appTypes.AppVersion, process string) (*provTypes.TsuruYamlKubernetesProcessConfig, error) {
+	yamlData, err := version.TsuruYamlData()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if yamlData.Kubernetes == nil {
+		return nil, nil
+	}
+	return yamlData.Kubernetes.GetProcessConfigs(process), nil
+}
+
+// disruptionBudgetForProcess returns the disruption budget of the process,
+// declared in tsuru.yaml or in the cluster config for the app pool. A nil
+// budget means the process must not have a pod disruption budget.
+func disruptionBudgetForProcess(client *ClusterClient, a provision.App, config *provTypes.TsuruYamlKubernetesProcessConfig) (*provTypes.TsuruYamlKubernetesDisruptionBudget, error) {
+	if config != nil && config.DisruptionBudget != nil {
+		if config.DisruptionBudget.Disabled {
+			return nil, nil
+		}
+		return config.DisruptionBudget, nil
+	}
+	return client.disruptionBudget(a.GetPool())
+}
+
+func intOrPercent(v string) (*intstr.IntOrString, error) {
+	if v == "" {
+		return nil, nil
+	}
+	n, isPercent, err := provTypes.ParseIntOrPercent(v)
+	if err != nil {
+		return nil, err
+	}
+	if isPercent {
+		value := intstr.FromString(v)
+		return &value, nil
+	}
+	value := intstr.FromInt(n)
+	return &value, nil
+}
+
+// ensureDisruptionBudget creates, updates or removes the pod disruption
+// budget covering the units of all versions of the process.
+func ensureDisruptionBudget(ctx context.Context, client *ClusterClient, a provision.App, process string, version appTypes.AppVersion) error {
+	config, err := processConfigForVersion(version, process)
+	if err != nil {
+		return err
+	}
+	budget, err := disruptionBudgetForProcess(client, a, config)
+	if err != nil {
+		return err
+	}
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	name := pdbNameForApp(a, process)
+	if budget == nil {
+		err = client.PolicyV1beta1().PodDisruptionBudgets(ns).Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	maxUnavailable, err := intOrPercent(budget.MaxUnavailable)
+	if err != nil {
+		return err
+	}
+	minAvailable, err := intOrPercent(budget.MinAvailable)
+	if err != nil {
+		return err
+	}
+	ls, err := provision.ServiceLabels(ctx, provision.ServiceLabelsOpts{
+		App:     a,
+		Process: process,
+		ServiceLabelExtendedOpts: provision.ServiceLabelExtendedOpts{
+			Prefix:      tsuruLabelPrefix,
+			Provisioner: provisionerName,
+		},
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	pdb := &policy.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    ls.WithoutIsolated().WithoutRoutable().WithoutVersion().ToLabels(),
+		},
+		Spec: policy.PodDisruptionBudgetSpec{
+			MaxUnavailable: maxUnavailable,
+			MinAvailable:   minAvailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": deploymentNameForAppBase(a, process)},
+			},
+		},
+	}
+	existing, err := client.PolicyV1beta1().PodDisruptionBudgets(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+		_, err = client.PolicyV1beta1().PodDisruptionBudgets(ns).Create(ctx, pdb, metav1.CreateOptions{})
+		return errors.WithStack(err)
+	}
+	pdb.ResourceVersion = existing.ResourceVersion
+	_, err = client.PolicyV1beta1().PodDisruptionBudgets(ns).Update(ctx, pdb, metav1.UpdateOptions{})
+	return errors.WithStack(err)
+}
+
+// cleanupDisruptionBudgets removes the pod disruption budgets of the app
+// whose process is not in processInUse.
+func cleanupDisruptionBudgets(ctx context.Context, client *ClusterClient, ns string, a provision.App, processInUse map[string]struct{}) error {
+	ls, err := provision.ServiceLabels(ctx, provision.ServiceLabelsOpts{
+		App: a,
+		ServiceLabelExtendedOpts: provision.ServiceLabelExtendedOpts{
+			Prefix: tsuruLabelPrefix,
+		},
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	pdbs, err := client.PolicyV1beta1().PodDisruptionBudgets(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set(ls.ToAppSelector())).String(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	multiErrors := tsuruErrors.NewMultiError()
+	for _, pdb := range pdbs.Items {
+		if _, ok := processInUse[labelSetFromMeta(&pdb.ObjectMeta).AppProcess()]; ok {
+			continue
+		}
+		err = client.PolicyV1beta1().PodDisruptionBudgets(ns).Delete(ctx, pdb.Name, metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			multiErrors.Add(errors.WithStack(err))
+		}
+	}
+	return multiErrors.ToError()
+}
+
+// topologySpreadForProcess returns the constraints spreading the units of
+// the process across zones and nodes. The tsuru.yaml config of the process
+// overrides the cluster config for the app pool.
+func topologySpreadForProcess(client *ClusterClient, a provision.App, config *provTypes.TsuruYamlKubernetesProcessConfig, selector map[string]string) ([]apiv1.TopologySpreadConstraint, error) {
+	spread, err := client.topologySpread(a.GetPool())
+	if err != nil {
+		return nil, err
+	}
+	if config != nil && config.TopologySpread != nil {
+		if config.TopologySpread.Zone != "" {
+			spread.Zone = config.TopologySpread.Zone
+		}
+		if config.TopologySpread.Node != "" {
+			spread.Node = config.TopologySpread.Node
+		}
+	}
+	var constraints []apiv1.TopologySpreadConstraint
+	for _, item := range []struct {
+		key  string
+		mode string
+	}{
+		{key: zoneTopologyKey, mode: spread.Zone},
+		{key: nodeTopologyKey, mode: spread.Node},
+	} {
+		var action apiv1.UnsatisfiableConstraintAction
+		switch item.mode {
+		case provTypes.TopologySpreadHard:
+			action = apiv1.DoNotSchedule
+		case provTypes.TopologySpreadSoft:
+			action = apiv1.ScheduleAnyway
+		default:
+			continue
+		}
+		constraints = append(constraints, apiv1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       item.key,
+			WhenUnsatisfiable: action,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+		})
+	}
+	return constraints, nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/tsuru/tsuru/app/bind"
 	"github.com/tsuru/tsuru/app/image"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
 	tsuruNet "github.com/tsuru/tsuru/net"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/cluster"
@@ -67,22 +68,25 @@ type kubernetesProvisioner struct {
 }
 
 var (
-	_ provision.Provisioner              = &kubernetesProvisioner{}
-	_ provision.NodeProvisioner          = &kubernetesProvisioner{}
-	_ provision.NodeContainerProvisioner = &kubernetesProvisioner{}
-	_ provision.MessageProvisioner       = &kubernetesProvisioner{}
-	_ provision.SleepableProvisioner     = &kubernetesProvisioner{}
-	_ provision.VolumeProvisioner        = &kubernetesProvisioner{}
-	_ provision.BuilderDeploy            = &kubernetesProvisioner{}
-	_ provision.BuilderDeployKubeClient  = &kubernetesProvisioner{}
-	_ provision.InitializableProvisioner = &kubernetesProvisioner{}
-	_ provision.InterAppProvisioner      = &kubernetesProvisioner{}
-	_ provision.HCProvisioner            = &kubernetesProvisioner{}
-	_ provision.VersionsProvisioner      = &kubernetesProvisioner{}
-	_ provision.LogsProvisioner          = &kubernetesProvisioner{}
-	_ provision.AutoScaleProvisioner     = &kubernetesProvisioner{}
-	_ cluster.ClusteredProvisioner       = &kubernetesProvisioner{}
-	_ provision.UpdatableProvisioner     = &kubernetesProvisioner{}
+	_ provision.Provisioner                   = &kubernetesProvisioner{}
+	_ provision.NodeProvisioner               = &kubernetesProvisioner{}
+	_ provision.NodeContainerProvisioner      = &kubernetesProvisioner{}
+	_ provision.MessageProvisioner            = &kubernetesProvisioner{}
+	_ provision.SleepableProvisioner          = &kubernetesProvisioner{}
+	_ provision.VolumeProvisioner             = &kubernetesProvisioner{}
+	_ provision.BuilderDeploy                 = &kubernetesProvisioner{}
+	_ provision.BuilderDeployKubeClient       = &kubernetesProvisioner{}
+	_ provision.InitializableProvisioner      = &kubernetesProvisioner{}
+	_ provision.InterAppProvisioner           = &kubernetesProvisioner{}
+	_ provision.NetworkPolicyProvisioner      = &kubernetesProvisioner{}
+	_ provision.MultiClusterProvisioner       = &kubernetesProvisioner{}
+	_ provision.HCProvisioner                 = &kubernetesProvisioner{}
+	_ provision.VersionsProvisioner           = &kubernetesProvisioner{}
+	_ provision.LogsProvisioner               = &kubernetesProvisioner{}
+	_ provision.AutoScaleProvisioner          = &kubernetesProvisioner{}
+	_ cluster.ClusteredProvisioner            = &kubernetesProvisioner{}
+	_ provision.UpdatableProvisioner          = &kubernetesProvisioner{}
+	_ provision.RegistryCredentialProvisioner = &kubernetesProvisioner{}
 
 	mainKubernetesProvisioner *kubernetesProvisioner
 )
@@ -224,18 +228,38 @@ func (p *kubernetesProvisioner) GetName() string {
 }
 
 func (p *kubernetesProvisioner) Provision(ctx context.Context, a provision.App) error {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return err
 	}
-	return ensureAppCustomResourceSynced(ctx, client, a)
+	for _, client := range members.active() {
+		err = ensureAppCustomResourceSynced(ctx, client, a)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (p *kubernetesProvisioner) Destroy(ctx context.Context, a provision.App) error {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return err
 	}
+	err = p.destroyInCluster(ctx, members.serving[0], a)
+	if err != nil {
+		return err
+	}
+	for _, client := range members.all()[1:] {
+		err = p.destroyInCluster(ctx, client, a)
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			log.Errorf("unable to destroy app %q in cluster %q: %v", a.GetName(), client.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *kubernetesProvisioner) destroyInCluster(ctx context.Context, client *ClusterClient, a provision.App) error {
 	tclient, err := TsuruClientForConfig(client.restConfig)
 	if err != nil {
 		return err
@@ -299,6 +323,18 @@ func (p *kubernetesProvisioner) removeResources(ctx context.Context, client *Clu
 			}
 		}
 	}
+	err = removeJobsForAppNS(ctx, client, tsuruApp.Spec.NamespaceName, app)
+	if err != nil {
+		multiErrors.Add(err)
+	}
+	err = cleanupDisruptionBudgets(ctx, client, tsuruApp.Spec.NamespaceName, app, nil)
+	if err != nil {
+		multiErrors.Add(err)
+	}
+	err = deleteNetworkPolicy(ctx, client, tsuruApp.Spec.NamespaceName, app)
+	if err != nil {
+		multiErrors.Add(err)
+	}
 	err = client.CoreV1().ServiceAccounts(tsuruApp.Spec.NamespaceName).Delete(ctx, tsuruApp.Spec.ServiceAccountName, metav1.DeleteOptions{})
 	if err != nil && !k8sErrors.IsNotFound(err) {
 		multiErrors.Add(errors.WithStack(err))
@@ -333,11 +369,22 @@ func versionsForAppProcess(ctx context.Context, client *ClusterClient, a provisi
 }
 
 func changeState(ctx context.Context, a provision.App, process string, version appTypes.AppVersion, state servicecommon.ProcessState, w io.Writer) error {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return err
 	}
-	err = ensureAppCustomResourceSynced(ctx, client, a)
+	var multiErr tsuruErrors.MultiError
+	for _, client := range members.serving {
+		err = changeStateInCluster(ctx, client, a, process, version, state, w)
+		if err != nil {
+			multiErr.Add(err)
+		}
+	}
+	return multiErr.ToError()
+}
+
+func changeStateInCluster(ctx context.Context, client *ClusterClient, a provision.App, process string, version appTypes.AppVersion, state servicecommon.ProcessState, w io.Writer) error {
+	err := ensureAppCustomResourceSynced(ctx, client, a)
 	if err != nil {
 		return err
 	}
@@ -366,10 +413,14 @@ func changeState(ctx context.Context, a provision.App, process string, version a
 }
 
 func changeUnits(ctx context.Context, a provision.App, units int, processName string, version appTypes.AppVersion, w io.Writer) error {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return err
 	}
+	if len(members.serving) > 1 {
+		return changeUnitsInClusters(ctx, members.serving, a, units, processName, version, w)
+	}
+	client := members.serving[0]
 	err = ensureAppCustomResourceSynced(ctx, client, a)
 	if err != nil {
 		return err
@@ -462,6 +513,9 @@ func (p *kubernetesProvisioner) podsToUnitsMultiple(ctx context.Context, client
 			continue
 		}
 		l := labelSetFromMeta(&pod.ObjectMeta)
+		if l.IsJob() {
+			continue
+		}
 		node, ok := nodeMap[pod.Spec.NodeName]
 		if !ok && pod.Spec.NodeName != "" {
 			node, err = nodeInformer.Lister().Get(pod.Spec.NodeName)
@@ -654,7 +708,7 @@ func (p *kubernetesProvisioner) podsForApps(ctx context.Context, client *Cluster
 }
 
 func (p *kubernetesProvisioner) RoutableAddresses(ctx context.Context, a provision.App) ([]appTypes.RoutableAddresses, error) {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return nil, err
 	}
@@ -669,6 +723,27 @@ func (p *kubernetesProvisioner) RoutableAddresses(ctx context.Context, a provisi
 	if err != nil {
 		return nil, err
 	}
+	var allAddrs []appTypes.RoutableAddresses
+	prefixIdx := map[string]int{}
+	for _, client := range members.serving {
+		addrs, err := p.routableAddressesForCluster(ctx, client, a, webProcessName)
+		if err != nil {
+			return nil, err
+		}
+		for _, rAddr := range addrs {
+			idx, ok := prefixIdx[rAddr.Prefix]
+			if !ok {
+				prefixIdx[rAddr.Prefix] = len(allAddrs)
+				allAddrs = append(allAddrs, rAddr)
+				continue
+			}
+			allAddrs[idx].Addresses = append(allAddrs[idx].Addresses, rAddr.Addresses...)
+		}
+	}
+	return allAddrs, nil
+}
+
+func (p *kubernetesProvisioner) routableAddressesForCluster(ctx context.Context, client *ClusterClient, a provision.App, webProcessName string) ([]appTypes.RoutableAddresses, error) {
 	controller, err := getClusterController(p, client)
 	if err != nil {
 		return nil, err
@@ -776,7 +851,7 @@ func (p *kubernetesProvisioner) addressesForApp(ctx context.Context, client *Clu
 	addrs := make([]*url.URL, 0)
 	for _, pod := range pods {
 		labelSet := labelSetFromMeta(&pod.ObjectMeta)
-		if labelSet.IsIsolatedRun() {
+		if labelSet.IsIsolatedRun() || labelSet.IsJob() {
 			continue
 		}
 		if labelSet.AppProcess() != processName {
@@ -829,21 +904,14 @@ func (p *kubernetesProvisioner) addressesForPool(client *ClusterClient, poolName
 }
 
 func (p *kubernetesProvisioner) RegisterUnit(ctx context.Context, a provision.App, unitID string, customData map[string]interface{}) error {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return err
 	}
-	ns, err := client.AppNamespace(ctx, a)
+	client, pod, err := memberForUnit(ctx, members, a, unitID)
 	if err != nil {
 		return err
 	}
-	pod, err := client.CoreV1().Pods(ns).Get(ctx, unitID, metav1.GetOptions{})
-	if err != nil {
-		if k8sErrors.IsNotFound(err) {
-			return &provision.UnitNotFoundError{ID: unitID}
-		}
-		return errors.WithStack(err)
-	}
 	units, err := p.podsToUnits(ctx, client, []apiv1.Pod{*pod}, a, nil)
 	if err != nil {
 		return err
@@ -889,10 +957,29 @@ func (p *kubernetesProvisioner) ListNodes(ctx context.Context, addressFilter []s
 }
 
 func (p *kubernetesProvisioner) InternalAddresses(ctx context.Context, a provision.App) ([]provision.AppInternalAddress, error) {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return nil, err
 	}
+	addresses := []provision.AppInternalAddress{}
+	seen := map[provision.AppInternalAddress]bool{}
+	for _, client := range members.active() {
+		clusterAddrs, err := p.internalAddressesForCluster(ctx, client, a)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range clusterAddrs {
+			if seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			addresses = append(addresses, addr)
+		}
+	}
+	return addresses, nil
+}
+
+func (p *kubernetesProvisioner) internalAddressesForCluster(ctx context.Context, client *ClusterClient, a provision.App) ([]provision.AppInternalAddress, error) {
 	ns, err := client.AppNamespace(ctx, a)
 	if err != nil {
 		return nil, err
@@ -1063,6 +1150,10 @@ func (p *kubernetesProvisioner) AddNode(ctx context.Context, opts provision.AddN
 			},
 		}
 		setNodeMetadata(node, opts.Pool, opts.IaaSID, opts.Metadata)
+		err = setNodeTaintsForPool(client, node, "", opts.Pool)
+		if err != nil {
+			return err
+		}
 		_, err = client.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{})
 		if err == nil {
 			return nil
@@ -1191,7 +1282,12 @@ func (p *kubernetesProvisioner) internalNodeUpdate(ctx context.Context, opts pro
 		})
 	}
 	node.Spec.Taints = taints
+	oldPool := labelSetFromMeta(&node.ObjectMeta).NodePool()
 	setNodeMetadata(node, opts.Pool, iaasID, opts.Metadata)
+	err = setNodeTaintsForPool(client, node, oldPool, opts.Pool)
+	if err != nil {
+		return err
+	}
 	_, err = client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
 	return errors.WithStack(err)
 }
@@ -1221,6 +1317,7 @@ func (p *kubernetesProvisioner) Deploy(ctx context.Context, args provision.Deplo
 			attachOutput:      args.Event,
 			attachInput:       strings.NewReader("."),
 			inputFile:         "/dev/null",
+			architectures:     client.buildArchitectures(args.App.GetPool()),
 		}
 		err = createDeployPod(ctx, params)
 		if err != nil {
@@ -1230,6 +1327,12 @@ func (p *kubernetesProvisioner) Deploy(ctx context.Context, args provision.Deplo
 		if err != nil {
 			return "", err
 		}
+		if args.ImageReady != nil {
+			err = args.ImageReady(ctx, args.Version)
+			if err != nil {
+				return "", err
+			}
+		}
 	}
 	manager := &serviceManager{
 		client: client,
@@ -1246,10 +1349,22 @@ func (p *kubernetesProvisioner) Deploy(ctx context.Context, args provision.Deplo
 	if err != nil {
 		return "", errors.WithStack(err)
 	}
+	err = updateJobsVersion(ctx, client, args.App, args.Version)
+	if err != nil {
+		return "", err
+	}
 	err = ensureAppCustomResourceSynced(ctx, client, args.App)
 	if err != nil {
 		return "", err
 	}
+	members, err := membersForPool(ctx, args.App.GetPool())
+	if err != nil {
+		return "", err
+	}
+	err = deployToMembers(ctx, members, args)
+	if err != nil {
+		return "", err
+	}
 	return args.Version.VersionInfo().DeployImage, nil
 }
 
@@ -1269,7 +1384,7 @@ func (p *kubernetesProvisioner) RemoveNodeContainer(ctx context.Context, name st
 }
 
 func (p *kubernetesProvisioner) ExecuteCommand(ctx context.Context, opts provision.ExecOptions) error {
-	client, err := clusterForPool(ctx, opts.App.GetPool())
+	members, err := membersForPool(ctx, opts.App.GetPool())
 	if err != nil {
 		return err
 	}
@@ -1284,7 +1399,7 @@ func (p *kubernetesProvisioner) ExecuteCommand(ctx context.Context, opts provisi
 		opts.Cmds = append([]string{"/usr/bin/env", "TERM=" + opts.Term}, opts.Cmds...)
 	}
 	eOpts := execOpts{
-		client:   client,
+		client:   members.serving[0],
 		app:      opts.App,
 		cmds:     opts.Cmds,
 		stdout:   opts.Stdout,
@@ -1294,11 +1409,16 @@ func (p *kubernetesProvisioner) ExecuteCommand(ctx context.Context, opts provisi
 		tty:      opts.Stdin != nil,
 	}
 	if len(opts.Units) == 0 {
-		return runIsolatedCmdPod(ctx, client, eOpts)
+		return runIsolatedCmdPod(ctx, eOpts.client, eOpts)
 	}
 	for _, u := range opts.Units {
+		client, _, err := memberForUnit(ctx, members, opts.App, u)
+		if err != nil {
+			return err
+		}
+		eOpts.client = client
 		eOpts.unit = u
-		err := execCommand(ctx, eOpts)
+		err = execCommand(ctx, eOpts)
 		if err != nil {
 			return err
 		}
@@ -1320,12 +1440,14 @@ func runIsolatedCmdPod(ctx context.Context, client *ClusterClient, opts execOpts
 		return errors.WithStack(err)
 	}
 	var version appTypes.AppVersion
+	var architectures []string
 	if opts.image == "" {
 		version, err = servicemanager.AppVersion.LatestSuccessfulVersion(ctx, opts.app)
 		if err != nil {
 			return errors.WithStack(err)
 		}
-		opts.image = version.VersionInfo().DeployImage
+		opts.image = version.VersionInfo().DeployImageReference()
+		architectures = version.VersionInfo().Architectures
 	}
 	appEnvs := provision.EnvsForApp(opts.app, "", false, version)
 	var envs []apiv1.EnvVar
@@ -1333,18 +1455,19 @@ func runIsolatedCmdPod(ctx context.Context, client *ClusterClient, opts execOpts
 		envs = append(envs, apiv1.EnvVar{Name: envData.Name, Value: envData.Value})
 	}
 	return runPod(ctx, runSinglePodArgs{
-		client:       client,
-		eventsOutput: opts.eventsOutput,
-		stdout:       opts.stdout,
-		stderr:       opts.stderr,
-		stdin:        opts.stdin,
-		termSize:     opts.termSize,
-		image:        opts.image,
-		labels:       labels,
-		cmds:         opts.cmds,
-		envs:         envs,
-		name:         baseName,
-		app:          opts.app,
+		client:        client,
+		eventsOutput:  opts.eventsOutput,
+		stdout:        opts.stdout,
+		stderr:        opts.stderr,
+		stdin:         opts.stdin,
+		termSize:      opts.termSize,
+		image:         opts.image,
+		labels:        labels,
+		cmds:          opts.cmds,
+		envs:          envs,
+		name:          baseName,
+		app:           opts.app,
+		architectures: architectures,
 	})
 }
 
@@ -1690,17 +1813,35 @@ func (p *kubernetesProvisioner) HandlesHC() bool {
 }
 
 func (p *kubernetesProvisioner) ToggleRoutable(ctx context.Context, a provision.App, version appTypes.AppVersion, isRoutable bool) error {
-	client, err := clusterForPool(ctx, a.GetPool())
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return err
 	}
+	for i, client := range members.active() {
+		err = toggleRoutableInCluster(ctx, client, a, version, isRoutable)
+		if err == errNoVersionDeployment {
+			if i > 0 {
+				continue
+			}
+			return errors.Errorf("no deployment found for version %v", version.Version())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errNoVersionDeployment = errors.New("no deployment found for version")
+
+func toggleRoutableInCluster(ctx context.Context, client *ClusterClient, a provision.App, version appTypes.AppVersion, isRoutable bool) error {
 	depsData, err := deploymentsDataForApp(ctx, client, a)
 	if err != nil {
 		return err
 	}
 	depsForVersion, ok := depsData.versioned[version.Version()]
 	if !ok {
-		return errors.Errorf("no deployment found for version %v", version.Version())
+		return errNoVersionDeployment
 	}
 	for _, depData := range depsForVersion {
 		err = toggleRoutableDeployment(ctx, client, version.Version(), depData.dep, isRoutable)
@@ -1773,17 +1914,25 @@ func toggleRoutableDeployment(ctx context.Context, client *ClusterClient, versio
 }
 
 func (p *kubernetesProvisioner) DeployedVersions(ctx context.Context, a provision.App) ([]int, error) {
-	client, err := clusterForPool(ctx, a.GetPool())
-	if err != nil {
-		return nil, err
-	}
-	deps, err := deploymentsDataForApp(ctx, client, a)
+	members, err := membersForPool(ctx, a.GetPool())
 	if err != nil {
 		return nil, err
 	}
+	seen := map[int]bool{}
 	var versions []int
-	for v := range deps.versioned {
-		versions = append(versions, v)
+	for _, client := range members.active() {
+		deps, err := deploymentsDataForApp(ctx, client, a)
+		if err != nil {
+			return nil, err
+		}
+		for v := range deps.versioned {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			versions = append(versions, v)
+		}
 	}
+	sort.Ints(versions)
 	return versions, nil
 }
@@ -20,6 +20,7 @@ import (
 
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/kr/pretty"
+	"github.com/pkg/errors"
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/app/bind"
@@ -199,6 +200,27 @@ func (s *S) TestAddNode(c *check.C) {
 	})
 }
 
+func (s *S) TestAddNodeWithSchedulingPolicy(c *check.C) {
+	config.Set("kubernetes:register-node", true)
+	defer config.Unset("kubernetes:register-node")
+	s.clusterClient.CustomData["p1:"+schedulingPolicyKey] = `{"taints": [{"key": "spot", "value": "true", "effect": "NoSchedule"}], "dedicated": true}`
+	defer delete(s.clusterClient.CustomData, "p1:"+schedulingPolicyKey)
+	s.mock.WaitNodeUpdate(c, func() {
+		err := s.p.AddNode(context.TODO(), provision.AddNodeOptions{
+			Address: "my-node-addr",
+			Pool:    "p1",
+		})
+		c.Assert(err, check.IsNil)
+	})
+	nodes, err := s.p.ListNodes(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(nodes, check.HasLen, 1)
+	c.Assert(nodes[0].(*kubernetesNodeWrapper).node.Spec.Taints, check.DeepEquals, []apiv1.Taint{
+		{Key: "spot", Value: "true", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "tsuru.io/dedicated-pool", Value: "p1", Effect: apiv1.TaintEffectNoSchedule},
+	})
+}
+
 func (s *S) TestAddNodePrefixed(c *check.C) {
 	config.Set("kubernetes:register-node", true)
 	defer config.Unset("kubernetes:register-node")
@@ -1568,6 +1590,40 @@ func (s *S) TestDeploy(c *check.C) {
 	})
 }
 
+func (s *S) TestDeployBuiltImageReady(c *check.C) {
+	a, _, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypeApp, Value: a.GetName()},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermAppDeploy),
+	})
+	c.Assert(err, check.IsNil)
+	version := newVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "run mycmd arg1",
+		},
+	})
+	var readyImage string
+	_, err = s.p.Deploy(context.TODO(), provision.DeployArgs{
+		App:     a,
+		Version: version,
+		Event:   evt,
+		ImageReady: func(ctx context.Context, v appTypes.AppVersion) error {
+			readyImage = v.VersionInfo().DeployImage
+			return errors.New("version blocked")
+		},
+	})
+	c.Assert(err, check.ErrorMatches, "version blocked")
+	c.Assert(readyImage, check.Equals, "tsuru/app-myapp:v1")
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	deps, err := s.client.AppsV1().Deployments(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(deps.Items, check.HasLen, 0)
+}
+
 func (s *S) TestDeployCreatesAppCR(c *check.C) {
 	a, _, rollback := s.mock.DefaultReactions(c)
 	defer rollback()
@@ -0,0 +1,176 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const tsuruDedicatedPoolTaint = tsuruLabelPrefix + "dedicated-pool"
+
+// poolSchedulingPolicy restricts where the pods of a pool may run, on top of
+// the pool node selector. Taints are applied to the nodes added to the pool
+// and tolerated by its pods, dedicated pools also taint their nodes so that
+// only pods of the pool are scheduled on them.
+type poolSchedulingPolicy struct {
+	RequiredAffinity  []apiv1.NodeSelectorRequirement `json:"requiredAffinity,omitempty"`
+	PreferredAffinity []apiv1.PreferredSchedulingTerm `json:"preferredAffinity,omitempty"`
+	Tolerations       []apiv1.Toleration              `json:"tolerations,omitempty"`
+	Taints            []apiv1.Taint                   `json:"taints,omitempty"`
+	Dedicated         bool                            `json:"dedicated,omitempty"`
+}
+
+func (p poolSchedulingPolicy) nodeTaints(pool string) []apiv1.Taint {
+	taints := append([]apiv1.Taint{}, p.Taints...)
+	if p.Dedicated {
+		taints = append(taints, apiv1.Taint{
+			Key:    tsuruDedicatedPoolTaint,
+			Value:  pool,
+			Effect: apiv1.TaintEffectNoSchedule,
+		})
+	}
+	return taints
+}
+
+func (p poolSchedulingPolicy) podTolerations(pool string) []apiv1.Toleration {
+	tolerations := append([]apiv1.Toleration{}, p.Tolerations...)
+	for _, taint := range p.nodeTaints(pool) {
+		tolerations = append(tolerations, apiv1.Toleration{
+			Key:      taint.Key,
+			Operator: apiv1.TolerationOpEqual,
+			Value:    taint.Value,
+			Effect:   taint.Effect,
+		})
+	}
+	return tolerations
+}
+
+func (p poolSchedulingPolicy) nodeAffinity() *apiv1.NodeAffinity {
+	if len(p.RequiredAffinity) == 0 && len(p.PreferredAffinity) == 0 {
+		return nil
+	}
+	affinity := &apiv1.NodeAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: p.PreferredAffinity,
+	}
+	if len(p.RequiredAffinity) > 0 {
+		affinity.RequiredDuringSchedulingIgnoredDuringExecution = &apiv1.NodeSelector{
+			NodeSelectorTerms: []apiv1.NodeSelectorTerm{{
+				MatchExpressions: p.RequiredAffinity,
+			}},
+		}
+	}
+	return affinity
+}
+
+// applySchedulingPolicy sets the node affinity and tolerations of a pod
+// running in the pool.
+func applySchedulingPolicy(client *ClusterClient, pool string, spec *apiv1.PodSpec) error {
+	policy, err := client.schedulingPolicy(pool)
+	if err != nil {
+		return err
+	}
+	if tolerations := policy.podTolerations(pool); len(tolerations) > 0 {
+		spec.Tolerations = append(spec.Tolerations, tolerations...)
+	}
+	nodeAffinity := policy.nodeAffinity()
+	if nodeAffinity == nil {
+		return nil
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &apiv1.Affinity{}
+	}
+	spec.Affinity.NodeAffinity = nodeAffinity
+	return nil
+}
+
+// applyArchitectures restricts the pod to nodes of the architectures its
+// image is available for. Pods of versions with unknown architectures are not
+// restricted.
+func applyArchitectures(spec *apiv1.PodSpec, architectures []string) {
+	if len(architectures) == 0 {
+		return
+	}
+	requirement := apiv1.NodeSelectorRequirement{
+		Key:      apiv1.LabelArchStable,
+		Operator: apiv1.NodeSelectorOpIn,
+		Values:   architectures,
+	}
+	if spec.Affinity == nil {
+		spec.Affinity = &apiv1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &apiv1.NodeAffinity{}
+	}
+	nodeAffinity := spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &apiv1.NodeSelector{}
+	}
+	required := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(required.NodeSelectorTerms) == 0 {
+		required.NodeSelectorTerms = []apiv1.NodeSelectorTerm{{}}
+	}
+	// Terms are ORed, so the requirement must be in each one of them.
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+}
+
+// setNodeTaints adds the taints of the pool policy to the node, removing the
+// taints of the policy of the pool the node belonged to and the dedicated
+// taints of any other pool.
+func setNodeTaints(node *apiv1.Node, pool string, policy, oldPolicy poolSchedulingPolicy) {
+	taints := node.Spec.Taints[:0]
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == tsuruDedicatedPoolTaint && taint.Value != pool {
+			continue
+		}
+		if hasTaint(oldPolicy.Taints, taint) {
+			continue
+		}
+		taints = append(taints, taint)
+	}
+	for _, taint := range policy.nodeTaints(pool) {
+		found := false
+		for i := range taints {
+			if taints[i].Key == taint.Key && taints[i].Effect == taint.Effect {
+				taints[i].Value = taint.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			taints = append(taints, taint)
+		}
+	}
+	node.Spec.Taints = taints
+}
+
+func hasTaint(taints []apiv1.Taint, taint apiv1.Taint) bool {
+	for _, t := range taints {
+		if t.Key == taint.Key && t.Value == taint.Value && t.Effect == taint.Effect {
+			return true
+		}
+	}
+	return false
+}
+
+func setNodeTaintsForPool(client *ClusterClient, node *apiv1.Node, oldPool, pool string) error {
+	if pool == "" {
+		return nil
+	}
+	policy, err := client.schedulingPolicy(pool)
+	if err != nil {
+		return err
+	}
+	var oldPolicy poolSchedulingPolicy
+	if oldPool != "" && oldPool != pool {
+		oldPolicy, err = client.schedulingPolicy(oldPool)
+		if err != nil {
+			return err
+		}
+	}
+	setNodeTaints(node, pool, policy, oldPolicy)
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"github.com/tsuru/tsuru/provision/servicecommon"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func (s *S) TestApplySchedulingPolicy(c *check.C) {
+	s.clusterClient.CustomData["p1:"+schedulingPolicyKey] = `{
+		"requiredAffinity": [{"key": "node.kubernetes.io/instance-type", "operator": "In", "values": ["r5.xlarge"]}],
+		"preferredAffinity": [{"weight": 10, "preference": {"matchExpressions": [{"key": "spot", "operator": "DoesNotExist"}]}}],
+		"tolerations": [{"key": "spot", "operator": "Exists", "effect": "NoSchedule"}],
+		"taints": [{"key": "high-memory", "value": "true", "effect": "NoSchedule"}],
+		"dedicated": true
+	}`
+	defer delete(s.clusterClient.CustomData, "p1:"+schedulingPolicyKey)
+	spec := apiv1.PodSpec{}
+	err := applySchedulingPolicy(s.clusterClient, "p1", &spec)
+	c.Assert(err, check.IsNil)
+	c.Assert(spec.Tolerations, check.DeepEquals, []apiv1.Toleration{
+		{Key: "spot", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "high-memory", Operator: apiv1.TolerationOpEqual, Value: "true", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "tsuru.io/dedicated-pool", Operator: apiv1.TolerationOpEqual, Value: "p1", Effect: apiv1.TaintEffectNoSchedule},
+	})
+	c.Assert(spec.Affinity, check.DeepEquals, &apiv1.Affinity{
+		NodeAffinity: &apiv1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+				NodeSelectorTerms: []apiv1.NodeSelectorTerm{{
+					MatchExpressions: []apiv1.NodeSelectorRequirement{
+						{Key: "node.kubernetes.io/instance-type", Operator: apiv1.NodeSelectorOpIn, Values: []string{"r5.xlarge"}},
+					},
+				}},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []apiv1.PreferredSchedulingTerm{
+				{
+					Weight: 10,
+					Preference: apiv1.NodeSelectorTerm{
+						MatchExpressions: []apiv1.NodeSelectorRequirement{
+							{Key: "spot", Operator: apiv1.NodeSelectorOpDoesNotExist},
+						},
+					},
+				},
+			},
+		},
+	})
+	spec = apiv1.PodSpec{}
+	err = applySchedulingPolicy(s.clusterClient, "p2", &spec)
+	c.Assert(err, check.IsNil)
+	c.Assert(spec, check.DeepEquals, apiv1.PodSpec{})
+}
+
+func (s *S) TestApplySchedulingPolicyInvalid(c *check.C) {
+	s.clusterClient.CustomData[schedulingPolicyKey] = `{"taints": "x"}`
+	defer delete(s.clusterClient.CustomData, schedulingPolicyKey)
+	err := applySchedulingPolicy(s.clusterClient, "p1", &apiv1.PodSpec{})
+	c.Assert(err, check.ErrorMatches, "misconfigured cluster scheduling policy: .*")
+}
+
+func (s *S) TestSetNodeTaints(c *check.C) {
+	node := &apiv1.Node{
+		Spec: apiv1.NodeSpec{
+			Taints: []apiv1.Taint{
+				{Key: "tsuru.io/disabled", Effect: apiv1.TaintEffectNoSchedule},
+				{Key: "tsuru.io/dedicated-pool", Value: "p2", Effect: apiv1.TaintEffectNoSchedule},
+				{Key: "spot", Value: "false", Effect: apiv1.TaintEffectNoSchedule},
+			},
+		},
+	}
+	setNodeTaints(node, "p1", poolSchedulingPolicy{
+		Taints:    []apiv1.Taint{{Key: "spot", Value: "true", Effect: apiv1.TaintEffectNoSchedule}},
+		Dedicated: true,
+	}, poolSchedulingPolicy{})
+	c.Assert(node.Spec.Taints, check.DeepEquals, []apiv1.Taint{
+		{Key: "tsuru.io/disabled", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "spot", Value: "true", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "tsuru.io/dedicated-pool", Value: "p1", Effect: apiv1.TaintEffectNoSchedule},
+	})
+}
+
+func (s *S) TestSetNodeTaintsRemovesOldPoolTaints(c *check.C) {
+	node := &apiv1.Node{
+		Spec: apiv1.NodeSpec{
+			Taints: []apiv1.Taint{
+				{Key: "tsuru.io/disabled", Effect: apiv1.TaintEffectNoSchedule},
+				{Key: "gpu", Value: "true", Effect: apiv1.TaintEffectNoSchedule},
+				{Key: "maintenance", Value: "true", Effect: apiv1.TaintEffectNoExecute},
+			},
+		},
+	}
+	setNodeTaints(node, "p1", poolSchedulingPolicy{
+		Taints: []apiv1.Taint{{Key: "spot", Value: "true", Effect: apiv1.TaintEffectNoSchedule}},
+	}, poolSchedulingPolicy{
+		Taints: []apiv1.Taint{{Key: "gpu", Value: "true", Effect: apiv1.TaintEffectNoSchedule}},
+	})
+	c.Assert(node.Spec.Taints, check.DeepEquals, []apiv1.Taint{
+		{Key: "tsuru.io/disabled", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "maintenance", Value: "true", Effect: apiv1.TaintEffectNoExecute},
+		{Key: "spot", Value: "true", Effect: apiv1.TaintEffectNoSchedule},
+	})
+}
+
+func (s *S) TestNodeContainerTolerations(c *check.C) {
+	s.clusterClient.CustomData["p1:"+schedulingPolicyKey] = `{"taints": [{"key": "spot", "value": "true", "effect": "NoSchedule"}], "dedicated": true}`
+	defer delete(s.clusterClient.CustomData, "p1:"+schedulingPolicyKey)
+	s.clusterClient.CustomData["p2:"+schedulingPolicyKey] = `{"taints": [{"key": "gpu", "value": "true", "effect": "NoSchedule"}]}`
+	defer delete(s.clusterClient.CustomData, "p2:"+schedulingPolicyKey)
+	baseTolerations := []apiv1.Toleration{
+		{Key: "tsuru.io/disabled", Operator: apiv1.TolerationOpExists},
+		{Key: "tsuru.io/dedicated-pool", Operator: apiv1.TolerationOpExists},
+	}
+	spotToleration := apiv1.Toleration{Key: "spot", Operator: apiv1.TolerationOpEqual, Value: "true", Effect: apiv1.TaintEffectNoSchedule}
+	gpuToleration := apiv1.Toleration{Key: "gpu", Operator: apiv1.TolerationOpEqual, Value: "true", Effect: apiv1.TaintEffectNoSchedule}
+	tolerations, err := nodeContainerTolerations(s.clusterClient, "p1", servicecommon.PoolFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(tolerations, check.DeepEquals, append(append([]apiv1.Toleration{}, baseTolerations...), spotToleration))
+	tolerations, err = nodeContainerTolerations(s.clusterClient, "", servicecommon.PoolFilter{Include: []string{"p1", "p2"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(tolerations, check.DeepEquals, append(append([]apiv1.Toleration{}, baseTolerations...), spotToleration, gpuToleration))
+	tolerations, err = nodeContainerTolerations(s.clusterClient, "", servicecommon.PoolFilter{Include: []string{"p1", "p2"}, Exclude: []string{"p1"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(tolerations, check.DeepEquals, append(append([]apiv1.Toleration{}, baseTolerations...), gpuToleration))
+}
+
+func (s *S) TestApplyArchitectures(c *check.C) {
+	spec := apiv1.PodSpec{}
+	applyArchitectures(&spec, nil)
+	c.Assert(spec, check.DeepEquals, apiv1.PodSpec{})
+	applyArchitectures(&spec, []string{"amd64", "arm64"})
+	archRequirement := apiv1.NodeSelectorRequirement{Key: "kubernetes.io/arch", Operator: apiv1.NodeSelectorOpIn, Values: []string{"amd64", "arm64"}}
+	c.Assert(spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, check.DeepEquals, &apiv1.NodeSelector{
+		NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+			{MatchExpressions: []apiv1.NodeSelectorRequirement{archRequirement}},
+		},
+	})
+	spec = apiv1.PodSpec{
+		Affinity: &apiv1.Affinity{
+			NodeAffinity: &apiv1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+					NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+						{MatchExpressions: []apiv1.NodeSelectorRequirement{{Key: "zone", Operator: apiv1.NodeSelectorOpIn, Values: []string{"a"}}}},
+						{MatchExpressions: []apiv1.NodeSelectorRequirement{{Key: "zone", Operator: apiv1.NodeSelectorOpIn, Values: []string{"b"}}}},
+					},
+				},
+			},
+		},
+	}
+	applyArchitectures(&spec, []string{"amd64", "arm64"})
+	c.Assert(spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, check.DeepEquals, &apiv1.NodeSelector{
+		NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+			{MatchExpressions: []apiv1.NodeSelectorRequirement{{Key: "zone", Operator: apiv1.NodeSelectorOpIn, Values: []string{"a"}}, archRequirement}},
+			{MatchExpressions: []apiv1.NodeSelectorRequirement{{Key: "zone", Operator: apiv1.NodeSelectorOpIn, Values: []string{"b"}}, archRequirement}},
+		},
+	})
+}
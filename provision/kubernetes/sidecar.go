@@ -0,0 +1,106 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+type processContainers struct {
+	initContainers []apiv1.Container
+	sidecars       []apiv1.Container
+	volumes        []apiv1.Volume
+	mounts         []apiv1.VolumeMount
+}
+
+// containersForProcess returns the init containers and sidecars of the
+// process, declared both in tsuru.yaml and in the cluster config for the app
+// pool. Images declared in tsuru.yaml must be allowed by the sidecar-image
+// constraint of the pool. Every container gets the resources of the process,
+// as set by the app plan.
+func containersForProcess(ctx context.Context, client *ClusterClient, a provision.App, yamlData provTypes.TsuruYamlData, process string, resources apiv1.ResourceRequirements) (processContainers, error) {
+	var config provTypes.TsuruYamlKubernetesProcessConfig
+	if yamlData.Kubernetes != nil {
+		if processConfig := yamlData.Kubernetes.GetProcessConfigs(process); processConfig != nil {
+			config = *processConfig
+		}
+	}
+	if images := config.Images(); len(images) > 0 {
+		p, err := pool.GetPoolByName(ctx, a.GetPool())
+		if err != nil {
+			return processContainers{}, err
+		}
+		err = p.ValidateSidecarImages(images)
+		if err != nil {
+			return processContainers{}, err
+		}
+	}
+	poolInitContainers, err := client.poolContainers(a.GetPool(), initContainersKey)
+	if err != nil {
+		return processContainers{}, err
+	}
+	poolSidecars, err := client.poolContainers(a.GetPool(), sidecarsKey)
+	if err != nil {
+		return processContainers{}, err
+	}
+	config.InitContainers = append(poolInitContainers, config.InitContainers...)
+	config.Sidecars = append(poolSidecars, config.Sidecars...)
+	err = config.ValidateContainers()
+	if err != nil {
+		return processContainers{}, errors.Wrapf(err, "invalid containers for process %q", process)
+	}
+	var result processContainers
+	for _, v := range config.SharedVolumes {
+		result.volumes = append(result.volumes, apiv1.Volume{
+			Name: v.Name,
+			VolumeSource: apiv1.VolumeSource{
+				EmptyDir: &apiv1.EmptyDirVolumeSource{},
+			},
+		})
+		if v.MountPath != "" {
+			result.mounts = append(result.mounts, apiv1.VolumeMount{
+				Name:      v.Name,
+				MountPath: v.MountPath,
+			})
+		}
+	}
+	for _, c := range config.InitContainers {
+		result.initContainers = append(result.initContainers, containerFromYaml(c, resources))
+	}
+	for _, c := range config.Sidecars {
+		result.sidecars = append(result.sidecars, containerFromYaml(c, resources))
+	}
+	return result, nil
+}
+
+func containerFromYaml(c provTypes.TsuruYamlKubernetesContainer, resources apiv1.ResourceRequirements) apiv1.Container {
+	container := apiv1.Container{
+		Name:      c.Name,
+		Image:     c.Image,
+		Command:   c.Command,
+		Args:      c.Args,
+		Resources: resources,
+	}
+	for _, m := range c.VolumeMounts {
+		container.VolumeMounts = append(container.VolumeMounts, apiv1.VolumeMount{
+			Name:      m.Name,
+			MountPath: m.MountPath,
+		})
+	}
+	for name, value := range c.Env {
+		container.Env = append(container.Env, apiv1.EnvVar{Name: name, Value: value})
+	}
+	sort.Slice(container.Env, func(i, j int) bool {
+		return container.Env[i].Name < container.Env[j].Name
+	})
+	return container
+}
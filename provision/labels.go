@@ -26,6 +26,7 @@ var (
 	labelIsService         = "is-service"
 	labelIsHeadlessService = "is-headless-service"
 	labelIsRoutable        = "is-routable"
+	labelIsJob             = "is-job"
 
 	labelAppName     = "app-name"
 	labelAppProcess  = "app-process"
@@ -33,6 +34,8 @@ var (
 	labelAppPlatform = "app-platform"
 	labelAppVersion  = "app-version"
 
+	labelJobName = "job-name"
+
 	labelNodeContainerName = "node-container-name"
 	labelNodeContainerPool = "node-container-pool"
 
@@ -125,6 +128,18 @@ func (s *LabelSet) ToAppSelector() map[string]string {
 	return withPrefix(subMap(s.Labels, labelAppName), s.Prefix)
 }
 
+// ToAllProcessesSelector selects the units of every process and version of
+// the app, leaving out build pods.
+func (s *LabelSet) ToAllProcessesSelector() map[string]string {
+	return withPrefix(subMap(s.Labels, labelAppName, labelIsBuild), s.Prefix)
+}
+
+// AppNameKey returns the key of the label holding the app name, used to
+// select units of several apps at once.
+func (s *LabelSet) AppNameKey() string {
+	return s.Prefix + labelAppName
+}
+
 func (s *LabelSet) ToNodeContainerSelector() map[string]string {
 	return withPrefix(subMap(s.Labels, labelNodeContainerName, labelNodeContainerPool), s.Prefix)
 }
@@ -153,6 +168,14 @@ func (s *LabelSet) ToHPASelector() map[string]string {
 	return withPrefix(subMap(s.Labels, keys...), s.Prefix)
 }
 
+func (s *LabelSet) ToJobSelector() map[string]string {
+	keys := []string{labelAppName, labelIsJob}
+	if s.getLabel(labelJobName) != "" {
+		keys = append(keys, labelJobName)
+	}
+	return withPrefix(subMap(s.Labels, keys...), s.Prefix)
+}
+
 func (s *LabelSet) AppName() string {
 	return s.getLabel(labelAppName)
 }
@@ -305,6 +328,14 @@ func (s *LabelSet) IsBase() bool {
 	return s.hasLabel(labelIsIsolatedRun)
 }
 
+func (s *LabelSet) IsJob() bool {
+	return s.getBoolLabel(labelIsJob)
+}
+
+func (s *LabelSet) JobName() string {
+	return s.getLabel(labelJobName)
+}
+
 func (s *LabelSet) IsRoutable() bool {
 	return s.getBoolLabel(labelIsRoutable)
 }
@@ -341,6 +372,13 @@ func (s *LabelSet) ToggleIsRoutable(isRoutable bool) {
 	s.addLabel(labelIsRoutable, strconv.FormatBool(isRoutable))
 }
 
+func (s *LabelSet) SetJob(name string) {
+	s.addLabel(labelIsJob, strconv.FormatBool(true))
+	if name != "" {
+		s.addLabel(labelJobName, name)
+	}
+}
+
 func (s *LabelSet) SetBuildImage(image string) {
 	s.addLabel(labelBuildImage, image)
 }
@@ -45,6 +45,30 @@ func (s *S) TestLabelSetSelectors(c *check.C) {
 	})
 }
 
+func (s *S) TestLabelSetJob(c *check.C) {
+	ls := &provision.LabelSet{
+		Labels: map[string]string{
+			"app-name":    "app",
+			"app-process": "proc",
+		},
+		Prefix: "tsuru.io/",
+	}
+	c.Assert(ls.IsJob(), check.Equals, false)
+	ls.SetJob("")
+	c.Assert(ls.IsJob(), check.Equals, true)
+	c.Assert(ls.ToJobSelector(), check.DeepEquals, map[string]string{
+		"tsuru.io/app-name": "app",
+		"tsuru.io/is-job":   "true",
+	})
+	ls.SetJob("cleanup")
+	c.Assert(ls.JobName(), check.Equals, "cleanup")
+	c.Assert(ls.ToJobSelector(), check.DeepEquals, map[string]string{
+		"tsuru.io/app-name": "app",
+		"tsuru.io/is-job":   "true",
+		"tsuru.io/job-name": "cleanup",
+	})
+}
+
 func (s *S) TestProcessLabels(c *check.C) {
 	config.Set("routers:fake:type", "fake")
 	defer config.Unset("routers")
@@ -19,7 +19,7 @@ import (
 
 var (
 	ErrInvalidConstraintType = errors.Errorf("invalid constraint type. Valid types are: %s", validConstraintTypes)
-	validConstraintTypes     = []poolConstraintType{ConstraintTypeTeam, ConstraintTypeService, ConstraintTypeRouter, ConstraintTypePlan}
+	validConstraintTypes     = []poolConstraintType{ConstraintTypeTeam, ConstraintTypeService, ConstraintTypeRouter, ConstraintTypePlan, ConstraintTypeSidecarImage, ConstraintTypeImageSigner, ConstraintTypeVulnerabilitySeverity, ConstraintTypeCNBBuilderImage}
 )
 
 type poolConstraintType string
@@ -29,6 +29,13 @@ const (
 	ConstraintTypeRouter  = poolConstraintType("router")
 	ConstraintTypeService = poolConstraintType("service")
 	ConstraintTypePlan    = poolConstraintType("plan")
+
+	ConstraintTypeSidecarImage = poolConstraintType("sidecar-image")
+	ConstraintTypeImageSigner  = poolConstraintType("image-signer")
+
+	ConstraintTypeVulnerabilitySeverity = poolConstraintType("vulnerability-severity")
+
+	ConstraintTypeCNBBuilderImage = poolConstraintType("cnb-builder-image")
 )
 
 type regexpCache struct {
@@ -162,6 +162,88 @@ func (p *Pool) ValidateRouters(routers []appTypes.AppRouter) error {
 	return nil
 }
 
+// ValidateSidecarImages checks whether the images of init containers and
+// sidecars are allowed by the sidecar-image constraint of the pool. Pools
+// without this constraint allow any image.
+func (p *Pool) ValidateSidecarImages(images []string) error {
+	if len(images) == 0 {
+		return nil
+	}
+	constraints, err := getConstraintsForPool(p.Name, ConstraintTypeSidecarImage)
+	if err != nil {
+		return err
+	}
+	constraint := constraints[ConstraintTypeSidecarImage]
+	if constraint == nil {
+		return nil
+	}
+	for _, img := range images {
+		if !constraint.check(img) {
+			msg := fmt.Sprintf("image %q is not allowed for sidecars on pool %q", img, p.Name)
+			return &tsuruErrors.ValidationError{Message: msg}
+		}
+	}
+	return nil
+}
+
+// ValidateCNBBuilderImage checks whether apps in the pool may be built with
+// their own buildpacks builder image, which is allowed by the
+// cnb-builder-image constraint of the pool. Pools without this constraint
+// only build apps with the builder image set in the cluster.
+func (p *Pool) ValidateCNBBuilderImage(img string) error {
+	constraints, err := getConstraintsForPool(p.Name, ConstraintTypeCNBBuilderImage)
+	if err != nil {
+		return err
+	}
+	if !constraints[ConstraintTypeCNBBuilderImage].check(img) {
+		msg := fmt.Sprintf("buildpacks builder image %q is not allowed on pool %q", img, p.Name)
+		return &tsuruErrors.ValidationError{Message: msg}
+	}
+	return nil
+}
+
+// GetImageSigners returns the names of the keys trusted to sign images
+// deployed in the pool, set by the image-signer constraint. Pools without
+// this constraint accept unsigned images.
+func (p *Pool) GetImageSigners() ([]string, error) {
+	constraints, err := getConstraintsForPool(p.Name, ConstraintTypeImageSigner)
+	if err != nil {
+		return nil, err
+	}
+	constraint := constraints[ConstraintTypeImageSigner]
+	if constraint == nil || constraint.Blacklist {
+		return nil, nil
+	}
+	var signers []string
+	for _, v := range constraint.Values {
+		if v != "" && v != "*" {
+			signers = append(signers, v)
+		}
+	}
+	return signers, nil
+}
+
+// GetVulnerabilitySeverity returns the minimum severity of the known
+// vulnerabilities blocking versions of apps in the pool from becoming
+// routable, set by the vulnerability-severity constraint. Pools without this
+// constraint don't block vulnerable versions.
+func (p *Pool) GetVulnerabilitySeverity() (string, error) {
+	constraints, err := getConstraintsForPool(p.Name, ConstraintTypeVulnerabilitySeverity)
+	if err != nil {
+		return "", err
+	}
+	constraint := constraints[ConstraintTypeVulnerabilitySeverity]
+	if constraint == nil || constraint.Blacklist {
+		return "", nil
+	}
+	for _, v := range constraint.Values {
+		if v != "" && v != "*" {
+			return v, nil
+		}
+	}
+	return "", nil
+}
+
 func (p *Pool) allowedValues() (map[poolConstraintType][]string, error) {
 	teams, err := teamsNames(p.ctx)
 	if err != nil {
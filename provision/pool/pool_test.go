@@ -101,6 +101,66 @@ func (s *S) TestValidateRouters(c *check.C) {
 	c.Assert(err, check.NotNil)
 }
 
+func (s *S) TestValidateSidecarImages(c *check.C) {
+	pool := Pool{Name: "pool1"}
+	err := pool.ValidateSidecarImages(nil)
+	c.Assert(err, check.IsNil)
+	err = pool.ValidateSidecarImages([]string{"envoyproxy/envoy:v1.16"})
+	c.Assert(err, check.IsNil)
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool*", Field: ConstraintTypeSidecarImage, Values: []string{"envoyproxy/envoy:*", "busybox"}})
+	c.Assert(err, check.IsNil)
+	err = pool.ValidateSidecarImages([]string{"envoyproxy/envoy:v1.16", "busybox"})
+	c.Assert(err, check.IsNil)
+	err = pool.ValidateSidecarImages([]string{"busybox:latest"})
+	c.Assert(err, check.DeepEquals, &tsuruErrors.ValidationError{Message: `image "busybox:latest" is not allowed for sidecars on pool "pool1"`})
+}
+
+func (s *S) TestValidateCNBBuilderImage(c *check.C) {
+	pool := Pool{Name: "pool1"}
+	err := pool.ValidateCNBBuilderImage("paketobuildpacks/builder:base")
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool*", Field: ConstraintTypeCNBBuilderImage, Values: []string{"paketobuildpacks/builder:*"}})
+	c.Assert(err, check.IsNil)
+	err = pool.ValidateCNBBuilderImage("paketobuildpacks/builder:base")
+	c.Assert(err, check.IsNil)
+	err = pool.ValidateCNBBuilderImage("evil/builder:latest")
+	c.Assert(err, check.DeepEquals, &tsuruErrors.ValidationError{Message: `buildpacks builder image "evil/builder:latest" is not allowed on pool "pool1"`})
+}
+
+func (s *S) TestGetImageSigners(c *check.C) {
+	pool := Pool{Name: "pool1"}
+	signers, err := pool.GetImageSigners()
+	c.Assert(err, check.IsNil)
+	c.Assert(signers, check.IsNil)
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool*", Field: ConstraintTypeImageSigner, Values: []string{"release", "ci"}})
+	c.Assert(err, check.IsNil)
+	signers, err = pool.GetImageSigners()
+	c.Assert(err, check.IsNil)
+	c.Assert(signers, check.DeepEquals, []string{"release", "ci"})
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool1", Field: ConstraintTypeImageSigner, Values: []string{"*"}})
+	c.Assert(err, check.IsNil)
+	signers, err = pool.GetImageSigners()
+	c.Assert(err, check.IsNil)
+	c.Assert(signers, check.IsNil)
+}
+
+func (s *S) TestGetVulnerabilitySeverity(c *check.C) {
+	pool := Pool{Name: "pool1"}
+	severity, err := pool.GetVulnerabilitySeverity()
+	c.Assert(err, check.IsNil)
+	c.Assert(severity, check.Equals, "")
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool*", Field: ConstraintTypeVulnerabilitySeverity, Values: []string{"high"}})
+	c.Assert(err, check.IsNil)
+	severity, err = pool.GetVulnerabilitySeverity()
+	c.Assert(err, check.IsNil)
+	c.Assert(severity, check.Equals, "high")
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool1", Field: ConstraintTypeVulnerabilitySeverity, Values: []string{"high"}, Blacklist: true})
+	c.Assert(err, check.IsNil)
+	severity, err = pool.GetVulnerabilitySeverity()
+	c.Assert(err, check.IsNil)
+	c.Assert(severity, check.Equals, "")
+}
+
 func (s *S) TestAddPool(c *check.C) {
 	msg := "Invalid pool name, pool name should have at most 40 " +
 		"characters, containing only lower case letters, numbers or dashes, " +
@@ -230,6 +230,8 @@ type App interface {
 
 	GetMemory() int64
 	GetMilliCPU() int
+	GetProcessMemory(process string) int64
+	GetProcessMilliCPU(process string) int
 	GetSwap() int64
 	GetCpuShare() int
 
@@ -288,11 +290,29 @@ type BuilderKubeClient interface {
 	DownloadFromContainer(context.Context, App, *event.Event, string) (io.ReadCloser, error)
 }
 
+// BuilderKubeClientCNB is a BuilderKubeClient able to build app images
+// running the Cloud Native Buildpacks lifecycle.
+type BuilderKubeClientCNB interface {
+	// CNBBuilderImage returns the buildpacks builder image used to build the
+	// app, or an empty string if the app isn't built with buildpacks.
+	CNBBuilderImage(ctx context.Context, a App) (string, error)
+	BuildCNBPod(ctx context.Context, a App, evt *event.Event, archiveFile io.Reader, version appTypes.AppVersion, builderImage string) error
+	// RebaseCNBPod saves previousImage, built with buildpacks, as the image
+	// of the version, on top of the current run image of the builder.
+	RebaseCNBPod(ctx context.Context, a App, evt *event.Event, previousImage string, version appTypes.AppVersion, builderImage string) error
+}
+
 type DeployArgs struct {
 	App              App
 	Version          appTypes.AppVersion
 	Event            *event.Event
 	PreserveVersions bool
+	// ImageReady is called by provisioners building the version image during
+	// the deploy, once the image is pushed and before units are started.
+	ImageReady func(context.Context, appTypes.AppVersion) error
+	// Verify is called once the units of the version are deployed, before
+	// the deploy is committed. Errors roll the deploy back.
+	Verify func(context.Context, appTypes.AppVersion) error
 }
 
 // BuilderDeploy is a provisioner that allows deploy builded image.
@@ -413,6 +433,29 @@ type AppInternalAddress struct {
 	Port     int32
 }
 
+// NetworkPolicy describes the traffic allowed to and from the units of an
+// app: ingress from the units of IngressApps and egress to the units of
+// EgressApps and to EgressCIDRs.
+type NetworkPolicy struct {
+	IngressApps []string
+	EgressApps  []string
+	EgressCIDRs []string
+}
+
+// NetworkPolicyProvisioner is a provisioner able to restrict the network
+// traffic of app units.
+type NetworkPolicyProvisioner interface {
+	SetNetworkPolicy(ctx context.Context, a App, policy NetworkPolicy) error
+}
+
+// MultiClusterProvisioner is a provisioner able to run the units of an app
+// in multiple clusters backing the same pool.
+type MultiClusterProvisioner interface {
+	// Failover moves the units of the app away from the given drained
+	// cluster to the clusters still serving the app pool.
+	Failover(ctx context.Context, a App, cluster string, w io.Writer) error
+}
+
 // MessageProvisioner is a provisioner that provides a welcome message for
 // logging.
 type MessageProvisioner interface {
@@ -539,6 +582,13 @@ type CleanImageProvisioner interface {
 	CleanImage(appName string, image string) error
 }
 
+// RegistryCredentialProvisioner is a provisioner that stores copies of the
+// registry credentials of teams, which must be removed along with the
+// credential.
+type RegistryCredentialProvisioner interface {
+	RemoveRegistryCredential(ctx context.Context, team, registry string) error
+}
+
 type AutoScaleSpec struct {
 	Process    string `json:"process"`
 	MinUnits   uint   `json:"minUnits"`
@@ -74,6 +74,8 @@ type FakeApp struct {
 	Swap              int64
 	CpuShare          int
 	MilliCPU          int
+	ProcessMemory     map[string]int64
+	ProcessMilliCPU   map[string]int
 	commMut           sync.Mutex
 	Deploys           uint
 	env               map[string]bind.EnvVar
@@ -127,6 +129,20 @@ func (a *FakeApp) GetMemory() int64 {
 	return a.Memory
 }
 
+func (a *FakeApp) GetProcessMilliCPU(process string) int {
+	if cpu, ok := a.ProcessMilliCPU[process]; ok {
+		return cpu
+	}
+	return a.MilliCPU
+}
+
+func (a *FakeApp) GetProcessMemory(process string) int64 {
+	if memory, ok := a.ProcessMemory[process]; ok {
+		return memory
+	}
+	return a.Memory
+}
+
 func (a *FakeApp) GetSwap() int64 {
 	return a.Swap
 }
@@ -868,18 +884,33 @@ func (p *FakeProvisioner) Deploy(ctx context.Context, args provision.DeployArgs)
 	if err := p.getError("Deploy"); err != nil {
 		return "", err
 	}
+	image := args.Version.VersionInfo().DeployImage
+	if image == "" {
+		image = args.Version.VersionInfo().BuildImage
+		err := args.Version.CommitBaseImage()
+		if err != nil {
+			return "", err
+		}
+		if args.ImageReady != nil {
+			err = args.ImageReady(ctx, args.Version)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
 	p.mut.Lock()
 	defer p.mut.Unlock()
 	pApp, ok := p.apps[args.App.GetName()]
 	if !ok {
 		return "", errNotProvisioned
 	}
-	if args.Version.VersionInfo().DeployImage != "" {
-		pApp.image = args.Version.VersionInfo().DeployImage
-	} else {
-		pApp.image = args.Version.VersionInfo().BuildImage
-	}
+	pApp.image = image
 	args.Event.Write([]byte("Builder deploy called"))
+	if args.Verify != nil {
+		if err := args.Verify(ctx, args.Version); err != nil {
+			return "", err
+		}
+	}
 	p.apps[args.App.GetName()] = pApp
 	err := args.Version.CommitBaseImage()
 	if err != nil {
@@ -1491,3 +1522,81 @@ func (p *AutoScaleProvisioner) RemoveAutoScale(ctx context.Context, app provisio
 	}
 	return nil
 }
+
+type JobProvisioner struct {
+	*FakeProvisioner
+	jobs map[string][]provision.JobSpec
+	runs map[string][]provision.JobRun
+}
+
+var _ provision.JobProvisioner = &JobProvisioner{}
+
+func (p *JobProvisioner) ListJobs(ctx context.Context, app provision.App) ([]provision.JobSpec, error) {
+	return p.jobs[app.GetName()], nil
+}
+
+func (p *JobProvisioner) SetJob(ctx context.Context, app provision.App, spec provision.JobSpec) error {
+	if p.jobs == nil {
+		p.jobs = make(map[string][]provision.JobSpec)
+	}
+	jobs := p.jobs[app.GetName()]
+	for i := range jobs {
+		if jobs[i].Name == spec.Name {
+			jobs[i] = spec
+			return nil
+		}
+	}
+	p.jobs[app.GetName()] = append(jobs, spec)
+	return nil
+}
+
+func (p *JobProvisioner) RemoveJob(ctx context.Context, app provision.App, name string) error {
+	jobs := p.jobs[app.GetName()]
+	for i := range jobs {
+		if jobs[i].Name == name {
+			p.jobs[app.GetName()] = append(jobs[:i], jobs[i+1:]...)
+			return nil
+		}
+	}
+	return provision.ErrJobNotFound
+}
+
+func (p *JobProvisioner) ListJobRuns(ctx context.Context, app provision.App, name string) ([]provision.JobRun, error) {
+	for _, job := range p.jobs[app.GetName()] {
+		if job.Name == name {
+			return p.runs[app.GetName()+"/"+name], nil
+		}
+	}
+	return nil, provision.ErrJobNotFound
+}
+
+// AddJobRun adds a run to the history of the job.
+func (p *JobProvisioner) AddJobRun(app provision.App, run provision.JobRun) {
+	if p.runs == nil {
+		p.runs = make(map[string][]provision.JobRun)
+	}
+	key := app.GetName() + "/" + run.Job
+	p.runs[key] = append(p.runs[key], run)
+}
+
+type NetworkPolicyProvisioner struct {
+	*FakeProvisioner
+	policies map[string]provision.NetworkPolicy
+}
+
+var _ provision.NetworkPolicyProvisioner = &NetworkPolicyProvisioner{}
+
+func (p *NetworkPolicyProvisioner) SetNetworkPolicy(ctx context.Context, app provision.App, policy provision.NetworkPolicy) error {
+	if err := p.getError("SetNetworkPolicy"); err != nil {
+		return err
+	}
+	if p.policies == nil {
+		p.policies = make(map[string]provision.NetworkPolicy)
+	}
+	p.policies[app.GetName()] = policy
+	return nil
+}
+
+func (p *NetworkPolicyProvisioner) NetworkPolicy(app string) provision.NetworkPolicy {
+	return p.policies[app]
+}
@@ -41,6 +41,7 @@ type pipelineArgs struct {
 	newVersionSpec   ProcessSpec
 	event            *event.Event
 	preserveVersions bool
+	verify           func(context.Context, appTypes.AppVersion) error
 }
 
 type labelReplicas struct {
@@ -82,6 +83,7 @@ func RunServicePipeline(ctx context.Context, manager ServiceManager, oldVersionN
 	}
 	pipeline := action.NewPipeline(
 		updateServices,
+		verifyDeploy,
 		updateImageInDB,
 		removeOldServices,
 	)
@@ -94,6 +96,7 @@ func RunServicePipeline(ctx context.Context, manager ServiceManager, oldVersionN
 		newVersion:       args.Version,
 		newVersionSpec:   newSpec,
 		event:            args.Event,
+		verify:           args.Verify,
 	})
 }
 
@@ -234,6 +237,21 @@ var updateServices = &action.Action{
 	},
 }
 
+var verifyDeploy = &action.Action{
+	Name: "verify-deploy",
+	Forward: func(ctx action.FWContext) (action.Result, error) {
+		args := ctx.Params[0].(*pipelineArgs)
+		if args.verify == nil {
+			return ctx.Previous, nil
+		}
+		err := args.verify(ctx.Context, args.newVersion)
+		if err != nil {
+			return nil, err
+		}
+		return ctx.Previous, nil
+	},
+}
+
 var updateImageInDB = &action.Action{
 	Name: "update-image-in-db",
 	Forward: func(ctx action.FWContext) (action.Result, error) {
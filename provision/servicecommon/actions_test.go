@@ -263,6 +263,43 @@ func (s *S) TestRunServicePipelineNilSpec(c *check.C) {
 	c.Assert(newVersion.VersionInfo().DeploySuccessful, check.Equals, true)
 }
 
+func (s *S) TestRunServicePipelineVerifyFailure(c *check.C) {
+	m := &recordManager{}
+	fakeApp := provisiontest.NewFakeApp("myapp", "whitespace", 1)
+	oldVersion := newSuccessfulVersion(c, fakeApp, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python web1",
+		},
+	})
+	newVersion := newVersion(c, fakeApp, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python web2",
+		},
+	})
+	var verified appTypes.AppVersion
+	err := RunServicePipeline(context.TODO(), m, oldVersion.Version(), provision.DeployArgs{
+		App:     fakeApp,
+		Version: newVersion,
+		Verify: func(ctx context.Context, version appTypes.AppVersion) error {
+			verified = version
+			return errors.New("verification failed")
+		},
+	}, nil)
+	c.Assert(err, check.ErrorMatches, "verification failed")
+	c.Assert(verified, check.Equals, newVersion)
+	labelsWeb, err := provision.ServiceLabels(context.TODO(), provision.ServiceLabelsOpts{
+		App:     fakeApp,
+		Process: "web",
+		Version: 2,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(m.calls, check.DeepEquals, []managerCall{
+		{action: "deploy", app: fakeApp, processName: "web", version: newVersion, replicas: 1, labels: labelsWeb},
+		{action: "remove", app: fakeApp, processName: "web", versionNumber: newVersion.Version()},
+	})
+	c.Assert(newVersion.VersionInfo().DeploySuccessful, check.Equals, false)
+}
+
 func (s *S) TestRunServicePipelineSingleProcess(c *check.C) {
 	m := &recordManager{}
 	fakeApp := provisiontest.NewFakeApp("myapp", "whitespace", 1)
@@ -0,0 +1,150 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quota
+
+import (
+	"context"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/types/quota"
+)
+
+var _ quota.TeamQuotaService = &TeamQuotaService{}
+
+// TeamQuotaService limits the resources allocated by all apps and service
+// instances owned by a team. Usage is always calculated by the UsageReader,
+// only limits are stored.
+type TeamQuotaService struct {
+	Storage     quota.TeamQuotaStorage
+	UsageReader quota.TeamUsageReader
+}
+
+// Get implements Get method from TeamQuotaService interface
+func (s *TeamQuotaService) Get(ctx context.Context, team string) (*quota.TeamQuota, error) {
+	limit, err := s.getLimit(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.UsageReader.GetTeamUsage(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	apps := usage.Apps
+	if apps == nil {
+		apps = []quota.TeamAppUsage{}
+	}
+	return &quota.TeamQuota{
+		Limit: *limit,
+		InUse: usage.Total(),
+		Apps:  apps,
+	}, nil
+}
+
+// SetLimit redefines the limits of the team. Negative values mean unlimited
+// and no limit may be lower than the resources currently in use.
+func (s *TeamQuotaService) SetLimit(ctx context.Context, team string, limit quota.TeamResources) error {
+	limit = normalizeTeamLimit(limit)
+	usage, err := s.UsageReader.GetTeamUsage(ctx, team)
+	if err != nil {
+		return err
+	}
+	inUse := usage.Total()
+	for _, r := range teamResourceValues(limit, inUse, quota.TeamResources{}) {
+		if r.limit != -1 && r.limit < r.inUse {
+			return quota.ErrLimitLowerThanAllocated
+		}
+	}
+	return s.Storage.SetLimit(ctx, team, limit)
+}
+
+// Check implements Check method from TeamQuotaService interface
+func (s *TeamQuotaService) Check(ctx context.Context, team string, requested quota.TeamResources) error {
+	limit, err := s.getLimit(ctx, team)
+	if err != nil {
+		return err
+	}
+	if *limit == quota.UnlimitedTeamResources {
+		return nil
+	}
+	usage, err := s.UsageReader.GetTeamUsage(ctx, team)
+	if err != nil {
+		return err
+	}
+	for _, r := range teamResourceValues(*limit, usage.Total(), requested) {
+		if r.limit == -1 || r.requested <= 0 {
+			continue
+		}
+		if r.inUse+r.requested > r.limit {
+			available := r.limit - r.inUse
+			if available < 0 {
+				available = 0
+			}
+			return &quota.TeamQuotaExceededError{
+				Team:      team,
+				Resource:  r.name,
+				Requested: r.requested,
+				Available: available,
+			}
+		}
+	}
+	return nil
+}
+
+// getLimit returns the limit stored for the team, falling back to the
+// defaults in the config file for teams without a quota.
+func (s *TeamQuotaService) getLimit(ctx context.Context, team string) (*quota.TeamResources, error) {
+	limit, err := s.Storage.GetLimit(ctx, team)
+	if err == nil {
+		return limit, nil
+	}
+	if err != quota.ErrQuotaNotFound {
+		return nil, err
+	}
+	defaultLimit := quota.UnlimitedTeamResources
+	if v, err := config.GetInt("quota:units-per-team"); err == nil {
+		defaultLimit.Units = v
+	}
+	if v, err := config.GetInt("quota:memory-per-team"); err == nil {
+		defaultLimit.Memory = int64(v)
+	}
+	if v, err := config.GetInt("quota:cpu-milli-per-team"); err == nil {
+		defaultLimit.CPUMilli = v
+	}
+	if v, err := config.GetInt("quota:service-instances-per-team"); err == nil {
+		defaultLimit.ServiceInstances = v
+	}
+	defaultLimit = normalizeTeamLimit(defaultLimit)
+	return &defaultLimit, nil
+}
+
+func normalizeTeamLimit(limit quota.TeamResources) quota.TeamResources {
+	if limit.Units < 0 {
+		limit.Units = -1
+	}
+	if limit.Memory < 0 {
+		limit.Memory = -1
+	}
+	if limit.CPUMilli < 0 {
+		limit.CPUMilli = -1
+	}
+	if limit.ServiceInstances < 0 {
+		limit.ServiceInstances = -1
+	}
+	return limit
+}
+
+type teamResourceValue struct {
+	name                    string
+	limit, inUse, requested int64
+}
+
+func teamResourceValues(limit, inUse, requested quota.TeamResources) []teamResourceValue {
+	return []teamResourceValue{
+		{name: "units", limit: int64(limit.Units), inUse: int64(inUse.Units), requested: int64(requested.Units)},
+		{name: "memory", limit: limit.Memory, inUse: inUse.Memory, requested: requested.Memory},
+		{name: "cpu", limit: int64(limit.CPUMilli), inUse: int64(inUse.CPUMilli), requested: int64(requested.CPUMilli)},
+		{name: "service instances", limit: int64(limit.ServiceInstances), inUse: int64(inUse.ServiceInstances), requested: int64(requested.ServiceInstances)},
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quota
+
+import (
+	"context"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+func teamUsage() *quota.MockTeamUsageReader {
+	return &quota.MockTeamUsageReader{
+		OnGetTeamUsage: func(team string) (*quota.TeamUsage, error) {
+			return &quota.TeamUsage{
+				Apps: []quota.TeamAppUsage{
+					{App: "app1", Plan: "small", Units: 2, Memory: 2048, CPUMilli: 200},
+					{App: "app2", Plan: "large", Units: 1, Memory: 4096, CPUMilli: 1000},
+				},
+				ServiceInstances: 3,
+			}, nil
+		},
+	}
+}
+
+func teamLimit(limit *quota.TeamResources) *quota.MockTeamQuotaStorage {
+	return &quota.MockTeamQuotaStorage{
+		OnGetLimit: func(team string) (*quota.TeamResources, error) {
+			if limit == nil {
+				return nil, quota.ErrQuotaNotFound
+			}
+			return limit, nil
+		},
+		OnSetLimit: func(team string, l quota.TeamResources) error {
+			limit = &l
+			return nil
+		},
+	}
+}
+
+func (s *S) TestTeamQuotaGet(c *check.C) {
+	qs := &TeamQuotaService{
+		Storage:     teamLimit(&quota.TeamResources{Units: 10, Memory: -1, CPUMilli: 2000, ServiceInstances: 5}),
+		UsageReader: teamUsage(),
+	}
+	q, err := qs.Get(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q.Limit, check.DeepEquals, quota.TeamResources{Units: 10, Memory: -1, CPUMilli: 2000, ServiceInstances: 5})
+	c.Assert(q.InUse, check.DeepEquals, quota.TeamResources{Units: 3, Memory: 6144, CPUMilli: 1200, ServiceInstances: 3})
+	c.Assert(q.Apps, check.HasLen, 2)
+}
+
+func (s *S) TestTeamQuotaGetDefaultLimit(c *check.C) {
+	config.Set("quota:units-per-team", 20)
+	defer config.Unset("quota:units-per-team")
+	qs := &TeamQuotaService{Storage: teamLimit(nil), UsageReader: teamUsage()}
+	q, err := qs.Get(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q.Limit, check.DeepEquals, quota.TeamResources{Units: 20, Memory: -1, CPUMilli: -1, ServiceInstances: -1})
+}
+
+func (s *S) TestTeamQuotaSetLimit(c *check.C) {
+	storage := teamLimit(nil)
+	qs := &TeamQuotaService{Storage: storage, UsageReader: teamUsage()}
+	err := qs.SetLimit(context.TODO(), "myteam", quota.TeamResources{Units: 3, Memory: -10, CPUMilli: 1200, ServiceInstances: 3})
+	c.Assert(err, check.IsNil)
+	limit, err := storage.GetLimit(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(*limit, check.DeepEquals, quota.TeamResources{Units: 3, Memory: -1, CPUMilli: 1200, ServiceInstances: 3})
+}
+
+func (s *S) TestTeamQuotaSetLimitLowerThanInUse(c *check.C) {
+	qs := &TeamQuotaService{Storage: teamLimit(nil), UsageReader: teamUsage()}
+	err := qs.SetLimit(context.TODO(), "myteam", quota.TeamResources{Units: -1, Memory: 4096, CPUMilli: -1, ServiceInstances: -1})
+	c.Assert(err, check.Equals, quota.ErrLimitLowerThanAllocated)
+}
+
+func (s *S) TestTeamQuotaCheck(c *check.C) {
+	qs := &TeamQuotaService{
+		Storage:     teamLimit(&quota.TeamResources{Units: 5, Memory: 8192, CPUMilli: -1, ServiceInstances: 3}),
+		UsageReader: teamUsage(),
+	}
+	err := qs.Check(context.TODO(), "myteam", quota.TeamResources{Units: 2, Memory: 2048, CPUMilli: 100000})
+	c.Assert(err, check.IsNil)
+	err = qs.Check(context.TODO(), "myteam", quota.TeamResources{Units: -1, Memory: -1024})
+	c.Assert(err, check.IsNil)
+	err = qs.Check(context.TODO(), "myteam", quota.TeamResources{Units: 1, Memory: 4096})
+	c.Assert(err, check.DeepEquals, &quota.TeamQuotaExceededError{
+		Team:      "myteam",
+		Resource:  "memory",
+		Requested: 4096,
+		Available: 2048,
+	})
+	err = qs.Check(context.TODO(), "myteam", quota.TeamResources{ServiceInstances: 1})
+	c.Assert(err, check.DeepEquals, &quota.TeamQuotaExceededError{
+		Team:      "myteam",
+		Resource:  "service instances",
+		Requested: 1,
+		Available: 0,
+	})
+	c.Assert(err.Error(), check.Equals, `Quota exceeded for service instances in team "myteam". Available: 0, Requested: 1.`)
+}
+
+func (s *S) TestTeamQuotaCheckUnlimited(c *check.C) {
+	qs := &TeamQuotaService{
+		Storage: teamLimit(nil),
+		UsageReader: &quota.MockTeamUsageReader{
+			OnGetTeamUsage: func(team string) (*quota.TeamUsage, error) {
+				c.Fatal("usage should not be calculated for unlimited teams")
+				return nil, nil
+			},
+		},
+	}
+	err := qs.Check(context.TODO(), "myteam", quota.TeamResources{Units: 100})
+	c.Assert(err, check.IsNil)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/storage"
+	registryTypes "github.com/tsuru/tsuru/types/registry"
+)
+
+var _ registryTypes.CredentialService = &credentialService{}
+
+type credentialService struct {
+	storage registryTypes.CredentialStorage
+}
+
+func CredentialService() (registryTypes.CredentialService, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &credentialService{storage: dbDriver.RegistryCredentialStorage}, nil
+}
+
+func (s *credentialService) Create(ctx context.Context, c registryTypes.Credential) error {
+	c.Normalize()
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	return s.storage.Insert(ctx, c)
+}
+
+func (s *credentialService) Update(ctx context.Context, c registryTypes.Credential) error {
+	c.Normalize()
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	return s.storage.Update(ctx, c)
+}
+
+func (s *credentialService) Delete(ctx context.Context, team, registry string) error {
+	return s.storage.Delete(ctx, team, registryTypes.NormalizeRegistry(registry))
+}
+
+func (s *credentialService) List(ctx context.Context, teams []string) ([]registryTypes.Credential, error) {
+	creds, err := s.storage.FindByTeams(ctx, teams)
+	if err != nil {
+		return nil, err
+	}
+	for i := range creds {
+		creds[i].Token = ""
+	}
+	return creds, nil
+}
+
+func (s *credentialService) FindForImage(ctx context.Context, teams []string, image string) (*registryTypes.Credential, error) {
+	if len(teams) == 0 {
+		return nil, registryTypes.ErrCredentialNotFound
+	}
+	creds, err := s.storage.FindByTeams(ctx, teams)
+	if err != nil {
+		return nil, err
+	}
+	host := registryTypes.ImageRegistry(image)
+	for _, team := range teams {
+		for i := range creds {
+			if creds[i].Team == team && creds[i].Registry == host {
+				return &creds[i], nil
+			}
+		}
+	}
+	return nil, registryTypes.ErrCredentialNotFound
+}
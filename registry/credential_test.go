@@ -0,0 +1,137 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+
+	registryTypes "github.com/tsuru/tsuru/types/registry"
+	check "gopkg.in/check.v1"
+)
+
+type fakeCredentialStorage struct {
+	creds []registryTypes.Credential
+}
+
+func (f *fakeCredentialStorage) Insert(ctx context.Context, c registryTypes.Credential) error {
+	if _, err := f.Find(ctx, c.Team, c.Registry); err == nil {
+		return registryTypes.ErrCredentialAlreadyExists
+	}
+	f.creds = append(f.creds, c)
+	return nil
+}
+
+func (f *fakeCredentialStorage) Update(ctx context.Context, c registryTypes.Credential) error {
+	for i := range f.creds {
+		if f.creds[i].Team == c.Team && f.creds[i].Registry == c.Registry {
+			f.creds[i] = c
+			return nil
+		}
+	}
+	return registryTypes.ErrCredentialNotFound
+}
+
+func (f *fakeCredentialStorage) Delete(ctx context.Context, team, registry string) error {
+	for i := range f.creds {
+		if f.creds[i].Team == team && f.creds[i].Registry == registry {
+			f.creds = append(f.creds[:i], f.creds[i+1:]...)
+			return nil
+		}
+	}
+	return registryTypes.ErrCredentialNotFound
+}
+
+func (f *fakeCredentialStorage) Find(ctx context.Context, team, registry string) (*registryTypes.Credential, error) {
+	for _, c := range f.creds {
+		if c.Team == team && c.Registry == registry {
+			return &c, nil
+		}
+	}
+	return nil, registryTypes.ErrCredentialNotFound
+}
+
+func (f *fakeCredentialStorage) FindByTeams(ctx context.Context, teams []string) ([]registryTypes.Credential, error) {
+	var result []registryTypes.Credential
+	for _, c := range f.creds {
+		for _, t := range teams {
+			if c.Team == t {
+				result = append(result, c)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *S) TestCredentialServiceCreate(c *check.C) {
+	svc := &credentialService{storage: &fakeCredentialStorage{}}
+	err := svc.Create(context.TODO(), registryTypes.Credential{Team: "t1", Registry: "HTTPS://Registry.example.com:5000/", Username: "u", Token: "p"})
+	c.Assert(err, check.IsNil)
+	cred, err := svc.storage.Find(context.TODO(), "t1", "registry.example.com:5000")
+	c.Assert(err, check.IsNil)
+	c.Assert(cred.Token, check.Equals, "p")
+	err = svc.Create(context.TODO(), registryTypes.Credential{Team: "t1", Registry: "registry.example.com:5000", Username: "u", Token: "p"})
+	c.Assert(err, check.Equals, registryTypes.ErrCredentialAlreadyExists)
+}
+
+func (s *S) TestCredentialServiceCreateInvalid(c *check.C) {
+	svc := &credentialService{storage: &fakeCredentialStorage{}}
+	tests := []struct {
+		cred registryTypes.Credential
+		err  string
+	}{
+		{registryTypes.Credential{Registry: "r.io", Username: "u", Token: "p"}, "team is required"},
+		{registryTypes.Credential{Team: "t1", Username: "u", Token: "p"}, "registry is required"},
+		{registryTypes.Credential{Team: "t1", Registry: "r.io/tsuru", Username: "u", Token: "p"}, "registry must be a host, optionally followed by a port"},
+		{registryTypes.Credential{Team: "t1", Registry: "r.io", Username: "u"}, "username and token are required"},
+	}
+	for _, tt := range tests {
+		err := svc.Create(context.TODO(), tt.cred)
+		c.Assert(err, check.ErrorMatches, tt.err)
+	}
+}
+
+func (s *S) TestCredentialServiceListHidesTokens(c *check.C) {
+	storage := &fakeCredentialStorage{creds: []registryTypes.Credential{
+		{Team: "t1", Registry: "r.io", Username: "u", Token: "p"},
+		{Team: "t2", Registry: "r.io", Username: "u", Token: "p"},
+	}}
+	svc := &credentialService{storage: storage}
+	creds, err := svc.List(context.TODO(), []string{"t1"})
+	c.Assert(err, check.IsNil)
+	c.Assert(creds, check.DeepEquals, []registryTypes.Credential{{Team: "t1", Registry: "r.io", Username: "u"}})
+	c.Assert(storage.creds[0].Token, check.Equals, "p")
+}
+
+func (s *S) TestCredentialServiceFindForImage(c *check.C) {
+	svc := &credentialService{storage: &fakeCredentialStorage{creds: []registryTypes.Credential{
+		{Team: "t1", Registry: "docker.io", Username: "hub", Token: "p"},
+		{Team: "t1", Registry: "r.io:5000", Username: "t1", Token: "p"},
+		{Team: "t2", Registry: "r.io:5000", Username: "t2", Token: "p"},
+		{Team: "t2", Registry: "localhost", Username: "local", Token: "p"},
+	}}}
+	tests := []struct {
+		teams    []string
+		image    string
+		username string
+	}{
+		{[]string{"t1"}, "myimage", "hub"},
+		{[]string{"t1"}, "tsuru/myimage:v1", "hub"},
+		{[]string{"t1", "t2"}, "r.io:5000/tsuru/myimage", "t1"},
+		{[]string{"t2", "t1"}, "R.io:5000/tsuru/myimage", "t2"},
+		{[]string{"t1", "t2"}, "localhost/myimage", "local"},
+		{[]string{"t2"}, "myimage", ""},
+		{[]string{"t1"}, "other.io/myimage", ""},
+		{nil, "r.io:5000/tsuru/myimage", ""},
+	}
+	for _, tt := range tests {
+		cred, err := svc.FindForImage(context.TODO(), tt.teams, tt.image)
+		if tt.username == "" {
+			c.Assert(err, check.Equals, registryTypes.ErrCredentialNotFound, check.Commentf("image %q", tt.image))
+			continue
+		}
+		c.Assert(err, check.IsNil, check.Commentf("image %q", tt.image))
+		c.Assert(cred.Username, check.Equals, tt.username, check.Commentf("image %q", tt.image))
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	manifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+	manifestV2MediaType   = "application/vnd.docker.distribution.manifest.v2+json"
+	ociIndexMediaType     = "application/vnd.oci.image.index.v1+json"
+	ociManifestMediaType  = "application/vnd.oci.image.manifest.v1+json"
+)
+
+type manifestPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+type manifestDescriptor struct {
+	MediaType string            `json:"mediaType"`
+	Digest    string            `json:"digest"`
+	Size      int               `json:"size"`
+	Platform  *manifestPlatform `json:"platform,omitempty"`
+}
+
+type imageManifest struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	MediaType     string               `json:"mediaType,omitempty"`
+	Config        *manifestDescriptor  `json:"config,omitempty"`
+	Layers        []manifestDescriptor `json:"layers,omitempty"`
+	Manifests     []manifestDescriptor `json:"manifests,omitempty"`
+}
+
+// ImageArchitectures returns the architectures the image is available for,
+// listed in its manifest list or, for single architecture images, in its
+// config.
+func ImageArchitectures(ctx context.Context, image string, creds *Credentials) ([]string, error) {
+	host, repo, reference := parseImageReference(image)
+	r := &dockerRegistry{server: host, auth: creds}
+	data, err := r.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repo, reference), strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get manifest for image %s", image)
+	}
+	var manifest imageManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid manifest for image %s", image)
+	}
+	if len(manifest.Manifests) > 0 {
+		var archs []string
+		for _, m := range manifest.Manifests {
+			// Attestations are stored in the list with an unknown platform.
+			if m.Platform == nil || m.Platform.Architecture == "" || m.Platform.Architecture == "unknown" {
+				continue
+			}
+			archs = appendUnique(archs, m.Platform.Architecture)
+		}
+		sort.Strings(archs)
+		return archs, nil
+	}
+	if manifest.Config == nil || manifest.Config.Digest == "" {
+		return nil, errors.Errorf("manifest for image %s has no config", image)
+	}
+	data, err = r.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", repo, manifest.Config.Digest), "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get config for image %s", image)
+	}
+	var config manifestPlatform
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid config for image %s", image)
+	}
+	if config.Architecture == "" {
+		return nil, nil
+	}
+	return []string{config.Architecture}, nil
+}
+
+// CreateManifestList pushes image as a manifest list joining the images built
+// for each architecture, which must be single architecture images in the same
+// repository.
+func CreateManifestList(ctx context.Context, image string, archImages map[string]string) error {
+	host, repo, reference := parseImageReference(image)
+	r := &dockerRegistry{server: host}
+	archs := make([]string, 0, len(archImages))
+	for arch := range archImages {
+		archs = append(archs, arch)
+	}
+	sort.Strings(archs)
+	list := imageManifest{
+		SchemaVersion: 2,
+		MediaType:     manifestListMediaType,
+	}
+	for _, arch := range archs {
+		archHost, archRepo, archReference := parseImageReference(archImages[arch])
+		if archHost != host || archRepo != repo {
+			return errors.Errorf("image %s is not in the repository of %s", archImages[arch], image)
+		}
+		data, err := r.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repo, archReference), manifestV2MediaType+", "+ociManifestMediaType)
+		if err != nil {
+			return errors.Wrapf(err, "unable to get manifest for image %s", archImages[arch])
+		}
+		var manifest imageManifest
+		err = json.Unmarshal(data, &manifest)
+		if err != nil {
+			return errors.Wrapf(err, "invalid manifest for image %s", archImages[arch])
+		}
+		mediaType := manifest.MediaType
+		if mediaType == "" {
+			mediaType = manifestV2MediaType
+		}
+		if mediaType == manifestListMediaType || mediaType == ociIndexMediaType {
+			return errors.Errorf("image %s is already a manifest list", archImages[arch])
+		}
+		list.Manifests = append(list.Manifests, manifestDescriptor{
+			MediaType: mediaType,
+			Digest:    fmt.Sprintf("sha256:%x", sha256.Sum256(data)),
+			Size:      len(data),
+			Platform:  &manifestPlatform{Architecture: arch, OS: "linux"},
+		})
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	resp, err := r.doRequestWithBody(ctx, "PUT", fmt.Sprintf("/v2/%s/manifests/%s", repo, reference), map[string]string{
+		"Content-Type": manifestListMediaType,
+	}, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return errors.Errorf("invalid status code pushing manifest list %s (%d)", image, resp.StatusCode)
+	}
+	return nil
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
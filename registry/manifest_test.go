@@ -0,0 +1,70 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	registrytest "github.com/tsuru/tsuru/registry/testing"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) pushArchImage(repo, tag, arch string) []byte {
+	config := s.server.PushBlob(repo, []byte(fmt.Sprintf(`{"architecture": %q, "os": "linux"}`, arch)))
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion": 2, "mediaType": "application/vnd.docker.distribution.manifest.v2+json", "config": {"digest": %q}}`, config))
+	s.server.PushManifest(repo, tag, manifest)
+	return manifest
+}
+
+func (s *S) TestImageArchitectures(c *check.C) {
+	s.pushArchImage("tsuru/myapp", "v1", "arm64")
+	archs, err := ImageArchitectures(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(archs, check.DeepEquals, []string{"arm64"})
+	s.server.PushManifest("tsuru/myapp", "v2", []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.oci.image.index.v1+json",
+		"manifests": [
+			{"digest": "sha256:a", "platform": {"architecture": "arm64", "os": "linux"}},
+			{"digest": "sha256:b", "platform": {"architecture": "amd64", "os": "linux"}},
+			{"digest": "sha256:c", "platform": {"architecture": "unknown", "os": "unknown"}}
+		]
+	}`))
+	archs, err = ImageArchitectures(context.TODO(), s.server.Addr()+"/tsuru/myapp:v2", nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(archs, check.DeepEquals, []string{"amd64", "arm64"})
+	_, err = ImageArchitectures(context.TODO(), s.server.Addr()+"/tsuru/myapp:v3", nil)
+	c.Assert(err, check.ErrorMatches, "unable to get manifest for image .*/tsuru/myapp:v3: image not found")
+}
+
+func (s *S) TestCreateManifestList(c *check.C) {
+	amd64Manifest := s.pushArchImage("tsuru/python", "v1-amd64", "amd64")
+	arm64Manifest := s.pushArchImage("tsuru/python", "v1-arm64", "arm64")
+	image := s.server.Addr() + "/tsuru/python:v1"
+	err := CreateManifestList(context.TODO(), image, map[string]string{
+		"amd64": s.server.Addr() + "/tsuru/python:v1-amd64",
+		"arm64": s.server.Addr() + "/tsuru/python:v1-arm64",
+	})
+	c.Assert(err, check.IsNil)
+	archs, err := ImageArchitectures(context.TODO(), image, nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(archs, check.DeepEquals, []string{"amd64", "arm64"})
+	digest, err := ImageDigest(context.TODO(), image, nil)
+	c.Assert(err, check.IsNil)
+	var list imageManifest
+	err = json.Unmarshal(s.server.Repos[0].Manifests[digest], &list)
+	c.Assert(err, check.IsNil)
+	c.Assert(list.MediaType, check.Equals, manifestListMediaType)
+	c.Assert(list.Manifests, check.HasLen, 2)
+	c.Assert(list.Manifests[0].Digest, check.Equals, registrytest.Digest(amd64Manifest))
+	c.Assert(list.Manifests[0].Size, check.Equals, len(amd64Manifest))
+	c.Assert(list.Manifests[1].Digest, check.Equals, registrytest.Digest(arm64Manifest))
+	err = CreateManifestList(context.TODO(), image, map[string]string{
+		"amd64": s.server.Addr() + "/tsuru/other:v1-amd64",
+	})
+	c.Assert(err, check.ErrorMatches, "image .*/tsuru/other:v1-amd64 is not in the repository of .*/tsuru/python:v1")
+}
@@ -5,9 +5,11 @@
 package registry
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -24,6 +26,25 @@ import (
 type dockerRegistry struct {
 	server string
 	client *http.Client
+	// auth overrides the credentials of the tsuru registry from config, it's
+	// used to talk to registries other than the one used by tsuru.
+	auth  *Credentials
+	token string
+}
+
+// Credentials authenticate requests to a registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+func (r *dockerRegistry) credentials() (string, string) {
+	if r.auth != nil {
+		return r.auth.Username, r.auth.Password
+	}
+	username, _ := config.GetString("docker:registry-auth:username")
+	password, _ := config.GetString("docker:registry-auth:password")
+	return username, password
 }
 
 var (
@@ -102,6 +123,22 @@ func RemoveAppImages(ctx context.Context, appName string) error {
 	return multi.ToError()
 }
 
+// ImageDigest asks the image registry for the digest of the manifest the
+// image reference points to. When creds is nil the credentials for the tsuru
+// registry are used.
+func ImageDigest(ctx context.Context, image string, creds *Credentials) (string, error) {
+	host, repo, reference := parseImageReference(image)
+	if strings.HasPrefix(reference, "sha256:") {
+		return reference, nil
+	}
+	r := &dockerRegistry{server: host, auth: creds}
+	digest, err := r.manifestDigest(ctx, repo, reference)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get digest for image %s", image)
+	}
+	return digest, nil
+}
+
 func (r dockerRegistry) getDigest(ctx context.Context, image, tag string) (string, error) {
 	path := fmt.Sprintf("/v2/%s/manifests/%s", image, tag)
 	resp, err := r.doRequest(ctx, "HEAD", path, map[string]string{"Accept": "application/vnd.docker.distribution.manifest.v2+json"})
@@ -162,6 +199,10 @@ func (r dockerRegistry) removeImage(ctx context.Context, image, digest string) e
 }
 
 func (r *dockerRegistry) doRequest(ctx context.Context, method, path string, headers map[string]string) (resp *http.Response, err error) {
+	return r.doRequestWithBody(ctx, method, path, headers, nil)
+}
+
+func (r *dockerRegistry) doRequestWithBody(ctx context.Context, method, path string, headers map[string]string, body []byte) (resp *http.Response, err error) {
 	u, _ := url.Parse(r.server)
 	server := r.server
 	if u != nil && u.Host != "" {
@@ -173,7 +214,7 @@ func (r *dockerRegistry) doRequest(ctx context.Context, method, path string, hea
 	for _, scheme := range []string{"https", "http"} {
 		endpoint := fmt.Sprintf("%s://%s%s", scheme, server, path)
 		var req *http.Request
-		req, err = http.NewRequest(method, endpoint, nil)
+		req, err = http.NewRequest(method, endpoint, bodyReader(body))
 		if err != nil {
 			return nil, err
 		}
@@ -183,11 +224,7 @@ func (r *dockerRegistry) doRequest(ctx context.Context, method, path string, hea
 		for k, v := range headers {
 			req.Header.Set(k, v)
 		}
-		username, _ := config.GetString("docker:registry-auth:username")
-		password, _ := config.GetString("docker:registry-auth:password")
-		if len(username) > 0 || len(password) > 0 {
-			req.SetBasicAuth(username, password)
-		}
+		r.setAuth(req)
 		resp, err = r.client.Do(req)
 		if err != nil {
 			if _, ok := err.(net.Error); ok {
@@ -195,11 +232,100 @@ func (r *dockerRegistry) doRequest(ctx context.Context, method, path string, hea
 			}
 			return nil, err
 		}
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if resp.StatusCode == http.StatusUnauthorized && r.token == "" && strings.HasPrefix(challenge, "Bearer ") {
+			resp.Body.Close()
+			r.token, err = r.bearerToken(ctx, challenge)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Del("Authorization")
+			r.setAuth(req)
+			if body != nil {
+				req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			}
+			resp, err = r.client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return resp, nil
 	}
 	return nil, err
 }
 
+func bodyReader(body []byte) io.Reader {
+	if body == nil {
+		return nil
+	}
+	return bytes.NewReader(body)
+}
+
+func (r *dockerRegistry) setAuth(req *http.Request) {
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+		return
+	}
+	username, password := r.credentials()
+	if len(username) > 0 || len(password) > 0 {
+		req.SetBasicAuth(username, password)
+	}
+}
+
+// bearerToken requests a token to the authorization server announced by the
+// registry in the challenge, as described by the docker token authentication
+// specification.
+func (r *dockerRegistry) bearerToken(ctx context.Context, challenge string) (string, error) {
+	params := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return "", errors.Errorf("invalid registry auth challenge: %q", challenge)
+	}
+	query := url.Values{}
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			query.Set(k, params[k])
+		}
+	}
+	req, err := http.NewRequest("GET", realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	username, password := r.credentials()
+	if len(username) > 0 || len(password) > 0 {
+		req.SetBasicAuth(username, password)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unable to get registry token from %s: status %d", realm, resp.StatusCode)
+	}
+	var data struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	if err != nil {
+		return "", err
+	}
+	if data.Token == "" {
+		data.Token = data.AccessToken
+	}
+	return data.Token, nil
+}
+
 func parseImage(imageName string) (registry string, image string, tag string) {
 	parts := strings.SplitN(imageName, "/", 3)
 	switch len(parts) {
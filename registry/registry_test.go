@@ -222,3 +222,16 @@ func (s *S) TestDockerRegistryDoRequestTLS(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(rsp.StatusCode, check.Equals, http.StatusOK)
 }
+
+func (s *S) TestImageDigest(c *check.C) {
+	digest := s.server.PushManifest("tsuru/myapp", "v1", []byte(`{"schemaVersion": 2}`))
+	result, err := ImageDigest(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, digest)
+	result, err = ImageDigest(context.TODO(), s.server.Addr()+"/tsuru/myapp@"+digest, nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, digest)
+	_, err = ImageDigest(context.TODO(), s.server.Addr()+"/tsuru/myapp:v2", nil)
+	c.Assert(err, check.ErrorMatches, "unable to get digest for image .*/tsuru/myapp:v2: image not found")
+	c.Assert(errors.Cause(err), check.Equals, ErrImageNotFound)
+}
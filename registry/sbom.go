@@ -0,0 +1,273 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	PackageTypeDeb    = "deb"
+	PackageTypeAPK    = "apk"
+	PackageTypePython = "python"
+
+	dpkgDatabase     = "var/lib/dpkg/status"
+	dpkgDatabaseDir  = "var/lib/dpkg/status.d/"
+	apkDatabase      = "lib/apk/db/installed"
+	pythonMetadata   = "METADATA"
+	maxPackageDBSize = 64 * 1024 * 1024
+)
+
+// SBOMPackage is a package installed in an image.
+type SBOMPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+}
+
+// SBOM is the software bill of materials of an image, listing the packages
+// found in its filesystem.
+type SBOM struct {
+	Image        string        `json:"image"`
+	Digest       string        `json:"digest"`
+	Architecture string        `json:"architecture,omitempty"`
+	Packages     []SBOMPackage `json:"packages"`
+}
+
+// ImageSBOM generates the SBOM of the image reading the package databases of
+// dpkg and apk and the metadata of python packages from its layers. For
+// multi-architecture images, the amd64 image is used, or the first one
+// listed when there's no amd64 image.
+func ImageSBOM(ctx context.Context, image string, creds *Credentials) (*SBOM, error) {
+	host, repo, reference := parseImageReference(image)
+	r := &dockerRegistry{server: host, auth: creds}
+	manifest, data, err := r.manifest(ctx, repo, reference)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get manifest for image %s", image)
+	}
+	sbom := &SBOM{
+		Image:  image,
+		Digest: fmt.Sprintf("sha256:%x", sha256.Sum256(data)),
+	}
+	if len(manifest.Manifests) > 0 {
+		desc := sbomPlatformManifest(manifest.Manifests)
+		if desc == nil {
+			return nil, errors.Errorf("no platform image found in manifest list %s", image)
+		}
+		sbom.Architecture = desc.Platform.Architecture
+		manifest, _, err = r.manifest(ctx, repo, desc.Digest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get %s manifest for image %s", sbom.Architecture, image)
+		}
+	}
+	files := map[string][]byte{}
+	for _, layer := range manifest.Layers {
+		err = r.readPackageFiles(ctx, repo, layer.Digest, files)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read layer %s of image %s", layer.Digest, image)
+		}
+	}
+	sbom.Packages = packagesFromFiles(files)
+	return sbom, nil
+}
+
+func (r *dockerRegistry) manifest(ctx context.Context, repo, reference string) (*imageManifest, []byte, error) {
+	data, err := r.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repo, reference), strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return nil, nil, err
+	}
+	var manifest imageManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid manifest")
+	}
+	return &manifest, data, nil
+}
+
+func sbomPlatformManifest(manifests []manifestDescriptor) *manifestDescriptor {
+	var found *manifestDescriptor
+	for i, m := range manifests {
+		if m.Platform == nil || m.Platform.Architecture == "" || m.Platform.Architecture == "unknown" {
+			continue
+		}
+		if m.Platform.Architecture == "amd64" {
+			return &manifests[i]
+		}
+		if found == nil {
+			found = &manifests[i]
+		}
+	}
+	return found
+}
+
+// readPackageFiles reads the package databases in the layer into files,
+// applying the whiteouts of the layer to the files read from the previous
+// layers.
+func (r *dockerRegistry) readPackageFiles(ctx context.Context, repo, digest string, files map[string][]byte) error {
+	resp, err := r.doRequest(ctx, "GET", fmt.Sprintf("/v2/%s/blobs/%s", repo, digest), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrImageNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("invalid status code requesting blob (%d)", resp.StatusCode)
+	}
+	buffered := bufio.NewReader(resp.Body)
+	var reader io.Reader = buffered
+	if magic, _ := buffered.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		dir, base := path.Split(name)
+		if base == ".wh..wh..opq" {
+			removeFiles(files, dir)
+			continue
+		}
+		if strings.HasPrefix(base, ".wh.") {
+			removeFiles(files, dir+strings.TrimPrefix(base, ".wh."))
+			continue
+		}
+		if header.Typeflag != tar.TypeReg || !isPackageFile(name) {
+			continue
+		}
+		data, err := ioutil.ReadAll(io.LimitReader(tarReader, maxPackageDBSize))
+		if err != nil {
+			return err
+		}
+		files[name] = data
+	}
+}
+
+func removeFiles(files map[string][]byte, name string) {
+	name = strings.TrimSuffix(name, "/")
+	for file := range files {
+		if file == name || strings.HasPrefix(file, name+"/") {
+			delete(files, file)
+		}
+	}
+}
+
+func isPackageFile(name string) bool {
+	switch {
+	case name == dpkgDatabase, name == apkDatabase:
+		return true
+	case strings.HasPrefix(name, dpkgDatabaseDir):
+		return !strings.HasSuffix(name, ".md5sums")
+	}
+	return path.Base(name) == pythonMetadata && strings.HasSuffix(path.Dir(name), ".dist-info")
+}
+
+func packagesFromFiles(files map[string][]byte) []SBOMPackage {
+	var pkgs []SBOMPackage
+	for name, data := range files {
+		stanzas := parseStanzas(data)
+		switch {
+		case name == apkDatabase:
+			for _, s := range stanzas {
+				pkgs = appendPackage(pkgs, PackageTypeAPK, s["P"], s["V"])
+			}
+		case path.Base(name) == pythonMetadata:
+			if len(stanzas) > 0 {
+				pkgs = appendPackage(pkgs, PackageTypePython, stanzas[0]["Name"], stanzas[0]["Version"])
+			}
+		default:
+			for _, s := range stanzas {
+				// Packages in the distroless status.d directory have no status.
+				if status, ok := s["Status"]; ok && !strings.HasSuffix(status, " installed") {
+					continue
+				}
+				pkgs = appendPackage(pkgs, PackageTypeDeb, s["Package"], s["Version"])
+			}
+		}
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Type != pkgs[j].Type {
+			return pkgs[i].Type < pkgs[j].Type
+		}
+		if pkgs[i].Name != pkgs[j].Name {
+			return pkgs[i].Name < pkgs[j].Name
+		}
+		return pkgs[i].Version < pkgs[j].Version
+	})
+	result := pkgs[:0]
+	for i, pkg := range pkgs {
+		if i == 0 || pkg != pkgs[i-1] {
+			result = append(result, pkg)
+		}
+	}
+	return result
+}
+
+func appendPackage(pkgs []SBOMPackage, pkgType, name, version string) []SBOMPackage {
+	if name == "" || version == "" {
+		return pkgs
+	}
+	return append(pkgs, SBOMPackage{Name: name, Version: version, Type: pkgType})
+}
+
+// parseStanzas parses the blank line separated stanzas of "Key: value"
+// fields used by the dpkg and apk databases and by python metadata,
+// ignoring continuation lines.
+func parseStanzas(data []byte) []map[string]string {
+	var stanzas []map[string]string
+	var current map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if current == nil {
+			current = map[string]string{}
+			stanzas = append(stanzas, current)
+		}
+		key := strings.TrimSpace(parts[0])
+		if _, ok := current[key]; !ok {
+			current[key] = strings.TrimSpace(parts[1])
+		}
+	}
+	return stanzas
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"strings"
+
+	check "gopkg.in/check.v1"
+)
+
+func layerBlob(c *check.C, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		c.Assert(err, check.IsNil)
+		_, err = tarWriter.Write([]byte(content))
+		c.Assert(err, check.IsNil)
+	}
+	c.Assert(tarWriter.Close(), check.IsNil)
+	c.Assert(gzipWriter.Close(), check.IsNil)
+	return buf.Bytes()
+}
+
+func (s *S) pushLayeredImage(c *check.C, repo, tag string, layers ...map[string]string) string {
+	var descriptors []string
+	for _, layer := range layers {
+		digest := s.server.PushBlob(repo, layerBlob(c, layer))
+		descriptors = append(descriptors, fmt.Sprintf(`{"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "digest": %q}`, digest))
+	}
+	return s.server.PushManifest(repo, tag, []byte(fmt.Sprintf(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+		"config": {"digest": "sha256:abc"},
+		"layers": [%s]
+	}`, strings.Join(descriptors, ", "))))
+}
+
+func (s *S) TestImageSBOM(c *check.C) {
+	digest := s.pushLayeredImage(c, "tsuru/myapp", "v1",
+		map[string]string{
+			"var/lib/dpkg/status": "Package: openssl\nStatus: install ok installed\nVersion: 3.0.11-1~deb12u1\nDescription: tls\n multiline description\n\n" +
+				"Package: removed\nStatus: deinstall ok config-files\nVersion: 1.0\n",
+			"usr/lib/python3/site-packages/old-1.0.dist-info/METADATA": "Name: old\nVersion: 1.0\n",
+		},
+		map[string]string{
+			"./usr/lib/python3/site-packages/.wh.old-1.0.dist-info":            "",
+			"usr/lib/python3/site-packages/requests-2.31.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: requests\nVersion: 2.31.0\n\nLong description\nName: other\n",
+			"lib/apk/db/installed": "C:Q1abc=\nP:musl\nV:1.2.4-r2\n\nP:busybox\nV:1.36.1-r5\n",
+		},
+	)
+	sbom, err := ImageSBOM(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(sbom, check.DeepEquals, &SBOM{
+		Image:  s.server.Addr() + "/tsuru/myapp:v1",
+		Digest: digest,
+		Packages: []SBOMPackage{
+			{Name: "busybox", Version: "1.36.1-r5", Type: PackageTypeAPK},
+			{Name: "musl", Version: "1.2.4-r2", Type: PackageTypeAPK},
+			{Name: "openssl", Version: "3.0.11-1~deb12u1", Type: PackageTypeDeb},
+			{Name: "requests", Version: "2.31.0", Type: PackageTypePython},
+		},
+	})
+}
+
+func (s *S) TestImageSBOMManifestList(c *check.C) {
+	armDigest := s.pushLayeredImage(c, "tsuru/myapp", "", map[string]string{
+		"var/lib/dpkg/status.d/base":         "Package: base-files\nVersion: 12.4\n",
+		"var/lib/dpkg/status.d/base.md5sums": "abc  etc/os-release\n",
+	})
+	listDigest := s.server.PushManifest("tsuru/myapp", "v1", []byte(fmt.Sprintf(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.oci.image.index.v1+json",
+		"manifests": [
+			{"digest": "sha256:attestation", "platform": {"architecture": "unknown", "os": "unknown"}},
+			{"digest": %q, "platform": {"architecture": "arm64", "os": "linux"}}
+		]
+	}`, armDigest)))
+	sbom, err := ImageSBOM(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(sbom.Digest, check.Equals, listDigest)
+	c.Assert(sbom.Architecture, check.Equals, "arm64")
+	c.Assert(sbom.Packages, check.DeepEquals, []SBOMPackage{
+		{Name: "base-files", Version: "12.4", Type: PackageTypeDeb},
+	})
+}
+
+func (s *S) TestImageSBOMNotFound(c *check.C) {
+	_, err := ImageSBOM(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil)
+	c.Assert(err, check.ErrorMatches, "unable to get manifest for image .*/tsuru/myapp:v1: .*")
+}
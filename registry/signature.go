@@ -0,0 +1,299 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureType       = "cosign container image signature"
+
+	dockerHubHost  = "registry-1.docker.io"
+	maxPayloadSize = 1024 * 1024
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+var ErrSignatureNotFound = errors.New("no signature found")
+
+// SignatureError is returned when an image doesn't have a valid signature from
+// any of the trusted keys.
+type SignatureError struct {
+	Image  string
+	Digest string
+	Reason string
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("image %s (%s) failed signature verification: %s", e.Image, e.Digest, e.Reason)
+}
+
+// VerifiedSignature describes the signature accepted for an image.
+type VerifiedSignature struct {
+	Digest string
+	Key    string
+}
+
+type signatureManifest struct {
+	Layers []struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+type signaturePayload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// ParsePublicKey parses a PEM encoded ECDSA or RSA public key.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid public key: no PEM data found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid public key")
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		return key, nil
+	}
+	return nil, errors.Errorf("unsupported public key type %T", key)
+}
+
+// VerifyImageSignature looks for cosign signatures of the image in its
+// registry, stored with the sha256-<digest>.sig tag, and checks whether one
+// of them was made by one of the keys for the image digest and repository.
+// When creds is nil the credentials for the tsuru registry are used.
+func VerifyImageSignature(ctx context.Context, image string, creds *Credentials, keys map[string]crypto.PublicKey) (*VerifiedSignature, error) {
+	host, repo, reference := parseImageReference(image)
+	r := &dockerRegistry{server: host, auth: creds}
+	digest := reference
+	if !strings.HasPrefix(reference, "sha256:") {
+		var err error
+		digest, err = r.manifestDigest(ctx, repo, reference)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get digest for image %s", image)
+		}
+	}
+	sigTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	data, err := r.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repo, sigTag), strings.Join(manifestMediaTypes, ", "))
+	if err == ErrImageNotFound {
+		return nil, &SignatureError{Image: image, Digest: digest, Reason: ErrSignatureNotFound.Error()}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get signatures for image %s", image)
+	}
+	var manifest signatureManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid signature manifest for image %s", image)
+	}
+	keyNames := make([]string, 0, len(keys))
+	for name := range keys {
+		keyNames = append(keyNames, name)
+	}
+	sort.Strings(keyNames)
+	reason := ErrSignatureNotFound.Error()
+	for _, layer := range manifest.Layers {
+		encodedSig, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(encodedSig)
+		if err != nil {
+			reason = "invalid signature encoding"
+			continue
+		}
+		payload, err := r.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", repo, layer.Digest), "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get signature payload for image %s", image)
+		}
+		if fmt.Sprintf("sha256:%x", sha256.Sum256(payload)) != layer.Digest {
+			reason = "signature payload doesn't match its digest"
+			continue
+		}
+		keyName := ""
+		for _, name := range keyNames {
+			if verifySignature(keys[name], payload, signature) {
+				keyName = name
+				break
+			}
+		}
+		if keyName == "" {
+			reason = "signature not made by a trusted key"
+			continue
+		}
+		err = checkPayload(payload, host, repo, digest)
+		if err != nil {
+			reason = err.Error()
+			continue
+		}
+		return &VerifiedSignature{Digest: digest, Key: keyName}, nil
+	}
+	return nil, &SignatureError{Image: image, Digest: digest, Reason: reason}
+}
+
+func verifySignature(key crypto.PublicKey, payload, signature []byte) bool {
+	hash := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var sig ecdsaSignature
+		if _, err := asn1.Unmarshal(signature, &sig); err != nil || sig.R == nil || sig.S == nil {
+			return false
+		}
+		return ecdsa.Verify(k, hash[:], sig.R, sig.S)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil
+	}
+	return false
+}
+
+// checkPayload ensures the signed payload refers to the verified digest and
+// to the image repository, so a signature can't be copied to other images.
+func checkPayload(data []byte, host, repo, digest string) error {
+	var payload signaturePayload
+	err := json.Unmarshal(data, &payload)
+	if err != nil {
+		return errors.New("invalid signature payload")
+	}
+	if payload.Critical.Type != cosignSignatureType {
+		return errors.Errorf("unexpected signature type %q", payload.Critical.Type)
+	}
+	if payload.Critical.Image.DockerManifestDigest != digest {
+		return errors.Errorf("signature is for digest %s", payload.Critical.Image.DockerManifestDigest)
+	}
+	if !sameRepository(payload.Critical.Identity.DockerReference, host, repo) {
+		return errors.Errorf("signature is for repository %s", payload.Critical.Identity.DockerReference)
+	}
+	return nil
+}
+
+func sameRepository(reference, host, repo string) bool {
+	if reference == host+"/"+repo {
+		return true
+	}
+	if host != dockerHubHost {
+		return false
+	}
+	for _, alias := range []string{"docker.io/", "index.docker.io/"} {
+		if reference == alias+repo {
+			return true
+		}
+	}
+	return reference == strings.TrimPrefix(repo, "library/")
+}
+
+// parseImageReference splits the image in registry host, repository and tag
+// or digest, defaulting to Docker Hub and to the latest tag.
+func parseImageReference(image string) (host, repo, reference string) {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, reference = name[:i], name[i+1:]
+	}
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		host, name = parts[0], parts[1]
+	} else {
+		host = dockerHubHost
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	if host == "docker.io" || host == "index.docker.io" {
+		host = dockerHubHost
+	}
+	tag := "latest"
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name, tag = name[:i], name[i+1:]
+	}
+	if reference == "" {
+		reference = tag
+	}
+	return host, name, reference
+}
+
+func (r *dockerRegistry) manifestDigest(ctx context.Context, repo, reference string) (string, error) {
+	resp, err := r.doRequest(ctx, "HEAD", fmt.Sprintf("/v2/%s/manifests/%s", repo, reference), map[string]string{
+		"Accept": strings.Join(manifestMediaTypes, ", "),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrImageNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("invalid status code getting manifest (%d)", resp.StatusCode)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", errors.Errorf("empty digest returned for image %s:%s", repo, reference)
+	}
+	return digest, nil
+}
+
+func (r *dockerRegistry) get(ctx context.Context, path, accept string) ([]byte, error) {
+	var headers map[string]string
+	if accept != "" {
+		headers = map[string]string{"Accept": accept}
+	}
+	resp, err := r.doRequest(ctx, "GET", path, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrImageNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("invalid status code requesting %s (%d)", path, resp.StatusCode)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxPayloadSize))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	check "gopkg.in/check.v1"
+)
+
+func publicKeyPEM(c *check.C, key crypto.PublicKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	c.Assert(err, check.IsNil)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func (s *S) pushSignedImage(c *check.C, signer crypto.Signer) string {
+	digest := s.server.PushManifest("tsuru/myapp", "v1", []byte(`{"schemaVersion": 2, "layers": []}`))
+	err := s.server.Sign("tsuru/myapp", digest, s.server.Addr()+"/tsuru/myapp", signer)
+	c.Assert(err, check.IsNil)
+	return digest
+}
+
+func (s *S) TestParsePublicKey(c *check.C) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	key, err := ParsePublicKey(publicKeyPEM(c, ecKey.Public()))
+	c.Assert(err, check.IsNil)
+	c.Assert(key, check.DeepEquals, ecKey.Public())
+	_, err = ParsePublicKey([]byte("not a key"))
+	c.Assert(err, check.ErrorMatches, "invalid public key: no PEM data found")
+}
+
+func (s *S) TestParseImageReference(c *check.C) {
+	tests := []struct {
+		image, host, repo, reference string
+	}{
+		{"busybox", "registry-1.docker.io", "library/busybox", "latest"},
+		{"tsuru/app:v1", "registry-1.docker.io", "tsuru/app", "v1"},
+		{"docker.io/tsuru/app:v1", "registry-1.docker.io", "tsuru/app", "v1"},
+		{"localhost:5000/tsuru/app", "localhost:5000", "tsuru/app", "latest"},
+		{"r.io/team/tsuru/app:v1@sha256:abc", "r.io", "team/tsuru/app", "sha256:abc"},
+	}
+	for _, tt := range tests {
+		host, repo, reference := parseImageReference(tt.image)
+		c.Check([]string{host, repo, reference}, check.DeepEquals, []string{tt.host, tt.repo, tt.reference}, check.Commentf("image %q", tt.image))
+	}
+}
+
+func (s *S) TestVerifyImageSignature(c *check.C) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	digest := s.pushSignedImage(c, key)
+	keys := map[string]crypto.PublicKey{"release": key.Public()}
+	sig, err := VerifyImageSignature(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil, keys)
+	c.Assert(err, check.IsNil)
+	c.Assert(sig, check.DeepEquals, &VerifiedSignature{Digest: digest, Key: "release"})
+	sig, err = VerifyImageSignature(context.TODO(), s.server.Addr()+"/tsuru/myapp@"+digest, nil, keys)
+	c.Assert(err, check.IsNil)
+	c.Assert(sig.Digest, check.Equals, digest)
+}
+
+func (s *S) TestVerifyImageSignatureRSA(c *check.C) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, check.IsNil)
+	s.pushSignedImage(c, key)
+	sig, err := VerifyImageSignature(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil, map[string]crypto.PublicKey{"rsa": key.Public()})
+	c.Assert(err, check.IsNil)
+	c.Assert(sig.Key, check.Equals, "rsa")
+}
+
+func (s *S) TestVerifyImageSignatureNotSigned(c *check.C) {
+	digest := s.server.PushManifest("tsuru/myapp", "v1", []byte(`{"schemaVersion": 2}`))
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	_, err = VerifyImageSignature(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil, map[string]crypto.PublicKey{"k": key.Public()})
+	c.Assert(err, check.DeepEquals, &SignatureError{Image: s.server.Addr() + "/tsuru/myapp:v1", Digest: digest, Reason: "no signature found"})
+}
+
+func (s *S) TestVerifyImageSignatureUntrustedKey(c *check.C) {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	trusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	s.pushSignedImage(c, signer)
+	_, err = VerifyImageSignature(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil, map[string]crypto.PublicKey{"k": trusted.Public()})
+	c.Assert(err, check.FitsTypeOf, &SignatureError{})
+	c.Assert(err.(*SignatureError).Reason, check.Equals, "signature not made by a trusted key")
+}
+
+func (s *S) TestVerifyImageSignatureCopiedFromOtherImage(c *check.C) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	digest := s.server.PushManifest("tsuru/myapp", "v1", []byte(`{"schemaVersion": 2}`))
+	err = s.server.Sign("tsuru/myapp", digest, s.server.Addr()+"/tsuru/otherapp", key)
+	c.Assert(err, check.IsNil)
+	_, err = VerifyImageSignature(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil, map[string]crypto.PublicKey{"k": key.Public()})
+	c.Assert(err, check.FitsTypeOf, &SignatureError{})
+	c.Assert(err.(*SignatureError).Reason, check.Equals, "signature is for repository "+s.server.Addr()+"/tsuru/otherapp")
+}
+
+func (s *S) TestVerifyImageSignatureImageNotFound(c *check.C) {
+	_, err := VerifyImageSignature(context.TODO(), s.server.Addr()+"/tsuru/myapp:v1", nil, nil)
+	c.Assert(err, check.ErrorMatches, "unable to get digest for image .*: image not found")
+}
@@ -7,11 +7,16 @@
 package testing
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/gorilla/mux"
@@ -22,6 +27,9 @@ type Repository struct {
 	Tags     map[string]string
 	Username string
 	Password string
+	// Manifests and Blobs hold the content served for each digest.
+	Manifests map[string][]byte
+	Blobs     map[string][]byte
 }
 
 type tagListResponse struct {
@@ -78,6 +86,101 @@ func (s *RegistryServer) AddRepo(r Repository) {
 	s.Repos = append(s.Repos, r)
 }
 
+// PushManifest stores the manifest in the repository, creating the repository
+// if needed, and tags it. It returns the manifest digest.
+func (s *RegistryServer) PushManifest(name, tag string, manifest []byte) string {
+	digest := Digest(manifest)
+	s.updateRepo(name, func(repo *Repository) {
+		repo.Manifests[digest] = manifest
+		if tag != "" {
+			repo.Tags[tag] = digest
+		}
+	})
+	return digest
+}
+
+// PushBlob stores the blob in the repository, creating the repository if
+// needed. It returns the blob digest.
+func (s *RegistryServer) PushBlob(name string, blob []byte) string {
+	digest := Digest(blob)
+	s.updateRepo(name, func(repo *Repository) {
+		repo.Blobs[digest] = blob
+	})
+	return digest
+}
+
+// Sign stores a cosign signature, made by the signer, for the manifest digest
+// in the repository, the same way it's done by cosign sign. The reference is
+// the repository written in the signed payload.
+func (s *RegistryServer) Sign(name, digest, reference string, signer crypto.Signer) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"critical": map[string]interface{}{
+			"identity": map[string]string{"docker-reference": reference},
+			"image":    map[string]string{"docker-manifest-digest": digest},
+			"type":     "cosign container image signature",
+		},
+		"optional": nil,
+	})
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(payload)
+	signature, err := signer.Sign(rand.Reader, hash[:], crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	payloadDigest := s.PushBlob(name, payload)
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []map[string]interface{}{{
+			"mediaType": "application/vnd.dev.cosign.simplesigning.v1+json",
+			"digest":    payloadDigest,
+			"size":      len(payload),
+			"annotations": map[string]string{
+				"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(signature),
+			},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	s.PushManifest(name, strings.Replace(digest, ":", "-", 1)+".sig", manifest)
+	return nil
+}
+
+func (s *RegistryServer) updateRepo(name string, fn func(*Repository)) {
+	s.reposLock.Lock()
+	defer s.reposLock.Unlock()
+	index := -1
+	for i := range s.Repos {
+		if s.Repos[i].Name == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		s.Repos = append(s.Repos, Repository{Name: name})
+		index = len(s.Repos) - 1
+	}
+	repo := &s.Repos[index]
+	if repo.Tags == nil {
+		repo.Tags = map[string]string{}
+	}
+	if repo.Manifests == nil {
+		repo.Manifests = map[string][]byte{}
+	}
+	if repo.Blobs == nil {
+		repo.Blobs = map[string][]byte{}
+	}
+	fn(repo)
+}
+
+// Digest returns the content digest in the format used by the registry API.
+func Digest(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
 func (s *RegistryServer) SetStorageDelete(sd bool) {
 	s.reposLock.Lock()
 	s.storageDelete = sd
@@ -93,7 +196,10 @@ func (s *RegistryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (s *RegistryServer) buildMuxer() {
 	s.muxer = mux.NewRouter()
 	s.muxer.Path("/v2/{name:.*}/manifests/{tag:.*}").Methods("HEAD").HandlerFunc(s.getDigest)
+	s.muxer.Path("/v2/{name:.*}/manifests/{tag:.*}").Methods("GET").HandlerFunc(s.getManifest)
+	s.muxer.Path("/v2/{name:.*}/manifests/{tag:.*}").Methods("PUT").HandlerFunc(s.putManifest)
 	s.muxer.Path("/v2/{name:.*}/manifests/{digest:.*}").Methods("DELETE").HandlerFunc(s.removeTag)
+	s.muxer.Path("/v2/{name:.*}/blobs/{digest:.*}").Methods("GET").HandlerFunc(s.getBlob)
 	s.muxer.Path("/v2/{name:.*}/tags/list").Methods("GET").HandlerFunc(s.listTags)
 }
 
@@ -165,9 +271,92 @@ func (s *RegistryServer) getDigest(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if _, ok := repo.Manifests[tag]; ok {
+		w.Header().Set("Docker-Content-Digest", tag)
+		return
+	}
 	http.Error(w, fmt.Sprintf("unknown tag=%s", tag), http.StatusNotFound)
 }
 
+func (s *RegistryServer) getManifest(w http.ResponseWriter, r *http.Request) {
+	err := s.auth(w, r)
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	reference := mux.Vars(r)["tag"]
+	repo, index := s.findRepository(name)
+	if index < 0 {
+		http.Error(w, fmt.Sprintf("unknown repository name=%s", name), http.StatusNotFound)
+		return
+	}
+	s.reposLock.RLock()
+	defer s.reposLock.RUnlock()
+	digest := reference
+	if d, ok := repo.Tags[reference]; ok {
+		digest = d
+	}
+	manifest, ok := repo.Manifests[digest]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown manifest=%s", reference), http.StatusNotFound)
+		return
+	}
+	var content struct {
+		MediaType string `json:"mediaType"`
+	}
+	json.Unmarshal(manifest, &content)
+	if content.MediaType != "" {
+		w.Header().Set("Content-Type", content.MediaType)
+	}
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Write(manifest)
+}
+
+func (s *RegistryServer) putManifest(w http.ResponseWriter, r *http.Request) {
+	err := s.auth(w, r)
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	tag := mux.Vars(r)["tag"]
+	manifest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if strings.HasPrefix(tag, "sha256:") {
+		tag = ""
+	}
+	digest := s.PushManifest(name, tag, manifest)
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *RegistryServer) getBlob(w http.ResponseWriter, r *http.Request) {
+	err := s.auth(w, r)
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	digest := mux.Vars(r)["digest"]
+	repo, index := s.findRepository(name)
+	if index < 0 {
+		http.Error(w, fmt.Sprintf("unknown repository name=%s", name), http.StatusNotFound)
+		return
+	}
+	s.reposLock.RLock()
+	defer s.reposLock.RUnlock()
+	blob, ok := repo.Blobs[digest]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown blob=%s", digest), http.StatusNotFound)
+		return
+	}
+	w.Write(blob)
+}
+
 func (s *RegistryServer) listTags(w http.ResponseWriter, r *http.Request) {
 	err := s.auth(w, r)
 	if err != nil {
@@ -24,6 +24,7 @@ import (
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/servicemanager"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
 )
 
 var (
@@ -206,6 +207,12 @@ func (si *ServiceInstance) Update(service Service, updateData ServiceInstance, e
 	if err != nil {
 		return err
 	}
+	if updateData.TeamOwner != si.TeamOwner {
+		err = servicemanager.TeamQuota.Check(si.ctx, updateData.TeamOwner, quota.TeamResources{ServiceInstances: 1})
+		if err != nil {
+			return err
+		}
+	}
 	conn, err := db.Conn()
 	if err != nil {
 		return err
@@ -439,6 +446,10 @@ func validateServiceInstance(ctx context.Context, si ServiceInstance, s *Service
 	if err != nil {
 		return err
 	}
+	err = servicemanager.TeamQuota.Check(ctx, si.TeamOwner, quota.TeamResources{ServiceInstances: 1})
+	if err != nil {
+		return err
+	}
 	return validateMultiCluster(ctx, s, si)
 }
 
@@ -486,6 +497,17 @@ func CreateServiceInstance(ctx context.Context, instance ServiceInstance, servic
 	return pipeline.Execute(ctx, *service, &instance, evt, requestID)
 }
 
+// CountServiceInstancesByTeamOwner returns the number of service instances
+// owned by the team.
+func CountServiceInstancesByTeamOwner(team string) (int, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return conn.ServiceInstances().Find(bson.M{"teamowner": team}).Count()
+}
+
 func GetServiceInstancesByServices(services []Service) ([]ServiceInstance, error) {
 	var instances []ServiceInstance
 	conn, err := db.Conn()
@@ -10,8 +10,10 @@ import (
 	"github.com/tsuru/tsuru/types/app/image"
 	"github.com/tsuru/tsuru/types/auth"
 	"github.com/tsuru/tsuru/types/cache"
+	"github.com/tsuru/tsuru/types/metering"
 	"github.com/tsuru/tsuru/types/provision"
 	"github.com/tsuru/tsuru/types/quota"
+	"github.com/tsuru/tsuru/types/registry"
 	"github.com/tsuru/tsuru/types/router"
 	"github.com/tsuru/tsuru/types/service"
 	"github.com/tsuru/tsuru/types/tracker"
@@ -26,6 +28,8 @@ type MockService struct {
 	Team                      *auth.MockTeamService
 	UserQuota                 *quota.MockQuotaService
 	AppQuota                  *quota.MockQuotaService
+	TeamQuota                 *quota.MockTeamQuotaService
+	Metering                  *metering.MockMeteringService
 	Cluster                   *provision.MockClusterService
 	ServiceBroker             *service.MockServiceBrokerService
 	ServiceBrokerCatalogCache *service.MockServiceBrokerCatalogCacheService
@@ -33,6 +37,7 @@ type MockService struct {
 	DynamicRouter             *router.MockDynamicRouterService
 	AuthGroup                 auth.GroupService
 	Pool                      *provision.MockPoolService
+	RegistryCredential        *registry.MockCredentialService
 }
 
 // SetMockService return a new MockService and set as a servicemanager
@@ -44,6 +49,8 @@ func SetMockService(m *MockService) {
 	m.Team = &auth.MockTeamService{}
 	m.UserQuota = &quota.MockQuotaService{}
 	m.AppQuota = &quota.MockQuotaService{}
+	m.TeamQuota = &quota.MockTeamQuotaService{}
+	m.Metering = &metering.MockMeteringService{}
 	m.Cluster = &provision.MockClusterService{}
 	m.ServiceBroker = &service.MockServiceBrokerService{}
 	m.ServiceBrokerCatalogCache = &service.MockServiceBrokerCatalogCacheService{}
@@ -51,6 +58,7 @@ func SetMockService(m *MockService) {
 	m.DynamicRouter = &router.MockDynamicRouterService{}
 	m.AuthGroup = &auth.MockGroupService{}
 	m.Pool = &provision.MockPoolService{}
+	m.RegistryCredential = &registry.MockCredentialService{}
 	servicemanager.AppCache = m.Cache
 	servicemanager.Plan = m.Plan
 	servicemanager.Platform = m.Platform
@@ -58,6 +66,8 @@ func SetMockService(m *MockService) {
 	servicemanager.Team = m.Team
 	servicemanager.UserQuota = m.UserQuota
 	servicemanager.AppQuota = m.AppQuota
+	servicemanager.TeamQuota = m.TeamQuota
+	servicemanager.Metering = m.Metering
 	servicemanager.Cluster = m.Cluster
 	servicemanager.ServiceBroker = m.ServiceBroker
 	servicemanager.ServiceBrokerCatalogCache = m.ServiceBrokerCatalogCache
@@ -65,6 +75,7 @@ func SetMockService(m *MockService) {
 	servicemanager.DynamicRouter = m.DynamicRouter
 	servicemanager.AuthGroup = m.AuthGroup
 	servicemanager.Pool = m.Pool
+	servicemanager.RegistryCredential = m.RegistryCredential
 }
 
 func (m *MockService) ResetCache() {
@@ -118,6 +129,16 @@ func (m *MockService) ResetAppQuota() {
 	m.AppQuota.OnSetLimit = nil
 }
 
+func (m *MockService) ResetTeamQuota() {
+	m.TeamQuota.OnGet = nil
+	m.TeamQuota.OnSetLimit = nil
+	m.TeamQuota.OnCheck = nil
+}
+
+func (m *MockService) ResetMetering() {
+	m.Metering.OnReport = nil
+}
+
 func (m *MockService) ResetCluster() {
 	m.Cluster.OnCreate = nil
 	m.Cluster.OnUpdate = nil
@@ -125,6 +146,7 @@ func (m *MockService) ResetCluster() {
 	m.Cluster.OnFindByName = nil
 	m.Cluster.OnFindByProvisioner = nil
 	m.Cluster.OnFindByPool = nil
+	m.Cluster.OnFindAllByPool = nil
 	m.Cluster.OnDelete = nil
 }
 
@@ -145,3 +167,11 @@ func (m *MockService) ResetPool() {
 	m.Pool.OnFindByName = nil
 	m.Pool.OnList = nil
 }
+
+func (m *MockService) ResetRegistryCredential() {
+	m.RegistryCredential.OnCreate = nil
+	m.RegistryCredential.OnUpdate = nil
+	m.RegistryCredential.OnDelete = nil
+	m.RegistryCredential.OnList = nil
+	m.RegistryCredential.OnFindForImage = nil
+}
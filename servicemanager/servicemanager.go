@@ -10,8 +10,10 @@ import (
 	"github.com/tsuru/tsuru/types/auth"
 	"github.com/tsuru/tsuru/types/cache"
 	"github.com/tsuru/tsuru/types/event"
+	"github.com/tsuru/tsuru/types/metering"
 	"github.com/tsuru/tsuru/types/provision"
 	"github.com/tsuru/tsuru/types/quota"
+	"github.com/tsuru/tsuru/types/registry"
 	"github.com/tsuru/tsuru/types/router"
 	"github.com/tsuru/tsuru/types/service"
 	"github.com/tsuru/tsuru/types/tracker"
@@ -28,6 +30,8 @@ var (
 	Webhook                   event.WebhookService
 	AppQuota                  quota.QuotaService
 	UserQuota                 quota.QuotaService
+	TeamQuota                 quota.TeamQuotaService
+	Metering                  metering.MeteringService
 	Cluster                   provision.ClusterService
 	ServiceBroker             service.ServiceBrokerService
 	ServiceBrokerCatalogCache service.ServiceBrokerCatalogCacheService
@@ -37,4 +41,5 @@ var (
 	DynamicRouter             router.DynamicRouterService
 	AuthGroup                 auth.GroupService
 	Pool                      provision.PoolService
+	RegistryCredential        registry.CredentialService
 )
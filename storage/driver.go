@@ -14,8 +14,10 @@ import (
 	"github.com/tsuru/tsuru/types/auth"
 	"github.com/tsuru/tsuru/types/cache"
 	"github.com/tsuru/tsuru/types/event"
+	"github.com/tsuru/tsuru/types/metering"
 	"github.com/tsuru/tsuru/types/provision"
 	"github.com/tsuru/tsuru/types/quota"
+	"github.com/tsuru/tsuru/types/registry"
 	"github.com/tsuru/tsuru/types/router"
 	"github.com/tsuru/tsuru/types/service"
 	"github.com/tsuru/tsuru/types/tracker"
@@ -29,6 +31,8 @@ type DbDriver struct {
 	TeamTokenStorage                 auth.TeamTokenStorage
 	UserQuotaStorage                 quota.QuotaStorage
 	AppQuotaStorage                  quota.QuotaStorage
+	TeamQuotaStorage                 quota.TeamQuotaStorage
+	MeteringStorage                  metering.MeteringStorage
 	WebhookStorage                   event.WebhookStorage
 	ClusterStorage                   provision.ClusterStorage
 	ServiceBrokerStorage             service.ServiceBrokerStorage
@@ -40,6 +44,7 @@ type DbDriver struct {
 	DynamicRouterStorage             router.DynamicRouterStorage
 	AuthGroupStorage                 auth.GroupStorage
 	PoolStorage                      provision.PoolStorage
+	RegistryCredentialStorage        registry.CredentialStorage
 }
 
 var (
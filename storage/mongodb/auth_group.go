@@ -83,6 +83,37 @@ func (s *authGroupStorage) RemoveRole(name, roleName, contextValue string) error
 	return err
 }
 
+func (s *authGroupStorage) Create(name string) error {
+	if name == "" {
+		return errAuthGroupNameEmpty
+	}
+	coll, err := s.collection()
+	if err != nil {
+		return err
+	}
+	defer coll.Close()
+	_, err = coll.Upsert(bson.M{"name": name}, bson.M{
+		"$setOnInsert": bson.M{"name": name},
+	})
+	return err
+}
+
+func (s *authGroupStorage) Remove(name string) error {
+	if name == "" {
+		return errAuthGroupNameEmpty
+	}
+	coll, err := s.collection()
+	if err != nil {
+		return err
+	}
+	defer coll.Close()
+	err = coll.Remove(bson.M{"name": name})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
 func roleToBson(ri auth.RoleInstance) bson.D {
 	// Order matters in $addToSet, that's why bson.D is used instead
 	// of bson.M.
@@ -32,7 +32,8 @@ type cluster struct {
 	Pools       []string          `bson:",omitempty"`
 	CustomData  map[string]string `bson:",omitempty"`
 	Default     bool
-	Writer      io.Writer `bson:"-"`
+	Placement   *provision.ClusterPlacement `bson:",omitempty"`
+	Writer      io.Writer                   `bson:"-"`
 }
 
 func clustersCollection(conn *db.Storage) *dbStorage.Collection {
@@ -47,12 +48,27 @@ func (s *clusterStorage) Upsert(ctx context.Context, c provision.Cluster) error
 	defer conn.Close()
 	coll := clustersCollection(conn)
 	updates := bson.M{}
-	if len(c.Pools) > 0 {
+	if len(c.Pools) > 0 && c.Placement == nil {
 		updates["$pullAll"] = bson.M{"pools": c.Pools}
 	}
 	if c.Default {
 		updates["$set"] = bson.M{"default": false}
 	}
+	if len(c.Pools) > 0 && c.Placement != nil {
+		// Clusters with a placement share their pools with each other, only
+		// clusters without one lose the pools.
+		query := bson.M{"provisioner": c.Provisioner, "placement": bson.M{"$exists": false}}
+
+		span := newMongoDBSpan(ctx, mongoSpanUpdateAll, clusterCollection)
+		span.SetQueryStatement(query)
+		defer span.Finish()
+
+		_, err = coll.UpdateAll(query, bson.M{"$pullAll": bson.M{"pools": c.Pools}})
+		if err != nil {
+			span.SetError(err)
+			return errors.WithStack(err)
+		}
+	}
 	if len(updates) > 0 {
 		query := bson.M{"provisioner": c.Provisioner}
 
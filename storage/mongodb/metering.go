@@ -0,0 +1,158 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/storage"
+	"github.com/tsuru/tsuru/types/metering"
+)
+
+const meteringCollectionName = "metering_usage"
+
+var _ metering.MeteringStorage = &meteringStorage{}
+
+type meteringStorage struct{}
+
+type usageSlot struct {
+	Units   int
+	Seconds int64
+}
+
+type hourlyUsage struct {
+	App      string
+	Process  string
+	Team     string
+	Pool     string
+	Plan     string
+	Hour     time.Time
+	Memory   int64
+	CPUMilli int
+	// Samples is indexed by the offset in seconds of each sample in the hour.
+	Samples map[string]usageSlot
+}
+
+func (s *meteringStorage) collection() (*storage.Collection, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	coll := conn.Collection(meteringCollectionName)
+	err = coll.EnsureIndex(mgo.Index{
+		Key:    []string{"hour", "app", "process", "team", "pool", "plan"},
+		Unique: true,
+	})
+	if err != nil {
+		coll.Close()
+		return nil, err
+	}
+	return coll, nil
+}
+
+func (s *meteringStorage) AddSample(ctx context.Context, sample metering.UsageSample) error {
+	span := newMongoDBSpan(ctx, mongoSpanUpsert, meteringCollectionName)
+	defer span.Finish()
+
+	coll, err := s.collection()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer coll.Close()
+	sampleTime := sample.Time.UTC()
+	hour := sampleTime.Truncate(time.Hour)
+	offset := strconv.Itoa(int(sampleTime.Sub(hour) / time.Second))
+	query := bson.M{
+		"hour":    hour,
+		"app":     sample.App,
+		"process": sample.Process,
+		"team":    sample.Team,
+		"pool":    sample.Pool,
+		"plan":    sample.Plan,
+	}
+	span.SetQueryStatement(query)
+	update := bson.M{"$set": bson.M{
+		"memory":   sample.Memory,
+		"cpumilli": sample.CPUMilli,
+		"samples." + offset: usageSlot{
+			Units:   sample.Units,
+			Seconds: int64(sample.Interval / time.Second),
+		},
+	}}
+	_, err = coll.Upsert(query, update)
+	if mgo.IsDup(err) {
+		// concurrent upserts may race to insert the document, the second
+		// attempt always updates the existing one.
+		_, err = coll.Upsert(query, update)
+	}
+	span.SetError(err)
+	return err
+}
+
+func (s *meteringStorage) FindUsage(ctx context.Context, filter metering.UsageFilter) ([]metering.HourlyUsage, error) {
+	query := bson.M{}
+	hourQuery := bson.M{}
+	if !filter.Start.IsZero() {
+		hourQuery["$gte"] = filter.Start.UTC().Truncate(time.Hour)
+	}
+	if !filter.End.IsZero() {
+		hourQuery["$lt"] = filter.End.UTC()
+	}
+	if len(hourQuery) > 0 {
+		query["hour"] = hourQuery
+	}
+	if len(filter.Teams) > 0 {
+		query["team"] = bson.M{"$in": filter.Teams}
+	}
+	if len(filter.Pools) > 0 {
+		query["pool"] = bson.M{"$in": filter.Pools}
+	}
+	if len(filter.Apps) > 0 {
+		query["app"] = bson.M{"$in": filter.Apps}
+	}
+	span := newMongoDBSpan(ctx, mongoSpanFind, meteringCollectionName)
+	span.SetQueryStatement(query)
+	defer span.Finish()
+
+	coll, err := s.collection()
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	defer coll.Close()
+	var usages []hourlyUsage
+	err = coll.Find(query).Sort("hour", "app", "process").All(&usages)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	result := make([]metering.HourlyUsage, len(usages))
+	for i, u := range usages {
+		var unitSeconds int64
+		for _, slot := range u.Samples {
+			unitSeconds += int64(slot.Units) * slot.Seconds
+		}
+		result[i] = metering.HourlyUsage{
+			UsageKey: metering.UsageKey{
+				App:     u.App,
+				Process: u.Process,
+				Team:    u.Team,
+				Pool:    u.Pool,
+				Plan:    u.Plan,
+			},
+			Hour:        u.Hour.UTC(),
+			UnitSeconds: unitSeconds,
+			Memory:      u.Memory,
+			CPUMilli:    u.CPUMilli,
+		}
+	}
+	return result, nil
+}
@@ -18,6 +18,8 @@ func init() {
 		TeamTokenStorage:                 &teamTokenStorage{},
 		UserQuotaStorage:                 authQuotaStorage(),
 		AppQuotaStorage:                  appQuotaStorage(),
+		TeamQuotaStorage:                 &teamQuotaStorage{},
+		MeteringStorage:                  &meteringStorage{},
 		WebhookStorage:                   &webhookStorage{},
 		ClusterStorage:                   &clusterStorage{},
 		ServiceBrokerStorage:             &serviceBrokerStorage{},
@@ -28,6 +30,7 @@ func init() {
 		DynamicRouterStorage:             &dynamicRouterStorage{},
 		AuthGroupStorage:                 &authGroupStorage{},
 		PoolStorage:                      &PoolStorage{},
+		RegistryCredentialStorage:        &registryCredentialStorage{},
 	}
 	storage.RegisterDbDriver("mongodb", mongodbDriver)
 }
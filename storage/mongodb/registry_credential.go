@@ -0,0 +1,133 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+	dbStorage "github.com/tsuru/tsuru/db/storage"
+	"github.com/tsuru/tsuru/types/registry"
+)
+
+const registryCredentialsCollectionName = "registry_credentials"
+
+var _ registry.CredentialStorage = &registryCredentialStorage{}
+
+type registryCredentialStorage struct{}
+
+func registryCredentialsCollection(conn *db.Storage) *dbStorage.Collection {
+	c := conn.Collection(registryCredentialsCollectionName)
+	c.EnsureIndex(mgo.Index{Key: []string{"team", "registry"}, Unique: true})
+	return c
+}
+
+func (s *registryCredentialStorage) Insert(ctx context.Context, c registry.Credential) error {
+	span := newMongoDBSpan(ctx, mongoSpanInsert, registryCredentialsCollectionName)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = registryCredentialsCollection(conn).Insert(c)
+	if mgo.IsDup(err) {
+		err = registry.ErrCredentialAlreadyExists
+	}
+	span.SetError(err)
+	return err
+}
+
+func (s *registryCredentialStorage) Update(ctx context.Context, c registry.Credential) error {
+	query := bson.M{"team": c.Team, "registry": c.Registry}
+	span := newMongoDBSpan(ctx, mongoSpanUpdate, registryCredentialsCollectionName)
+	span.SetQueryStatement(query)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = registryCredentialsCollection(conn).Update(query, c)
+	if err == mgo.ErrNotFound {
+		err = registry.ErrCredentialNotFound
+	}
+	span.SetError(err)
+	return err
+}
+
+func (s *registryCredentialStorage) Delete(ctx context.Context, team, reg string) error {
+	query := bson.M{"team": team, "registry": reg}
+	span := newMongoDBSpan(ctx, mongoSpanDelete, registryCredentialsCollectionName)
+	span.SetQueryStatement(query)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = registryCredentialsCollection(conn).Remove(query)
+	if err == mgo.ErrNotFound {
+		err = registry.ErrCredentialNotFound
+	}
+	span.SetError(err)
+	return err
+}
+
+func (s *registryCredentialStorage) Find(ctx context.Context, team, reg string) (*registry.Credential, error) {
+	query := bson.M{"team": team, "registry": reg}
+	span := newMongoDBSpan(ctx, mongoSpanFindOne, registryCredentialsCollectionName)
+	span.SetQueryStatement(query)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	defer conn.Close()
+	var result registry.Credential
+	err = registryCredentialsCollection(conn).Find(query).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			err = registry.ErrCredentialNotFound
+		}
+		span.SetError(err)
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *registryCredentialStorage) FindByTeams(ctx context.Context, teams []string) ([]registry.Credential, error) {
+	var query bson.M
+	if teams != nil {
+		query = bson.M{"team": bson.M{"$in": teams}}
+	}
+	span := newMongoDBSpan(ctx, mongoSpanFind, registryCredentialsCollectionName)
+	span.SetQueryStatement(query)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	defer conn.Close()
+	var result []registry.Credential
+	err = registryCredentialsCollection(conn).Find(query).Sort("team", "registry").All(&result)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	return result, nil
+}
@@ -139,6 +139,12 @@ func (s *TeamStorage) Delete(ctx context.Context, t auth.Team) error {
 	if err == mgo.ErrNotFound {
 		err = auth.ErrTeamNotFound
 	}
+	if err == nil {
+		err = conn.Collection(teamQuotasCollectionName).RemoveId(t.Name)
+		if err == mgo.ErrNotFound {
+			err = nil
+		}
+	}
 	span.SetError(err)
 	return err
 }
@@ -0,0 +1,65 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/globalsign/mgo"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/types/quota"
+)
+
+// team quotas are kept apart from the teams collection because team updates
+// replace the whole document.
+const teamQuotasCollectionName = "team_quotas"
+
+var _ quota.TeamQuotaStorage = &teamQuotaStorage{}
+
+type teamQuotaStorage struct{}
+
+type teamQuota struct {
+	Team  string `bson:"_id"`
+	Limit quota.TeamResources
+}
+
+func (s *teamQuotaStorage) GetLimit(ctx context.Context, team string) (*quota.TeamResources, error) {
+	span := newMongoDBSpan(ctx, mongoSpanFindID, teamQuotasCollectionName)
+	span.SetMongoID(team)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	defer conn.Close()
+	var q teamQuota
+	err = conn.Collection(teamQuotasCollectionName).FindId(team).One(&q)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, quota.ErrQuotaNotFound
+		}
+		span.SetError(err)
+		return nil, err
+	}
+	return &q.Limit, nil
+}
+
+func (s *teamQuotaStorage) SetLimit(ctx context.Context, team string, limit quota.TeamResources) error {
+	span := newMongoDBSpan(ctx, mongoSpanUpsertID, teamQuotasCollectionName)
+	span.SetMongoID(team)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Collection(teamQuotasCollectionName).UpsertId(team, teamQuota{Team: team, Limit: limit})
+	span.SetError(err)
+	return err
+}
@@ -95,3 +95,32 @@ func (s *AuthGroupSuite) TestList(c *check.C) {
 	c.Assert(groups, check.HasLen, 1)
 	c.Assert(groups[0].Name, check.Equals, "g2")
 }
+
+func (s *AuthGroupSuite) TestCreate(c *check.C) {
+	err := s.AuthGroupStorage.AddRole("g1", "r1", "v1")
+	c.Assert(err, check.IsNil)
+	err = s.AuthGroupStorage.Create("g1")
+	c.Assert(err, check.IsNil)
+	err = s.AuthGroupStorage.Create("g2")
+	c.Assert(err, check.IsNil)
+	groups, err := s.AuthGroupStorage.List([]string{"g1"})
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.DeepEquals, []auth.Group{
+		{Name: "g1", Roles: []auth.RoleInstance{{Name: "r1", ContextValue: "v1"}}},
+	})
+	groups, err = s.AuthGroupStorage.List([]string{"g2"})
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.DeepEquals, []auth.Group{{Name: "g2"}})
+}
+
+func (s *AuthGroupSuite) TestRemove(c *check.C) {
+	err := s.AuthGroupStorage.AddRole("g1", "r1", "v1")
+	c.Assert(err, check.IsNil)
+	err = s.AuthGroupStorage.Remove("g1")
+	c.Assert(err, check.IsNil)
+	groups, err := s.AuthGroupStorage.List(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.HasLen, 0)
+	err = s.AuthGroupStorage.Remove("g1")
+	c.Assert(err, check.IsNil)
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storagetest
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/types/metering"
+	check "gopkg.in/check.v1"
+)
+
+type MeteringSuite struct {
+	SuiteHooks
+	MeteringStorage metering.MeteringStorage
+}
+
+func (s *MeteringSuite) TestAddSample(c *check.C) {
+	hour := time.Date(2026, 5, 10, 14, 0, 0, 0, time.UTC)
+	key := metering.UsageKey{App: "myapp", Process: "web", Team: "myteam", Pool: "pool1", Plan: "small"}
+	for i := 0; i < 4; i++ {
+		err := s.MeteringStorage.AddSample(context.TODO(), metering.UsageSample{
+			UsageKey: key,
+			Time:     hour.Add(time.Duration(i) * 15 * time.Minute),
+			Interval: 15 * time.Minute,
+			Units:    i + 1,
+			Memory:   1024,
+			CPUMilli: 500,
+		})
+		c.Assert(err, check.IsNil)
+	}
+	// sampling the same slot again replaces the previous value
+	err := s.MeteringStorage.AddSample(context.TODO(), metering.UsageSample{
+		UsageKey: key,
+		Time:     hour.Add(45 * time.Minute),
+		Interval: 15 * time.Minute,
+		Units:    2,
+		Memory:   1024,
+		CPUMilli: 500,
+	})
+	c.Assert(err, check.IsNil)
+	usage, err := s.MeteringStorage.FindUsage(context.TODO(), metering.UsageFilter{})
+	c.Assert(err, check.IsNil)
+	c.Assert(usage, check.DeepEquals, []metering.HourlyUsage{
+		{UsageKey: key, Hour: hour, UnitSeconds: (1 + 2 + 3 + 2) * 900, Memory: 1024, CPUMilli: 500},
+	})
+}
+
+func (s *MeteringSuite) TestFindUsageFilter(c *check.C) {
+	hour := time.Date(2026, 5, 10, 14, 0, 0, 0, time.UTC)
+	samples := []metering.UsageSample{
+		{UsageKey: metering.UsageKey{App: "app1", Process: "web", Team: "team1", Pool: "pool1", Plan: "small"}, Time: hour},
+		{UsageKey: metering.UsageKey{App: "app1", Process: "web", Team: "team1", Pool: "pool1", Plan: "small"}, Time: hour.Add(time.Hour)},
+		{UsageKey: metering.UsageKey{App: "app2", Process: "web", Team: "team2", Pool: "pool1", Plan: "small"}, Time: hour},
+		{UsageKey: metering.UsageKey{App: "app3", Process: "web", Team: "team1", Pool: "pool2", Plan: "small"}, Time: hour.Add(2 * time.Hour)},
+	}
+	for _, sample := range samples {
+		sample.Interval = time.Minute
+		sample.Units = 1
+		err := s.MeteringStorage.AddSample(context.TODO(), sample)
+		c.Assert(err, check.IsNil)
+	}
+	usage, err := s.MeteringStorage.FindUsage(context.TODO(), metering.UsageFilter{Teams: []string{"team1"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(usage, check.HasLen, 3)
+	usage, err = s.MeteringStorage.FindUsage(context.TODO(), metering.UsageFilter{Pools: []string{"pool1"}, Apps: []string{"app2"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(usage, check.HasLen, 1)
+	c.Assert(usage[0].App, check.Equals, "app2")
+	usage, err = s.MeteringStorage.FindUsage(context.TODO(), metering.UsageFilter{
+		Start: hour.Add(30 * time.Minute),
+		End:   hour.Add(2 * time.Hour),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(usage, check.HasLen, 3)
+	for _, u := range usage {
+		c.Assert(u.Hour.Before(hour.Add(2*time.Hour)), check.Equals, true)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storagetest
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/types/registry"
+	check "gopkg.in/check.v1"
+)
+
+type RegistryCredentialSuite struct {
+	SuiteHooks
+	RegistryCredentialStorage registry.CredentialStorage
+}
+
+func (s *RegistryCredentialSuite) TestInsertAndFind(c *check.C) {
+	cred := registry.Credential{Team: "team1", Registry: "registry.example.com", Username: "bot", Token: "secret"}
+	err := s.RegistryCredentialStorage.Insert(context.TODO(), cred)
+	c.Assert(err, check.IsNil)
+	found, err := s.RegistryCredentialStorage.Find(context.TODO(), "team1", "registry.example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(*found, check.DeepEquals, cred)
+	_, err = s.RegistryCredentialStorage.Find(context.TODO(), "team2", "registry.example.com")
+	c.Assert(err, check.Equals, registry.ErrCredentialNotFound)
+}
+
+func (s *RegistryCredentialSuite) TestInsertDuplicated(c *check.C) {
+	cred := registry.Credential{Team: "team1", Registry: "registry.example.com", Username: "bot", Token: "secret"}
+	err := s.RegistryCredentialStorage.Insert(context.TODO(), cred)
+	c.Assert(err, check.IsNil)
+	err = s.RegistryCredentialStorage.Insert(context.TODO(), cred)
+	c.Assert(err, check.Equals, registry.ErrCredentialAlreadyExists)
+	cred.Team = "team2"
+	err = s.RegistryCredentialStorage.Insert(context.TODO(), cred)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *RegistryCredentialSuite) TestUpdate(c *check.C) {
+	cred := registry.Credential{Team: "team1", Registry: "registry.example.com", Username: "bot", Token: "secret"}
+	err := s.RegistryCredentialStorage.Update(context.TODO(), cred)
+	c.Assert(err, check.Equals, registry.ErrCredentialNotFound)
+	err = s.RegistryCredentialStorage.Insert(context.TODO(), cred)
+	c.Assert(err, check.IsNil)
+	cred.Username = "other-bot"
+	cred.Token = "new-secret"
+	err = s.RegistryCredentialStorage.Update(context.TODO(), cred)
+	c.Assert(err, check.IsNil)
+	found, err := s.RegistryCredentialStorage.Find(context.TODO(), "team1", "registry.example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(*found, check.DeepEquals, cred)
+}
+
+func (s *RegistryCredentialSuite) TestDelete(c *check.C) {
+	err := s.RegistryCredentialStorage.Delete(context.TODO(), "team1", "registry.example.com")
+	c.Assert(err, check.Equals, registry.ErrCredentialNotFound)
+	cred := registry.Credential{Team: "team1", Registry: "registry.example.com", Username: "bot", Token: "secret"}
+	err = s.RegistryCredentialStorage.Insert(context.TODO(), cred)
+	c.Assert(err, check.IsNil)
+	err = s.RegistryCredentialStorage.Delete(context.TODO(), "team1", "registry.example.com")
+	c.Assert(err, check.IsNil)
+	_, err = s.RegistryCredentialStorage.Find(context.TODO(), "team1", "registry.example.com")
+	c.Assert(err, check.Equals, registry.ErrCredentialNotFound)
+}
+
+func (s *RegistryCredentialSuite) TestFindByTeams(c *check.C) {
+	creds := []registry.Credential{
+		{Team: "team2", Registry: "registry.example.com", Username: "bot", Token: "secret"},
+		{Team: "team1", Registry: "registry.example.com", Username: "bot", Token: "secret"},
+		{Team: "team1", Registry: "docker.io", Username: "bot", Token: "secret"},
+		{Team: "team3", Registry: "docker.io", Username: "bot", Token: "secret"},
+	}
+	for _, cred := range creds {
+		err := s.RegistryCredentialStorage.Insert(context.TODO(), cred)
+		c.Assert(err, check.IsNil)
+	}
+	found, err := s.RegistryCredentialStorage.FindByTeams(context.TODO(), []string{"team1", "team2"})
+	c.Assert(err, check.IsNil)
+	c.Assert(found, check.DeepEquals, []registry.Credential{creds[2], creds[1], creds[0]})
+	found, err = s.RegistryCredentialStorage.FindByTeams(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(found, check.HasLen, 4)
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storagetest
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+type TeamQuotaSuite struct {
+	SuiteHooks
+	TeamQuotaStorage quota.TeamQuotaStorage
+}
+
+func (s *TeamQuotaSuite) TestGetLimitNotFound(c *check.C) {
+	_, err := s.TeamQuotaStorage.GetLimit(context.TODO(), "myteam")
+	c.Assert(err, check.Equals, quota.ErrQuotaNotFound)
+}
+
+func (s *TeamQuotaSuite) TestSetLimit(c *check.C) {
+	limit := quota.TeamResources{Units: 10, Memory: 1024, CPUMilli: -1, ServiceInstances: 2}
+	err := s.TeamQuotaStorage.SetLimit(context.TODO(), "myteam", limit)
+	c.Assert(err, check.IsNil)
+	stored, err := s.TeamQuotaStorage.GetLimit(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(*stored, check.DeepEquals, limit)
+	limit.Units = 20
+	err = s.TeamQuotaStorage.SetLimit(context.TODO(), "myteam", limit)
+	c.Assert(err, check.IsNil)
+	stored, err = s.TeamQuotaStorage.GetLimit(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(*stored, check.DeepEquals, limit)
+	_, err = s.TeamQuotaStorage.GetLimit(context.TODO(), "otherteam")
+	c.Assert(err, check.Equals, quota.ErrQuotaNotFound)
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"net"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+)
+
+// AppDependencies are the apps and external networks an app is expected to
+// reach. Provisioners enforcing network policies only allow traffic to them,
+// besides the service instances bound to the app.
+type AppDependencies struct {
+	Apps  []string `json:"apps,omitempty" bson:",omitempty"`
+	CIDRs []string `json:"cidrs,omitempty" bson:",omitempty"`
+}
+
+func (d AppDependencies) Empty() bool {
+	return len(d.Apps) == 0 && len(d.CIDRs) == 0
+}
+
+func (d AppDependencies) Validate() error {
+	for _, cidr := range d.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return &tsuruErrors.ValidationError{Message: "invalid cidr " + cidr}
+		}
+	}
+	return nil
+}
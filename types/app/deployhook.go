@@ -0,0 +1,51 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"net/url"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+)
+
+const (
+	// DeployHookPreDeploy hooks are called once the image of the new
+	// version is available, before its units are started. A failure aborts
+	// the deploy.
+	DeployHookPreDeploy = "pre-deploy"
+
+	// DeployHookPostDeploy hooks are called once the units of the new
+	// version are running, before the deploy is committed. A failure rolls
+	// the deploy back.
+	DeployHookPostDeploy = "post-deploy"
+)
+
+// DeployHook is an HTTP endpoint tsuru calls with the deploy metadata at a
+// given stage of the deploy, waiting up to Timeout seconds for a response.
+// When Secret is set, requests are signed with it.
+type DeployHook struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Stage   string `json:"stage"`
+	Timeout int    `json:"timeout,omitempty" bson:",omitempty"`
+	Secret  string `json:"secret,omitempty" bson:",omitempty"`
+}
+
+func (h DeployHook) Validate() error {
+	if h.Name == "" {
+		return &tsuruErrors.ValidationError{Message: "deploy hook name is required"}
+	}
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &tsuruErrors.ValidationError{Message: "invalid url for deploy hook " + h.Name}
+	}
+	if h.Stage != DeployHookPreDeploy && h.Stage != DeployHookPostDeploy {
+		return &tsuruErrors.ValidationError{Message: "invalid stage " + h.Stage + " for deploy hook " + h.Name}
+	}
+	if h.Timeout < 0 {
+		return &tsuruErrors.ValidationError{Message: "timeout for deploy hook " + h.Name + " must not be negative"}
+	}
+	return nil
+}
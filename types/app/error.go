@@ -26,6 +26,7 @@ var (
 	ErrInvalidPlatform        = errors.New("Invalid platform")
 	ErrMissingFileContent     = errors.New("Missing file content.")
 	ErrDeletePlatformWithApps = errors.New("Platform has apps. You must remove them before remove the platform.")
+	ErrDependencyNotFound     = errors.New("dependency not found")
 	ErrInvalidPlatformName    = &tsuruErrors.ValidationError{
 		Message: "Invalid platform name, should have at most 40 " +
 			"characters, containing only lower case letters, numbers or dashes, " +
@@ -7,6 +7,7 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -41,6 +42,8 @@ type AppVersion interface {
 	CommitBuildImage() error
 	BaseImageName() string
 	CommitBaseImage() error
+	CommitDeployDigest(digest string) error
+	CommitArchitectures(architectures []string) error
 	CommitSuccessful() error
 	MarkToRemoval() error
 	VersionInfo() AppVersionInfo
@@ -73,6 +76,8 @@ type AppVersionInfo struct {
 	Description      string                 `json:"description"`
 	BuildImage       string                 `json:"buildImage"`
 	DeployImage      string                 `json:"deployImage"`
+	DeployDigest     string                 `json:"deployDigest"`
+	Architectures    []string               `json:"architectures"`
 	CustomBuildTag   string                 `json:"customBuildTag"`
 	CustomData       map[string]interface{} `json:"customData"`
 	Processes        map[string][]string    `json:"processes"`
@@ -86,6 +91,19 @@ type AppVersionInfo struct {
 	MarkedToRemoval  bool                   `json:"markedToRemoval"`
 }
 
+// DeployImageReference returns the image used to run the version, pinned to
+// its digest when the digest is known.
+func (i AppVersionInfo) DeployImageReference() string {
+	if i.DeployDigest == "" || i.DeployImage == "" {
+		return i.DeployImage
+	}
+	repo := i.DeployImage
+	if idx := strings.LastIndex(repo, ":"); idx > strings.LastIndex(repo, "/") {
+		repo = repo[:idx]
+	}
+	return repo + "@" + i.DeployDigest
+}
+
 type NewVersionArgs struct {
 	EventID        string
 	App            App
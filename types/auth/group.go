@@ -15,6 +15,8 @@ type GroupStorage interface {
 
 type GroupService interface {
 	List(filter []string) ([]Group, error)
+	Create(name string) error
+	Remove(name string) error
 	AddRole(name, roleName, contextValue string) error
 	RemoveRole(name, roleName, contextValue string) error
 }
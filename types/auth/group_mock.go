@@ -12,6 +12,8 @@ type MockGroupService struct {
 	OnAddRole    func(name, roleName, contextValue string) error
 	OnRemoveRole func(name, roleName, contextValue string) error
 	OnList       func(filter []string) ([]Group, error)
+	OnCreate     func(name string) error
+	OnRemove     func(name string) error
 }
 
 func (m *MockGroupService) AddRole(name string, roleName, contextValue string) error {
@@ -34,3 +36,17 @@ func (m *MockGroupService) List(filter []string) ([]Group, error) {
 	}
 	return m.OnList(filter)
 }
+
+func (m *MockGroupService) Create(name string) error {
+	if m.OnCreate == nil {
+		return nil
+	}
+	return m.OnCreate(name)
+}
+
+func (m *MockGroupService) Remove(name string) error {
+	if m.OnRemove == nil {
+		return nil
+	}
+	return m.OnRemove(name)
+}
@@ -54,6 +54,7 @@ type TeamTokenService interface {
 	Authenticate(ctx context.Context, header string) (Token, error)
 	FindByTokenID(ctx context.Context, tokenID string) (TeamToken, error)
 	FindByUserToken(ctx context.Context, t Token) ([]TeamToken, error)
+	List(ctx context.Context) ([]TeamToken, error)
 	AddRole(ctx context.Context, tokenID string, roleName, contextValue string) error
 	RemoveRole(ctx context.Context, tokenID string, roleName, contextValue string) error
 }
@@ -19,6 +19,7 @@ type User struct {
 	APIKey   string
 	Roles    []RoleInstance
 	Groups   []string
+	Disabled bool
 }
 
 type RoleInstance struct {
@@ -35,6 +36,7 @@ var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrInvalidKey   = errors.New("invalid key")
 	ErrKeyDisabled  = errors.New("key management is disabled")
+	ErrUserDisabled = errors.New("user is disabled")
 )
 
 func (e *ErrTeamStillUsed) Error() string {
@@ -0,0 +1,98 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metering
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidGroupBy = errors.New("invalid group by, must be one of: team, pool, app")
+
+type GroupBy string
+
+const (
+	GroupByTeam GroupBy = "team"
+	GroupByPool GroupBy = "pool"
+	GroupByApp  GroupBy = "app"
+)
+
+func (g GroupBy) Valid() bool {
+	return g == GroupByTeam || g == GroupByPool || g == GroupByApp
+}
+
+// UsageKey identifies the units being metered. Team, pool and plan are
+// recorded at sampling time, so later changes to the app don't affect past
+// usage.
+type UsageKey struct {
+	App     string
+	Process string
+	Team    string
+	Pool    string
+	Plan    string
+}
+
+// UsageSample is the number of running units of an app process at a given
+// time. Memory and CPUMilli are the resources of each unit.
+type UsageSample struct {
+	UsageKey
+	Time     time.Time
+	Interval time.Duration
+	Units    int
+	Memory   int64
+	CPUMilli int
+}
+
+// HourlyUsage aggregates the samples of an app process in an hour.
+type HourlyUsage struct {
+	UsageKey
+	Hour        time.Time
+	UnitSeconds int64
+	Memory      int64
+	CPUMilli    int
+}
+
+type UsageFilter struct {
+	Start time.Time
+	End   time.Time
+	Teams []string
+	Pools []string
+	Apps  []string
+}
+
+type MeteringStorage interface {
+	// AddSample stores the sample in the hourly aggregate. Samples are
+	// identified by their time slot, adding the same slot more than once
+	// overwrites the previous value.
+	AddSample(ctx context.Context, sample UsageSample) error
+	FindUsage(ctx context.Context, filter UsageFilter) ([]HourlyUsage, error)
+}
+
+type ReportOptions struct {
+	UsageFilter
+	GroupBy GroupBy
+}
+
+type ReportEntry struct {
+	Group         string  `json:"group"`
+	UnitHours     float64 `json:"unitHours"`
+	MemoryGBHours float64 `json:"memoryGBHours"`
+	CPUHours      float64 `json:"cpuHours"`
+	Cost          float64 `json:"cost"`
+}
+
+type Report struct {
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	GroupBy  GroupBy       `json:"groupBy"`
+	Currency string        `json:"currency"`
+	Entries  []ReportEntry `json:"entries"`
+	Total    ReportEntry   `json:"total"`
+}
+
+type MeteringService interface {
+	Report(ctx context.Context, opts ReportOptions) (*Report, error)
+}
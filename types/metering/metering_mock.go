@@ -0,0 +1,33 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metering
+
+import "context"
+
+var (
+	_ MeteringStorage = &MockMeteringStorage{}
+	_ MeteringService = &MockMeteringService{}
+)
+
+type MockMeteringStorage struct {
+	OnAddSample func(UsageSample) error
+	OnFindUsage func(UsageFilter) ([]HourlyUsage, error)
+}
+
+func (m *MockMeteringStorage) AddSample(ctx context.Context, sample UsageSample) error {
+	return m.OnAddSample(sample)
+}
+
+func (m *MockMeteringStorage) FindUsage(ctx context.Context, filter UsageFilter) ([]HourlyUsage, error) {
+	return m.OnFindUsage(filter)
+}
+
+type MockMeteringService struct {
+	OnReport func(ReportOptions) (*Report, error)
+}
+
+func (m *MockMeteringService) Report(ctx context.Context, opts ReportOptions) (*Report, error) {
+	return m.OnReport(opts)
+}
@@ -19,6 +19,10 @@ var (
 	ContextTypes = []ContextType{
 		CtxGlobal, CtxApp, CtxTeam, CtxUser, CtxPool, CtxIaaS, CtxService, CtxServiceInstance, CtxVolume, CtxRouter,
 	}
+
+	// CtxTag is not a valid role context, it is used to carry the tags of
+	// the object being checked so that role conditions can be evaluated.
+	CtxTag = ContextType("tag")
 )
 
 type ContextType string
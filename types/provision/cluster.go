@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sort"
 )
 
 // Cluster represents a cluster of nodes.
@@ -21,9 +22,76 @@ type Cluster struct {
 	Pools       []string          `json:"pools"`
 	CustomData  map[string]string `json:"custom_data"`
 	Default     bool              `json:"default"`
+	Placement   *ClusterPlacement `json:"placement,omitempty"`
 	Writer      io.Writer         `json:"-"`
 }
 
+const (
+	ClusterRoleActive  = "active"
+	ClusterRolePassive = "passive"
+)
+
+// ClusterPlacement allows a cluster to share its pools with other clusters.
+// Units of apps in a shared pool are distributed across the active members,
+// proportionally to their weights. Passive members only receive units when
+// every active member is drained.
+type ClusterPlacement struct {
+	Role    string `json:"role,omitempty"`
+	Weight  int    `json:"weight,omitempty"`
+	Drained bool   `json:"drained,omitempty"`
+}
+
+func (p *ClusterPlacement) IsActive() bool {
+	return p == nil || p.Role == "" || p.Role == ClusterRoleActive
+}
+
+func (p *ClusterPlacement) IsDrained() bool {
+	return p != nil && p.Drained
+}
+
+// GetWeight returns the share of units the cluster receives among the active
+// members of a pool, defaulting to 1.
+func (p *ClusterPlacement) GetWeight() int {
+	if p == nil || p.Weight == 0 {
+		return 1
+	}
+	return p.Weight
+}
+
+func (p *ClusterPlacement) Validate() error {
+	switch p.Role {
+	case "", ClusterRoleActive, ClusterRolePassive:
+	default:
+		return errors.New("placement role must be either active or passive")
+	}
+	if p.Weight < 0 {
+		return errors.New("placement weight must not be negative")
+	}
+	return nil
+}
+
+// SortByPlacement orders the member clusters of a pool by precedence: active
+// members first, then passive members and drained members last. The first
+// cluster is the primary member of the pool.
+func SortByPlacement(clusters []Cluster) {
+	rank := func(c Cluster) int {
+		switch {
+		case c.Placement.IsDrained():
+			return 2
+		case c.Placement.IsActive():
+			return 0
+		}
+		return 1
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		ri, rj := rank(clusters[i]), rank(clusters[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+}
+
 type ClusterHelpInfo struct {
 	ProvisionerHelp string            `json:"provisioner_help"`
 	CustomDataHelp  map[string]string `json:"custom_data_help"`
@@ -37,6 +105,7 @@ type ClusterService interface {
 	FindByProvisioner(context.Context, string) ([]Cluster, error)
 	FindByPool(context.Context, string, string) (*Cluster, error)
 	FindByPools(context.Context, string, []string) (map[string]Cluster, error)
+	FindAllByPool(context.Context, string, string) ([]Cluster, error)
 	Delete(context.Context, Cluster) error
 }
 
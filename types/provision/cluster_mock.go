@@ -69,6 +69,7 @@ type MockClusterService struct {
 	OnFindByProvisioner func(string) ([]Cluster, error)
 	OnFindByPool        func(string, string) (*Cluster, error)
 	OnFindByPools       func(string, []string) (map[string]Cluster, error)
+	OnFindAllByPool     func(string, string) ([]Cluster, error)
 	OnDelete            func(Cluster) error
 }
 
@@ -121,6 +122,13 @@ func (m *MockClusterService) FindByPools(ctx context.Context, provisioner string
 	return m.OnFindByPools(provisioner, pool)
 }
 
+func (m *MockClusterService) FindAllByPool(ctx context.Context, prov, pool string) ([]Cluster, error) {
+	if m.OnFindAllByPool == nil {
+		return nil, nil
+	}
+	return m.OnFindAllByPool(prov, pool)
+}
+
 func (m *MockClusterService) Delete(ctx context.Context, c Cluster) error {
 	if m.OnDelete == nil {
 		return nil
@@ -0,0 +1,101 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package provision
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var containerNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// TsuruYamlKubernetesContainer is an extra container, either an init
+// container or a sidecar, running in the same pod as a process.
+type TsuruYamlKubernetesContainer struct {
+	Name         string                           `json:"name"`
+	Image        string                           `json:"image"`
+	Command      []string                         `json:"command,omitempty" bson:",omitempty"`
+	Args         []string                         `json:"args,omitempty" bson:",omitempty"`
+	Env          map[string]string                `json:"env,omitempty" bson:",omitempty"`
+	VolumeMounts []TsuruYamlKubernetesVolumeMount `json:"volume_mounts,omitempty" yaml:"volume_mounts" bson:"volume_mounts,omitempty"`
+}
+
+// TsuruYamlKubernetesVolumeMount mounts a shared volume in an extra
+// container.
+type TsuruYamlKubernetesVolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path" yaml:"mount_path" bson:"mount_path"`
+}
+
+// TsuruYamlKubernetesSharedVolume is an empty volume shared between the
+// process container and its extra containers. It's mounted in the process
+// container at MountPath.
+type TsuruYamlKubernetesSharedVolume struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path,omitempty" yaml:"mount_path" bson:"mount_path,omitempty"`
+}
+
+// Images returns the images used by the init containers and sidecars of the
+// process.
+func (c *TsuruYamlKubernetesProcessConfig) Images() []string {
+	var images []string
+	for _, containers := range [][]TsuruYamlKubernetesContainer{c.InitContainers, c.Sidecars} {
+		for _, container := range containers {
+			images = append(images, container.Image)
+		}
+	}
+	return images
+}
+
+// ValidateContainers checks the init containers, sidecars and shared volumes
+// of the process.
+func (c *TsuruYamlKubernetesProcessConfig) ValidateContainers() error {
+	volumes := map[string]struct{}{}
+	for _, v := range c.SharedVolumes {
+		if !containerNameRegexp.MatchString(v.Name) {
+			return errors.Errorf("invalid shared volume name %q", v.Name)
+		}
+		if _, ok := volumes[v.Name]; ok {
+			return errors.Errorf("duplicated shared volume %q", v.Name)
+		}
+		if v.MountPath != "" && !strings.HasPrefix(v.MountPath, "/") {
+			return errors.Errorf("shared volume %q: mount_path must be absolute", v.Name)
+		}
+		volumes[v.Name] = struct{}{}
+	}
+	names := map[string]struct{}{}
+	for _, containers := range [][]TsuruYamlKubernetesContainer{c.InitContainers, c.Sidecars} {
+		for _, container := range containers {
+			if err := container.validate(volumes); err != nil {
+				return err
+			}
+			if _, ok := names[container.Name]; ok {
+				return errors.Errorf("duplicated container %q", container.Name)
+			}
+			names[container.Name] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func (c *TsuruYamlKubernetesContainer) validate(volumes map[string]struct{}) error {
+	if !containerNameRegexp.MatchString(c.Name) || len(c.Name) > 63 {
+		return errors.Errorf("invalid container name %q", c.Name)
+	}
+	if c.Image == "" {
+		return errors.Errorf("container %q: image is required", c.Name)
+	}
+	for _, m := range c.VolumeMounts {
+		if _, ok := volumes[m.Name]; !ok {
+			return errors.Errorf("container %q: shared volume %q not found", c.Name, m.Name)
+		}
+		if !strings.HasPrefix(m.MountPath, "/") {
+			return errors.Errorf("container %q: mount_path must be absolute", c.Name)
+		}
+	}
+	return nil
+}
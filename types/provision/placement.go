@@ -0,0 +1,125 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package provision
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	TopologySpreadHard = "hard"
+	TopologySpreadSoft = "soft"
+	TopologySpreadNone = "none"
+)
+
+// TsuruYamlKubernetesDisruptionBudget overrides the pod disruption budget
+// policy of the pool for a process. Values are either an absolute number of
+// units or a percentage, like "25%".
+type TsuruYamlKubernetesDisruptionBudget struct {
+	MaxUnavailable string `json:"max_unavailable,omitempty" yaml:"max_unavailable" bson:"max_unavailable,omitempty"`
+	MinAvailable   string `json:"min_available,omitempty" yaml:"min_available" bson:"min_available,omitempty"`
+	Disabled       bool   `json:"disabled,omitempty" bson:",omitempty"`
+}
+
+// UnmarshalJSON accepts both numbers and strings, as tsuru.yaml users will
+// usually write max_unavailable: 1 instead of max_unavailable: "1".
+func (b *TsuruYamlKubernetesDisruptionBudget) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		MaxUnavailable interface{} `json:"max_unavailable"`
+		MinAvailable   interface{} `json:"min_available"`
+		Disabled       bool        `json:"disabled"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	*b = TsuruYamlKubernetesDisruptionBudget{
+		MaxUnavailable: intOrPercentString(raw.MaxUnavailable),
+		MinAvailable:   intOrPercentString(raw.MinAvailable),
+		Disabled:       raw.Disabled,
+	}
+	return nil
+}
+
+func intOrPercentString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func (b *TsuruYamlKubernetesDisruptionBudget) Validate() error {
+	if b.Disabled {
+		return nil
+	}
+	if (b.MaxUnavailable == "") == (b.MinAvailable == "") {
+		return errors.New("exactly one of max_unavailable or min_available must be set")
+	}
+	for _, v := range []string{b.MaxUnavailable, b.MinAvailable} {
+		if v == "" {
+			continue
+		}
+		if _, _, err := ParseIntOrPercent(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseIntOrPercent parses values like "2" or "25%", returning whether the
+// value is a percentage.
+func ParseIntOrPercent(v string) (int, bool, error) {
+	isPercent := strings.HasSuffix(v, "%")
+	n, err := strconv.Atoi(strings.TrimSuffix(v, "%"))
+	if err != nil || n < 0 || (isPercent && n > 100) {
+		return 0, false, errors.Errorf("invalid value %q, must be a non-negative number or a percentage", v)
+	}
+	return n, isPercent, nil
+}
+
+// TsuruYamlKubernetesTopologySpread overrides how the units of a process are
+// spread across zones and nodes. Each field is one of hard, soft or none.
+type TsuruYamlKubernetesTopologySpread struct {
+	Zone string `json:"zone,omitempty" bson:",omitempty"`
+	Node string `json:"node,omitempty" bson:",omitempty"`
+}
+
+func (t *TsuruYamlKubernetesTopologySpread) Validate() error {
+	for _, v := range []string{t.Zone, t.Node} {
+		if err := ValidateTopologySpreadMode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidateTopologySpreadMode(mode string) error {
+	switch mode {
+	case "", TopologySpreadHard, TopologySpreadSoft, TopologySpreadNone:
+		return nil
+	}
+	return errors.Errorf("invalid topology spread %q, must be one of %s, %s or %s", mode, TopologySpreadHard, TopologySpreadSoft, TopologySpreadNone)
+}
+
+// ValidatePlacement checks the disruption budget and topology spread of the
+// process.
+func (c *TsuruYamlKubernetesProcessConfig) ValidatePlacement() error {
+	if c.DisruptionBudget != nil {
+		if err := c.DisruptionBudget.Validate(); err != nil {
+			return errors.Wrap(err, "disruption_budget")
+		}
+	}
+	if c.TopologySpread != nil {
+		if err := c.TopologySpread.Validate(); err != nil {
+			return errors.Wrap(err, "topology_spread")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package provision
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TsuruYamlProbes are the probes of a process declared in the probes section
+// of tsuru.yaml, indexed by process name.
+type TsuruYamlProbes struct {
+	Readiness *TsuruYamlProbe `json:"readiness,omitempty" bson:",omitempty"`
+	Liveness  *TsuruYamlProbe `json:"liveness,omitempty" bson:",omitempty"`
+	Startup   *TsuruYamlProbe `json:"startup,omitempty" bson:",omitempty"`
+}
+
+// TsuruYamlProbe checks a process using exactly one of an HTTP request, a
+// TCP connection or a command.
+type TsuruYamlProbe struct {
+	HTTP                *TsuruYamlProbeHTTP `json:"http,omitempty" bson:",omitempty"`
+	TCP                 *TsuruYamlProbeTCP  `json:"tcp,omitempty" bson:",omitempty"`
+	Command             []string            `json:"command,omitempty" bson:",omitempty"`
+	InitialDelaySeconds int                 `json:"initial_delay_seconds,omitempty" yaml:"initial_delay_seconds" bson:"initial_delay_seconds,omitempty"`
+	PeriodSeconds       int                 `json:"period_seconds,omitempty" yaml:"period_seconds" bson:"period_seconds,omitempty"`
+	TimeoutSeconds      int                 `json:"timeout_seconds,omitempty" yaml:"timeout_seconds" bson:"timeout_seconds,omitempty"`
+	FailureThreshold    int                 `json:"failure_threshold,omitempty" yaml:"failure_threshold" bson:"failure_threshold,omitempty"`
+	SuccessThreshold    int                 `json:"success_threshold,omitempty" yaml:"success_threshold" bson:"success_threshold,omitempty"`
+}
+
+type TsuruYamlProbeHTTP struct {
+	Path    string            `json:"path"`
+	Port    int               `json:"port,omitempty" bson:",omitempty"`
+	Scheme  string            `json:"scheme,omitempty" bson:",omitempty"`
+	Headers map[string]string `json:"headers,omitempty" bson:",omitempty"`
+}
+
+type TsuruYamlProbeTCP struct {
+	Port int `json:"port,omitempty" bson:",omitempty"`
+}
+
+func (p *TsuruYamlProbes) Validate() error {
+	probes := []struct {
+		name  string
+		probe *TsuruYamlProbe
+	}{
+		{name: "readiness", probe: p.Readiness},
+		{name: "liveness", probe: p.Liveness},
+		{name: "startup", probe: p.Startup},
+	}
+	for _, item := range probes {
+		if item.probe == nil {
+			continue
+		}
+		if err := item.probe.Validate(); err != nil {
+			return errors.Wrapf(err, "%s probe", item.name)
+		}
+		if item.name != "readiness" && item.probe.SuccessThreshold > 1 {
+			return errors.Errorf("%s probe: success_threshold must be 1", item.name)
+		}
+	}
+	return nil
+}
+
+func (p *TsuruYamlProbe) Validate() error {
+	var handlers int
+	if p.HTTP != nil {
+		handlers++
+		if !strings.HasPrefix(p.HTTP.Path, "/") {
+			return errors.Errorf("http path must start with /, got %q", p.HTTP.Path)
+		}
+		switch strings.ToLower(p.HTTP.Scheme) {
+		case "", "http", "https":
+		default:
+			return errors.Errorf("invalid http scheme %q, must be http or https", p.HTTP.Scheme)
+		}
+		if err := validateProbePort(p.HTTP.Port); err != nil {
+			return err
+		}
+	}
+	if p.TCP != nil {
+		handlers++
+		if err := validateProbePort(p.TCP.Port); err != nil {
+			return err
+		}
+	}
+	if len(p.Command) > 0 {
+		handlers++
+	}
+	if handlers != 1 {
+		return errors.New("exactly one of http, tcp or command must be set")
+	}
+	fields := []struct {
+		name  string
+		value int
+	}{
+		{name: "initial_delay_seconds", value: p.InitialDelaySeconds},
+		{name: "period_seconds", value: p.PeriodSeconds},
+		{name: "timeout_seconds", value: p.TimeoutSeconds},
+		{name: "failure_threshold", value: p.FailureThreshold},
+		{name: "success_threshold", value: p.SuccessThreshold},
+	}
+	for _, f := range fields {
+		if f.value < 0 {
+			return errors.Errorf("%s must not be negative", f.name)
+		}
+	}
+	return nil
+}
+
+func validateProbePort(port int) error {
+	if port < 0 || port > 65535 {
+		return errors.Errorf("invalid port %d", port)
+	}
+	return nil
+}
+
+// ValidateProbes checks the probes of all processes in tsuru.yaml.
+func (y TsuruYamlData) ValidateProbes() error {
+	for process, probes := range y.Probes {
+		if err := probes.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid probes for process %q", process)
+		}
+	}
+	return nil
+}
@@ -4,12 +4,16 @@
 
 package provision
 
-import "github.com/tsuru/tsuru/types/router"
+import (
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/types/router"
+)
 
 type TsuruYamlData struct {
 	Hooks       *TsuruYamlHooks            `json:"hooks,omitempty" bson:",omitempty"`
 	Healthcheck *TsuruYamlHealthcheck      `json:"healthcheck,omitempty" bson:",omitempty"`
 	Kubernetes  *TsuruYamlKubernetesConfig `json:"kubernetes,omitempty" bson:",omitempty"`
+	Probes      map[string]TsuruYamlProbes `json:"probes,omitempty" bson:",omitempty"`
 }
 
 type TsuruYamlHooks struct {
@@ -63,7 +67,12 @@ func (in *TsuruYamlKubernetesConfig) DeepCopy() *TsuruYamlKubernetesConfig {
 type TsuruYamlKubernetesGroup map[string]TsuruYamlKubernetesProcessConfig
 
 type TsuruYamlKubernetesProcessConfig struct {
-	Ports []TsuruYamlKubernetesProcessPortConfig `json:"ports"`
+	Ports            []TsuruYamlKubernetesProcessPortConfig `json:"ports"`
+	InitContainers   []TsuruYamlKubernetesContainer         `json:"init_containers,omitempty" yaml:"init_containers" bson:"init_containers,omitempty"`
+	Sidecars         []TsuruYamlKubernetesContainer         `json:"sidecars,omitempty" bson:",omitempty"`
+	SharedVolumes    []TsuruYamlKubernetesSharedVolume      `json:"shared_volumes,omitempty" yaml:"shared_volumes" bson:"shared_volumes,omitempty"`
+	DisruptionBudget *TsuruYamlKubernetesDisruptionBudget   `json:"disruption_budget,omitempty" yaml:"disruption_budget" bson:"disruption_budget,omitempty"`
+	TopologySpread   *TsuruYamlKubernetesTopologySpread     `json:"topology_spread,omitempty" yaml:"topology_spread" bson:"topology_spread,omitempty"`
 }
 
 type TsuruYamlKubernetesProcessPortConfig struct {
@@ -73,9 +82,20 @@ type TsuruYamlKubernetesProcessPortConfig struct {
 	TargetPort int    `json:"target_port,omitempty"`
 }
 
-func (y TsuruYamlData) ToRouterHC() router.HealthcheckData {
+// ToRouterHC returns the router healthcheck of the app. The legacy
+// healthcheck is used when use_in_router is set, otherwise the http readiness
+// probe of the given web process is used.
+func (y TsuruYamlData) ToRouterHC(webProcess string) router.HealthcheckData {
 	hc := y.Healthcheck
 	if hc == nil || !hc.UseInRouter {
+		if y.RouterHCFromProbe(webProcess) {
+			readiness := y.Probes[webProcess].Readiness
+			return router.HealthcheckData{
+				Path:    readiness.HTTP.Path,
+				Scheme:  readiness.HTTP.Scheme,
+				Headers: readiness.HTTP.Headers,
+			}
+		}
 		return router.HealthcheckData{
 			Path: "/",
 		}
@@ -87,6 +107,16 @@ func (y TsuruYamlData) ToRouterHC() router.HealthcheckData {
 	}
 }
 
+// RouterHCFromProbe reports whether the router healthcheck of the app comes
+// from the http readiness probe of the given web process.
+func (y TsuruYamlData) RouterHCFromProbe(webProcess string) bool {
+	if y.Healthcheck != nil && y.Healthcheck.UseInRouter {
+		return false
+	}
+	readiness := y.Probes[webProcess].Readiness
+	return readiness != nil && readiness.HTTP != nil
+}
+
 func (y *TsuruYamlKubernetesConfig) GetProcessConfigs(procName string) *TsuruYamlKubernetesProcessConfig {
 	for _, group := range y.Groups {
 		for p, proc := range group {
@@ -97,3 +127,22 @@ func (y *TsuruYamlKubernetesConfig) GetProcessConfigs(procName string) *TsuruYam
 	}
 	return nil
 }
+
+// ValidateKubernetes checks the kubernetes configs of all processes in
+// tsuru.yaml.
+func (y TsuruYamlData) ValidateKubernetes() error {
+	if y.Kubernetes == nil {
+		return nil
+	}
+	for _, group := range y.Kubernetes.Groups {
+		for process, config := range group {
+			if err := config.ValidateContainers(); err != nil {
+				return errors.Wrapf(err, "invalid containers for process %q", process)
+			}
+			if err := config.ValidatePlacement(); err != nil {
+				return errors.Wrapf(err, "invalid placement for process %q", process)
+			}
+		}
+	}
+	return nil
+}
@@ -60,3 +60,54 @@ func (m *MockQuotaService) Set(ctx context.Context, item QuotaItem, quantity int
 func (m *MockQuotaService) Get(ctx context.Context, item QuotaItem) (*Quota, error) {
 	return m.OnGet(item)
 }
+
+var (
+	_ TeamQuotaStorage = &MockTeamQuotaStorage{}
+	_ TeamQuotaService = &MockTeamQuotaService{}
+	_ TeamUsageReader  = &MockTeamUsageReader{}
+)
+
+type MockTeamQuotaStorage struct {
+	OnGetLimit func(string) (*TeamResources, error)
+	OnSetLimit func(string, TeamResources) error
+}
+
+func (m *MockTeamQuotaStorage) GetLimit(ctx context.Context, team string) (*TeamResources, error) {
+	return m.OnGetLimit(team)
+}
+
+func (m *MockTeamQuotaStorage) SetLimit(ctx context.Context, team string, limit TeamResources) error {
+	return m.OnSetLimit(team, limit)
+}
+
+type MockTeamUsageReader struct {
+	OnGetTeamUsage func(string) (*TeamUsage, error)
+}
+
+func (m *MockTeamUsageReader) GetTeamUsage(ctx context.Context, team string) (*TeamUsage, error) {
+	return m.OnGetTeamUsage(team)
+}
+
+type MockTeamQuotaService struct {
+	OnGet      func(string) (*TeamQuota, error)
+	OnSetLimit func(string, TeamResources) error
+	OnCheck    func(string, TeamResources) error
+}
+
+func (m *MockTeamQuotaService) Get(ctx context.Context, team string) (*TeamQuota, error) {
+	return m.OnGet(team)
+}
+
+func (m *MockTeamQuotaService) SetLimit(ctx context.Context, team string, limit TeamResources) error {
+	if m.OnSetLimit == nil {
+		return nil
+	}
+	return m.OnSetLimit(team, limit)
+}
+
+func (m *MockTeamQuotaService) Check(ctx context.Context, team string, requested TeamResources) error {
+	if m.OnCheck == nil {
+		return nil
+	}
+	return m.OnCheck(team, requested)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quota
+
+import (
+	"context"
+	"fmt"
+)
+
+// TeamResources holds the amount of each resource covered by team quotas.
+// Memory is expressed in bytes. When used as a limit, -1 means unlimited.
+type TeamResources struct {
+	Units            int   `json:"units"`
+	Memory           int64 `json:"memory"`
+	CPUMilli         int   `json:"cpumilli"`
+	ServiceInstances int   `json:"serviceinstances"`
+}
+
+// UnlimitedTeamResources is the limit used by teams without a quota.
+var UnlimitedTeamResources = TeamResources{Units: -1, Memory: -1, CPUMilli: -1, ServiceInstances: -1}
+
+// TeamAppUsage holds the resources allocated by a single app, derived from
+// its running units and plan.
+type TeamAppUsage struct {
+	App      string `json:"app"`
+	Plan     string `json:"plan"`
+	Units    int    `json:"units"`
+	Memory   int64  `json:"memory"`
+	CPUMilli int    `json:"cpumilli"`
+}
+
+type TeamUsage struct {
+	Apps             []TeamAppUsage
+	ServiceInstances int
+}
+
+// Total sums the usage of every app in the team.
+func (u *TeamUsage) Total() TeamResources {
+	total := TeamResources{ServiceInstances: u.ServiceInstances}
+	for _, a := range u.Apps {
+		total.Units += a.Units
+		total.Memory += a.Memory
+		total.CPUMilli += a.CPUMilli
+	}
+	return total
+}
+
+type TeamQuota struct {
+	Limit TeamResources  `json:"limit"`
+	InUse TeamResources  `json:"inuse"`
+	Apps  []TeamAppUsage `json:"apps"`
+}
+
+// TeamUsageReader calculates the resources currently allocated by a team.
+type TeamUsageReader interface {
+	GetTeamUsage(ctx context.Context, team string) (*TeamUsage, error)
+}
+
+type TeamQuotaService interface {
+	Get(ctx context.Context, team string) (*TeamQuota, error)
+	SetLimit(ctx context.Context, team string, limit TeamResources) error
+	// Check returns a *TeamQuotaExceededError if the team is not able to
+	// allocate the requested resources in addition to the ones in use.
+	// Requested values lower than or equal to zero are not checked.
+	Check(ctx context.Context, team string, requested TeamResources) error
+}
+
+type TeamQuotaStorage interface {
+	// GetLimit returns ErrQuotaNotFound when no limit was set for the team.
+	GetLimit(ctx context.Context, team string) (*TeamResources, error)
+	SetLimit(ctx context.Context, team string, limit TeamResources) error
+}
+
+type TeamQuotaExceededError struct {
+	Team      string
+	Resource  string
+	Requested int64
+	Available int64
+}
+
+func (err *TeamQuotaExceededError) Error() string {
+	return fmt.Sprintf("Quota exceeded for %s in team %q. Available: %d, Requested: %d.", err.Resource, err.Team, err.Available, err.Requested)
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+)
+
+const (
+	DockerHubRegistry      = "docker.io"
+	DockerHubServerAddress = "https://index.docker.io/v1/"
+)
+
+var (
+	ErrCredentialNotFound      = errors.New("registry credential not found")
+	ErrCredentialAlreadyExists = errors.New("registry credential already exists for the team")
+)
+
+// Credential authenticates the pulls of images from a private registry done
+// on behalf of the apps of a team. The token is write only, it's never
+// returned by the API.
+type Credential struct {
+	Team     string `json:"team" form:"team"`
+	Registry string `json:"registry" form:"registry"`
+	Username string `json:"username" form:"username"`
+	Token    string `json:"token,omitempty" form:"token"`
+}
+
+// Normalize strips the scheme and trailing slashes from the registry host,
+// which is case insensitive.
+func (c *Credential) Normalize() {
+	c.Registry = NormalizeRegistry(c.Registry)
+}
+
+func (c *Credential) Validate() error {
+	if c.Team == "" {
+		return &tsuruErrors.ValidationError{Message: "team is required"}
+	}
+	if c.Registry == "" {
+		return &tsuruErrors.ValidationError{Message: "registry is required"}
+	}
+	if strings.ContainsAny(c.Registry, "/ ") {
+		return &tsuruErrors.ValidationError{Message: "registry must be a host, optionally followed by a port"}
+	}
+	if c.Username == "" || c.Token == "" {
+		return &tsuruErrors.ValidationError{Message: "username and token are required"}
+	}
+	return nil
+}
+
+func NormalizeRegistry(registry string) string {
+	registry = strings.TrimSpace(strings.ToLower(registry))
+	for _, prefix := range []string{"https://", "http://"} {
+		registry = strings.TrimPrefix(registry, prefix)
+	}
+	return strings.TrimRight(registry, "/")
+}
+
+// ImageRegistry returns the registry host of the image, following the docker
+// rules: the first component of the name is a registry if it has a dot or a
+// port or if it's localhost, otherwise the image comes from Docker Hub.
+func ImageRegistry(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return DockerHubRegistry
+	}
+	host := parts[0]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return DockerHubRegistry
+	}
+	return NormalizeRegistry(host)
+}
+
+// ServerAddress returns the address used as key for the registry in docker
+// config files and auth configs.
+func ServerAddress(registry string) string {
+	if registry == DockerHubRegistry {
+		return DockerHubServerAddress
+	}
+	return registry
+}
+
+type CredentialStorage interface {
+	Insert(ctx context.Context, c Credential) error
+	Update(ctx context.Context, c Credential) error
+	Delete(ctx context.Context, team, registry string) error
+	Find(ctx context.Context, team, registry string) (*Credential, error)
+	FindByTeams(ctx context.Context, teams []string) ([]Credential, error)
+}
+
+type CredentialService interface {
+	Create(ctx context.Context, c Credential) error
+	Update(ctx context.Context, c Credential) error
+	Delete(ctx context.Context, team, registry string) error
+	// List returns the credentials of the teams, without their tokens.
+	List(ctx context.Context, teams []string) ([]Credential, error)
+	// FindForImage returns the credential to pull the image, looking for a
+	// credential of each team in order. It returns ErrCredentialNotFound if
+	// none of the teams has a credential for the image registry.
+	FindForImage(ctx context.Context, teams []string, image string) (*Credential, error)
+}
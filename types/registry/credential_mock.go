@@ -0,0 +1,52 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package registry
+
+import "context"
+
+var _ CredentialService = &MockCredentialService{}
+
+type MockCredentialService struct {
+	OnCreate       func(Credential) error
+	OnUpdate       func(Credential) error
+	OnDelete       func(team, registry string) error
+	OnList         func(teams []string) ([]Credential, error)
+	OnFindForImage func(teams []string, image string) (*Credential, error)
+}
+
+func (m *MockCredentialService) Create(ctx context.Context, c Credential) error {
+	if m.OnCreate == nil {
+		return nil
+	}
+	return m.OnCreate(c)
+}
+
+func (m *MockCredentialService) Update(ctx context.Context, c Credential) error {
+	if m.OnUpdate == nil {
+		return nil
+	}
+	return m.OnUpdate(c)
+}
+
+func (m *MockCredentialService) Delete(ctx context.Context, team, registry string) error {
+	if m.OnDelete == nil {
+		return nil
+	}
+	return m.OnDelete(team, registry)
+}
+
+func (m *MockCredentialService) List(ctx context.Context, teams []string) ([]Credential, error) {
+	if m.OnList == nil {
+		return nil, nil
+	}
+	return m.OnList(teams)
+}
+
+func (m *MockCredentialService) FindForImage(ctx context.Context, teams []string, image string) (*Credential, error) {
+	if m.OnFindForImage == nil {
+		return nil, ErrCredentialNotFound
+	}
+	return m.OnFindForImage(teams, image)
+}
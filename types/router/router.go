@@ -41,6 +41,8 @@ type HealthcheckData struct {
 	Status  int
 	Body    string
 	TCPOnly bool
+	Scheme  string            `json:",omitempty"`
+	Headers map[string]string `json:",omitempty"`
 }
 
 func (hc *HealthcheckData) String() string {